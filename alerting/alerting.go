@@ -0,0 +1,261 @@
+// Package alerting evaluates typed alert conditions against a plugin's
+// streams and hands triggered alerts to the host, which delivers the
+// notifications.
+//
+// The host registers conditions through the register_alerts export; the
+// plugin feeds prices, indicator values and funding rates into Default():
+//
+//	func (h *streamHandler) onTicker(symbol string, price float64, ts int64) {
+//	    alerting.Default().OnPrice(symbol, price, ts)
+//	}
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+// ConditionType is the kind of an alert condition
+type ConditionType string
+
+const (
+	PriceCross         ConditionType = "price_cross"         // Price crosses Level
+	IndicatorThreshold ConditionType = "indicator_threshold" // Indicator crosses Level
+	FundingSpike       ConditionType = "funding_spike"       // Absolute funding rate reaches Level
+)
+
+// Direction restricts which crossings trigger
+type Direction string
+
+const (
+	Up   Direction = "up"
+	Down Direction = "down"
+	Any  Direction = "any"
+)
+
+// Condition is an alert condition registered by the host
+type Condition struct {
+	ID        string        `json:"id"`
+	Type      ConditionType `json:"type"`
+	Symbol    string        `json:"symbol"`
+	Indicator string        `json:"indicator,omitempty"` // IndicatorThreshold only
+	Level     string        `json:"level"`               // Price, indicator value or funding rate
+	Direction Direction     `json:"direction,omitempty"` // Defaults to Any; ignored for FundingSpike
+	Cooldown  int64         `json:"cooldown,omitempty"`  // Minimum seconds between two triggers
+	Message   string        `json:"message,omitempty"`
+}
+
+// Validate checks the condition is complete
+func (c Condition) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("condition id is required")
+	}
+	if c.Symbol == "" {
+		return fmt.Errorf("condition %s: symbol is required", c.ID)
+	}
+	switch c.Type {
+	case PriceCross, FundingSpike:
+	case IndicatorThreshold:
+		if c.Indicator == "" {
+			return fmt.Errorf("condition %s: indicator is required", c.ID)
+		}
+	default:
+		return fmt.Errorf("condition %s: unknown type %q", c.ID, c.Type)
+	}
+	switch c.Direction {
+	case "", Up, Down, Any:
+	default:
+		return fmt.Errorf("condition %s: unknown direction %q", c.ID, c.Direction)
+	}
+	if _, err := strconv.ParseFloat(c.Level, 64); err != nil {
+		return fmt.Errorf("condition %s: invalid level %q", c.ID, c.Level)
+	}
+	return nil
+}
+
+// Alert is a triggered condition, sent to the host via alert_trigger
+type Alert struct {
+	ConditionID string        `json:"conditionId"`
+	Type        ConditionType `json:"type"`
+	Symbol      string        `json:"symbol"`
+	Indicator   string        `json:"indicator,omitempty"`
+	Direction   Direction     `json:"direction"` // Up or Down
+	Level       string        `json:"level"`
+	Value       float64       `json:"value"`     // Value that triggered the alert
+	Timestamp   int64         `json:"timestamp"` // Unix timestamp in milliseconds
+	Message     string        `json:"message,omitempty"`
+	TraceID     string        `json:"traceId,omitempty"`
+}
+
+// Trigger sends an alert to the host. It fails with hostinfo.ErrUnsupported
+// on hosts without alert support.
+func Trigger(alert Alert) error {
+	if err := hostinfo.Require(hostinfo.AlertTrigger); err != nil {
+		return err
+	}
+	if alert.TraceID == "" {
+		alert.TraceID = trace.Current()
+	}
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	host.CallAlertTrigger(data)
+	return nil
+}
+
+type state struct {
+	cond      Condition
+	level     float64
+	last      float64
+	seen      bool
+	triggered int64 // Timestamp of the last trigger
+}
+
+// Evaluator holds registered conditions and triggers them as values arrive
+type Evaluator struct {
+	mu         sync.Mutex
+	conditions map[string]*state
+	trigger    func(Alert) error
+}
+
+// NewEvaluator creates an evaluator that sends alerts with Trigger
+func NewEvaluator() *Evaluator {
+	return &Evaluator{conditions: make(map[string]*state), trigger: Trigger}
+}
+
+// SetTrigger replaces the function alerts are delivered with
+func (e *Evaluator) SetTrigger(fn func(Alert) error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.trigger = fn
+}
+
+// Register adds or replaces conditions. Nothing is registered if one is invalid.
+func (e *Evaluator) Register(conditions ...Condition) error {
+	for _, c := range conditions {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, c := range conditions {
+		level, _ := strconv.ParseFloat(c.Level, 64)
+		e.conditions[c.ID] = &state{cond: c, level: level}
+	}
+	return nil
+}
+
+// Remove deletes conditions by ID
+func (e *Evaluator) Remove(ids ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, id := range ids {
+		delete(e.conditions, id)
+	}
+}
+
+// Conditions returns the registered conditions
+func (e *Evaluator) Conditions() []Condition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	conditions := make([]Condition, 0, len(e.conditions))
+	for _, s := range e.conditions {
+		conditions = append(conditions, s.cond)
+	}
+	return conditions
+}
+
+// OnPrice evaluates the PriceCross conditions of symbol
+func (e *Evaluator) OnPrice(symbol string, price float64, ts int64) error {
+	return e.evaluate(PriceCross, symbol, "", price, ts)
+}
+
+// OnIndicator evaluates the IndicatorThreshold conditions of symbol and indicator
+func (e *Evaluator) OnIndicator(symbol, indicator string, value float64, ts int64) error {
+	return e.evaluate(IndicatorThreshold, symbol, indicator, value, ts)
+}
+
+// OnFunding evaluates the FundingSpike conditions of symbol
+func (e *Evaluator) OnFunding(symbol string, rate float64, ts int64) error {
+	return e.evaluate(FundingSpike, symbol, "", rate, ts)
+}
+
+// evaluate triggers conditions on the transition into their triggered state.
+// The first value of a condition only initializes it.
+func (e *Evaluator) evaluate(typ ConditionType, symbol, indicator string, value float64, ts int64) error {
+	e.mu.Lock()
+	var alerts []Alert
+	for _, s := range e.conditions {
+		c := s.cond
+		if c.Type != typ || c.Symbol != symbol || c.Indicator != indicator {
+			continue
+		}
+
+		prev, seen := s.last, s.seen
+		s.last, s.seen = value, true
+		if !seen {
+			continue
+		}
+
+		dir, crossed := crossing(typ, prev, value, s.level)
+		if !crossed || (typ != FundingSpike && c.Direction != "" && c.Direction != Any && c.Direction != dir) {
+			continue
+		}
+		if c.Cooldown > 0 && s.triggered != 0 && ts-s.triggered < c.Cooldown*1000 {
+			continue
+		}
+		s.triggered = ts
+
+		alerts = append(alerts, Alert{
+			ConditionID: c.ID,
+			Type:        c.Type,
+			Symbol:      c.Symbol,
+			Indicator:   c.Indicator,
+			Direction:   dir,
+			Level:       c.Level,
+			Value:       value,
+			Timestamp:   ts,
+			Message:     c.Message,
+		})
+	}
+	trigger := e.trigger
+	e.mu.Unlock()
+
+	for _, alert := range alerts {
+		if err := trigger(alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func crossing(typ ConditionType, prev, value, level float64) (Direction, bool) {
+	if typ == FundingSpike {
+		// Spikes are measured on the absolute rate
+		prev, value = abs(prev), abs(value)
+		return Up, prev < level && value >= level
+	}
+	switch {
+	case prev < level && value >= level:
+		return Up, true
+	case prev > level && value <= level:
+		return Down, true
+	}
+	return "", false
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,41 @@
+package alerting
+
+import "testing"
+
+func TestEvaluatorTriggersOnCrossing(t *testing.T) {
+	var alerts []Alert
+	e := NewEvaluator()
+	e.SetTrigger(func(a Alert) error {
+		alerts = append(alerts, a)
+		return nil
+	})
+
+	err := e.Register(
+		Condition{ID: "btc-100", Type: PriceCross, Symbol: "BTC", Level: "100", Direction: Up, Cooldown: 120},
+		Condition{ID: "funding", Type: FundingSpike, Symbol: "BTC", Level: "0.001"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices := []float64{90, 101, 95, 105}
+	for i, p := range prices {
+		if err := e.OnPrice("BTC", p, int64(i)*30_000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The second upward crossing falls into the cooldown
+	if len(alerts) != 1 || alerts[0].ConditionID != "btc-100" || alerts[0].Value != 101 {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	_ = e.OnFunding("BTC", 0.0001, 0)
+	_ = e.OnFunding("BTC", -0.002, 1)
+	if len(alerts) != 2 || alerts[1].ConditionID != "funding" {
+		t.Fatalf("expected funding spike alert, got %+v", alerts)
+	}
+
+	if err := e.Register(Condition{ID: "bad", Type: IndicatorThreshold, Symbol: "BTC", Level: "1"}); err == nil {
+		t.Fatal("expected indicator condition without indicator to be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+package alerting
+
+import (
+	"sort"
+
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// RegisterRequest is the input of the register_alerts export. Conditions
+// replace all registered conditions unless Merge is set.
+type RegisterRequest struct {
+	Conditions []Condition `json:"conditions"`
+	Remove     []string    `json:"remove,omitempty"` // IDs to remove
+	Merge      bool        `json:"merge,omitempty"`
+}
+
+// RegisterResponse is the output of the register_alerts export
+type RegisterResponse struct {
+	Success    bool     `json:"success"`
+	Registered []string `json:"registered,omitempty"` // IDs of all registered conditions
+	Error      string   `json:"error,omitempty"`
+}
+
+var defaultEvaluator = NewEvaluator()
+
+// Default returns the evaluator the register_alerts export registers with
+func Default() *Evaluator {
+	return defaultEvaluator
+}
+
+func init() {
+	host.RegisterExport("register_alerts", register_alerts)
+}
+
+//go:wasmexport register_alerts
+func register_alerts() int32 {
+	var req RegisterRequest
+	if err := host.InputJSON(&req); err != nil {
+		host.OutputJSON(RegisterResponse{Error: "failed to parse register_alerts request"})
+		return 1
+	}
+
+	for _, c := range req.Conditions {
+		if err := c.Validate(); err != nil {
+			host.OutputJSON(RegisterResponse{Error: err.Error()})
+			return 1
+		}
+	}
+
+	e := defaultEvaluator
+	if !req.Merge {
+		for _, c := range e.Conditions() {
+			e.Remove(c.ID)
+		}
+	}
+	e.Remove(req.Remove...)
+	if err := e.Register(req.Conditions...); err != nil {
+		host.OutputJSON(RegisterResponse{Error: err.Error()})
+		return 1
+	}
+
+	resp := RegisterResponse{Success: true}
+	for _, c := range e.Conditions() {
+		resp.Registered = append(resp.Registered, c.ID)
+	}
+	sort.Strings(resp.Registered)
+	host.OutputJSON(resp)
+	return 0
+}
@@ -0,0 +1,180 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	pc "github.com/plusev-terminal/go-plugin-common/crypto"
+)
+
+// Builder assembles and signs a JSON Web Token
+//
+// Example (Coinbase Advanced Trade):
+//
+//	key, err := jwt.ParseECPrivateKeyPEM(apiSecret)
+//	token, err := jwt.New().
+//	    Header("kid", apiKey).
+//	    Header("nonce", nonce).
+//	    Issuer("cdp").
+//	    Subject(apiKey).
+//	    NotBefore(now).
+//	    ExpiresAt(now.Add(2 * time.Minute)).
+//	    Claim("uri", "GET api.coinbase.com/api/v3/brokerage/accounts").
+//	    SignES256(key)
+type Builder struct {
+	header map[string]any
+	claims map[string]any
+}
+
+// New creates an empty JWT builder
+func New() *Builder {
+	return &Builder{
+		header: map[string]any{"typ": "JWT"},
+		claims: make(map[string]any),
+	}
+}
+
+// Header sets a header field (e.g. "kid", "nonce")
+func (b *Builder) Header(key string, value any) *Builder {
+	b.header[key] = value
+	return b
+}
+
+// Claim sets a payload claim
+func (b *Builder) Claim(key string, value any) *Builder {
+	b.claims[key] = value
+	return b
+}
+
+// Issuer sets the "iss" claim
+func (b *Builder) Issuer(iss string) *Builder {
+	return b.Claim("iss", iss)
+}
+
+// Subject sets the "sub" claim
+func (b *Builder) Subject(sub string) *Builder {
+	return b.Claim("sub", sub)
+}
+
+// ID sets the "jti" claim
+func (b *Builder) ID(jti string) *Builder {
+	return b.Claim("jti", jti)
+}
+
+// IssuedAt sets the "iat" claim
+func (b *Builder) IssuedAt(t time.Time) *Builder {
+	return b.Claim("iat", t.Unix())
+}
+
+// NotBefore sets the "nbf" claim
+func (b *Builder) NotBefore(t time.Time) *Builder {
+	return b.Claim("nbf", t.Unix())
+}
+
+// ExpiresAt sets the "exp" claim
+func (b *Builder) ExpiresAt(t time.Time) *Builder {
+	return b.Claim("exp", t.Unix())
+}
+
+// SignHS256 returns the token signed with HMAC-SHA256
+func (b *Builder) SignHS256(secret []byte) (string, error) {
+	signingInput, err := b.signingInput("HS256")
+	if err != nil {
+		return "", err
+	}
+
+	sig := pc.HMACSHA256(secret, []byte(signingInput))
+	return signingInput + "." + pc.Base64URLEncode(sig), nil
+}
+
+// SignES256 returns the token signed with ECDSA P-256 and SHA-256
+func (b *Builder) SignES256(key *ecdsa.PrivateKey) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("es256 key is required")
+	}
+	if err := checkP256(key); err != nil {
+		return "", err
+	}
+	signingInput, err := b.signingInput("ES256")
+	if err != nil {
+		return "", err
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, pc.SHA256([]byte(signingInput)))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	// JWS uses the fixed-size R || S encoding, not ASN.1
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signingInput + "." + pc.Base64URLEncode(sig), nil
+}
+
+func (b *Builder) signingInput(alg string) (string, error) {
+	b.header["alg"] = alg
+
+	header, err := json.Marshal(b.header)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt header: %w", err)
+	}
+	claims, err := json.Marshal(b.claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt claims: %w", err)
+	}
+	return pc.Base64URLEncode(header) + "." + pc.Base64URLEncode(claims), nil
+}
+
+// ParseECPrivateKeyPEM parses a P-256 private key in SEC 1 ("EC PRIVATE KEY")
+// or PKCS #8 ("PRIVATE KEY") PEM format. Literal "\n" sequences, as found in
+// keys pasted into single-line config fields, are accepted.
+func ParseECPrivateKeyPEM(data string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(unescapeNewlines(data)))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in private key")
+	}
+
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		var ok bool
+		if key, ok = parsed.(*ecdsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("private key is not an ECDSA key")
+		}
+	}
+	if err := checkP256(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// checkP256 rejects keys on other curves, whose signatures don't fit ES256
+func checkP256(key *ecdsa.PrivateKey) error {
+	if key.Curve != elliptic.P256() {
+		return fmt.Errorf("es256 requires a P-256 key, got %s", key.Curve.Params().Name)
+	}
+	return nil
+}
+
+func unescapeNewlines(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == 'n' {
+			out = append(out, '\n')
+			i++
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
@@ -0,0 +1,8 @@
+package oauth2
+
+const (
+	// CMD_OAUTH_AUTHORIZE asks the plugin for an AuthorizationRequest
+	CMD_OAUTH_AUTHORIZE = "oauthAuthorize"
+	// CMD_OAUTH_CALLBACK delivers the AuthorizationCallback after the user granted access
+	CMD_OAUTH_CALLBACK = "oauthCallback"
+)
@@ -0,0 +1,53 @@
+package oauth2
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+)
+
+// ConfigFieldTypeOAuth2 is the ConfigField.Type the host renders as a
+// "Connect account" button instead of a text input
+const ConfigFieldTypeOAuth2 = "oauth2"
+
+// AuthorizationRequest is returned by the oauthAuthorize command. The host
+// opens AuthURL in the browser and listens on the redirect URL it passed in
+// the command params (key "redirectUrl").
+type AuthorizationRequest struct {
+	AuthURL string `json:"authUrl"`
+	State   string `json:"state"`
+}
+
+// AuthorizationCallback is passed as params of the oauthCallback command once
+// the provider redirected back to the host
+type AuthorizationCallback struct {
+	Code  string `json:"code" mapstructure:"code"`
+	State string `json:"state" mapstructure:"state"`
+	Error string `json:"error,omitempty" mapstructure:"error"`
+}
+
+// CredentialField describes an OAuth2 connection in the plugin's config fields.
+// The host stores the resulting Token encrypted under Name.
+type CredentialField struct {
+	Name        string
+	Label       string
+	Provider    string // Display name, e.g. "Schwab"
+	Description string
+	Scopes      []string
+	Required    bool
+}
+
+// ConfigField converts the credential into a ConfigField for GetConfigFields
+func (f CredentialField) ConfigField() plugin.ConfigField {
+	return plugin.ConfigField{
+		Name:        f.Name,
+		Label:       f.Label,
+		Type:        ConfigFieldTypeOAuth2,
+		Required:    f.Required,
+		Encrypt:     true,
+		Mask:        true,
+		Description: f.Description,
+		Options: map[string]any{
+			"provider": f.Provider,
+			"scopes":   f.Scopes,
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package oauth2
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// Config describes an OAuth2 provider and the client registered with it
+type Config struct {
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+	AuthURL      string   `json:"authUrl"`
+	TokenURL     string   `json:"tokenUrl"`
+	RedirectURL  string   `json:"redirectUrl"` // Provided by the host, see AuthorizationRequest
+	Scopes       []string `json:"scopes,omitempty"`
+
+	// BasicAuth sends client credentials via HTTP basic auth instead of the
+	// form body (required by e.g. Schwab)
+	BasicAuth bool `json:"basicAuth,omitempty"`
+}
+
+// Token is an OAuth2 token as persisted by the plugin (usually in an
+// encrypted config field)
+type Token struct {
+	AccessToken  string    `json:"accessToken"`
+	TokenType    string    `json:"tokenType,omitempty"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+}
+
+// expiryDelta refreshes tokens slightly before they actually expire
+const expiryDelta = 30 * time.Second
+
+// Valid reports whether the token can still be used at now
+func (t *Token) Valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || now.Add(expiryDelta).Before(t.Expiry)
+}
+
+// tokenResponse is the JSON body of a token endpoint response (RFC 6749 5.1)
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// AuthCodeURL returns the URL the user has to visit to grant access.
+// state must be verified when the host delivers the AuthorizationCallback.
+func (c Config) AuthCodeURL(state string) string {
+	values := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.RedirectURL},
+		"state":         {state},
+	}
+	if len(c.Scopes) > 0 {
+		values.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	sep := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.AuthURL + sep + values.Encode()
+}
+
+// Exchange trades an authorization code for a token
+func (c Config) Exchange(doer rt.RequestDoer, code string, now time.Time) (*Token, error) {
+	return c.requestToken(doer, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.RedirectURL},
+	}, now)
+}
+
+// Refresh obtains a new access token using a refresh token. If the provider
+// does not rotate refresh tokens, the old one is kept.
+func (c Config) Refresh(doer rt.RequestDoer, refreshToken string, now time.Time) (*Token, error) {
+	token, err := c.requestToken(doer, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// ClientCredentials obtains a token for the client itself (no user involved)
+func (c Config) ClientCredentials(doer rt.RequestDoer, now time.Time) (*Token, error) {
+	values := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		values.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	return c.requestToken(doer, values, now)
+}
+
+func (c Config) requestToken(doer rt.RequestDoer, values url.Values, now time.Time) (*Token, error) {
+	req := &rt.Request{
+		URL:     c.TokenURL,
+		Method:  "POST",
+		Headers: map[string]string{"Accept": "application/json"},
+	}
+
+	if c.BasicAuth {
+		req.Headers["Authorization"] = "Basic " + basicCredentials(c.ClientID, c.ClientSecret)
+	} else {
+		values.Set("client_id", c.ClientID)
+		if c.ClientSecret != "" {
+			values.Set("client_secret", c.ClientSecret)
+		}
+	}
+	req.SetFormBody(values)
+
+	res, err := doer.Send(req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	var body tokenResponse
+	if err := res.DecodeJSON(&body); err != nil {
+		return nil, err
+	}
+	if body.Error != "" {
+		return nil, fmt.Errorf("token request rejected: %s %s", body.Error, body.ErrorDescription)
+	}
+	if !res.IsSuccess() || body.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed with status %d: %s", res.Status, res.BodySnippet())
+	}
+
+	token := &Token{
+		AccessToken:  body.AccessToken,
+		TokenType:    body.TokenType,
+		RefreshToken: body.RefreshToken,
+		Scope:        body.Scope,
+	}
+	if body.ExpiresIn > 0 {
+		token.Expiry = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
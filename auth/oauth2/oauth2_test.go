@@ -0,0 +1,113 @@
+package oauth2_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/auth/oauth2"
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	requestertesting "github.com/plusev-terminal/go-plugin-common/requester/testing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+const tokenURL = "https://auth.example.com/token"
+
+var config = oauth2.Config{ClientID: "client", ClientSecret: "secret", TokenURL: tokenURL}
+
+func TestRefresh(t *testing.T) {
+	mock := requestertesting.NewMockRequester()
+	mock.On(tokenURL).Method("POST").Respond(`{"access_token":"new","token_type":"bearer","expires_in":3600}`)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	token, err := config.Refresh(mock, "refresh-1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "new" || token.RefreshToken != "refresh-1" || !token.Expiry.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	form, err := url.ParseQuery(string(mock.GetRequests()[0].Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "refresh-1" || form.Get("client_id") != "client" || form.Get("client_secret") != "secret" {
+		t.Errorf("unexpected token request %v", form)
+	}
+
+	mock.Reset()
+	mock.On(tokenURL).RespondStatus(http.StatusBadRequest, `{"error":"invalid_grant","error_description":"revoked"}`)
+	if _, err := config.Refresh(mock, "refresh-1", now); err == nil {
+		t.Error("expected a rejected refresh to fail")
+	}
+}
+
+func TestTokenSourceRefreshesExpiredToken(t *testing.T) {
+	mock := requestertesting.NewMockRequester()
+	route := mock.On(tokenURL).Respond(`{"access_token":"second","refresh_token":"refresh-2","expires_in":3600}`)
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var persisted []*oauth2.Token
+	first := &oauth2.Token{AccessToken: "first", RefreshToken: "refresh-1", Expiry: fake.Now().Add(time.Hour)}
+	ts := oauth2.NewTokenSource(config, mock, first, fake.Now, func(tok *oauth2.Token) { persisted = append(persisted, tok) })
+
+	if token, err := ts.Token(); err != nil || token != first {
+		t.Fatalf("expected the cached token, got %+v, %v", token, err)
+	}
+	if route.CallCount() != 0 {
+		t.Fatal("expected no refresh for a valid token")
+	}
+
+	// Tokens are refreshed shortly before they expire
+	fake.Advance(time.Hour - 10*time.Second)
+	token, err := ts.Token()
+	if err != nil || token.AccessToken != "second" || token.RefreshToken != "refresh-2" {
+		t.Fatalf("expected a refreshed token, got %+v, %v", token, err)
+	}
+	if _, err := ts.Token(); err != nil || route.CallCount() != 1 {
+		t.Fatalf("expected the refreshed token to be cached, got %d refreshes, %v", route.CallCount(), err)
+	}
+	if len(persisted) != 1 || persisted[0] != token {
+		t.Errorf("expected onRefresh with the new token, got %v", persisted)
+	}
+
+	expired := oauth2.NewTokenSource(config, mock, &oauth2.Token{AccessToken: "x", Expiry: fake.Now()}, fake.Now, nil)
+	if _, err := expired.Token(); err == nil {
+		t.Error("expected an expired token without refresh token to fail")
+	}
+}
+
+func TestMiddlewareRetriesAfter401(t *testing.T) {
+	mock := requestertesting.NewMockRequester()
+	refresh := mock.On(tokenURL).Respond(`{"access_token":"fresh","expires_in":3600}`)
+	rejected := mock.On("https://api.example.com/accounts").Header("Authorization", "Bearer revoked").RespondStatus(http.StatusUnauthorized, `{"error":"invalid token"}`)
+	accepted := mock.On("https://api.example.com/accounts").Header("Authorization", "Bearer fresh").Respond(`{"accounts":[]}`)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := &oauth2.Token{AccessToken: "revoked", RefreshToken: "refresh-1", Expiry: now.Add(time.Hour)}
+	ts := oauth2.NewTokenSource(config, mock, token, func() time.Time { return now }, nil)
+	doer := oauth2.Middleware(ts)(mock)
+
+	req := &rt.Request{Method: "GET", URL: "https://api.example.com/accounts"}
+	res, err := doer.Send(req, nil)
+	if err != nil || res.Status != http.StatusOK {
+		t.Fatalf("expected the retry to succeed, got %v, %v", res, err)
+	}
+	if rejected.CallCount() != 1 || refresh.CallCount() != 1 || accepted.CallCount() != 1 {
+		t.Errorf("expected one 401, one refresh and one retry, got %d, %d, %d", rejected.CallCount(), refresh.CallCount(), accepted.CallCount())
+	}
+	if req.Headers != nil {
+		t.Errorf("expected the caller's request to stay untouched, got %v", req.Headers)
+	}
+
+	// A second 401 is returned instead of refreshing in a loop
+	mock.Reset()
+	mock.On("https://api.example.com/accounts").RespondStatus(http.StatusUnauthorized, `{}`)
+	refresh = mock.On(tokenURL).Respond(`{"access_token":"fresh","expires_in":3600}`)
+	res, err = doer.Send(req, nil)
+	if err != nil || res.Status != http.StatusUnauthorized || refresh.CallCount() != 1 {
+		t.Errorf("expected a single refresh and the 401, got %v, %v, %d refreshes", res, err, refresh.CallCount())
+	}
+}
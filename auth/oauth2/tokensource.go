@@ -0,0 +1,118 @@
+package oauth2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// TokenSource hands out a valid access token, refreshing it when it expired
+type TokenSource struct {
+	mu        sync.Mutex
+	config    Config
+	doer      rt.RequestDoer
+	token     *Token
+	now       func() time.Time
+	onRefresh func(*Token)
+}
+
+// NewTokenSource creates a token source starting with token. doer is used for
+// refresh calls and must not itself use this token source. onRefresh is
+// called with every new token so the plugin can persist it; it may be nil.
+func NewTokenSource(config Config, doer rt.RequestDoer, token *Token, now func() time.Time, onRefresh func(*Token)) *TokenSource {
+	return &TokenSource{
+		config:    config,
+		doer:      doer,
+		token:     token,
+		now:       now,
+		onRefresh: onRefresh,
+	}
+}
+
+// Token returns a valid token, refreshing it if necessary
+func (s *TokenSource) Token() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid(s.now()) {
+		return s.token, nil
+	}
+	return s.refresh()
+}
+
+// refreshRejected refreshes the token after the API rejected rejected. If
+// another request refreshed it meanwhile, the current token is returned.
+func (s *TokenSource) refreshRejected(rejected *Token) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != rejected && s.token.Valid(s.now()) {
+		return s.token, nil
+	}
+	return s.refresh()
+}
+
+func (s *TokenSource) refresh() (*Token, error) {
+	if s.token == nil || s.token.RefreshToken == "" {
+		return nil, fmt.Errorf("oauth2 token expired and no refresh token is available, re-authorization required")
+	}
+
+	token, err := s.config.Refresh(s.doer, s.token.RefreshToken, s.now())
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	if s.onRefresh != nil {
+		s.onRefresh(token)
+	}
+	return token, nil
+}
+
+// Middleware returns a requester middleware adding the bearer token of ts
+// to every request. When the API answers 401, e.g. because the provider
+// revoked the token before its expiry, the token is refreshed and the
+// request retried once.
+func Middleware(ts *TokenSource) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return rt.DoerFunc(func(req *rt.Request, v any) (*rt.Response, error) {
+			token, err := ts.Token()
+			if err != nil {
+				return nil, err
+			}
+			res, err := next.Send(authorize(req, token), v)
+			if err != nil || res.Status != http.StatusUnauthorized || token.RefreshToken == "" {
+				return res, err
+			}
+
+			token, err = ts.refreshRejected(token)
+			if err != nil {
+				return nil, fmt.Errorf("refresh after 401: %w", err)
+			}
+			return next.Send(authorize(req, token), v)
+		})
+	}
+}
+
+// authorize returns a copy of req carrying token
+func authorize(req *rt.Request, token *Token) *rt.Request {
+	authed := *req
+	authed.Headers = make(map[string]string, len(req.Headers)+1)
+	for k, val := range req.Headers {
+		authed.Headers[k] = val
+	}
+	tokenType := token.TokenType
+	if tokenType == "" || tokenType == "bearer" {
+		tokenType = "Bearer"
+	}
+	authed.Headers["Authorization"] = tokenType + " " + token.AccessToken
+	return &authed
+}
+
+func basicCredentials(clientID, clientSecret string) string {
+	return base64.StdEncoding.EncodeToString([]byte(url.QueryEscape(clientID) + ":" + url.QueryEscape(clientSecret)))
+}
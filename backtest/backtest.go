@@ -0,0 +1,333 @@
+// Package backtest drives historical candles and trades through a Strategy,
+// simulates fills against the constraints of a trading.Market and collects
+// the results (equity curve, closed trades, drawdown).
+//
+// Orders placed while handling an event are matched from the next event on,
+// so a strategy never trades on the candle it just observed:
+//
+//	engine, err := backtest.NewEngine(backtest.Config{Market: market, InitialCash: 10000}, &myStrategy{})
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := engine.Run(backtest.CandleEvents(candles))
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Strategy receives market events and fills. Returning an error aborts the run.
+type Strategy interface {
+	OnCandle(ctx *Context, candle tt.OHLCVRecord) error
+	OnTrade(ctx *Context, trade tt.TradeRecord) error
+	OnFill(ctx *Context, fill tt.Fill) error
+}
+
+// BaseStrategy implements Strategy with no-ops; embed it to implement only
+// the callbacks a strategy needs
+type BaseStrategy struct{}
+
+func (BaseStrategy) OnCandle(*Context, tt.OHLCVRecord) error { return nil }
+func (BaseStrategy) OnTrade(*Context, tt.TradeRecord) error  { return nil }
+func (BaseStrategy) OnFill(*Context, tt.Fill) error          { return nil }
+
+// Event is a candle or a trade
+type Event struct {
+	Candle *tt.OHLCVRecord
+	Trade  *tt.TradeRecord
+}
+
+// Timestamp returns the event time in unix milliseconds
+func (e Event) Timestamp() int64 {
+	if e.Candle != nil {
+		return e.Candle.OpenTime * 1000
+	}
+	if e.Trade != nil {
+		return e.Trade.Timestamp
+	}
+	return 0
+}
+
+// CandleEvents wraps candles as events
+func CandleEvents(candles []tt.OHLCVRecord) []Event {
+	events := make([]Event, len(candles))
+	for i := range candles {
+		events[i] = Event{Candle: &candles[i]}
+	}
+	return events
+}
+
+// TradeEvents wraps trades as events
+func TradeEvents(trades []tt.TradeRecord) []Event {
+	events := make([]Event, len(trades))
+	for i := range trades {
+		events[i] = Event{Trade: &trades[i]}
+	}
+	return events
+}
+
+// MergeEvents combines event streams ordered by time. Events with equal time
+// keep the order of the arguments.
+func MergeEvents(streams ...[]Event) []Event {
+	var events []Event
+	for _, s := range streams {
+		events = append(events, s...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp() < events[j].Timestamp()
+	})
+	return events
+}
+
+// Config configures an Engine
+type Config struct {
+	Market      tt.Market
+	InitialCash float64 // In quote currency
+	Slippage    float64 // See FillSimulator.Slippage
+}
+
+// Engine runs one backtest
+type Engine struct {
+	strategy Strategy
+	sim      *FillSimulator
+	ctx      *Context
+	result   *Result
+	peak     float64
+}
+
+// NewEngine creates an engine for strategy
+func NewEngine(cfg Config, strategy Strategy) (*Engine, error) {
+	sim, err := NewFillSimulator(cfg.Market)
+	if err != nil {
+		return nil, err
+	}
+	sim.Slippage = cfg.Slippage
+
+	e := &Engine{
+		strategy: strategy,
+		sim:      sim,
+		result:   &Result{InitialCash: cfg.InitialCash},
+		peak:     cfg.InitialCash,
+	}
+	e.ctx = &Context{engine: e, cash: cfg.InitialCash}
+	return e, nil
+}
+
+// Run processes events in order and returns the result
+func (e *Engine) Run(events []Event) (*Result, error) {
+	for _, ev := range events {
+		e.ctx.now = ev.Timestamp()
+
+		if err := e.matchOrders(ev); err != nil {
+			return nil, err
+		}
+
+		var err error
+		switch {
+		case ev.Candle != nil:
+			if e.ctx.lastPrice, err = parsePrice("close", ev.Candle.Close); err != nil {
+				return nil, err
+			}
+			e.markEquity()
+			err = e.strategy.OnCandle(e.ctx, *ev.Candle)
+		case ev.Trade != nil:
+			if e.ctx.lastPrice, err = parsePrice("price", ev.Trade.Price); err != nil {
+				return nil, err
+			}
+			e.markEquity()
+			err = e.strategy.OnTrade(e.ctx, *ev.Trade)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("strategy failed at %d: %w", ev.Timestamp(), err)
+		}
+	}
+
+	e.result.FinalEquity = e.ctx.Equity()
+	if e.result.InitialCash != 0 {
+		e.result.Return = e.result.FinalEquity/e.result.InitialCash - 1
+	}
+	return e.result, nil
+}
+
+func (e *Engine) matchOrders(ev Event) error {
+	remaining := e.ctx.open[:0]
+	var fills []tt.Fill
+	for _, order := range e.ctx.open {
+		var fill tt.Fill
+		var ok bool
+		var err error
+		if ev.Candle != nil {
+			fill, ok, err = e.sim.MatchCandle(order, *ev.Candle)
+		} else {
+			fill, ok, err = e.sim.MatchTrade(order, *ev.Trade)
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			remaining = append(remaining, order)
+			continue
+		}
+		fills = append(fills, fill)
+	}
+	e.ctx.open = remaining
+
+	for _, fill := range fills {
+		if err := e.apply(fill); err != nil {
+			return err
+		}
+		if err := e.strategy.OnFill(e.ctx, fill); err != nil {
+			return fmt.Errorf("strategy failed on fill of %s: %w", fill.OrderID, err)
+		}
+	}
+	return nil
+}
+
+// apply books a fill into cash and position and records closed trades
+func (e *Engine) apply(fill tt.Fill) error {
+	price, err := strconv.ParseFloat(fill.Price, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fill price %q", fill.Price)
+	}
+	qty, err := strconv.ParseFloat(fill.Quantity, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fill quantity %q", fill.Quantity)
+	}
+	fee, err := strconv.ParseFloat(fill.Fee, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fill fee %q", fill.Fee)
+	}
+
+	ctx := e.ctx
+	signed := qty
+	if fill.Side == tt.SideSell {
+		signed = -qty
+	}
+	ctx.cash -= signed*price + fee
+	e.result.TotalFees += fee
+	e.result.Fills = append(e.result.Fills, fill)
+
+	// Closing (part of) the position
+	if ctx.position != 0 && (ctx.position > 0) != (signed > 0) {
+		closed := min(abs(signed), abs(ctx.position))
+		side, dir := "long", 1.0
+		if ctx.position < 0 {
+			side, dir = "short", -1.0
+		}
+		e.result.Trades = append(e.result.Trades, Trade{
+			Side:       side,
+			EntryTime:  ctx.entryTime,
+			ExitTime:   fill.Timestamp,
+			EntryPrice: ctx.entryPrice,
+			ExitPrice:  price,
+			Quantity:   closed,
+			PnL:        (price - ctx.entryPrice) * closed * dir,
+		})
+	}
+
+	next := ctx.position + signed
+	switch {
+	case abs(next) < 1e-12:
+		next = 0
+	case ctx.position == 0 || (ctx.position > 0) != (next > 0):
+		// Opened or flipped: the remainder enters at the fill price
+		ctx.entryPrice = price
+		ctx.entryTime = fill.Timestamp
+	case abs(next) > abs(ctx.position):
+		// Increased: average the entry price
+		ctx.entryPrice = (ctx.entryPrice*abs(ctx.position) + price*qty) / abs(next)
+	}
+	ctx.position = next
+	return nil
+}
+
+func (e *Engine) markEquity() {
+	equity := e.ctx.Equity()
+	e.result.EquityCurve = append(e.result.EquityCurve, EquityPoint{Timestamp: e.ctx.now, Equity: equity})
+	if equity > e.peak {
+		e.peak = equity
+	}
+	if e.peak > 0 {
+		e.result.MaxDrawdown = max(e.result.MaxDrawdown, (e.peak-equity)/e.peak)
+	}
+}
+
+// Context is the strategy's view of the simulated account
+type Context struct {
+	engine *Engine
+
+	now        int64
+	lastPrice  float64
+	cash       float64
+	position   float64
+	entryPrice float64
+	entryTime  int64
+	open       []tt.Order
+	nextID     int
+}
+
+// Now returns the time of the current event
+func (c *Context) Now() time.Time {
+	return time.UnixMilli(c.now).UTC()
+}
+
+// Cash returns the quote balance
+func (c *Context) Cash() float64 {
+	return c.cash
+}
+
+// Position returns the base quantity held; negative when short
+func (c *Context) Position() float64 {
+	return c.position
+}
+
+// Equity returns cash plus the position valued at the last price
+func (c *Context) Equity() float64 {
+	return c.cash + c.position*c.lastPrice
+}
+
+// PlaceOrder validates order against the market and queues it for matching
+// from the next event on. The returned order carries the assigned ID and the
+// rounded price and quantity.
+func (c *Context) PlaceOrder(order tt.Order) (tt.Order, error) {
+	order, err := c.engine.sim.Prepare(order, c.lastPrice)
+	if err != nil {
+		return order, err
+	}
+
+	c.nextID++
+	order.ID = "bt-" + strconv.Itoa(c.nextID)
+	order.Symbol = c.engine.sim.Market().Symbol
+	order.Status = tt.OrderStatusOpen
+	order.CreatedAt = c.now
+	c.open = append(c.open, order)
+	return order, nil
+}
+
+// CancelOrder removes an open order and reports whether it was open
+func (c *Context) CancelOrder(id string) bool {
+	for i, order := range c.open {
+		if order.ID == id {
+			c.open = append(c.open[:i], c.open[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// OpenOrders returns the orders waiting for a fill
+func (c *Context) OpenOrders() []tt.Order {
+	return append([]tt.Order(nil), c.open...)
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
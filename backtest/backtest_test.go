@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// buyThenSell buys on the first candle and sells on the third
+type buyThenSell struct {
+	BaseStrategy
+	seen  int
+	fills int
+}
+
+func (s *buyThenSell) OnCandle(ctx *Context, _ tt.OHLCVRecord) error {
+	s.seen++
+	side := ""
+	switch s.seen {
+	case 1:
+		side = tt.SideBuy
+	case 3:
+		side = tt.SideSell
+	default:
+		return nil
+	}
+	_, err := ctx.PlaceOrder(tt.Order{Side: side, Type: tt.OrderTypeMarket, Quantity: "1.00009"})
+	return err
+}
+
+func (s *buyThenSell) OnFill(*Context, tt.Fill) error {
+	s.fills++
+	return nil
+}
+
+func TestEngineRoundTrip(t *testing.T) {
+	market := tt.Market{Symbol: "BTCUSDT", PriceTick: "0.1", QuantityTick: "0.001", MinQuantity: "0.001", TakerFee: "0.001"}
+	candles := []tt.OHLCVRecord{
+		{OpenTime: 0, Open: "100", High: "100", Low: "100", Close: "100", Volume: "1"},
+		{OpenTime: 60, Open: "100", High: "110", Low: "90", Close: "105", Volume: "1"},
+		{OpenTime: 120, Open: "105", High: "120", Low: "80", Close: "90", Volume: "1"},
+		{OpenTime: 180, Open: "110", High: "110", Low: "110", Close: "110", Volume: "1"},
+	}
+
+	strategy := &buyThenSell{}
+	engine, err := NewEngine(Config{Market: market, InitialCash: 1000}, strategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(CandleEvents(candles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strategy.fills != 2 || len(result.Trades) != 1 {
+		t.Fatalf("expected 2 fills and 1 trade, got %d and %d", strategy.fills, len(result.Trades))
+	}
+	trade := result.Trades[0]
+	if trade.EntryPrice != 100 || trade.ExitPrice != 110 || trade.Quantity != 1 || trade.PnL != 10 {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+	if result.Fills[0].Quantity != "1.000" {
+		t.Fatalf("quantity not truncated to tick: %s", result.Fills[0].Quantity)
+	}
+
+	wantFees := 0.1 + 0.11
+	if math.Abs(result.TotalFees-wantFees) > 1e-9 || math.Abs(result.FinalEquity-(1010-wantFees)) > 1e-9 {
+		t.Fatalf("unexpected fees %v or equity %v", result.TotalFees, result.FinalEquity)
+	}
+	// Equity drops from 1005 to 990 while holding through the third candle
+	if math.Abs(result.MaxDrawdown-(1004.9-989.9)/1004.9) > 1e-9 {
+		t.Fatalf("unexpected drawdown %v", result.MaxDrawdown)
+	}
+}
+
+func TestPrepareRejectsSmallNotional(t *testing.T) {
+	sim, err := NewFillSimulator(tt.Market{Symbol: "X", MinNotional: "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sim.Prepare(tt.Order{Side: tt.SideBuy, Type: tt.OrderTypeLimit, Price: "1", Quantity: "5"}, 1); err == nil {
+		t.Fatal("expected notional below minimum to be rejected")
+	}
+}
@@ -0,0 +1,48 @@
+package backtest
+
+import (
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Result summarizes a backtest run. Amounts are in quote currency.
+type Result struct {
+	InitialCash float64       `json:"initialCash"`
+	FinalEquity float64       `json:"finalEquity"`
+	Return      float64       `json:"return"`      // FinalEquity/InitialCash - 1
+	MaxDrawdown float64       `json:"maxDrawdown"` // Largest peak-to-trough equity drop as a fraction of the peak
+	TotalFees   float64       `json:"totalFees"`
+	Fills       []tt.Fill     `json:"fills"`
+	Trades      []Trade       `json:"trades"`
+	EquityCurve []EquityPoint `json:"equityCurve"`
+}
+
+// Trade is a closed round trip. PnL excludes fees.
+type Trade struct {
+	Side       string  `json:"side"`      // "long" or "short"
+	EntryTime  int64   `json:"entryTime"` // Unix timestamp in milliseconds
+	ExitTime   int64   `json:"exitTime"`
+	EntryPrice float64 `json:"entryPrice"`
+	ExitPrice  float64 `json:"exitPrice"`
+	Quantity   float64 `json:"quantity"`
+	PnL        float64 `json:"pnl"`
+}
+
+// EquityPoint is the account equity at an event
+type EquityPoint struct {
+	Timestamp int64   `json:"timestamp"` // Unix timestamp in milliseconds
+	Equity    float64 `json:"equity"`
+}
+
+// WinRate returns the fraction of trades with positive PnL
+func (r *Result) WinRate() float64 {
+	if len(r.Trades) == 0 {
+		return 0
+	}
+	wins := 0
+	for _, t := range r.Trades {
+		if t.PnL > 0 {
+			wins++
+		}
+	}
+	return float64(wins) / float64(len(r.Trades))
+}
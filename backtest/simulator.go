@@ -0,0 +1,241 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/trading/utils"
+)
+
+// FillSimulator executes orders against historical candles and trades while
+// honoring the tick sizes, quantity and notional limits and fees of a Market.
+// Orders are filled completely or not at all.
+type FillSimulator struct {
+	market tt.Market
+
+	priceTick    float64
+	qtyTick      float64
+	minQty       float64
+	maxQty       float64
+	minNotional  float64
+	maxNotional  float64
+	makerFee     float64
+	takerFee     float64
+	priceDecimal int
+	qtyDecimal   int
+
+	// Slippage is the fraction market orders fill worse than the reference
+	// price, e.g. 0.0005 for 5 basis points
+	Slippage float64
+}
+
+// NewFillSimulator parses the constraints of market. Empty fields disable the
+// corresponding check.
+func NewFillSimulator(market tt.Market) (*FillSimulator, error) {
+	s := &FillSimulator{market: market}
+
+	fields := []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"priceTick", market.PriceTick, &s.priceTick},
+		{"quantityTick", market.QuantityTick, &s.qtyTick},
+		{"minQuantity", market.MinQuantity, &s.minQty},
+		{"maxQuantity", market.MaxQuantity, &s.maxQty},
+		{"minNotional", market.MinNotional, &s.minNotional},
+		{"maxNotional", market.MaxNotional, &s.maxNotional},
+		{"makerFee", market.MakerFee, &s.makerFee},
+		{"takerFee", market.TakerFee, &s.takerFee},
+	}
+	for _, f := range fields {
+		v, err := parseOptional(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("market %s: invalid %s: %w", market.Symbol, f.name, err)
+		}
+		*f.dst = v
+	}
+
+	s.priceDecimal = utils.TickDecimals(market.PriceTick)
+	s.qtyDecimal = utils.TickDecimals(market.QuantityTick)
+	return s, nil
+}
+
+// Market returns the simulated market
+func (s *FillSimulator) Market() tt.Market {
+	return s.market
+}
+
+// FeeRates returns the maker and taker fee rates
+func (s *FillSimulator) FeeRates() (maker, taker float64) {
+	return s.makerFee, s.takerFee
+}
+
+// Prepare rounds the order price to the price tick, truncates the quantity to
+// the quantity tick and checks the quantity and notional limits. refPrice is
+// used for the notional of market orders.
+func (s *FillSimulator) Prepare(order tt.Order, refPrice float64) (tt.Order, error) {
+	if order.Side != tt.SideBuy && order.Side != tt.SideSell {
+		return order, fmt.Errorf("invalid side %q", order.Side)
+	}
+
+	qty, err := strconv.ParseFloat(order.Quantity, 64)
+	if err != nil || qty <= 0 {
+		return order, fmt.Errorf("invalid quantity %q", order.Quantity)
+	}
+	if s.qtyTick > 0 {
+		qty = math.Floor(qty/s.qtyTick+1e-9) * s.qtyTick
+	}
+	if qty <= 0 || qty < s.minQty {
+		return order, fmt.Errorf("quantity %s below minimum %s", order.Quantity, s.market.MinQuantity)
+	}
+	if s.maxQty > 0 && qty > s.maxQty {
+		return order, fmt.Errorf("quantity %s above maximum %s", order.Quantity, s.market.MaxQuantity)
+	}
+	order.Quantity = s.formatQty(qty)
+
+	price := refPrice
+	switch order.Type {
+	case tt.OrderTypeMarket:
+		order.Price = ""
+	case tt.OrderTypeLimit:
+		price, err = strconv.ParseFloat(order.Price, 64)
+		if err != nil || price <= 0 {
+			return order, fmt.Errorf("invalid limit price %q", order.Price)
+		}
+		price = s.roundPrice(price)
+		order.Price = s.formatPrice(price)
+	default:
+		return order, fmt.Errorf("unsupported order type %q", order.Type)
+	}
+
+	notional := price * qty
+	if s.minNotional > 0 && notional < s.minNotional {
+		return order, fmt.Errorf("notional %g below minimum %s", notional, s.market.MinNotional)
+	}
+	if s.maxNotional > 0 && notional > s.maxNotional {
+		return order, fmt.Errorf("notional %g above maximum %s", notional, s.market.MaxNotional)
+	}
+
+	return order, nil
+}
+
+// MatchCandle tries to fill a prepared order during candle. Market orders fill
+// at the open; limit orders fill at their price (or a better open) when the
+// candle range reaches it.
+func (s *FillSimulator) MatchCandle(order tt.Order, candle tt.OHLCVRecord) (tt.Fill, bool, error) {
+	open, err := parsePrice("open", candle.Open)
+	if err != nil {
+		return tt.Fill{}, false, err
+	}
+	high, err := parsePrice("high", candle.High)
+	if err != nil {
+		return tt.Fill{}, false, err
+	}
+	low, err := parsePrice("low", candle.Low)
+	if err != nil {
+		return tt.Fill{}, false, err
+	}
+	return s.match(order, open, low, high, candle.OpenTime*1000)
+}
+
+// MatchTrade tries to fill a prepared order against a single trade
+func (s *FillSimulator) MatchTrade(order tt.Order, trade tt.TradeRecord) (tt.Fill, bool, error) {
+	price, err := parsePrice("price", trade.Price)
+	if err != nil {
+		return tt.Fill{}, false, err
+	}
+	return s.match(order, price, price, price, trade.Timestamp)
+}
+
+func (s *FillSimulator) match(order tt.Order, open, low, high float64, ts int64) (tt.Fill, bool, error) {
+	qty, err := strconv.ParseFloat(order.Quantity, 64)
+	if err != nil {
+		return tt.Fill{}, false, fmt.Errorf("invalid quantity %q", order.Quantity)
+	}
+
+	var price float64
+	maker := false
+	switch order.Type {
+	case tt.OrderTypeMarket:
+		price = open * (1 + s.Slippage)
+		if order.Side == tt.SideSell {
+			price = open * (1 - s.Slippage)
+		}
+		price = s.roundPrice(price)
+	case tt.OrderTypeLimit:
+		limit, err := strconv.ParseFloat(order.Price, 64)
+		if err != nil {
+			return tt.Fill{}, false, fmt.Errorf("invalid limit price %q", order.Price)
+		}
+		switch {
+		case order.Side == tt.SideBuy && open <= limit:
+			// Crossed on arrival: takes liquidity at the open
+			price = open
+		case order.Side == tt.SideSell && open >= limit:
+			price = open
+		case order.Side == tt.SideBuy && low <= limit:
+			price, maker = limit, true
+		case order.Side == tt.SideSell && high >= limit:
+			price, maker = limit, true
+		default:
+			return tt.Fill{}, false, nil
+		}
+	default:
+		return tt.Fill{}, false, fmt.Errorf("unsupported order type %q", order.Type)
+	}
+
+	rate := s.takerFee
+	if maker {
+		rate = s.makerFee
+	}
+
+	return tt.Fill{
+		OrderID:   order.ID,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Price:     s.formatPrice(price),
+		Quantity:  order.Quantity,
+		Fee:       strconv.FormatFloat(price*qty*rate, 'f', -1, 64),
+		Maker:     maker,
+		Timestamp: ts,
+	}, true, nil
+}
+
+func (s *FillSimulator) roundPrice(p float64) float64 {
+	if s.priceTick <= 0 {
+		return p
+	}
+	return math.Round(p/s.priceTick) * s.priceTick
+}
+
+func (s *FillSimulator) formatPrice(p float64) string {
+	if s.priceTick <= 0 {
+		return strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(p, 'f', s.priceDecimal, 64)
+}
+
+func (s *FillSimulator) formatQty(q float64) string {
+	if s.qtyTick <= 0 {
+		return strconv.FormatFloat(q, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(q, 'f', s.qtyDecimal, 64)
+}
+
+func parseOptional(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parsePrice(name, s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	return v, nil
+}
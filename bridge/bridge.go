@@ -0,0 +1,110 @@
+// Package bridge lets a plugin invoke commands of other installed plugins
+// through the plugin_call host function, so composite plugins (e.g. a
+// screener querying several data sources) can be built. The host only
+// forwards calls allowed by Meta.Resources.AllowedPlugins.
+//
+//	resp, err := bridge.Call("binance", plugin.Command{Name: "getMarkets"})
+//
+//	markets, err := bridge.Invoke[[]trading.Market]("binance", "getMarkets", nil)
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+// Error codes the host reports when it could not deliver a call
+const (
+	CodeNotFound         = "not_found"
+	CodePermissionDenied = "permission_denied"
+	CodeTimeout          = "timeout"
+)
+
+var (
+	// ErrPluginNotFound is returned when the target plugin is not installed
+	ErrPluginNotFound = errors.New("plugin not found")
+	// ErrPermissionDenied is returned when Meta does not allow the call
+	ErrPermissionDenied = errors.New("plugin call not permitted")
+	// ErrTimeout is returned when the target plugin did not answer in time
+	ErrTimeout = errors.New("plugin call timed out")
+)
+
+// CallRequest is the input of the plugin_call host function
+type CallRequest struct {
+	PluginID string         `json:"pluginId"`
+	Command  plugin.Command `json:"command"`
+}
+
+// CallResult is the answer of the plugin_call host function. Error and Code
+// are set when the call was not delivered; failures of the command itself are
+// reported in Response.
+type CallResult struct {
+	Response plugin.Response `json:"response"`
+	Code     string          `json:"code,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Call invokes cmd on the plugin pluginID and returns its response. The
+// current trace ID is passed on unless cmd has its own. A response with
+// Result false is not an error; use Invoke to treat it as one.
+func Call(pluginID string, cmd plugin.Command) (plugin.Response, error) {
+	if err := hostinfo.Require(hostinfo.PluginCall); err != nil {
+		return plugin.Response{}, err
+	}
+	if cmd.TraceID == "" {
+		cmd.TraceID = trace.Current()
+	}
+
+	var result CallResult
+	ok, err := host.CallJSON(host.CallPluginCall, CallRequest{PluginID: pluginID, Command: cmd}, &result)
+	if err != nil {
+		return plugin.Response{}, fmt.Errorf("plugin call %s/%s: %w", pluginID, cmd.Name, err)
+	}
+	if !ok {
+		return plugin.Response{}, fmt.Errorf("plugin call %s/%s: no answer from host", pluginID, cmd.Name)
+	}
+
+	if result.Code != "" || result.Error != "" {
+		var sentinel error
+		switch result.Code {
+		case CodeNotFound:
+			sentinel = ErrPluginNotFound
+		case CodePermissionDenied:
+			sentinel = ErrPermissionDenied
+		case CodeTimeout:
+			sentinel = ErrTimeout
+		default:
+			return plugin.Response{}, fmt.Errorf("plugin call %s/%s: %s", pluginID, cmd.Name, result.Error)
+		}
+		return plugin.Response{}, fmt.Errorf("plugin call %s/%s: %w", pluginID, cmd.Name, sentinel)
+	}
+	return result.Response, nil
+}
+
+// Invoke calls the command name with params on the plugin pluginID and
+// decodes the response data into T. Failed responses are returned as error.
+func Invoke[T any](pluginID, name string, params map[string]any) (T, error) {
+	var out T
+	resp, err := Call(pluginID, plugin.Command{Name: name, Params: params})
+	if err != nil {
+		return out, err
+	}
+	if !resp.Result {
+		return out, fmt.Errorf("%s/%s: %s", pluginID, name, resp.Error)
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("%s/%s: decoding response: %w", pluginID, name, err)
+	}
+	return out, nil
+}
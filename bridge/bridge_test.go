@@ -0,0 +1,41 @@
+//go:build !wasm
+
+package bridge_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/bridge"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+)
+
+func TestInvoke(t *testing.T) {
+	h := plugintest.New(t)
+	h.Plugins["prices"] = func(cmd plugin.Command) plugin.Response {
+		if cmd.Name != "getPrice" {
+			return plugin.ErrorResponseMsg("unknown command " + cmd.Name)
+		}
+		return plugin.SuccessResponse(map[string]any{"symbol": cmd.Params["symbol"], "price": "42"})
+	}
+
+	type price struct {
+		Symbol string `json:"symbol"`
+		Price  string `json:"price"`
+	}
+	got, err := bridge.Invoke[price]("prices", "getPrice", map[string]any{"symbol": "BTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Symbol != "BTC" || got.Price != "42" {
+		t.Fatalf("unexpected result %+v", got)
+	}
+
+	if _, err := bridge.Invoke[price]("prices", "other", nil); err == nil {
+		t.Fatal("expected failed response to be an error")
+	}
+	if _, err := bridge.Call("missing", plugin.Command{Name: "x"}); !errors.Is(err, bridge.ErrPluginNotFound) {
+		t.Fatalf("expected ErrPluginNotFound, got %v", err)
+	}
+}
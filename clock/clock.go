@@ -0,0 +1,58 @@
+// Package clock abstracts the current time so time-dependent code can be
+// tested around candle boundaries. Functions taking a now func() time.Time
+// accept a Clock's Now method value as well.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock reports the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to Clock
+type Func func() time.Time
+
+// Now implements Clock
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System is the clock of the machine running the code. Inside WASM plugins
+// use wasmutils.HostClock instead.
+var System Clock = Func(time.Now)
+
+// Fake is a manually controlled clock for tests
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates a fake clock standing at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now implements Clock
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
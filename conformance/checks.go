@@ -0,0 +1,145 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// ValidateMeta checks the required meta fields, the semver version and the
+// network patterns, see meta.Validate
+func ValidateMeta(meta m.Meta) error {
+	if err := m.Validate(meta); err != nil {
+		return fmt.Errorf("invalid meta: %w", err)
+	}
+	return nil
+}
+
+// ValidateConfigFields checks that fields have unique names, labels and types,
+// and that password fields are masked and encrypted
+func ValidateConfigFields(fields []plugin.ConfigField) error {
+	var errs []error
+	seen := make(map[string]bool, len(fields))
+	for i, field := range fields {
+		switch {
+		case field.Name == "":
+			errs = append(errs, fmt.Errorf("field %d has no name", i))
+			continue
+		case seen[field.Name]:
+			errs = append(errs, fmt.Errorf("field %s is declared twice", field.Name))
+		}
+		seen[field.Name] = true
+
+		if field.Label == "" {
+			errs = append(errs, fmt.Errorf("field %s has no label", field.Name))
+		}
+		if field.Type == "" {
+			errs = append(errs, fmt.Errorf("field %s has no type", field.Name))
+		}
+		if field.Type == "password" && (!field.Mask || !field.Encrypt) {
+			errs = append(errs, fmt.Errorf("password field %s must be masked and encrypted", field.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateMarkets checks that markets is non-empty with unique, complete symbols
+func ValidateMarkets(markets []tt.Market) error {
+	if len(markets) == 0 {
+		return fmt.Errorf("no markets returned")
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(markets))
+	for i, market := range markets {
+		if market.Symbol == "" || market.Base == "" || market.Quote == "" {
+			errs = append(errs, fmt.Errorf("market %d is missing symbol, base or quote", i))
+			continue
+		}
+		if seen[market.Symbol] {
+			errs = append(errs, fmt.Errorf("market %s is returned twice", market.Symbol))
+		}
+		seen[market.Symbol] = true
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateTimeframes checks that timeframes is non-empty and every entry parses
+func ValidateTimeframes(timeframes []string) error {
+	if len(timeframes) == 0 {
+		return fmt.Errorf("no timeframes returned")
+	}
+
+	var errs []error
+	for _, tf := range timeframes {
+		if _, err := tt.TimeframeFromString(tf); err != nil {
+			errs = append(errs, fmt.Errorf("invalid timeframe %q: %w", tf, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateOHLCV checks that candles are sorted by open time without
+// duplicates and that every candle has consistent OHLC values
+func ValidateOHLCV(candles []tt.OHLCVRecord) error {
+	var errs []error
+	for i, c := range candles {
+		if i > 0 && c.OpenTime <= candles[i-1].OpenTime {
+			errs = append(errs, fmt.Errorf("candle %d at %d is not after %d", i, c.OpenTime, candles[i-1].OpenTime))
+		}
+
+		values, err := parseCandle(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("candle %d at %d: %w", i, c.OpenTime, err))
+			continue
+		}
+		open, high, low, close, volume := values[0], values[1], values[2], values[3], values[4]
+		if low > high || high < max(open, close) || low > min(open, close) {
+			errs = append(errs, fmt.Errorf("candle %d at %d has inconsistent OHLC %s/%s/%s/%s", i, c.OpenTime, c.Open, c.High, c.Low, c.Close))
+		}
+		if volume < 0 {
+			errs = append(errs, fmt.Errorf("candle %d at %d has negative volume", i, c.OpenTime))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateStreamResponse checks that a stream command returned a valid StreamMarker
+func ValidateStreamResponse(res plugin.Response) error {
+	if res.ResponseType != "StreamMarker" {
+		return fmt.Errorf("expected responseType StreamMarker, got %q", res.ResponseType)
+	}
+
+	var marker stream.StreamMarker
+	if err := decodeData(res.Data, &marker); err != nil {
+		return fmt.Errorf("invalid stream marker: %w", err)
+	}
+	return marker.Validate()
+}
+
+func parseCandle(c tt.OHLCVRecord) ([5]float64, error) {
+	var values [5]float64
+	for i, s := range []string{c.Open, c.High, c.Low, c.Close, c.Volume} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return values, fmt.Errorf("invalid number %q", s)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// decodeData converts generic response data into out via JSON
+func decodeData(data any, out any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
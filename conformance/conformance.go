@@ -0,0 +1,307 @@
+// Package conformance runs the standard marketplace checks against a plugin:
+// meta shape, credential fields, markets, timeframes, OHLCV sanity and stream
+// setup. Checks for commands the plugin doesn't list in its features are
+// skipped.
+//
+// The suite talks to the plugin through an Invoker. A compiled .wasm is
+// loaded with the extism Go SDK, whose *extism.Plugin satisfies Invoker
+// directly; the test provides the host functions:
+//
+//	manifest := extism.Manifest{Wasm: []extism.Wasm{extism.WasmFile{Path: "plugin.wasm"}}}
+//	p, err := extism.NewPlugin(ctx, manifest, extism.PluginConfig{EnableWasi: true}, hostFunctions)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	conformance.Test(t, p, conformance.Options{Config: map[string]any{"apiKey": "..."}})
+//
+// The same suite runs in-process against plugintest:
+//
+//	h := plugintest.New(t)
+//	conformance.Test(t, conformance.InvokerFunc(h.Invoke), conformance.Options{})
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datasrc/exchange"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Invoker calls a plugin export with raw input and returns its exit code and
+// output. The output must be returned even for non-zero exit codes.
+type Invoker interface {
+	Call(export string, input []byte) (uint32, []byte, error)
+}
+
+// InvokerFunc adapts a function to Invoker
+type InvokerFunc func(export string, input []byte) (uint32, []byte, error)
+
+// Call implements Invoker
+func (f InvokerFunc) Call(export string, input []byte) (uint32, []byte, error) {
+	return f(export, input)
+}
+
+// Options configures a suite run
+type Options struct {
+	Config     map[string]any // Passed to the init export
+	Market     *tt.Market     // Market for OHLCV and stream checks; defaults to the first market returned
+	Timeframe  string         // Timeframe for OHLCV and stream checks; defaults to the first timeframe returned
+	OHLCVLimit int            // Candles requested in the OHLCV check; defaults to 100
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name    string
+	Skipped bool
+	Err     error
+}
+
+// Report holds the results of a suite run in execution order
+type Report struct {
+	Results []Result
+}
+
+// Err joins the errors of all failed checks
+func (r Report) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Name, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Test runs the suite and reports every failed check on t
+func Test(t testing.TB, inv Invoker, opts Options) {
+	t.Helper()
+	for _, res := range Run(inv, opts).Results {
+		switch {
+		case res.Err != nil:
+			t.Errorf("%s: %v", res.Name, res.Err)
+		case res.Skipped:
+			t.Logf("%s: skipped", res.Name)
+		}
+	}
+}
+
+// Run executes the suite
+func Run(inv Invoker, opts Options) Report {
+	if opts.OHLCVLimit <= 0 {
+		opts.OHLCVLimit = 100
+	}
+
+	s := &suite{inv: inv, opts: opts}
+	s.check("meta", s.checkMeta)
+	s.check("config fields", s.checkConfigFields)
+	s.check("init", s.checkInit)
+	if s.failed() {
+		return s.report
+	}
+
+	s.check("markets", s.checkMarkets)
+	s.check("timeframes", s.checkTimeframes)
+	s.check("ohlcv", s.checkOHLCV)
+	s.check("stream setup", s.checkStreamSetup)
+	return s.report
+}
+
+// errSkipped marks a check as not applicable
+var errSkipped = errors.New("skipped")
+
+type suite struct {
+	inv    Invoker
+	opts   Options
+	report Report
+
+	features  map[string]bool
+	markets   []tt.Market
+	timeframe string
+}
+
+func (s *suite) check(name string, fn func() error) {
+	err := fn()
+	if errors.Is(err, errSkipped) {
+		s.report.Results = append(s.report.Results, Result{Name: name, Skipped: true})
+		return
+	}
+	s.report.Results = append(s.report.Results, Result{Name: name, Err: err})
+}
+
+func (s *suite) failed() bool {
+	return s.report.Err() != nil
+}
+
+func (s *suite) checkMeta() error {
+	var meta m.Meta
+	if err := s.callJSON("meta", nil, &meta); err != nil {
+		return err
+	}
+	if err := ValidateMeta(meta); err != nil {
+		return err
+	}
+
+	s.features = make(map[string]bool, len(meta.Features))
+	for _, feature := range meta.Features {
+		s.features[feature] = true
+	}
+	return nil
+}
+
+func (s *suite) checkConfigFields() error {
+	var fields []plugin.ConfigField
+	if err := s.callJSON("get_configuration_fields", nil, &fields); err != nil {
+		return err
+	}
+	return ValidateConfigFields(fields)
+}
+
+func (s *suite) checkInit() error {
+	config := s.opts.Config
+	if config == nil {
+		config = map[string]any{}
+	}
+	input, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	rc, _, err := s.inv.Call("init", input)
+	if err != nil {
+		return err
+	}
+	if rc != 0 {
+		return fmt.Errorf("init returned %d", rc)
+	}
+	return nil
+}
+
+func (s *suite) checkMarkets() error {
+	if !s.features[exchange.CMD_GET_MARKETS] {
+		return errSkipped
+	}
+
+	var markets []tt.Market
+	if err := s.command(exchange.CMD_GET_MARKETS, nil, &markets); err != nil {
+		return err
+	}
+	if err := ValidateMarkets(markets); err != nil {
+		return err
+	}
+	s.markets = markets
+	return nil
+}
+
+func (s *suite) checkTimeframes() error {
+	if !s.features[exchange.CMD_GET_TIMEFRAMES] {
+		return errSkipped
+	}
+
+	var timeframes []string
+	if err := s.command(exchange.CMD_GET_TIMEFRAMES, nil, &timeframes); err != nil {
+		return err
+	}
+	if err := ValidateTimeframes(timeframes); err != nil {
+		return err
+	}
+	s.timeframe = timeframes[0]
+	return nil
+}
+
+func (s *suite) checkOHLCV() error {
+	market, timeframe, ok := s.target()
+	if !s.features[exchange.CMD_GET_OHLCV] || !ok {
+		return errSkipped
+	}
+
+	params := map[string]any{"market": market, "timeframe": timeframe, "limit": s.opts.OHLCVLimit}
+	var candles []tt.OHLCVRecord
+	if err := s.command(exchange.CMD_GET_OHLCV, params, &candles); err != nil {
+		return err
+	}
+	if len(candles) == 0 {
+		return fmt.Errorf("no candles returned for %s %s", market.Symbol, timeframe)
+	}
+	return ValidateOHLCV(candles)
+}
+
+func (s *suite) checkStreamSetup() error {
+	market, timeframe, ok := s.target()
+	if !s.features[exchange.CMD_OHLCV_STREAM] || !ok {
+		return errSkipped
+	}
+
+	params := map[string]any{"market": market, "timeframe": timeframe}
+	res, err := s.commandResponse(exchange.CMD_OHLCV_STREAM, params)
+	if err != nil {
+		return err
+	}
+	return ValidateStreamResponse(res)
+}
+
+// target returns the market and timeframe used by OHLCV and stream checks
+func (s *suite) target() (tt.Market, string, bool) {
+	var market tt.Market
+	switch {
+	case s.opts.Market != nil:
+		market = *s.opts.Market
+	case len(s.markets) > 0:
+		market = s.markets[0]
+	default:
+		return market, "", false
+	}
+
+	timeframe := s.opts.Timeframe
+	if timeframe == "" {
+		timeframe = s.timeframe
+	}
+	return market, timeframe, timeframe != ""
+}
+
+// command runs a command and decodes its data into out
+func (s *suite) command(name string, params map[string]any, out any) error {
+	res, err := s.commandResponse(name, params)
+	if err != nil {
+		return err
+	}
+	if err := decodeData(res.Data, out); err != nil {
+		return fmt.Errorf("unexpected %s data: %w", name, err)
+	}
+	return nil
+}
+
+// commandResponse runs a command through handle_command
+func (s *suite) commandResponse(name string, params map[string]any) (plugin.Response, error) {
+	var res plugin.Response
+	if err := s.callJSON("handle_command", plugin.Command{Name: name, Params: params}, &res); err != nil {
+		return res, err
+	}
+	if !res.Result {
+		return res, fmt.Errorf("%s failed: %s", name, res.Error)
+	}
+	return res, nil
+}
+
+// callJSON calls an export with JSON input and decodes its JSON output
+func (s *suite) callJSON(export string, in any, out any) error {
+	input := []byte("null")
+	if in != nil {
+		var err error
+		if input, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	_, output, err := s.inv.Call(export, input)
+	if err != nil {
+		return fmt.Errorf("%s: %w", export, err)
+	}
+	if err := json.Unmarshal(output, out); err != nil {
+		return fmt.Errorf("%s returned invalid JSON: %w", export, err)
+	}
+	return nil
+}
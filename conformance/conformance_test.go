@@ -0,0 +1,86 @@
+//go:build !wasm
+
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datasrc/exchange"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+type exchangePlugin struct{}
+
+func (exchangePlugin) GetMeta() m.Meta {
+	return m.Meta{PluginID: "test-exchange", Name: "Test", AppID: "datasrc", Version: "1.0.0"}
+}
+
+func (exchangePlugin) GetConfigFields() []plugin.ConfigField {
+	return []plugin.ConfigField{{Name: "apiSecret", Label: "API Secret", Type: "password", Mask: true, Encrypt: true}}
+}
+
+func (exchangePlugin) OnInit(*plugin.ConfigStore) error  { return nil }
+func (exchangePlugin) OnShutdown() error                 { return nil }
+func (exchangePlugin) GetRateLimits() []plugin.RateLimit { return nil }
+
+func (exchangePlugin) RegisterCommands(router *plugin.CommandRouter) {
+	router.Register(exchange.CMD_GET_MARKETS, func(map[string]any) plugin.Response {
+		return plugin.SuccessResponse([]tt.Market{{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT"}})
+	})
+	router.Register(exchange.CMD_GET_TIMEFRAMES, func(map[string]any) plugin.Response {
+		return plugin.SuccessResponse([]string{"1m", "1h"})
+	})
+	router.Register(exchange.CMD_GET_OHLCV, func(params map[string]any) plugin.Response {
+		p := exchange.GetOHLCVParamsFromMap(params)
+		if p.Market.Symbol != "BTCUSDT" || p.Timeframe != "1m" {
+			return plugin.ErrorResponseMsg("unexpected params")
+		}
+		return plugin.SuccessResponse([]tt.OHLCVRecord{
+			{OpenTime: 60, Open: "1", High: "2", Low: "0.5", Close: "1.5", Volume: "10"},
+			{OpenTime: 120, Open: "1.5", High: "1.5", Low: "1", Close: "1", Volume: "0"},
+		})
+	})
+	router.Register(exchange.CMD_OHLCV_STREAM, func(map[string]any) plugin.Response {
+		return plugin.SuccessTypedResponse("StreamMarker", stream.StreamMarker{
+			Stream:       true,
+			StreamID:     "btcusdt@kline_1m",
+			WebSocketURL: "wss://stream.example.com/ws",
+		})
+	})
+}
+
+func TestRunPassesConformingPlugin(t *testing.T) {
+	plugin.RegisterPlugin(exchangePlugin{})
+	h := plugintest.New(t)
+
+	report := Run(InvokerFunc(h.Invoke), Options{})
+	if err := report.Err(); err != nil {
+		t.Fatalf("expected conforming plugin to pass: %v", err)
+	}
+	for _, res := range report.Results {
+		if res.Skipped {
+			t.Errorf("check %s was skipped", res.Name)
+		}
+	}
+}
+
+func TestValidateOHLCVReportsBrokenCandles(t *testing.T) {
+	err := ValidateOHLCV([]tt.OHLCVRecord{
+		{OpenTime: 120, Open: "1", High: "2", Low: "0.5", Close: "1.5", Volume: "1"},
+		{OpenTime: 60, Open: "1", High: "2", Low: "0.5", Close: "1.5", Volume: "1"},
+		{OpenTime: 180, Open: "1", High: "0.9", Low: "0.5", Close: "1.5", Volume: "1"},
+	})
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{"candle 1 at 60 is not after 120", "candle 2 at 180 has inconsistent OHLC"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package crypto bundles the hashing, MAC and encoding primitives that
+// authenticated plugins need. Everything here only uses standard library
+// packages that are supported by TinyGo's wasip1 target.
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// HMACSHA256 returns the HMAC-SHA256 of message using key
+func HMACSHA256(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// HMACSHA512 returns the HMAC-SHA512 of message using key
+func HMACSHA512(key, message []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// HMACSHA256Hex returns the hex encoded HMAC-SHA256, the format most exchanges expect
+func HMACSHA256Hex(key, message string) string {
+	return hex.EncodeToString(HMACSHA256([]byte(key), []byte(message)))
+}
+
+// HMACSHA256Base64 returns the standard base64 encoded HMAC-SHA256
+func HMACSHA256Base64(key, message string) string {
+	return base64.StdEncoding.EncodeToString(HMACSHA256([]byte(key), []byte(message)))
+}
+
+// HMACSHA512Hex returns the hex encoded HMAC-SHA512
+func HMACSHA512Hex(key, message string) string {
+	return hex.EncodeToString(HMACSHA512([]byte(key), []byte(message)))
+}
+
+// HMACSHA512Base64 returns the standard base64 encoded HMAC-SHA512
+func HMACSHA512Base64(key, message string) string {
+	return base64.StdEncoding.EncodeToString(HMACSHA512([]byte(key), []byte(message)))
+}
+
+// SHA256 returns the SHA-256 digest of data
+func SHA256(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// SHA256Hex returns the hex encoded SHA-256 digest of data
+func SHA256Hex(data []byte) string {
+	return hex.EncodeToString(SHA256(data))
+}
+
+// SHA512 returns the SHA-512 digest of data
+func SHA512(data []byte) []byte {
+	sum := sha512.Sum512(data)
+	return sum[:]
+}
+
+// SHA512Hex returns the hex encoded SHA-512 digest of data
+func SHA512Hex(data []byte) string {
+	return hex.EncodeToString(SHA512(data))
+}
+
+// HexEncode encodes data as lowercase hex
+func HexEncode(data []byte) string {
+	return hex.EncodeToString(data)
+}
+
+// HexDecode decodes a hex string
+func HexDecode(s string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	return data, nil
+}
+
+// Base64Encode encodes data with standard, padded base64
+func Base64Encode(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Base64Decode decodes standard base64, with or without padding
+func Base64Decode(s string) ([]byte, error) {
+	if data, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return data, nil
+	}
+	data, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return data, nil
+}
+
+// Base64URLEncode encodes data with unpadded URL-safe base64 (as used by JWT)
+func Base64URLEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Base64URLDecode decodes URL-safe base64, with or without padding
+func Base64URLDecode(s string) ([]byte, error) {
+	if data, err := base64.RawURLEncoding.DecodeString(s); err == nil {
+		return data, nil
+	}
+	data, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url: %w", err)
+	}
+	return data, nil
+}
+
+// Equal compares two byte slices in constant time. Use it when checking
+// signatures (e.g. webhook payloads) to avoid timing side channels.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString compares two strings in constant time
+func EqualString(a, b string) bool {
+	return Equal([]byte(a), []byte(b))
+}
+
+// RandomBytes returns n cryptographically secure random bytes
+func RandomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return b, nil
+}
+
+// RandomHex returns n random bytes hex encoded, useful for nonces and OAuth state
+func RandomHex(n int) (string, error) {
+	b, err := RandomBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
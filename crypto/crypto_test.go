@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Known-answer vectors from RFC 4231 (test case 1), FIPS 180-2 ("abc") and
+// the common "quick brown fox" HMAC example
+const fox = "The quick brown fox jumps over the lazy dog"
+
+func TestHMACVectors(t *testing.T) {
+	key := bytes.Repeat([]byte{0x0b}, 20)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"RFC 4231 HMAC-SHA256", HexEncode(HMACSHA256(key, []byte("Hi There"))), "b0344c61d8db38535ca8afceaf0bf12b881dc200c9833da726e9376c2e32cff7"},
+		{"RFC 4231 HMAC-SHA512", HexEncode(HMACSHA512(key, []byte("Hi There"))), "87aa7cdea5ef619d4ff0b4241a1d6cb02379f4e2ce4ec2787ad0b30545e17cdedaa833b7d6b8a702038b274eaea3f4e4be9d914eeb61f1702e696c203a126854"},
+		{"HMACSHA256Hex", HMACSHA256Hex("key", fox), "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+		{"HMACSHA256Base64", HMACSHA256Base64("key", fox), "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg="},
+		{"HMACSHA512Hex", HMACSHA512Hex("key", fox), "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a"},
+		{"HMACSHA512Base64", HMACSHA512Base64("key", fox), "tCrwkFe6weLUFwjkipAuCbX/fxKrQopP6GZTxz3SSPuC+UilSfe3kaW0GRXuTR7Dk1NX5OIxclDQNyr6Lr7rOg=="},
+		{"SHA256Hex", SHA256Hex([]byte("abc")), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"SHA512Hex", SHA512Hex([]byte("abc")), "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestEncodingVectors(t *testing.T) {
+	// RFC 4648 section 10
+	if got := Base64Encode([]byte("foobar")); got != "Zm9vYmFy" {
+		t.Errorf("expected Zm9vYmFy, got %s", got)
+	}
+	if got := Base64Encode([]byte("fo")); got != "Zm8=" {
+		t.Errorf("expected Zm8=, got %s", got)
+	}
+	for _, s := range []string{"Zm8=", "Zm8"} {
+		if got, err := Base64Decode(s); err != nil || string(got) != "fo" {
+			t.Errorf("Base64Decode(%q): expected fo, got %q (%v)", s, got, err)
+		}
+	}
+
+	urlSafe := []byte{0xfb, 0xff, 0xfe}
+	if got := Base64URLEncode(urlSafe); got != "-__-" {
+		t.Errorf("expected -__-, got %s", got)
+	}
+	if got, err := Base64URLDecode("-__-"); err != nil || !bytes.Equal(got, urlSafe) {
+		t.Errorf("expected %x, got %x (%v)", urlSafe, got, err)
+	}
+
+	if got, err := HexDecode("DEADbeef"); err != nil || HexEncode(got) != "deadbeef" {
+		t.Errorf("expected deadbeef, got %x (%v)", got, err)
+	}
+	if _, err := HexDecode("xyz"); err == nil {
+		t.Error("expected error for invalid hex")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !EqualString("sig", "sig") || EqualString("sig", "sih") || Equal([]byte("a"), []byte("ab")) {
+		t.Error("unexpected constant time comparison result")
+	}
+}
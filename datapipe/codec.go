@@ -0,0 +1,87 @@
+package datapipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	mapstructure "github.com/go-viper/mapstructure/v2"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// validate is the validator used by DecodePort. It caches struct metadata,
+// so it is shared instead of created per decode.
+var validate = sync.OnceValue(func() *validator.Validate {
+	return validator.New()
+})
+
+// DecodePort decodes the payload of an input port into T using its json tags.
+// Struct payloads are validated with their `validate` tags.
+//
+// Example:
+//
+//	signal, err := datapipe.DecodePort[dt.Signal](req, "signal")
+func DecodePort[T any](req types.ProcessRequest, port string) (T, error) {
+	var out T
+	raw, ok := req.Input[port]
+	if !ok || raw == nil {
+		return out, fmt.Errorf("missing input port %q", port)
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+		),
+		Result:           &out,
+		TagName:          "json",
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return out, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return out, fmt.Errorf("failed to decode port %q: %w", port, err)
+	}
+
+	if isStruct(out) {
+		if err := validate().Struct(out); err != nil {
+			return out, fmt.Errorf("invalid payload on port %q: %w", port, err)
+		}
+	}
+
+	return out, nil
+}
+
+// EncodePort stores v under port in out, converted to plain JSON values the
+// host can pass to connected nodes
+//
+// Example:
+//
+//	out := map[string]any{}
+//	err := datapipe.EncodePort(out, "signal", dt.Signal{Type: "buy", Strength: 0.8})
+func EncodePort[T any](out map[string]any, port string, v T) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode port %q: %w", port, err)
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to encode port %q: %w", port, err)
+	}
+
+	out[port] = value
+	return nil
+}
+
+func isStruct(v any) bool {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct
+}
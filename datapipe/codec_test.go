@@ -0,0 +1,26 @@
+package datapipe
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+func TestPortCodec(t *testing.T) {
+	out := map[string]any{}
+	if err := EncodePort(out, "signal", types.Signal{Type: "buy", Strength: 0.5, Timestamp: 1700000000000}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodePort[types.Signal](types.ProcessRequest{Input: out}, "signal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "buy" || got.Strength != 0.5 || got.Timestamp != 1700000000000 {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+
+	if _, err := DecodePort[types.Signal](types.ProcessRequest{Input: out}, "missing"); err == nil {
+		t.Fatal("expected error for missing port")
+	}
+}
@@ -0,0 +1,20 @@
+package datapipe
+
+import (
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// RunClock returns the clock a node should use for req. Backtests and replays
+// get a clock fixed at RunContext.AsOf so they behave deterministically; live
+// runs get the host clock.
+func RunClock(req types.ProcessRequest) clock.Clock {
+	if req.Context.AsOf == 0 {
+		return wasmutils.HostClock
+	}
+	asOf := time.UnixMilli(req.Context.AsOf).UTC()
+	return clock.Func(func() time.Time { return asOf })
+}
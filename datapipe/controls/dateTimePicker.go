@@ -0,0 +1,136 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+type DateTimePickerOption func(c *DateTimePicker)
+
+type dateTimePickerOptions struct {
+	Min      string `json:"min,omitempty"`      // RFC3339
+	Max      string `json:"max,omitempty"`      // RFC3339
+	Timezone string `json:"timezone,omitempty"` // IANA name the picker displays times in, e.g. "America/New_York"
+	DateOnly bool   `json:"dateOnly,omitempty"`
+}
+
+func (o *dateTimePickerOptions) ToMap() (map[string]any, error) {
+	jsonData, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+
+	var resultMap map[string]any
+	err = json.Unmarshal(jsonData, &resultMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json to map: %w", err)
+	}
+
+	return resultMap, nil
+}
+
+func WithMinTime(t time.Time) DateTimePickerOption {
+	return func(c *DateTimePicker) {
+		c.dateTimePickerOptions.Min = t.Format(time.RFC3339)
+	}
+}
+
+func WithMaxTime(t time.Time) DateTimePickerOption {
+	return func(c *DateTimePicker) {
+		c.dateTimePickerOptions.Max = t.Format(time.RFC3339)
+	}
+}
+
+// WithTimezone sets the IANA timezone the picker displays times in.
+// It panics if the timezone is unknown.
+func WithTimezone(timezone string) DateTimePickerOption {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		panic(fmt.Sprintf("invalid timezone %q: %v", timezone, err))
+	}
+	return func(c *DateTimePicker) {
+		c.dateTimePickerOptions.Timezone = timezone
+	}
+}
+
+// WithDateOnly hides the time of day
+func WithDateOnly() DateTimePickerOption {
+	return func(c *DateTimePicker) {
+		c.dateTimePickerOptions.DateOnly = true
+	}
+}
+
+type DateTimePicker struct {
+	Control
+	dateTimePickerOptions
+}
+
+// NewDateTimePicker creates a picker for a single point in time, stored as
+// RFC3339 string in the node config
+func NewDateTimePicker(label, name string, options ...DateTimePickerOption) *types.GuiControl {
+	return newDateTimeControl(label, name, types.DATETIME, options)
+}
+
+// NewDateRangePicker creates a picker for a time window, stored as
+// {"start": RFC3339, "end": RFC3339} in the node config; read it back with
+// TimeRangeFromConfig
+//
+// Example:
+//
+//	controls.NewDateRangePicker("Backtest window", "window",
+//	    controls.WithMinTime(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)),
+//	    controls.WithTimezone("UTC"))
+func NewDateRangePicker(label, name string, options ...DateTimePickerOption) *types.GuiControl {
+	return newDateTimeControl(label, name, types.DATE_RANGE, options)
+}
+
+func newDateTimeControl(label, name string, controlType types.GuiControlType, options []DateTimePickerOption) *types.GuiControl {
+	c := &DateTimePicker{
+		Control: Control{
+			Label: label,
+			Name:  name,
+			Type:  controlType,
+		},
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	optionsMap, err := c.dateTimePickerOptions.ToMap()
+	if err != nil {
+		panic(fmt.Sprintf("failed to convert options to map: %v", err))
+	}
+
+	return NewControl(label, name, controlType, optionsMap)
+}
+
+// TimeFromConfig reads the time stored by a date/time picker
+func TimeFromConfig(config map[string]any, name string) (time.Time, error) {
+	t := utils.ExtractTime(name, config)
+	if t == nil {
+		return time.Time{}, fmt.Errorf("%s is not a valid time", name)
+	}
+	return *t, nil
+}
+
+// TimeRangeFromConfig reads the window stored by a date range picker
+func TimeRangeFromConfig(config map[string]any, name string) (start, end time.Time, err error) {
+	data := utils.ExtractMap(name, config)
+	if data == nil {
+		return start, end, fmt.Errorf("%s is not set", name)
+	}
+	if start, err = TimeFromConfig(data, "start"); err != nil {
+		return start, end, fmt.Errorf("%s: %w", name, err)
+	}
+	if end, err = TimeFromConfig(data, "end"); err != nil {
+		return start, end, fmt.Errorf("%s: %w", name, err)
+	}
+	if !end.After(start) {
+		return start, end, fmt.Errorf("%s: end must be after start", name)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,107 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+type MarketPickerOption func(c *MarketPicker)
+
+type marketPickerOptions struct {
+	Source     string   `json:"source,omitempty"`     // Data source plugin ID to list markets from; empty lets the user choose
+	Category   string   `json:"category,omitempty"`   // Data source category, e.g. "exchange"
+	AssetTypes []string `json:"assetTypes,omitempty"` // e.g. "spot", "perpetual"
+	Multiple   bool     `json:"multiple,omitempty"`
+}
+
+func (o *marketPickerOptions) ToMap() (map[string]any, error) {
+	jsonData, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+
+	var resultMap map[string]any
+	err = json.Unmarshal(jsonData, &resultMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json to map: %w", err)
+	}
+
+	return resultMap, nil
+}
+
+// WithMarketSource limits the picker to markets of one data source plugin
+func WithMarketSource(pluginID string) MarketPickerOption {
+	return func(c *MarketPicker) {
+		c.marketPickerOptions.Source = pluginID
+	}
+}
+
+// WithMarketCategory limits the picker to data sources of a category
+func WithMarketCategory(category string) MarketPickerOption {
+	return func(c *MarketPicker) {
+		c.marketPickerOptions.Category = category
+	}
+}
+
+// WithAssetTypes limits the picker to markets of the given asset types
+func WithAssetTypes(assetTypes ...string) MarketPickerOption {
+	return func(c *MarketPicker) {
+		c.marketPickerOptions.AssetTypes = assetTypes
+	}
+}
+
+// WithMultipleMarkets allows picking several markets; the config then holds a list
+func WithMultipleMarkets() MarketPickerOption {
+	return func(c *MarketPicker) {
+		c.marketPickerOptions.Multiple = true
+	}
+}
+
+type MarketPicker struct {
+	Control
+	marketPickerOptions
+}
+
+// NewMarketPicker creates a market picker. The host resolves it to its market
+// search and stores the chosen trading.Market as JSON in the node config; read
+// it back with MarketFromConfig.
+func NewMarketPicker(label, name string, options ...MarketPickerOption) *types.GuiControl {
+	c := &MarketPicker{
+		Control: Control{
+			Label: label,
+			Name:  name,
+			Type:  types.MARKET,
+		},
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	optionsMap, err := c.marketPickerOptions.ToMap()
+	if err != nil {
+		panic(fmt.Sprintf("failed to convert options to map: %v", err))
+	}
+
+	return NewControl(label, name, types.MARKET, optionsMap)
+}
+
+// MarketFromConfig reads the market stored by a market picker
+func MarketFromConfig(config map[string]any, name string) (tt.Market, error) {
+	var market tt.Market
+	data, ok := config[name].(map[string]any)
+	if !ok {
+		return market, fmt.Errorf("%s is not set", name)
+	}
+	if err := utils.MapToStruct(data, &market); err != nil {
+		return market, fmt.Errorf("invalid market in %s: %w", name, err)
+	}
+	if market.Symbol == "" {
+		return market, fmt.Errorf("market in %s has no symbol", name)
+	}
+	return market, nil
+}
@@ -0,0 +1,48 @@
+package controls
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// NewSection groups controls under a title
+func NewSection(title string, controls ...*types.GuiControl) *types.GuiSection {
+	return &types.GuiSection{Title: title, Kind: types.SECTION, Controls: controls}
+}
+
+// NewTab groups controls into a tab; sibling tabs form one tab bar
+//
+// Example:
+//
+//	def := types.GuiDefinition{Sections: []*types.GuiSection{
+//	    controls.NewTab("Trend", controls.NewNumberInput("Period", "period")),
+//	    controls.NewTab("Filters", controls.NewToggle("Use volume", "use_volume", false)),
+//	}}
+func NewTab(title string, controls ...*types.GuiControl) *types.GuiSection {
+	return &types.GuiSection{Title: title, Kind: types.TAB, Controls: controls}
+}
+
+// VisibleWhen shows control only while condition holds and returns it
+//
+// Example:
+//
+//	controls.VisibleWhen(
+//	    controls.NewNumberInput("Signal period", "signal_period"),
+//	    types.FieldEquals("mode", "macd"),
+//	)
+func VisibleWhen(control *types.GuiControl, condition *types.Condition) *types.GuiControl {
+	control.VisibleWhen = condition
+	return control
+}
+
+// ValidateWith attaches validation rules to control and returns it
+//
+// Example:
+//
+//	controls.ValidateWith(
+//	    controls.NewNumberInput("Fast period", "fast_period"),
+//	    &types.Validation{Required: true, Compare: []types.FieldComparison{{Op: types.LT, Field: "slow_period"}}},
+//	)
+func ValidateWith(control *types.GuiControl, validation *types.Validation) *types.GuiControl {
+	control.Validation = validation
+	return control
+}
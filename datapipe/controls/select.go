@@ -0,0 +1,98 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// SelectOption is a single choice of a select control
+type SelectOption struct {
+	Label string `json:"label"`
+	Value any    `json:"value"`
+}
+
+type SelectControlOption func(c *Select)
+
+type selectOptions struct {
+	Options  []SelectOption `json:"options"`
+	Multiple bool           `json:"multiple,omitempty"`
+	Default  any            `json:"default,omitempty"`
+}
+
+func (o *selectOptions) ToMap() (map[string]any, error) {
+	jsonData, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+
+	var resultMap map[string]any
+	err = json.Unmarshal(jsonData, &resultMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json to map: %w", err)
+	}
+
+	return resultMap, nil
+}
+
+// WithMultiple allows selecting several options; the control becomes a multiselect
+func WithMultiple() SelectControlOption {
+	return func(c *Select) {
+		c.selectOptions.Multiple = true
+	}
+}
+
+// WithDefault preselects the options with the given values
+func WithDefault(values ...any) SelectControlOption {
+	return func(c *Select) {
+		c.defaults = values
+	}
+}
+
+type Select struct {
+	Control
+	selectOptions
+	defaults []any
+}
+
+// NewSelect creates a dropdown for enum-style choices
+//
+// Example:
+//
+//	controls.NewSelect("Direction", "direction", []controls.SelectOption{
+//	    {Label: "Long", Value: "long"},
+//	    {Label: "Short", Value: "short"},
+//	}, controls.WithDefault("long"))
+func NewSelect(label, name string, options []SelectOption, opts ...SelectControlOption) *types.GuiControl {
+	c := &Select{
+		Control: Control{
+			Label: label,
+			Name:  name,
+			Type:  types.SELECT,
+		},
+		selectOptions: selectOptions{
+			Options: options,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.selectOptions.Multiple {
+		c.Type = types.MULTISELECT
+		if c.defaults != nil {
+			c.selectOptions.Default = c.defaults
+		}
+	} else if len(c.defaults) > 0 {
+		c.selectOptions.Default = c.defaults[0]
+	}
+
+	optionsMap, err := c.selectOptions.ToMap()
+	if err != nil {
+		panic(fmt.Sprintf("failed to convert options to map: %v", err))
+	}
+
+	return NewControl(label, name, c.Type, optionsMap)
+}
@@ -0,0 +1,89 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+type TextInputOption func(c *TextInput)
+
+type textInputOptions struct {
+	Placeholder string `json:"placeholder,omitempty"`
+	MaxLength   int    `json:"maxLength,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+}
+
+func (o *textInputOptions) ToMap() (map[string]any, error) {
+	jsonData, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+
+	var resultMap map[string]any
+	err = json.Unmarshal(jsonData, &resultMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json to map: %w", err)
+	}
+
+	return resultMap, nil
+}
+
+func WithPlaceholder(placeholder string) TextInputOption {
+	return func(c *TextInput) {
+		c.textInputOptions.Placeholder = placeholder
+	}
+}
+
+func WithMaxLength(maxLength int) TextInputOption {
+	return func(c *TextInput) {
+		c.textInputOptions.MaxLength = maxLength
+	}
+}
+
+// WithPattern restricts the input to values matching the regular expression.
+// It panics if pattern does not compile.
+func WithPattern(pattern string) TextInputOption {
+	regexp.MustCompile(pattern)
+	return func(c *TextInput) {
+		c.textInputOptions.Pattern = pattern
+	}
+}
+
+type TextInput struct {
+	Control
+	textInputOptions
+}
+
+// NewTextInput creates a single-line text input, e.g. for a webhook URL
+func NewTextInput(label, name string, options ...TextInputOption) *types.GuiControl {
+	return newTextControl(label, name, types.TEXT_INPUT, options)
+}
+
+// NewTextArea creates a multi-line text input, e.g. for a list of symbols
+func NewTextArea(label, name string, options ...TextInputOption) *types.GuiControl {
+	return newTextControl(label, name, types.TEXTAREA, options)
+}
+
+func newTextControl(label, name string, controlType types.GuiControlType, options []TextInputOption) *types.GuiControl {
+	c := &TextInput{
+		Control: Control{
+			Label: label,
+			Name:  name,
+			Type:  controlType,
+		},
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	optionsMap, err := c.textInputOptions.ToMap()
+	if err != nil {
+		panic(fmt.Sprintf("failed to convert options to map: %v", err))
+	}
+
+	return NewControl(label, name, controlType, optionsMap)
+}
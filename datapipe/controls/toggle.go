@@ -0,0 +1,15 @@
+package controls
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// NewToggle creates an on/off switch, e.g. for "emit on close only"
+func NewToggle(label, name string, defaultValue bool) *types.GuiControl {
+	return NewControl(label, name, types.TOGGLE, map[string]any{"default": defaultValue})
+}
+
+// NewCheckbox creates a checkbox, e.g. for "use wicks"
+func NewCheckbox(label, name string, defaultValue bool) *types.GuiControl {
+	return NewControl(label, name, types.CHECKBOX, map[string]any{"default": defaultValue})
+}
@@ -0,0 +1,58 @@
+package datapipe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// Error is a Process failure with an error code. Fail copies the code and
+// retryability into the ProcessResponse.
+type Error struct {
+	Code      types.ErrorCode
+	Retryable bool
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError marks err as caused by invalid node configuration
+func ConfigError(err error) error {
+	return &Error{Code: types.ErrCodeConfig, Err: err}
+}
+
+// InputError marks err as caused by an upstream payload not matching the port
+func InputError(err error) error {
+	return &Error{Code: types.ErrCodeInvalidInput, Err: err}
+}
+
+// TransientError marks err as a temporary upstream failure worth retrying
+func TransientError(err error) error {
+	return &Error{Code: types.ErrCodeUpstream, Retryable: true, Err: err}
+}
+
+// NotReady reports that the node cannot produce output yet, e.g. during an
+// indicator warm-up; the engine skips the tick instead of failing the run
+func NotReady(format string, args ...any) error {
+	return &Error{Code: types.ErrCodeNotReady, Retryable: true, Err: fmt.Errorf(format, args...)}
+}
+
+// errorResponse converts err into a failed response. Errors without a code
+// are reported as ErrCodeInternal.
+func errorResponse(err error) types.ProcessResponse {
+	resp := types.ProcessResponse{Error: err.Error(), ErrorCode: types.ErrCodeInternal}
+
+	var coded *Error
+	if errors.As(err, &coded) {
+		resp.ErrorCode = coded.Code
+		resp.Retryable = coded.Retryable
+	}
+	return resp
+}
@@ -0,0 +1,153 @@
+package datapipe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+// ProcessFunc processes one data flow through the node
+type ProcessFunc func(req types.ProcessRequest) types.ProcessResponse
+
+// NodeDefinition describes a datapipe node declaratively. RegisterNode turns
+// it into a plugin with all exports wired up.
+type NodeDefinition struct {
+	Meta         m.Meta
+	Ports        types.Connections
+	Gui          types.GuiDefinition
+	CustomTypes  []types.DataTypeDefinition
+	ConfigFields []plugin.ConfigField
+	RateLimits   []plugin.RateLimit
+	ProcessFunc  ProcessFunc
+
+	// Optional lifecycle hooks
+	OnInit     func(config *plugin.ConfigStore) error
+	OnShutdown func() error
+}
+
+// RegisterNode registers a node and generates all WASM exports. Like
+// plugin.RegisterPlugin it MUST be called in init().
+//
+// The node answers CMD_GET_NODE_META with its ports and GUI and CMD_PROCESS by
+// validating the request config against the GUI rules and the inputs against
+// the port schemas, then calling ProcessFunc.
+//
+// Example:
+//
+//	func init() {
+//	    datapipe.RegisterNode(datapipe.NodeDefinition{
+//	        Meta: m.Meta{PluginID: "ema", Name: "EMA", AppID: "datapipes", Version: "1.0.0"},
+//	        Ports: dt.Connections{
+//	            Inputs:  []dt.NodePort{{Name: "ohlcv", DataTypes: []dt.DataType{dt.DataTypeOHLCVRecord}}},
+//	            Outputs: []dt.NodePort{{Name: "ema", DataTypes: []dt.DataType{dt.DataTypeSeries}}},
+//	        },
+//	        Gui:         dt.GuiDefinition{Controls: []*dt.GuiControl{controls.NewNumberInput("Period", "period")}},
+//	        ProcessFunc: process,
+//	    })
+//	}
+func RegisterNode(def NodeDefinition) {
+	plugin.RegisterPlugin(&node{def: def})
+}
+
+// node adapts a NodeDefinition to plugin.Plugin
+type node struct {
+	def NodeDefinition
+}
+
+func (n *node) GetMeta() m.Meta {
+	return n.def.Meta
+}
+
+func (n *node) GetConfigFields() []plugin.ConfigField {
+	return n.def.ConfigFields
+}
+
+func (n *node) GetRateLimits() []plugin.RateLimit {
+	return n.def.RateLimits
+}
+
+func (n *node) OnInit(config *plugin.ConfigStore) error {
+	if n.def.OnInit == nil {
+		return nil
+	}
+	return n.def.OnInit(config)
+}
+
+func (n *node) OnShutdown() error {
+	if n.def.OnShutdown == nil {
+		return nil
+	}
+	return n.def.OnShutdown()
+}
+
+func (n *node) RegisterCommands(router *plugin.CommandRouter) {
+	router.Register(CMD_GET_NODE_META, n.handleGetNodeMeta)
+	router.Register(CMD_PROCESS, n.handleProcess)
+}
+
+func (n *node) handleGetNodeMeta(_ map[string]any) plugin.Response {
+	return plugin.SuccessResponse(types.NodeMeta{
+		Name:          n.def.Meta.Name,
+		GuiDefinition: n.def.Gui,
+		Connections:   n.def.Ports,
+		CustomTypes:   n.def.CustomTypes,
+	})
+}
+
+func (n *node) handleProcess(params map[string]any) plugin.Response {
+	req, err := decodeProcessRequest(params)
+	if err != nil {
+		return processError(InputError(err))
+	}
+
+	// Correlate logs with the pipeline run when the host sent no trace ID
+	if trace.Current() == "" && req.Context.RunID != "" {
+		defer trace.Set(req.Context.RunID)()
+	}
+
+	if err := ValidateConfig(req.Config, n.def.Gui); err != nil {
+		return processError(ConfigError(err))
+	}
+
+	if err := ValidateInput(req, n.def.Ports.Inputs); err != nil {
+		return processError(InputError(err))
+	}
+
+	resp := n.def.ProcessFunc(req)
+	if !resp.Success {
+		return failedResponse(resp)
+	}
+	if err := checkOutputs(resp, n.def.Ports); err != nil {
+		return processError(err)
+	}
+	return plugin.SuccessResponse(resp)
+}
+
+func processError(err error) plugin.Response {
+	return failedResponse(errorResponse(err))
+}
+
+// failedResponse keeps the error code and retryability of a failed
+// ProcessResponse in the command response data
+func failedResponse(resp types.ProcessResponse) plugin.Response {
+	if resp.ErrorCode == "" {
+		resp.ErrorCode = types.ErrCodeInternal
+	}
+	return plugin.Response{Result: false, Error: resp.Error, Data: resp}
+}
+
+func decodeProcessRequest(params map[string]any) (types.ProcessRequest, error) {
+	var req types.ProcessRequest
+	data, err := json.Marshal(params)
+	if err != nil {
+		return req, fmt.Errorf("failed to encode process params: %w", err)
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return req, fmt.Errorf("invalid process request: %w", err)
+	}
+	return req, nil
+}
@@ -0,0 +1,87 @@
+//go:build !wasm
+
+package datapipe_test
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe"
+	"github.com/plusev-terminal/go-plugin-common/datapipe/controls"
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+)
+
+func TestRegisterNode(t *testing.T) {
+	datapipe.RegisterNode(datapipe.NodeDefinition{
+		Meta: m.Meta{PluginID: "double", Name: "Double", AppID: "datapipes", Version: "1.0.0"},
+		Ports: dt.Connections{
+			Outputs: []dt.NodePort{{Name: "out", DataTypes: []dt.DataType{dt.DataTypeSeries}}},
+		},
+		Gui: dt.GuiDefinition{Controls: []*dt.GuiControl{
+			controls.ValidateWith(controls.NewNumberInput("Factor", "factor"), &dt.Validation{Required: true}),
+		}},
+		ProcessFunc: func(req dt.ProcessRequest) dt.ProcessResponse {
+			factor := req.Config["factor"].(float64)
+			switch {
+			case factor < 0:
+				return datapipe.Emit("undeclared", factor)
+			case factor == 0:
+				return datapipe.NoOutput()
+			}
+			return datapipe.Emit("out", factor*2)
+		},
+	})
+
+	h := plugintest.New(t)
+	meta, err := h.Meta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Features) != 2 {
+		t.Fatalf("expected process and get_node_meta features, got %v", meta.Features)
+	}
+
+	resp := h.Command(datapipe.CMD_PROCESS, map[string]any{"input": map[string]any{}, "config": map[string]any{"factor": 2}})
+	if !resp.Result {
+		t.Fatalf("process failed: %s", resp.Error)
+	}
+	out := resp.Data.(map[string]any)["output"].(map[string]any)
+	if out["out"] != 4.0 {
+		t.Fatalf("unexpected output %v", out)
+	}
+
+	if resp := h.Command(datapipe.CMD_PROCESS, map[string]any{"config": map[string]any{"factor": 0}}); !resp.Result {
+		t.Fatalf("no output should not be a failure: %s", resp.Error)
+	}
+	if resp := h.Command(datapipe.CMD_PROCESS, map[string]any{"config": map[string]any{"factor": -1}}); resp.Result {
+		t.Fatal("expected output on undeclared port to fail")
+	}
+
+	resp = h.Command(datapipe.CMD_PROCESS, map[string]any{"config": map[string]any{}})
+	if resp.Result {
+		t.Fatal("expected missing factor to fail validation")
+	}
+	if code := resp.Data.(map[string]any)["errorCode"]; code != string(dt.ErrCodeConfig) {
+		t.Fatalf("expected config error code, got %v", code)
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	h := plugintest.New(t)
+
+	tracker := datapipe.NewProgressTracker(300)
+	for i := 0; i < 300; i++ {
+		if err := tracker.Advance(1, "candles"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updates := h.Progress()
+	if len(updates) != 101 {
+		t.Fatalf("expected one update per percent from 0 to 100, got %d", len(updates))
+	}
+	if last := updates[len(updates)-1]; last.Percent != 100 || last.Step != 300 || last.TotalSteps != 300 {
+		t.Fatalf("unexpected final update %+v", last)
+	}
+}
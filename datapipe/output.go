@@ -0,0 +1,63 @@
+package datapipe
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// Emit returns a successful response carrying v on port. Other output ports
+// do not fire.
+//
+// Example:
+//
+//	if crossedUp {
+//	    return datapipe.Emit("long", signal)
+//	}
+//	if crossedDown {
+//	    return datapipe.Emit("short", signal)
+//	}
+//	return datapipe.NoOutput()
+func Emit[T any](port string, v T) types.ProcessResponse {
+	out := map[string]any{}
+	if err := EncodePort(out, port, v); err != nil {
+		return Fail(&Error{Code: types.ErrCodeInternal, Err: err})
+	}
+	return types.ProcessResponse{Success: true, Output: out}
+}
+
+// NoOutput returns a successful response that fires no output port. The host
+// treats it as a completed invocation, not as a failure.
+func NoOutput() types.ProcessResponse {
+	return types.ProcessResponse{Success: true, Output: map[string]any{}}
+}
+
+// Fail returns a failed response. Errors created with ConfigError,
+// TransientError, NotReady, ... keep their code; others are ErrCodeInternal.
+//
+// Example:
+//
+//	if len(candles) < period {
+//	    return datapipe.Fail(datapipe.NotReady("need %d candles, have %d", period, len(candles)))
+//	}
+func Fail(err error) types.ProcessResponse {
+	return errorResponse(err)
+}
+
+// checkOutputs rejects output on ports the node did not declare
+func checkOutputs(resp types.ProcessResponse, ports types.Connections) error {
+	if len(ports.Outputs) == 0 {
+		return nil
+	}
+
+	declared := make(map[string]bool, len(ports.Outputs))
+	for _, port := range ports.Outputs {
+		declared[port.Name] = true
+	}
+	for name := range resp.Output {
+		if !declared[name] {
+			return &Error{Code: types.ErrCodeInternal, Err: fmt.Errorf("output on undeclared port %q", name)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package datapipe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+// ReportProgress sends a progress update to the host via the report_progress
+// host function. Percent is clamped to 0-100. Hosts without progress
+// support silently drop the update.
+func ReportProgress(p types.Progress) error {
+	if !hostinfo.Has(hostinfo.ReportProgress) {
+		return nil
+	}
+	p.Percent = min(max(p.Percent, 0), 100)
+	if p.TraceID == "" {
+		p.TraceID = trace.Current()
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress: %w", err)
+	}
+
+	host.CallReportProgress(data)
+	return nil
+}
+
+// ProgressTracker reports progress over a known number of steps. Updates are
+// only sent when the whole percentage changes, so calling Advance once per
+// record of a large backfill does not flood the host.
+//
+// Example:
+//
+//	tracker := datapipe.NewProgressTracker(len(batches))
+//	for _, batch := range batches {
+//	    process(batch)
+//	    tracker.Advance(1, "processing candles")
+//	}
+type ProgressTracker struct {
+	total       int
+	done        int
+	lastPercent int
+}
+
+// NewProgressTracker creates a tracker for total steps
+func NewProgressTracker(total int) *ProgressTracker {
+	return &ProgressTracker{total: total, lastPercent: -1}
+}
+
+// Advance marks n more steps as done and reports if the percentage changed
+func (t *ProgressTracker) Advance(n int, message string) error {
+	t.done = min(t.done+n, t.total)
+
+	percent := 100
+	if t.total > 0 {
+		percent = t.done * 100 / t.total
+	}
+	if percent == t.lastPercent {
+		return nil
+	}
+	t.lastPercent = percent
+
+	return ReportProgress(types.Progress{
+		Percent:    float64(percent),
+		Step:       t.done,
+		TotalSteps: t.total,
+		Message:    message,
+	})
+}
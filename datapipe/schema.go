@@ -0,0 +1,103 @@
+package datapipe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// ValidateInput checks the payloads of req against the schemas of ports (see
+// types.PortSchema). A port payload may be a single object or a list of
+// objects. Ports without input are skipped since not every upstream fires on
+// every call. The error names the port, item and field, e.g.
+// "port ohlcv[2]: missing field close".
+func ValidateInput(req types.ProcessRequest, ports []types.NodePort) error {
+	var errs []error
+	for _, port := range ports {
+		schema := types.PortSchema(port)
+		payload, ok := req.Input[port.Name]
+		if len(schema) == 0 || !ok || payload == nil {
+			continue
+		}
+
+		switch p := payload.(type) {
+		case map[string]any:
+			if err := validatePayload(p, schema); err != nil {
+				errs = append(errs, fmt.Errorf("port %s: %w", port.Name, err))
+			}
+		case []any:
+			for i, item := range p {
+				obj, ok := item.(map[string]any)
+				if !ok {
+					errs = append(errs, fmt.Errorf("port %s[%d]: expected object, got %s", port.Name, i, jsonType(item)))
+					continue
+				}
+				if err := validatePayload(obj, schema); err != nil {
+					errs = append(errs, fmt.Errorf("port %s[%d]: %w", port.Name, i, err))
+				}
+			}
+		default:
+			errs = append(errs, fmt.Errorf("port %s: expected object or list, got %s", port.Name, jsonType(payload)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validatePayload(payload map[string]any, schema []types.DataTypeField) error {
+	for _, field := range schema {
+		value, ok := payload[field.Name]
+		if !ok || value == nil {
+			if field.Required {
+				return fmt.Errorf("missing field %s", field.Name)
+			}
+			continue
+		}
+		if !matchesType(value, field.Type) {
+			return fmt.Errorf("field %s: expected %s, got %s", field.Name, field.Type, jsonType(value))
+		}
+	}
+	return nil
+}
+
+func matchesType(value any, fieldType string) bool {
+	switch fieldType {
+	case types.FieldTypeString:
+		_, ok := value.(string)
+		return ok
+	case types.FieldTypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case types.FieldTypeTimestamp:
+		// Unix timestamps or RFC3339 strings
+		switch value.(type) {
+		case float64, string:
+			return true
+		}
+		return false
+	case types.FieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	}
+	// Unknown types are not checked
+	return true
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
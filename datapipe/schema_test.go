@@ -0,0 +1,24 @@
+package datapipe
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+func TestValidateInput(t *testing.T) {
+	ports := []types.NodePort{{Name: "ohlcv", DataTypes: []types.DataType{types.DataTypeOHLCVRecord}}}
+	candle := map[string]any{"openTime": 1.0, "open": "1", "high": "2", "low": "0.5", "close": "1.5", "volume": "10"}
+
+	req := types.ProcessRequest{Input: map[string]any{"ohlcv": []any{candle}}}
+	if err := ValidateInput(req, ports); err != nil {
+		t.Fatalf("valid input rejected: %v", err)
+	}
+
+	broken := map[string]any{"openTime": 2.0, "open": "1", "high": "2", "low": "0.5", "volume": "10"}
+	req.Input["ohlcv"] = []any{candle, broken}
+	err := ValidateInput(req, ports)
+	if err == nil || err.Error() != "port ohlcv[1]: missing field close" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package datapipe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// LoadState decodes the node state persisted by the host. The zero value is
+// returned on the first call of a node instance.
+//
+// Example:
+//
+//	type emaState struct {
+//	    Value float64 `json:"value"`
+//	    Count int     `json:"count"`
+//	}
+//
+//	state, err := datapipe.LoadState[emaState](req)
+func LoadState[T any](req types.ProcessRequest) (T, error) {
+	var state T
+	if len(req.State) == 0 || string(req.State) == "null" {
+		return state, nil
+	}
+	if err := json.Unmarshal(req.State, &state); err != nil {
+		return state, fmt.Errorf("failed to decode node state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveState stores state in resp so the host passes it to the next Process
+// call of the same node instance
+func SaveState[T any](resp *types.ProcessResponse, state T) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode node state: %w", err)
+	}
+	resp.State = data
+	return nil
+}
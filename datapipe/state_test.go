@@ -0,0 +1,29 @@
+package datapipe
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	type emaState struct {
+		Value float64 `json:"value"`
+		Count int     `json:"count"`
+	}
+
+	first, err := LoadState[emaState](types.ProcessRequest{})
+	if err != nil || first != (emaState{}) {
+		t.Fatalf("expected zero state, got %+v, %v", first, err)
+	}
+
+	var resp types.ProcessResponse
+	if err := SaveState(&resp, emaState{Value: 1.5, Count: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := LoadState[emaState](types.ProcessRequest{State: resp.State})
+	if err != nil || next != (emaState{Value: 1.5, Count: 3}) {
+		t.Fatalf("unexpected state %+v, %v", next, err)
+	}
+}
@@ -0,0 +1,159 @@
+package types
+
+import "reflect"
+
+// GuiSectionKind is how a section is rendered
+type GuiSectionKind string
+
+const (
+	SECTION GuiSectionKind = "section" // Titled, optionally collapsible group
+	TAB     GuiSectionKind = "tab"     // Sibling tabs are rendered as one tab bar
+)
+
+// GuiSection groups controls and nested sections
+type GuiSection struct {
+	Title       string         `json:"title"`
+	Kind        GuiSectionKind `json:"kind"`
+	Collapsed   bool           `json:"collapsed,omitempty"` // Initially collapsed (sections only)
+	Controls    []*GuiControl  `json:"controls,omitempty"`
+	Sections    []*GuiSection  `json:"sections,omitempty"`
+	VisibleWhen *Condition     `json:"visibleWhen,omitempty"`
+}
+
+// Condition is a predicate over the values of other controls. Exactly one of
+// Equals, NotEquals, In, All or Any is used; Field names the control for the
+// first three.
+type Condition struct {
+	Field     string       `json:"field,omitempty"`
+	Equals    any          `json:"equals,omitempty"`
+	NotEquals any          `json:"notEquals,omitempty"`
+	In        []any        `json:"in,omitempty"`
+	All       []*Condition `json:"all,omitempty"`
+	Any       []*Condition `json:"any,omitempty"`
+}
+
+// FieldEquals holds while the field has value
+func FieldEquals(field string, value any) *Condition {
+	return &Condition{Field: field, Equals: value}
+}
+
+// FieldNotEquals holds while the field does not have value
+func FieldNotEquals(field string, value any) *Condition {
+	return &Condition{Field: field, NotEquals: value}
+}
+
+// FieldIn holds while the field has one of values
+func FieldIn(field string, values ...any) *Condition {
+	return &Condition{Field: field, In: values}
+}
+
+// AllOf holds while all conditions hold
+func AllOf(conditions ...*Condition) *Condition {
+	return &Condition{All: conditions}
+}
+
+// AnyOf holds while at least one condition holds
+func AnyOf(conditions ...*Condition) *Condition {
+	return &Condition{Any: conditions}
+}
+
+// Evaluate checks the condition against config values. A nil condition holds.
+func (c *Condition) Evaluate(values map[string]any) bool {
+	switch {
+	case c == nil:
+		return true
+	case len(c.All) > 0:
+		for _, sub := range c.All {
+			if !sub.Evaluate(values) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for _, sub := range c.Any {
+			if sub.Evaluate(values) {
+				return true
+			}
+		}
+		return false
+	case len(c.In) > 0:
+		for _, v := range c.In {
+			if valuesEqual(values[c.Field], v) {
+				return true
+			}
+		}
+		return false
+	case c.NotEquals != nil:
+		return !valuesEqual(values[c.Field], c.NotEquals)
+	default:
+		return valuesEqual(values[c.Field], c.Equals)
+	}
+}
+
+// AllControls returns the top-level controls followed by those of all
+// sections, depth first
+func (d GuiDefinition) AllControls() []*GuiControl {
+	controls := append([]*GuiControl(nil), d.Controls...)
+	var walk func(sections []*GuiSection)
+	walk = func(sections []*GuiSection) {
+		for _, section := range sections {
+			controls = append(controls, section.Controls...)
+			walk(section.Sections)
+		}
+	}
+	walk(d.Sections)
+	return controls
+}
+
+// VisibleControls returns the controls visible for the given config values,
+// taking the conditions of enclosing sections into account
+func (d GuiDefinition) VisibleControls(values map[string]any) []*GuiControl {
+	var controls []*GuiControl
+	add := func(list []*GuiControl) {
+		for _, control := range list {
+			if control.VisibleWhen.Evaluate(values) {
+				controls = append(controls, control)
+			}
+		}
+	}
+
+	add(d.Controls)
+	var walk func(sections []*GuiSection)
+	walk = func(sections []*GuiSection) {
+		for _, section := range sections {
+			if !section.VisibleWhen.Evaluate(values) {
+				continue
+			}
+			add(section.Controls)
+			walk(section.Sections)
+		}
+	}
+	walk(d.Sections)
+	return controls
+}
+
+// valuesEqual compares config values, treating all numeric types alike since
+// values decoded from JSON are float64
+func valuesEqual(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+package types
+
+// Field types of DataTypeField
+const (
+	FieldTypeString    = "string"
+	FieldTypeNumber    = "number"
+	FieldTypeTimestamp = "timestamp"
+	FieldTypeBoolean   = "boolean"
+)
+
+// BuiltinSchemas are the payload fields of the standard data types. Ports
+// without an own Schema are validated against these.
+var BuiltinSchemas = map[DataType][]DataTypeField{
+	DataTypeOHLCVRecord: {
+		{Name: "openTime", Type: FieldTypeTimestamp, Required: true},
+		{Name: "open", Type: FieldTypeString, Required: true},
+		{Name: "high", Type: FieldTypeString, Required: true},
+		{Name: "low", Type: FieldTypeString, Required: true},
+		{Name: "close", Type: FieldTypeString, Required: true},
+		{Name: "volume", Type: FieldTypeString, Required: true},
+	},
+	DataTypeSignal: {
+		{Name: "type", Type: FieldTypeString, Required: true},
+		{Name: "strength", Type: FieldTypeNumber},
+		{Name: "timestamp", Type: FieldTypeTimestamp},
+		{Name: "message", Type: FieldTypeString},
+	},
+	DataTypeStartSignal: {
+		{Name: "timestamp", Type: FieldTypeTimestamp},
+	},
+	DataTypeTrade: {
+		{Name: "timestamp", Type: FieldTypeTimestamp, Required: true},
+		{Name: "price", Type: FieldTypeString, Required: true},
+		{Name: "quantity", Type: FieldTypeString, Required: true},
+		{Name: "side", Type: FieldTypeString},
+	},
+	DataTypeSeries: {
+		{Name: "name", Type: FieldTypeString, Required: true},
+	},
+}
+
+// PortSchema returns the schema payloads of port are validated against: its
+// own Schema, or the built-in schema if the port accepts exactly one standard
+// data type. Nil means the payload is not checked.
+func PortSchema(port NodePort) []DataTypeField {
+	if len(port.Schema) > 0 {
+		return port.Schema
+	}
+	if len(port.DataTypes) == 1 {
+		return BuiltinSchemas[port.DataTypes[0]]
+	}
+	return nil
+}
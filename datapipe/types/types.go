@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // DataType represents the type of data flowing through node ports
 type DataType string
 
@@ -7,6 +9,9 @@ const (
 	DataTypeOHLCVRecord DataType = "OHLCVRecord"
 	DataTypeSignal      DataType = "Signal"
 	DataTypeStartSignal DataType = "StartSignal"
+	DataTypeOrderbook   DataType = "Orderbook" // trading.Orderbook
+	DataTypeTrade       DataType = "Trade"     // trading.TradeRecord
+	DataTypeSeries      DataType = "Series"    // Series
 )
 
 type NodeMeta struct {
@@ -46,44 +51,95 @@ type Connections struct {
 
 // NodePort defines an input or output port on a node
 type NodePort struct {
-	Name      string     `json:"name"`
-	DataTypes []DataType `json:"dataTypes"`
+	Name        string          `json:"name"`
+	DataTypes   []DataType      `json:"dataTypes"`
+	Conditional bool            `json:"conditional,omitempty"` // Output port that only fires on some invocations
+	Schema      []DataTypeField `json:"schema,omitempty"`      // Fields payloads must have; defaults to the built-in schema of the data type
 }
 
 // ProcessRequest contains the input data and configuration for processing
 type ProcessRequest struct {
-	Input  map[string]any `json:"input"`  // Input data keyed by port name
-	Config map[string]any `json:"config"` // Plugin configuration values
+	Input   map[string]any  `json:"input"`           // Input data keyed by port name
+	Config  map[string]any  `json:"config"`          // Plugin configuration values
+	State   json.RawMessage `json:"state,omitempty"` // State saved by the previous Process call of this node instance
+	Context RunContext      `json:"context"`         // Metadata of the pipeline run
+}
+
+// RunContext describes the pipeline run a Process call belongs to
+type RunContext struct {
+	RunID          string   `json:"runId,omitempty"`          // Unique ID of the pipeline run
+	NodeInstanceID string   `json:"nodeInstanceId,omitempty"` // ID of this node within the pipeline
+	UpstreamNodes  []string `json:"upstreamNodes,omitempty"`  // Names of the nodes feeding this one
+	DryRun         bool     `json:"dryRun,omitempty"`         // Nodes must not cause side effects such as placing orders
+	AsOf           int64    `json:"asOf,omitempty"`           // Unix timestamp in milliseconds the run is evaluated at (backtests, replays); 0 for live
+}
+
+// IsBacktest reports whether the run replays history rather than running live
+func (c RunContext) IsBacktest() bool {
+	return c.AsOf != 0
 }
 
 // ProcessResponse contains the output data from processing
 type ProcessResponse struct {
-	Success bool           `json:"success"`          // Whether processing succeeded
-	Output  map[string]any `json:"output,omitempty"` // Output data keyed by port name
-	Error   string         `json:"error,omitempty"`  // Error message if failed
+	Success   bool            `json:"success"`             // Whether processing succeeded
+	Output    map[string]any  `json:"output,omitempty"`    // Output data keyed by port name; ports missing here do not fire
+	Error     string          `json:"error,omitempty"`     // Error message if failed
+	ErrorCode ErrorCode       `json:"errorCode,omitempty"` // Class of the failure, see ErrorCode
+	Retryable bool            `json:"retryable,omitempty"` // The same call may succeed when retried
+	State     json.RawMessage `json:"state,omitempty"`     // State the host persists for the next call; omitted keeps the previous state
+}
+
+// ErrorCode classifies a failed Process call so the pipeline engine can react
+// without parsing error strings
+type ErrorCode string
+
+const (
+	ErrCodeConfig       ErrorCode = "config"        // Invalid node configuration; fail the run
+	ErrCodeInvalidInput ErrorCode = "invalid_input" // Upstream payload does not match the port
+	ErrCodeUpstream     ErrorCode = "upstream"      // Transient failure of an external service; retry
+	ErrCodeNotReady     ErrorCode = "not_ready"     // Not enough data yet; skip this tick
+	ErrCodeInternal     ErrorCode = "internal"      // Bug or unexpected condition in the node
+)
+
+// Progress is a progress update of a long-running Process call, shown by the
+// host in the pipeline run UI
+type Progress struct {
+	Percent    float64 `json:"percent"`              // 0-100
+	Step       int     `json:"step,omitempty"`       // Current step, e.g. batch number
+	TotalSteps int     `json:"totalSteps,omitempty"` // Number of steps if known
+	Message    string  `json:"message,omitempty"`
+	TraceID    string  `json:"traceId,omitempty"` // Correlates the update with the running command
 }
 
 type GuiControlType string
 
 const (
 	TEXT_INPUT   GuiControlType = "text_input"
+	TEXTAREA     GuiControlType = "textarea"
 	NUMBER_INPUT GuiControlType = "number_input"
 	CHECKBOX     GuiControlType = "checkbox"
+	TOGGLE       GuiControlType = "toggle"
 	SELECT       GuiControlType = "select"
 	MULTISELECT  GuiControlType = "multiselect"
+	MARKET       GuiControlType = "market_picker"
+	DATETIME     GuiControlType = "datetime_picker"
+	DATE_RANGE   GuiControlType = "date_range_picker"
 )
 
 // GuiDefinition defines the configuration UI for the plugin
 type GuiDefinition struct {
-	Controls []*GuiControl `json:"controls"`
+	Controls []*GuiControl `json:"controls"`           // Controls rendered before any section
+	Sections []*GuiSection `json:"sections,omitempty"` // Optional grouping into sections and tabs
 }
 
 // GuiControl defines a single UI control in the configuration GUI
 type GuiControl struct {
-	Label   string         `json:"label"`
-	Name    string         `json:"name"`
-	Type    GuiControlType `json:"type"`
-	Options map[string]any `json:"options"`
+	Label       string         `json:"label"`
+	Name        string         `json:"name"`
+	Type        GuiControlType `json:"type"`
+	Options     map[string]any `json:"options"`
+	VisibleWhen *Condition     `json:"visibleWhen,omitempty"` // Show only while the condition holds
+	Validation  *Validation    `json:"validation,omitempty"`  // Rules enforced by the host UI and ValidateConfig
 }
 
 // Signal represents a trading signal
@@ -95,6 +151,8 @@ type Signal struct {
 }
 
 // ToMap converts Signal to a map for processing
+//
+// Deprecated: use datapipe.EncodePort.
 func (s *Signal) ToMap() map[string]any {
 	return map[string]any{
 		"type":      s.Type,
@@ -105,6 +163,8 @@ func (s *Signal) ToMap() map[string]any {
 }
 
 // FromMap populates Signal from a map
+//
+// Deprecated: use datapipe.DecodePort.
 func (s *Signal) FromMap(m map[string]any) {
 	if v, ok := m["type"].(string); ok {
 		s.Type = v
@@ -126,6 +186,8 @@ type StartSignal struct {
 }
 
 // ToMap converts StartSignal to a map for processing
+//
+// Deprecated: use datapipe.EncodePort.
 func (ss *StartSignal) ToMap() map[string]any {
 	return map[string]any{
 		"timestamp": ss.Timestamp,
@@ -133,8 +195,37 @@ func (ss *StartSignal) ToMap() map[string]any {
 }
 
 // FromMap populates StartSignal from a map
+//
+// Deprecated: use datapipe.DecodePort.
 func (ss *StartSignal) FromMap(m map[string]any) {
 	if v, ok := m["timestamp"].(float64); ok {
 		ss.Timestamp = int64(v)
 	}
 }
+
+// Series is a named series of timestamped values, e.g. an indicator output
+type Series struct {
+	Name   string        `json:"name"`
+	Points []SeriesPoint `json:"points"`
+}
+
+// SeriesPoint is a single value of a Series. Decimal optionally carries the
+// exact value as a string when float precision is not enough.
+type SeriesPoint struct {
+	Timestamp int64   `json:"timestamp"` // Unix timestamp in milliseconds
+	Value     float64 `json:"value"`
+	Decimal   string  `json:"decimal,omitempty"`
+}
+
+// Append adds a point to the series
+func (s *Series) Append(timestamp int64, value float64) {
+	s.Points = append(s.Points, SeriesPoint{Timestamp: timestamp, Value: value})
+}
+
+// Last returns the most recent point, if any
+func (s *Series) Last() (SeriesPoint, bool) {
+	if len(s.Points) == 0 {
+		return SeriesPoint{}, false
+	}
+	return s.Points[len(s.Points)-1], true
+}
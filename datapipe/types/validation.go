@@ -0,0 +1,221 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CompareOp is the operator of a cross-field comparison
+type CompareOp string
+
+const (
+	LT  CompareOp = "lt"
+	LTE CompareOp = "lte"
+	GT  CompareOp = "gt"
+	GTE CompareOp = "gte"
+	EQ  CompareOp = "eq"
+	NE  CompareOp = "ne"
+)
+
+// Validation declares the rules a control or config field value must satisfy.
+// The same block is sent to the host UI and enforced by ValidateConfig, so both
+// reject the same values.
+type Validation struct {
+	Required     bool              `json:"required,omitempty"`
+	RequiredWhen *Condition        `json:"requiredWhen,omitempty"` // Required only while the condition holds
+	Min          *float64          `json:"min,omitempty"`          // Minimum numeric value
+	Max          *float64          `json:"max,omitempty"`          // Maximum numeric value
+	MinLength    *int              `json:"minLength,omitempty"`    // Minimum string length or item count
+	MaxLength    *int              `json:"maxLength,omitempty"`    // Maximum string length or item count
+	Pattern      string            `json:"pattern,omitempty"`      // Regular expression strings must match
+	Compare      []FieldComparison `json:"compare,omitempty"`      // Cross-field rules
+	Message      string            `json:"message,omitempty"`      // Replaces the generated message
+}
+
+// FieldComparison compares the value with the value of another field, e.g.
+// {Op: LT, Field: "slow_period"} on "fast_period"
+type FieldComparison struct {
+	Op      CompareOp `json:"op"`
+	Field   string    `json:"field"`
+	Message string    `json:"message,omitempty"`
+}
+
+// FieldError is a validation failure of a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors collects all failures of one validation pass
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Check validates the value of field against the rules. values holds the whole
+// config and is used by RequiredWhen and cross-field comparisons.
+func (v *Validation) Check(field string, values map[string]any) *FieldError {
+	if v == nil {
+		return nil
+	}
+
+	value := values[field]
+	if isEmpty(value) {
+		if v.Required || (v.RequiredWhen != nil && v.RequiredWhen.Evaluate(values)) {
+			return v.fail(field, "is required")
+		}
+		return nil
+	}
+
+	if v.Min != nil || v.Max != nil {
+		n, ok := toFloat(value)
+		if !ok {
+			return v.fail(field, "must be a number")
+		}
+		if v.Min != nil && n < *v.Min {
+			return v.fail(field, fmt.Sprintf("must be at least %v", *v.Min))
+		}
+		if v.Max != nil && n > *v.Max {
+			return v.fail(field, fmt.Sprintf("must be at most %v", *v.Max))
+		}
+	}
+
+	if v.MinLength != nil || v.MaxLength != nil {
+		l, ok := length(value)
+		if !ok {
+			return v.fail(field, "has no length")
+		}
+		if v.MinLength != nil && l < *v.MinLength {
+			return v.fail(field, fmt.Sprintf("must have at least %d characters or items", *v.MinLength))
+		}
+		if v.MaxLength != nil && l > *v.MaxLength {
+			return v.fail(field, fmt.Sprintf("must have at most %d characters or items", *v.MaxLength))
+		}
+	}
+
+	if v.Pattern != "" {
+		s, ok := value.(string)
+		if !ok {
+			return v.fail(field, "must be a string")
+		}
+		re, err := regexp.Compile(v.Pattern)
+		if err != nil {
+			return &FieldError{Field: field, Message: fmt.Sprintf("invalid pattern %q: %v", v.Pattern, err)}
+		}
+		if !re.MatchString(s) {
+			return v.fail(field, fmt.Sprintf("must match %s", v.Pattern))
+		}
+	}
+
+	for _, cmp := range v.Compare {
+		other, ok := values[cmp.Field]
+		if !ok || isEmpty(other) {
+			continue
+		}
+		holds, err := compare(value, cmp.Op, other)
+		if err != nil {
+			return &FieldError{Field: field, Message: err.Error()}
+		}
+		if !holds {
+			if cmp.Message != "" {
+				return &FieldError{Field: field, Message: cmp.Message}
+			}
+			return v.fail(field, fmt.Sprintf("must be %s %s", opText[cmp.Op], cmp.Field))
+		}
+	}
+
+	return nil
+}
+
+func (v *Validation) fail(field, msg string) *FieldError {
+	if v.Message != "" {
+		msg = v.Message
+	}
+	return &FieldError{Field: field, Message: msg}
+}
+
+var opText = map[CompareOp]string{
+	LT:  "less than",
+	LTE: "at most",
+	GT:  "greater than",
+	GTE: "at least",
+	EQ:  "equal to",
+	NE:  "different from",
+}
+
+func compare(a any, op CompareOp, b any) (bool, error) {
+	switch op {
+	case EQ:
+		return valuesEqual(a, b), nil
+	case NE:
+		return !valuesEqual(a, b), nil
+	}
+
+	var c int
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %T", b)
+		}
+		switch {
+		case fa < fb:
+			c = -1
+		case fa > fb:
+			c = 1
+		}
+	} else {
+		sa, okA := a.(string)
+		sb, okB := b.(string)
+		if !okA || !okB {
+			return false, fmt.Errorf("cannot compare %T with %T", a, b)
+		}
+		// RFC3339 timestamps in the same zone order lexically
+		c = strings.Compare(sa, sb)
+	}
+
+	switch op {
+	case LT:
+		return c < 0, nil
+	case LTE:
+		return c <= 0, nil
+	case GT:
+		return c > 0, nil
+	case GTE:
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown compare op %q", op)
+}
+
+func isEmpty(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []any:
+		return len(val) == 0
+	}
+	return false
+}
+
+func length(v any) (int, bool) {
+	switch val := v.(type) {
+	case string:
+		return len([]rune(val)), true
+	case []any:
+		return len(val), true
+	case []string:
+		return len(val), true
+	}
+	return 0, false
+}
@@ -0,0 +1,22 @@
+package datapipe
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+// ValidateConfig checks config against the validation rules of the visible
+// controls of gui. Hidden controls are skipped. The returned error is a
+// types.ValidationErrors listing every failing field.
+func ValidateConfig(config map[string]any, gui types.GuiDefinition) error {
+	var errs types.ValidationErrors
+	for _, control := range gui.VisibleControls(config) {
+		if fe := control.Validation.Check(control.Name, config); fe != nil {
+			errs = append(errs, *fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
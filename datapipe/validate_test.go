@@ -0,0 +1,33 @@
+package datapipe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/datapipe/types"
+)
+
+func TestValidateConfig(t *testing.T) {
+	one := 1.0
+	gui := types.GuiDefinition{
+		Controls: []*types.GuiControl{
+			{Name: "mode", Validation: &types.Validation{Required: true}},
+			{Name: "fast", Validation: &types.Validation{Min: &one, Compare: []types.FieldComparison{{Op: types.LT, Field: "slow"}}}},
+			{Name: "slow"},
+			{Name: "signal", VisibleWhen: types.FieldEquals("mode", "macd"), Validation: &types.Validation{Required: true}},
+		},
+	}
+
+	if err := ValidateConfig(map[string]any{"mode": "ema", "fast": 5.0, "slow": 10.0}, gui); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	err := ValidateConfig(map[string]any{"mode": "macd", "fast": 20.0, "slow": 10.0}, gui)
+	var errs types.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "fast" || errs[1].Field != "signal" {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
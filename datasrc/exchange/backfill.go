@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// PlanBackfill builds a BackfillPlan for the requested range.
+//
+// maxPerRequest is the maximum number of candles the exchange returns per
+// request. limit should be the plugin's RateLimit entry for getOHLCV so that
+// the plan's cost and estimate match what the host's limiter will enforce:
+// each chunk costs limit.Cost tokens, the first limit.Burst tokens are
+// available at once and the rest refill at limit.RPS. Unset Cost and Burst
+// default to 1 as on the host.
+//
+//	plan, err := exchange.PlanBackfill(params, 1000, plugin.RateLimit{
+//	    Command: exchange.CMD_GET_OHLCV, RPS: 10, Burst: 20, Cost: 2,
+//	})
+func PlanBackfill(params PlanBackfillParams, maxPerRequest int, limit plugin.RateLimit) (BackfillPlan, error) {
+	if err := params.Validate(); err != nil {
+		return BackfillPlan{}, err
+	}
+	if maxPerRequest <= 0 {
+		return BackfillPlan{}, fmt.Errorf("maxPerRequest must be > 0")
+	}
+	cost := max(limit.Cost, 1)
+	burst := max(limit.Burst, 1)
+
+	tf, err := tt.TimeframeFromString(params.Timeframe)
+	if err != nil {
+		return BackfillPlan{}, fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	plan := BackfillPlan{Command: CMD_GET_OHLCV}
+	for _, r := range utils.SplitRange(*params.StartTime, *params.EndTime, tf, maxPerRequest) {
+		if r.Count == 0 {
+			continue
+		}
+		plan.Chunks = append(plan.Chunks, BackfillChunk{
+			StartTime: r.Start,
+			EndTime:   r.End,
+			Limit:     r.Count,
+			Cost:      cost,
+		})
+		plan.TotalCost += cost
+	}
+
+	if limit.RPS > 0 {
+		// The burst is spent right away, the rest waits for refills
+		plan.EstimatedSeconds = float64(max(plan.TotalCost-burst, 0)) / limit.RPS
+	}
+
+	return plan, nil
+}
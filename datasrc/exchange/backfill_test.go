@@ -0,0 +1,50 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestPlanBackfill(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(250 * time.Hour)
+	params := PlanBackfillParams{Market: tt.Market{Symbol: "BTCUSDT"}, Timeframe: "1h", StartTime: &start, EndTime: &end}
+
+	plan, err := PlanBackfill(params, 100, plugin.RateLimit{Command: CMD_GET_OHLCV, RPS: 0.5, Burst: 2, Cost: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []BackfillChunk{
+		{StartTime: start, EndTime: start.Add(100 * time.Hour), Limit: 100, Cost: 2},
+		{StartTime: start.Add(100 * time.Hour), EndTime: start.Add(200 * time.Hour), Limit: 100, Cost: 2},
+		{StartTime: start.Add(200 * time.Hour), EndTime: end, Limit: 50, Cost: 2},
+	}
+	if len(plan.Chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %+v", len(want), plan.Chunks)
+	}
+	for i, chunk := range plan.Chunks {
+		if !chunk.StartTime.Equal(want[i].StartTime) || !chunk.EndTime.Equal(want[i].EndTime) || chunk.Limit != want[i].Limit || chunk.Cost != want[i].Cost {
+			t.Errorf("chunk %d: expected %+v, got %+v", i, want[i], chunk)
+		}
+	}
+	if plan.TotalCost != 6 {
+		t.Errorf("expected total cost 6, got %d", plan.TotalCost)
+	}
+	// 2 of 6 tokens come from the burst, 4 refill at 0.5/s
+	if plan.EstimatedSeconds != 8 {
+		t.Errorf("expected 8s estimate, got %v", plan.EstimatedSeconds)
+	}
+
+	// Cost and burst default to 1; a range within the burst takes no time
+	plan, err = PlanBackfill(params, 1000, plugin.RateLimit{Command: CMD_GET_OHLCV, RPS: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Chunks) != 1 || plan.TotalCost != 1 || plan.EstimatedSeconds != 0 {
+		t.Errorf("expected one free chunk, got %+v", plan)
+	}
+}
@@ -1,9 +1,18 @@
 package exchange
 
 const (
-	CMD_ACCOUNT_BALANCES = "accountBalances"
-	CMD_GET_MARKETS      = "getMarkets"
-	CMD_GET_TIMEFRAMES   = "getTimeframes"
-	CMD_OHLCV_STREAM     = "ohlcvStream"
-	CMD_GET_OHLCV        = "getOHLCV"
+	CMD_ACCOUNT_BALANCES  = "accountBalances"
+	CMD_GET_MARKETS       = "getMarkets"
+	CMD_GET_MARKETS_DELTA = "getMarketsDelta"
+	CMD_GET_TIMEFRAMES    = "getTimeframes"
+	CMD_OHLCV_STREAM      = "ohlcvStream"
+	CMD_GET_OHLCV         = "getOHLCV"
+	CMD_GET_OHLCV_BATCH   = "getOHLCVBatch"
+	CMD_PLAN_BACKFILL     = "planBackfill"
+
+	CMD_GET_OPTION_CHAIN    = "getOptionChain"
+	CMD_OPTION_CHAIN_STREAM = "optionChainStream"
+
+	CMD_GET_WATCHLISTS = "getWatchlists"
+	CMD_SYNC_WATCHLIST = "syncWatchlist"
 )
@@ -0,0 +1,68 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// PaginateMarkets slices an in-memory market list into a MarketsPage.
+// The cursor is the offset of the next market, encoded as a decimal string.
+// Useful for plugins that receive the full list from the exchange in one
+// request but want to hand it to the host in smaller pages. A cursor that
+// is not a valid offset is an error rather than a restart from the first
+// page, which would make the host loop over the list.
+func PaginateMarkets(markets []tt.Market, params GetMarketsParams) (MarketsPage, error) {
+	offset := 0
+	if params.Cursor != "" {
+		v, err := strconv.Atoi(params.Cursor)
+		if err != nil || v < 0 {
+			return MarketsPage{}, fmt.Errorf("invalid markets cursor %q", params.Cursor)
+		}
+		offset = v
+	}
+	if offset >= len(markets) {
+		return MarketsPage{Markets: []tt.Market{}}, nil
+	}
+
+	end := len(markets)
+	if params.Limit > 0 && offset+params.Limit < end {
+		end = offset + params.Limit
+	}
+
+	page := MarketsPage{Markets: markets[offset:end]}
+	if end < len(markets) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// DiffMarkets compares two market snapshots keyed by symbol and returns the
+// added, changed and removed markets. AsOf is left for the caller to set.
+func DiffMarkets(previous, current []tt.Market) MarketsDelta {
+	prevBySymbol := make(map[string]tt.Market, len(previous))
+	for _, m := range previous {
+		prevBySymbol[m.Symbol] = m
+	}
+
+	delta := MarketsDelta{}
+	seen := make(map[string]bool, len(current))
+	for _, m := range current {
+		seen[m.Symbol] = true
+		old, ok := prevBySymbol[m.Symbol]
+		if !ok {
+			delta.Added = append(delta.Added, m)
+		} else if old != m {
+			delta.Changed = append(delta.Changed, m)
+		}
+	}
+
+	for _, m := range previous {
+		if !seen[m.Symbol] {
+			delta.Removed = append(delta.Removed, m.Symbol)
+		}
+	}
+
+	return delta
+}
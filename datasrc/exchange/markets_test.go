@@ -0,0 +1,26 @@
+package exchange
+
+import (
+	"testing"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestPaginateMarkets(t *testing.T) {
+	markets := []tt.Market{{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "SOLUSDT"}}
+
+	page, err := PaginateMarkets(markets, GetMarketsParams{Limit: 2})
+	if err != nil || len(page.Markets) != 2 || page.NextCursor != "2" {
+		t.Fatalf("unexpected first page %+v (%v)", page, err)
+	}
+	page, err = PaginateMarkets(markets, GetMarketsParams{Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(page.Markets) != 1 || page.Markets[0].Symbol != "SOLUSDT" || page.NextCursor != "" {
+		t.Fatalf("unexpected last page %+v (%v)", page, err)
+	}
+
+	for _, cursor := range []string{"abc", "-1", "1.5"} {
+		if _, err := PaginateMarkets(markets, GetMarketsParams{Cursor: cursor}); err == nil {
+			t.Errorf("expected error for cursor %q", cursor)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"fmt"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	tu "github.com/plusev-terminal/go-plugin-common/trading/utils"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// OHLCVFetchFunc fetches a single page of candles from the exchange.
+// The params passed in never ask for more than the paginator's maxPerRequest.
+type OHLCVFetchFunc func(params GetOHLCVParams) ([]tt.OHLCVRecord, error)
+
+// OHLCVPaginator splits a historical getOHLCV request into exchange-sized
+// sub-requests and assembles the sanitized result.
+//
+// Example:
+//
+//	p := exchange.NewOHLCVPaginator(1000, c.fetchKlines)
+//	candles, err := p.Fetch(exchange.GetOHLCVParamsFromMap(params))
+type OHLCVPaginator struct {
+	maxPerRequest int
+	fetch         OHLCVFetchFunc
+}
+
+// NewOHLCVPaginator creates a paginator for an exchange that returns at most
+// maxPerRequest candles per call
+func NewOHLCVPaginator(maxPerRequest int, fetch OHLCVFetchFunc) *OHLCVPaginator {
+	return &OHLCVPaginator{
+		maxPerRequest: maxPerRequest,
+		fetch:         fetch,
+	}
+}
+
+// Fetch retrieves all candles for the params range.
+//
+// Without a StartTime the request is passed through as a single fetch. With
+// a StartTime but no EndTime, pages are fetched forward until the exchange
+// returns a short page or Limit candles have been collected.
+func (p *OHLCVPaginator) Fetch(params GetOHLCVParams) ([]tt.OHLCVRecord, error) {
+	if p.maxPerRequest <= 0 {
+		return nil, fmt.Errorf("maxPerRequest must be > 0")
+	}
+
+	tf, err := tt.TimeframeFromString(params.Timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	if params.StartTime == nil {
+		sub := params
+		if sub.Limit <= 0 || sub.Limit > p.maxPerRequest {
+			sub.Limit = p.maxPerRequest
+		}
+		return p.fetch(sub)
+	}
+
+	sanitizer := tu.NewOHLCVSanitizer(tf)
+	result := make([]tt.OHLCVRecord, 0)
+
+	appendBatch := func(batch []tt.OHLCVRecord, end *time.Time) error {
+		clean, err := sanitizer.SanitizeBatch(batch)
+		if err != nil {
+			return err
+		}
+		for _, c := range clean {
+			if c.OpenTime < params.StartTime.Unix() || (end != nil && c.OpenTime >= end.Unix()) {
+				continue
+			}
+			result = append(result, c)
+		}
+		sanitizer.Release(clean)
+		return nil
+	}
+
+	if params.EndTime != nil {
+		for _, r := range utils.SplitRange(*params.StartTime, *params.EndTime, tf, p.maxPerRequest) {
+			if r.Count == 0 {
+				continue
+			}
+			batch, err := p.fetch(p.subRequest(params, r.Start, r.End, r.Count))
+			if err != nil {
+				return nil, err
+			}
+			if err := appendBatch(batch, params.EndTime); err != nil {
+				return nil, err
+			}
+			if params.Limit > 0 && len(result) >= params.Limit {
+				break
+			}
+		}
+		return truncateCandles(result, params.Limit), nil
+	}
+
+	for cursor := *params.StartTime; ; {
+		batch, err := p.fetch(p.subRequest(params, cursor, time.Time{}, p.maxPerRequest))
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err := appendBatch(batch, nil); err != nil {
+			return nil, err
+		}
+
+		last := sanitizer.GetLastCandle()
+		next := tf.Advance(time.Unix(last.OpenTime, 0).UTC(), 1)
+		if len(batch) < p.maxPerRequest || !next.After(cursor) {
+			break
+		}
+		if params.Limit > 0 && len(result) >= params.Limit {
+			break
+		}
+		cursor = next
+	}
+
+	return truncateCandles(result, params.Limit), nil
+}
+
+// subRequest derives a single page request from the original params.
+// A zero end leaves EndTime unset.
+func (p *OHLCVPaginator) subRequest(params GetOHLCVParams, start, end time.Time, limit int) GetOHLCVParams {
+	sub := params
+	sub.StartTime = &start
+	sub.EndTime = nil
+	if !end.IsZero() {
+		sub.EndTime = &end
+	}
+	sub.Limit = limit
+	return sub
+}
+
+func truncateCandles(candles []tt.OHLCVRecord, limit int) []tt.OHLCVRecord {
+	if limit > 0 && len(candles) > limit {
+		return candles[:limit]
+	}
+	return candles
+}
@@ -0,0 +1,84 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// fakeExchange serves 5m candles for any requested range, honoring the limit
+func fakeExchange(calls *int) OHLCVFetchFunc {
+	return func(params GetOHLCVParams) ([]tt.OHLCVRecord, error) {
+		*calls++
+		var out []tt.OHLCVRecord
+		for ts := params.StartTime.Unix(); len(out) < params.Limit; ts += 300 {
+			if params.EndTime != nil && ts >= params.EndTime.Unix() {
+				break
+			}
+			if ts >= 6000 {
+				break
+			}
+			out = append(out, tt.OHLCVRecord{OpenTime: ts, Open: "1", High: "1", Low: "1", Close: "1", Volume: "1"})
+		}
+		return out, nil
+	}
+}
+
+func TestOHLCVPaginator_Range(t *testing.T) {
+	calls := 0
+	p := NewOHLCVPaginator(2, fakeExchange(&calls))
+
+	start := time.Unix(3000, 0).UTC()
+	end := time.Unix(4500, 0).UTC() // 5 candles: 3000..4200
+	candles, err := p.Fetch(GetOHLCVParams{Timeframe: "5m", StartTime: &start, EndTime: &end})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("Expected 3 sub-requests, got %d", calls)
+	}
+	if len(candles) != 5 {
+		t.Fatalf("Expected 5 candles, got %d", len(candles))
+	}
+	for i, c := range candles {
+		if expected := int64(3000 + i*300); c.OpenTime != expected {
+			t.Fatalf("Expected openTime %d at index %d, got %d", expected, i, c.OpenTime)
+		}
+	}
+}
+
+func TestOHLCVPaginator_OpenEnded(t *testing.T) {
+	calls := 0
+	p := NewOHLCVPaginator(4, fakeExchange(&calls))
+
+	start := time.Unix(3000, 0).UTC()
+	candles, err := p.Fetch(GetOHLCVParams{Timeframe: "5m", StartTime: &start})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 3000..5700 are 10 candles; pages of 4, 4, 2
+	if len(candles) != 10 {
+		t.Fatalf("Expected 10 candles, got %d", len(candles))
+	}
+	if calls != 3 {
+		t.Fatalf("Expected 3 sub-requests, got %d", calls)
+	}
+}
+
+func TestOHLCVPaginator_Limit(t *testing.T) {
+	calls := 0
+	p := NewOHLCVPaginator(2, fakeExchange(&calls))
+
+	start := time.Unix(3000, 0).UTC()
+	candles, err := p.Fetch(GetOHLCVParams{Timeframe: "5m", StartTime: &start, Limit: 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(candles) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(candles))
+	}
+}
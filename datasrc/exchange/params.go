@@ -91,3 +91,260 @@ func AccountBalancesParamsFromMap(data map[string]any) AccountBalancesParams {
 	}
 	return params
 }
+
+// GetMarketsParams contains parameters for the getMarkets command.
+// Both fields are optional: an empty Cursor requests the first page and a
+// zero Limit lets the plugin return all markets in a single page.
+type GetMarketsParams struct {
+	Cursor string `json:"cursor,omitempty" mapstructure:"cursor"`
+	Limit  int    `json:"limit,omitempty" mapstructure:"limit"`
+}
+
+func (p GetMarketsParams) Validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	return nil
+}
+
+// GetMarketsParamsFromMap extracts GetMarketsParams from validated map
+func GetMarketsParamsFromMap(data map[string]any) GetMarketsParams {
+	return GetMarketsParams{
+		Cursor: utils.GetValue[string]("cursor", data),
+		Limit:  utils.ExtractInt("limit", data),
+	}
+}
+
+// GetMarketsDeltaParams contains parameters for the getMarketsDelta command.
+// Since is the AsOf value of the previous MarketsDelta (or of the full
+// market list) the host already holds.
+type GetMarketsDeltaParams struct {
+	Since *time.Time `json:"since" mapstructure:"since" validate:"required"`
+}
+
+func (p GetMarketsDeltaParams) Validate() error {
+	if p.Since == nil {
+		return fmt.Errorf("since is required")
+	}
+	return nil
+}
+
+// GetMarketsDeltaParamsFromMap extracts GetMarketsDeltaParams from validated map
+func GetMarketsDeltaParamsFromMap(data map[string]any) GetMarketsDeltaParams {
+	return GetMarketsDeltaParams{
+		Since: utils.ExtractTime("since", data),
+	}
+}
+
+// GetOHLCVBatchParams contains parameters for the getOHLCVBatch command.
+// All markets share the same timeframe, time range and limit.
+type GetOHLCVBatchParams struct {
+	Markets         []tt.Market `json:"markets" mapstructure:"markets" validate:"required,min=1"`
+	Timeframe       string      `json:"timeframe" mapstructure:"timeframe" validate:"required"`
+	StartTime       *time.Time  `json:"startTime,omitempty" mapstructure:"startTime"`
+	EndTime         *time.Time  `json:"endTime,omitempty" mapstructure:"endTime"`
+	Limit           int         `json:"limit,omitempty" mapstructure:"limit"`
+	CacheForSeconds int         `json:"cacheFor,omitempty" mapstructure:"cacheFor"` // in seconds
+}
+
+func (p GetOHLCVBatchParams) Validate() error {
+	if p.Timeframe == "" {
+		return fmt.Errorf("timeframe is required")
+	}
+	if len(p.Markets) == 0 {
+		return fmt.Errorf("markets must contain at least one market")
+	}
+	for i, m := range p.Markets {
+		if m.Symbol == "" {
+			return fmt.Errorf("markets[%d].symbol is required", i)
+		}
+	}
+	return nil
+}
+
+// Split expands the batch into one GetOHLCVParams per market, so plugins can
+// reuse their single-market getOHLCV implementation.
+func (p GetOHLCVBatchParams) Split() []GetOHLCVParams {
+	params := make([]GetOHLCVParams, 0, len(p.Markets))
+	for _, m := range p.Markets {
+		params = append(params, GetOHLCVParams{
+			Market:          m,
+			Timeframe:       p.Timeframe,
+			StartTime:       p.StartTime,
+			EndTime:         p.EndTime,
+			Limit:           p.Limit,
+			CacheForSeconds: p.CacheForSeconds,
+		})
+	}
+	return params
+}
+
+// GetOHLCVBatchParamsFromMap extracts GetOHLCVBatchParams from validated map
+func GetOHLCVBatchParamsFromMap(data map[string]any) GetOHLCVBatchParams {
+	params := GetOHLCVBatchParams{
+		Timeframe:       utils.GetValue[string]("timeframe", data),
+		StartTime:       utils.ExtractTime("startTime", data),
+		EndTime:         utils.ExtractTime("endTime", data),
+		Limit:           utils.ExtractInt("limit", data),
+		CacheForSeconds: utils.ExtractInt("cacheFor", data),
+	}
+	if list, ok := data["markets"].([]any); ok {
+		for _, item := range list {
+			v, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			var market tt.Market
+			_ = utils.MapToStruct(v, &market)
+			params.Markets = append(params.Markets, market)
+		}
+	}
+	return params
+}
+
+// PlanBackfillParams contains parameters for the planBackfill command.
+// The plugin answers with a BackfillPlan describing how the requested range
+// should be fetched via getOHLCV.
+type PlanBackfillParams struct {
+	Market    tt.Market  `json:"market" mapstructure:"market" validate:"required"`
+	Timeframe string     `json:"timeframe" mapstructure:"timeframe" validate:"required"`
+	StartTime *time.Time `json:"startTime" mapstructure:"startTime" validate:"required"`
+	EndTime   *time.Time `json:"endTime" mapstructure:"endTime" validate:"required"`
+}
+
+func (p PlanBackfillParams) Validate() error {
+	if p.Timeframe == "" {
+		return fmt.Errorf("timeframe is required")
+	}
+	if p.Market.Symbol == "" {
+		return fmt.Errorf("market.symbol is required")
+	}
+	if p.StartTime == nil || p.EndTime == nil {
+		return fmt.Errorf("startTime and endTime are required")
+	}
+	if !p.EndTime.After(*p.StartTime) {
+		return fmt.Errorf("endTime must be after startTime")
+	}
+	return nil
+}
+
+// PlanBackfillParamsFromMap extracts PlanBackfillParams from validated map
+func PlanBackfillParamsFromMap(data map[string]any) PlanBackfillParams {
+	params := PlanBackfillParams{
+		Timeframe: utils.GetValue[string]("timeframe", data),
+		StartTime: utils.ExtractTime("startTime", data),
+		EndTime:   utils.ExtractTime("endTime", data),
+	}
+	if v, ok := data["market"].(map[string]any); ok {
+		_ = utils.MapToStruct(v, &params.Market)
+	}
+	return params
+}
+
+// GetOptionChainParams contains parameters for the getOptionChain command.
+// All filters are optional; without them the complete chain of the
+// underlying is returned.
+type GetOptionChainParams struct {
+	Underlying string     `json:"underlying" mapstructure:"underlying" validate:"required"`
+	Expiry     *time.Time `json:"expiry,omitempty" mapstructure:"expiry"` // Only contracts expiring on this date
+	Type       OptionType `json:"type,omitempty" mapstructure:"type"`
+	MinStrike  string     `json:"minStrike,omitempty" mapstructure:"minStrike"`
+	MaxStrike  string     `json:"maxStrike,omitempty" mapstructure:"maxStrike"`
+}
+
+func (p GetOptionChainParams) Validate() error {
+	if p.Underlying == "" {
+		return fmt.Errorf("underlying is required")
+	}
+	if p.Type != "" && p.Type != OptionCall && p.Type != OptionPut {
+		return fmt.Errorf("type must be %q or %q", OptionCall, OptionPut)
+	}
+	return nil
+}
+
+// GetOptionChainParamsFromMap extracts GetOptionChainParams from validated map
+func GetOptionChainParamsFromMap(data map[string]any) GetOptionChainParams {
+	return GetOptionChainParams{
+		Underlying: utils.GetValue[string]("underlying", data),
+		Expiry:     utils.ExtractTime("expiry", data),
+		Type:       OptionType(utils.GetValue[string]("type", data)),
+		MinStrike:  utils.GetValue[string]("minStrike", data),
+		MaxStrike:  utils.GetValue[string]("maxStrike", data),
+	}
+}
+
+// OptionChainStreamParams contains parameters for the optionChainStream command
+type OptionChainStreamParams struct {
+	Underlying string     `json:"underlying" mapstructure:"underlying" validate:"required"`
+	Expiry     *time.Time `json:"expiry,omitempty" mapstructure:"expiry"`
+}
+
+func (p OptionChainStreamParams) Validate() error {
+	if p.Underlying == "" {
+		return fmt.Errorf("underlying is required")
+	}
+	return nil
+}
+
+// OptionChainStreamParamsFromMap extracts OptionChainStreamParams from validated map
+func OptionChainStreamParamsFromMap(data map[string]any) OptionChainStreamParams {
+	return OptionChainStreamParams{
+		Underlying: utils.GetValue[string]("underlying", data),
+		Expiry:     utils.ExtractTime("expiry", data),
+	}
+}
+
+// WatchlistSyncMode controls how syncWatchlist applies the terminal list
+type WatchlistSyncMode string
+
+const (
+	WatchlistReplace WatchlistSyncMode = "replace" // The exchange list becomes identical to the terminal list
+	WatchlistMerge   WatchlistSyncMode = "merge"   // Symbols are added, none are removed
+)
+
+// GetWatchlistsParams contains parameters for the getWatchlists command.
+// An empty IDs list returns all watchlists.
+type GetWatchlistsParams struct {
+	IDs []string `json:"ids,omitempty" mapstructure:"ids"`
+}
+
+func (p GetWatchlistsParams) Validate() error {
+	return nil
+}
+
+// GetWatchlistsParamsFromMap extracts GetWatchlistsParams from validated map
+func GetWatchlistsParamsFromMap(data map[string]any) GetWatchlistsParams {
+	var params GetWatchlistsParams
+	_ = utils.MapToStruct(data, &params)
+	return params
+}
+
+// SyncWatchlistParams contains parameters for the syncWatchlist command,
+// which pushes a terminal watchlist to the exchange. A Watchlist without ID
+// creates a new exchange-side list.
+type SyncWatchlistParams struct {
+	Watchlist Watchlist         `json:"watchlist" mapstructure:"watchlist" validate:"required"`
+	Mode      WatchlistSyncMode `json:"mode,omitempty" mapstructure:"mode"` // Defaults to WatchlistReplace
+}
+
+func (p SyncWatchlistParams) Validate() error {
+	if p.Watchlist.ID == "" && p.Watchlist.Name == "" {
+		return fmt.Errorf("watchlist.id or watchlist.name is required")
+	}
+	switch p.Mode {
+	case "", WatchlistReplace, WatchlistMerge:
+	default:
+		return fmt.Errorf("mode must be %q or %q", WatchlistReplace, WatchlistMerge)
+	}
+	return nil
+}
+
+// SyncWatchlistParamsFromMap extracts SyncWatchlistParams from validated map
+func SyncWatchlistParamsFromMap(data map[string]any) SyncWatchlistParams {
+	var params SyncWatchlistParams
+	_ = utils.MapToStruct(data, &params)
+	if params.Mode == "" {
+		params.Mode = WatchlistReplace
+	}
+	return params
+}
@@ -0,0 +1,167 @@
+package exchange
+
+import (
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// MarketsPage is the response data of a paginated getMarkets command.
+// An empty NextCursor means the last page has been reached.
+type MarketsPage struct {
+	Markets    []tt.Market `json:"markets"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// MarketsDelta is the response data of the getMarketsDelta command.
+//
+// If Reset is true the plugin could not compute a delta for the requested
+// Since value and Added contains the complete market list, which replaces
+// everything the host has stored so far.
+type MarketsDelta struct {
+	Added   []tt.Market `json:"added,omitempty"`
+	Changed []tt.Market `json:"changed,omitempty"`
+	Removed []string    `json:"removed,omitempty"` // Symbols of markets that no longer exist
+	Reset   bool        `json:"reset,omitempty"`
+	AsOf    time.Time   `json:"asOf"` // Pass as Since on the next getMarketsDelta call
+}
+
+// IsEmpty reports whether the delta contains no changes
+func (d MarketsDelta) IsEmpty() bool {
+	return !d.Reset && len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// OHLCVBatchEntry holds the result for a single market of a getOHLCVBatch call.
+// A failure for one market does not fail the whole batch; Error is set instead.
+type OHLCVBatchEntry struct {
+	Candles []tt.OHLCVRecord `json:"candles"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// OHLCVBatchResult is the response data of the getOHLCVBatch command, keyed by market symbol
+type OHLCVBatchResult map[string]OHLCVBatchEntry
+
+// Set stores the candles fetched for a market symbol
+func (r OHLCVBatchResult) Set(symbol string, candles []tt.OHLCVRecord) {
+	r[symbol] = OHLCVBatchEntry{Candles: candles}
+}
+
+// SetError records a fetch failure for a market symbol
+func (r OHLCVBatchResult) SetError(symbol string, err error) {
+	r[symbol] = OHLCVBatchEntry{Candles: []tt.OHLCVRecord{}, Error: err.Error()}
+}
+
+// BackfillChunk is a single sub-request of a BackfillPlan
+type BackfillChunk struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"` // exclusive
+	Limit     int       `json:"limit"`   // Candles expected in this chunk, pass as getOHLCV limit
+	Cost      int       `json:"cost"`    // Rate limit tokens this chunk consumes
+}
+
+// BackfillPlan is the response data of the planBackfill command
+type BackfillPlan struct {
+	Command          string          `json:"command"` // Command the host should call per chunk, usually getOHLCV
+	Chunks           []BackfillChunk `json:"chunks"`
+	TotalCost        int             `json:"totalCost"`
+	EstimatedSeconds float64         `json:"estimatedSeconds"` // Minimum duration when staying within the rate limit
+}
+
+// OptionType distinguishes calls from puts
+type OptionType string
+
+const (
+	OptionCall OptionType = "call"
+	OptionPut  OptionType = "put"
+)
+
+// Greeks are the sensitivities of an option price. Like prices they are
+// strings to preserve the precision reported by the exchange.
+type Greeks struct {
+	Delta string `json:"delta,omitempty"`
+	Gamma string `json:"gamma,omitempty"`
+	Theta string `json:"theta,omitempty"` // Per day
+	Vega  string `json:"vega,omitempty"`  // Per 1 vol point
+	Rho   string `json:"rho,omitempty"`
+}
+
+// OptionContract is a single listed option with its latest quote
+type OptionContract struct {
+	Symbol          string     `json:"symbol"`     // Exchange symbol, e.g. "BTC-27DEC24-60000-C"
+	Underlying      string     `json:"underlying"` // e.g. "BTC"
+	Strike          string     `json:"strike"`
+	Expiry          time.Time  `json:"expiry"`
+	Type            OptionType `json:"type"`
+	SettleAsset     string     `json:"settleAsset,omitempty"` // e.g. "BTC" for inverse, "USDC" for linear contracts
+	ContractSize    string     `json:"contractSize,omitempty"`
+	Bid             string     `json:"bid,omitempty"`
+	Ask             string     `json:"ask,omitempty"`
+	Mark            string     `json:"mark,omitempty"`
+	Last            string     `json:"last,omitempty"`
+	BidIV           string     `json:"bidIv,omitempty"`
+	AskIV           string     `json:"askIv,omitempty"`
+	MarkIV          string     `json:"markIv,omitempty"` // Implied volatility as a fraction, e.g. "0.55"
+	Greeks          Greeks     `json:"greeks"`
+	OpenInterest    string     `json:"openInterest,omitempty"`
+	Volume          string     `json:"volume,omitempty"` // 24h, in contracts
+	UnderlyingPrice string     `json:"underlyingPrice,omitempty"`
+	Timestamp       time.Time  `json:"timestamp"`
+}
+
+// OptionChain is the response data of the getOptionChain command
+type OptionChain struct {
+	Underlying string           `json:"underlying"`
+	Expiries   []time.Time      `json:"expiries"` // All listed expiries, also those filtered out of Contracts
+	Contracts  []OptionContract `json:"contracts"`
+	AsOf       time.Time        `json:"asOf"`
+}
+
+// OptionChainUpdate is a payload of the optionChainStream command. Only
+// contracts whose quote changed are included; Removed lists the symbols of
+// contracts that expired or were delisted.
+type OptionChainUpdate struct {
+	Underlying string           `json:"underlying"`
+	Contracts  []OptionContract `json:"contracts,omitempty"`
+	Removed    []string         `json:"removed,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// Watchlist is a list of favorite markets kept on the exchange side.
+// Symbols are market symbols as returned by getMarkets, in display order.
+type Watchlist struct {
+	ID        string    `json:"id" mapstructure:"id"` // Exchange-side ID, empty for lists not yet pushed
+	Name      string    `json:"name" mapstructure:"name"`
+	Symbols   []string  `json:"symbols" mapstructure:"symbols"`
+	ReadOnly  bool      `json:"readOnly,omitempty" mapstructure:"readOnly"` // The exchange does not allow editing this list
+	UpdatedAt time.Time `json:"updatedAt,omitempty" mapstructure:"updatedAt"`
+}
+
+// SyncWatchlistResult is the response data of the syncWatchlist command
+type SyncWatchlistResult struct {
+	Watchlist Watchlist `json:"watchlist"` // State on the exchange after the sync, with ID set
+	Added     []string  `json:"added,omitempty"`
+	Removed   []string  `json:"removed,omitempty"`
+	Skipped   []string  `json:"skipped,omitempty"` // Symbols the exchange does not accept in watchlists
+}
+
+// DiffSymbols returns the symbols of next missing in current (added) and
+// the symbols of current missing in next (removed)
+func DiffSymbols(current, next []string) (added, removed []string) {
+	have := make(map[string]bool, len(current))
+	for _, s := range current {
+		have[s] = true
+	}
+	want := make(map[string]bool, len(next))
+	for _, s := range next {
+		want[s] = true
+		if !have[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range current {
+		if !want[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,296 @@
+// Package fileimport reads OHLCV data from local files the host mounted for
+// the plugin, so personal datasets can be plugged into the terminal through a
+// generic file plugin. Plain CSV files and zip archives of CSV files are
+// supported; Parquet is not, as it needs a decoder too large for WASM
+// plugins.
+//
+//	records, err := fileimport.ReadFile(pluginMeta.Resources, "/data/btc_1h.csv", fileimport.Options{
+//	    Columns:    fileimport.Columns{Time: "date", Volume: "vol"},
+//	    TimeFormat: "2006-01-02 15:04",
+//	})
+package fileimport
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/meta"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Time formats understood besides Go time layouts
+const (
+	TimeUnix      = "unix"    // Seconds since the epoch
+	TimeUnixMilli = "unix_ms" // Milliseconds since the epoch
+)
+
+// ErrNoAccess is returned for paths outside the directories the plugin
+// declared in ResourceAccess.FsWriteAccess
+var ErrNoAccess = errors.New("fileimport: path is outside the declared fs access")
+
+// Columns maps the OHLCV fields to CSV header names, matched
+// case-insensitively. Empty fields use the names of DefaultColumns; an empty
+// Volume column in the file yields a volume of "0".
+type Columns struct {
+	Time   string `json:"time,omitempty"`
+	Open   string `json:"open,omitempty"`
+	High   string `json:"high,omitempty"`
+	Low    string `json:"low,omitempty"`
+	Close  string `json:"close,omitempty"`
+	Volume string `json:"volume,omitempty"`
+}
+
+// DefaultColumns is the mapping used for fields left empty in Options.Columns
+var DefaultColumns = Columns{
+	Time:   "time",
+	Open:   "open",
+	High:   "high",
+	Low:    "low",
+	Close:  "close",
+	Volume: "volume",
+}
+
+// Options configures how a CSV file is read
+type Options struct {
+	Columns Columns `json:"columns"`
+	// NoHeader is set for files without header row. Columns are then
+	// expected in the order time, open, high, low, close, volume.
+	NoHeader bool `json:"noHeader,omitempty"`
+	// Comma is the field delimiter, defaults to ','
+	Comma rune `json:"comma,omitempty"`
+	// TimeFormat is TimeUnix, TimeUnixMilli or a Go time layout. If empty,
+	// numbers are read as Unix seconds or milliseconds depending on their
+	// magnitude and text as RFC 3339 or "2006-01-02 15:04:05".
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// Location is used for layouts without zone, defaults to UTC
+	Location *time.Location `json:"-"`
+}
+
+// CheckAccess returns ErrNoAccess unless p lies within one of the guest
+// directories (the map values) of access.FsWriteAccess
+func CheckAccess(access meta.ResourceAccess, p string) error {
+	clean := path.Clean("/" + strings.TrimPrefix(p, "/"))
+	for _, dir := range access.FsWriteAccess {
+		root := path.Clean("/" + strings.TrimPrefix(dir, "/"))
+		if clean == root || strings.HasPrefix(clean, strings.TrimSuffix(root, "/")+"/") {
+			return nil
+		}
+	}
+	return ErrNoAccess
+}
+
+// ReadFile reads the OHLCV records of a CSV file, or of all CSV files in a
+// zip archive, after checking p against the declared fs access. Records are
+// returned in ascending time order.
+func ReadFile(access meta.ResourceAccess, p string, opts Options) ([]tt.OHLCVRecord, error) {
+	if err := CheckAccess(access, p); err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(path.Ext(p), ".zip") {
+		return readZip(p, opts)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSV(f, opts)
+}
+
+func readZip(p string, opts Options) ([]tt.OHLCVRecord, error) {
+	archive, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var records []tt.OHLCVRecord
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".csv") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		part, err := ReadCSV(rc, opts)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		records = append(records, part...)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// ReadCSV reads OHLCV records from CSV data. Records are returned in
+// ascending time order.
+func ReadCSV(r io.Reader, opts Options) ([]tt.OHLCVRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	index := [6]int{0, 1, 2, 3, 4, 5}
+	if !opts.NoHeader {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+		if index, err = columnIndex(header, opts.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []tt.OHLCVRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		record, err := parseRow(row, index, opts)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// columnIndex resolves the positions of the mapped columns in header.
+// The volume column may be missing (-1).
+func columnIndex(header []string, cols Columns) ([6]int, error) {
+	names := [6]string{cols.Time, cols.Open, cols.High, cols.Low, cols.Close, cols.Volume}
+	defaults := [6]string{DefaultColumns.Time, DefaultColumns.Open, DefaultColumns.High, DefaultColumns.Low, DefaultColumns.Close, DefaultColumns.Volume}
+
+	var index [6]int
+	for i, name := range names {
+		if name == "" {
+			name = defaults[i]
+		}
+		index[i] = -1
+		for j, h := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name) {
+				index[i] = j
+				break
+			}
+		}
+		if index[i] < 0 && i < 5 {
+			return index, fmt.Errorf("column %q not found in header", name)
+		}
+	}
+	return index, nil
+}
+
+func parseRow(row []string, index [6]int, opts Options) (tt.OHLCVRecord, error) {
+	field := func(i int) (string, error) {
+		if index[i] < 0 {
+			return "0", nil
+		}
+		if index[i] >= len(row) {
+			return "", fmt.Errorf("expected at least %d fields, got %d", index[i]+1, len(row))
+		}
+		return strings.TrimSpace(row[index[i]]), nil
+	}
+
+	raw, err := field(0)
+	if err != nil {
+		return tt.OHLCVRecord{}, err
+	}
+	openTime, err := parseTime(raw, opts)
+	if err != nil {
+		return tt.OHLCVRecord{}, err
+	}
+
+	var values [5]string
+	for i := range values {
+		v, err := field(i + 1)
+		if err != nil {
+			return tt.OHLCVRecord{}, err
+		}
+		if v == "" && i == 4 {
+			v = "0"
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return tt.OHLCVRecord{}, fmt.Errorf("invalid number %q", v)
+		}
+		values[i] = v
+	}
+
+	return tt.OHLCVRecord{
+		OpenTime: openTime.Unix(),
+		Open:     values[0],
+		High:     values[1],
+		Low:      values[2],
+		Close:    values[3],
+		Volume:   values[4],
+	}, nil
+}
+
+func parseTime(v string, opts Options) (time.Time, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch opts.TimeFormat {
+	case TimeUnix, TimeUnixMilli:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+		}
+		if opts.TimeFormat == TimeUnix {
+			return time.Unix(n, 0), nil
+		}
+		return time.UnixMilli(n), nil
+	case "":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			// Seconds stay below 1e11 until the year 5138
+			if n < 1e11 {
+				return time.Unix(n, 0), nil
+			}
+			return time.UnixMilli(n), nil
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognized time %q", v)
+	default:
+		t, err := time.ParseInLocation(opts.TimeFormat, v, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q: %w", v, err)
+		}
+		return t, nil
+	}
+}
+
+func sortRecords(records []tt.OHLCVRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].OpenTime < records[j].OpenTime
+	})
+}
@@ -0,0 +1,51 @@
+package fileimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/meta"
+)
+
+func TestReadCSVColumnMapping(t *testing.T) {
+	data := "Date;O;H;L;C\n" +
+		"2024-01-02 00:00;2;3;1;2.5\n" +
+		"2024-01-01 00:00;1;2;0.5;1.5\n"
+
+	records, err := ReadCSV(strings.NewReader(data), Options{
+		Columns:    Columns{Time: "date", Open: "o", High: "h", Low: "l", Close: "c", Volume: "v"},
+		Comma:      ';',
+		TimeFormat: "2006-01-02 15:04",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	first := records[0]
+	if first.OpenTime != time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix() || first.Close != "1.5" || first.Volume != "0" {
+		t.Fatalf("unexpected first record %+v", first)
+	}
+}
+
+func TestReadCSVReportsLine(t *testing.T) {
+	data := "time,open,high,low,close,volume\n1700000000,1,2,0.5,1.5,10\n1700003600,1,x,0.5,1.5,10\n"
+	_, err := ReadCSV(strings.NewReader(data), Options{})
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected error on line 3, got %v", err)
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	access := meta.ResourceAccess{FsWriteAccess: map[string]string{"/home/user/data": "/data"}}
+	if err := CheckAccess(access, "/data/btc.csv"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/database/x.csv", "/data/../etc/passwd", "/other.csv"} {
+		if err := CheckAccess(access, p); err != ErrNoAccess {
+			t.Fatalf("expected ErrNoAccess for %s, got %v", p, err)
+		}
+	}
+}
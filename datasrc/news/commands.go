@@ -0,0 +1,6 @@
+package news
+
+const (
+	CMD_GET_NEWS    = "getNews"
+	CMD_NEWS_STREAM = "newsStream"
+)
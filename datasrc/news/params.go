@@ -0,0 +1,104 @@
+package news
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// GetNewsParams contains parameters for the getNews command. All fields are
+// optional; an empty Cursor requests the newest page.
+type GetNewsParams struct {
+	Symbols    []string   `json:"symbols,omitempty" mapstructure:"symbols"`
+	Categories []string   `json:"categories,omitempty" mapstructure:"categories"`
+	Since      *time.Time `json:"since,omitempty" mapstructure:"since"`
+	Until      *time.Time `json:"until,omitempty" mapstructure:"until"`
+	Language   string     `json:"language,omitempty" mapstructure:"language"`
+	Cursor     string     `json:"cursor,omitempty" mapstructure:"cursor"`
+	Limit      int        `json:"limit,omitempty" mapstructure:"limit"`
+}
+
+func (p GetNewsParams) Validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	if p.Since != nil && p.Until != nil && !p.Until.After(*p.Since) {
+		return fmt.Errorf("until must be after since")
+	}
+	return nil
+}
+
+// GetNewsParamsFromMap extracts GetNewsParams from validated map
+func GetNewsParamsFromMap(data map[string]any) GetNewsParams {
+	return GetNewsParams{
+		Symbols:    stringList("symbols", data),
+		Categories: stringList("categories", data),
+		Since:      utils.ExtractTime("since", data),
+		Until:      utils.ExtractTime("until", data),
+		Language:   utils.GetValue[string]("language", data),
+		Cursor:     utils.GetValue[string]("cursor", data),
+		Limit:      utils.ExtractInt("limit", data),
+	}
+}
+
+// NewsStreamParams contains parameters for the newsStream command. Empty
+// filters stream all news.
+type NewsStreamParams struct {
+	Symbols       []string `json:"symbols,omitempty" mapstructure:"symbols"`
+	Categories    []string `json:"categories,omitempty" mapstructure:"categories"`
+	MinImportance int      `json:"minImportance,omitempty" mapstructure:"minImportance"`
+}
+
+func (p NewsStreamParams) Validate() error {
+	if p.MinImportance < 0 || p.MinImportance > 3 {
+		return fmt.Errorf("minImportance must be between 0 and 3")
+	}
+	return nil
+}
+
+// NewsStreamParamsFromMap extracts NewsStreamParams from validated map
+func NewsStreamParamsFromMap(data map[string]any) NewsStreamParams {
+	return NewsStreamParams{
+		Symbols:       stringList("symbols", data),
+		Categories:    stringList("categories", data),
+		MinImportance: utils.ExtractInt("minImportance", data),
+	}
+}
+
+// Matches reports whether item passes the stream filters
+func (p NewsStreamParams) Matches(item NewsItem) bool {
+	if item.Importance < p.MinImportance {
+		return false
+	}
+	return overlaps(p.Symbols, item.Symbols) && overlaps(p.Categories, item.Categories)
+}
+
+// overlaps reports whether filter is empty or shares an element with values
+func overlaps(filter, values []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		for _, v := range values {
+			if f == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stringList(key string, data map[string]any) []string {
+	list, ok := data[key].([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
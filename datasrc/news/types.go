@@ -0,0 +1,49 @@
+package news
+
+import (
+	"time"
+)
+
+// Sentiment labels derived from the score
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// NewsItem is a single news article or post
+type NewsItem struct {
+	ID          string    `json:"id"`
+	Headline    string    `json:"headline"`
+	Summary     string    `json:"summary,omitempty"`
+	Source      string    `json:"source"` // Publisher, e.g. "CoinDesk"
+	URL         string    `json:"url"`
+	ImageURL    string    `json:"imageUrl,omitempty"`
+	Symbols     []string  `json:"symbols,omitempty"`    // Related assets or market symbols
+	Categories  []string  `json:"categories,omitempty"` // e.g. "regulation", "earnings"
+	Sentiment   *float64  `json:"sentiment,omitempty"`  // -1 (negative) to 1 (positive); nil if not scored
+	Importance  int       `json:"importance,omitempty"` // 0 (unknown) to 3 (high)
+	Language    string    `json:"language,omitempty"`   // ISO 639-1, e.g. "en"
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// SentimentLabel classifies the sentiment score. Scores within ±0.2 are
+// neutral; unscored items return an empty label.
+func (n NewsItem) SentimentLabel() string {
+	switch {
+	case n.Sentiment == nil:
+		return ""
+	case *n.Sentiment > 0.2:
+		return SentimentPositive
+	case *n.Sentiment < -0.2:
+		return SentimentNegative
+	}
+	return SentimentNeutral
+}
+
+// NewsPage is the response data of the getNews command. An empty NextCursor
+// means the last page has been reached.
+type NewsPage struct {
+	Items      []NewsItem `json:"items"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,9 @@
+package onchain
+
+const (
+	CMD_GET_BLOCKS       = "getBlocks"
+	CMD_GET_TRANSFERS    = "getTransfers"
+	CMD_GET_HOLDER_STATS = "getHolderStats"
+	CMD_GET_TVL          = "getTVL"
+	CMD_GET_METRIC       = "getMetric"
+)
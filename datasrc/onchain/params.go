@@ -0,0 +1,161 @@
+package onchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// GetBlocksParams contains parameters for the getBlocks command. Without
+// FromNumber the latest Limit blocks are returned.
+type GetBlocksParams struct {
+	Chain      string `json:"chain" mapstructure:"chain" validate:"required"`
+	FromNumber uint64 `json:"fromNumber,omitempty" mapstructure:"fromNumber"`
+	Limit      int    `json:"limit,omitempty" mapstructure:"limit"`
+}
+
+func (p GetBlocksParams) Validate() error {
+	if p.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	return nil
+}
+
+// GetBlocksParamsFromMap extracts GetBlocksParams from validated map
+func GetBlocksParamsFromMap(data map[string]any) GetBlocksParams {
+	return GetBlocksParams{
+		Chain:      utils.GetValue[string]("chain", data),
+		FromNumber: uint64(utils.ExtractInt("fromNumber", data)),
+		Limit:      utils.ExtractInt("limit", data),
+	}
+}
+
+// GetTransfersParams contains parameters for the getTransfers command
+type GetTransfersParams struct {
+	Chain     string     `json:"chain" mapstructure:"chain" validate:"required"`
+	Asset     string     `json:"asset,omitempty" mapstructure:"asset"`
+	Address   string     `json:"address,omitempty" mapstructure:"address"`     // Transfers from or to this address
+	MinAmount string     `json:"minAmount,omitempty" mapstructure:"minAmount"` // e.g. whale alerts
+	StartTime *time.Time `json:"startTime,omitempty" mapstructure:"startTime"`
+	EndTime   *time.Time `json:"endTime,omitempty" mapstructure:"endTime"`
+	Cursor    string     `json:"cursor,omitempty" mapstructure:"cursor"`
+	Limit     int        `json:"limit,omitempty" mapstructure:"limit"`
+}
+
+func (p GetTransfersParams) Validate() error {
+	if p.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	if p.StartTime != nil && p.EndTime != nil && !p.EndTime.After(*p.StartTime) {
+		return fmt.Errorf("endTime must be after startTime")
+	}
+	return nil
+}
+
+// GetTransfersParamsFromMap extracts GetTransfersParams from validated map
+func GetTransfersParamsFromMap(data map[string]any) GetTransfersParams {
+	return GetTransfersParams{
+		Chain:     utils.GetValue[string]("chain", data),
+		Asset:     utils.GetValue[string]("asset", data),
+		Address:   utils.GetValue[string]("address", data),
+		MinAmount: utils.GetValue[string]("minAmount", data),
+		StartTime: utils.ExtractTime("startTime", data),
+		EndTime:   utils.ExtractTime("endTime", data),
+		Cursor:    utils.GetValue[string]("cursor", data),
+		Limit:     utils.ExtractInt("limit", data),
+	}
+}
+
+// GetHolderStatsParams contains parameters for the getHolderStats command
+type GetHolderStatsParams struct {
+	Chain    string `json:"chain" mapstructure:"chain" validate:"required"`
+	Asset    string `json:"asset" mapstructure:"asset" validate:"required"`
+	Contract string `json:"contract,omitempty" mapstructure:"contract"`
+}
+
+func (p GetHolderStatsParams) Validate() error {
+	if p.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if p.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	return nil
+}
+
+// GetHolderStatsParamsFromMap extracts GetHolderStatsParams from validated map
+func GetHolderStatsParamsFromMap(data map[string]any) GetHolderStatsParams {
+	return GetHolderStatsParams{
+		Chain:    utils.GetValue[string]("chain", data),
+		Asset:    utils.GetValue[string]("asset", data),
+		Contract: utils.GetValue[string]("contract", data),
+	}
+}
+
+// GetTVLParams contains parameters for the getTVL command
+type GetTVLParams struct {
+	Protocol  string     `json:"protocol" mapstructure:"protocol" validate:"required"`
+	Chain     string     `json:"chain,omitempty" mapstructure:"chain"`
+	StartTime *time.Time `json:"startTime,omitempty" mapstructure:"startTime"`
+	EndTime   *time.Time `json:"endTime,omitempty" mapstructure:"endTime"`
+}
+
+func (p GetTVLParams) Validate() error {
+	if p.Protocol == "" {
+		return fmt.Errorf("protocol is required")
+	}
+	if p.StartTime != nil && p.EndTime != nil && !p.EndTime.After(*p.StartTime) {
+		return fmt.Errorf("endTime must be after startTime")
+	}
+	return nil
+}
+
+// GetTVLParamsFromMap extracts GetTVLParams from validated map
+func GetTVLParamsFromMap(data map[string]any) GetTVLParams {
+	return GetTVLParams{
+		Protocol:  utils.GetValue[string]("protocol", data),
+		Chain:     utils.GetValue[string]("chain", data),
+		StartTime: utils.ExtractTime("startTime", data),
+		EndTime:   utils.ExtractTime("endTime", data),
+	}
+}
+
+// GetMetricParams contains parameters for the getMetric command
+type GetMetricParams struct {
+	Metric     string     `json:"metric" mapstructure:"metric" validate:"required"` // Plugin-defined, e.g. "exchange_netflow"
+	Asset      string     `json:"asset" mapstructure:"asset" validate:"required"`
+	Resolution string     `json:"resolution,omitempty" mapstructure:"resolution"` // Timeframe string, e.g. "1h", "1D"
+	StartTime  *time.Time `json:"startTime,omitempty" mapstructure:"startTime"`
+	EndTime    *time.Time `json:"endTime,omitempty" mapstructure:"endTime"`
+}
+
+func (p GetMetricParams) Validate() error {
+	if p.Metric == "" {
+		return fmt.Errorf("metric is required")
+	}
+	if p.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	if p.StartTime != nil && p.EndTime != nil && !p.EndTime.After(*p.StartTime) {
+		return fmt.Errorf("endTime must be after startTime")
+	}
+	return nil
+}
+
+// GetMetricParamsFromMap extracts GetMetricParams from validated map
+func GetMetricParamsFromMap(data map[string]any) GetMetricParams {
+	return GetMetricParams{
+		Metric:     utils.GetValue[string]("metric", data),
+		Asset:      utils.GetValue[string]("asset", data),
+		Resolution: utils.GetValue[string]("resolution", data),
+		StartTime:  utils.ExtractTime("startTime", data),
+		EndTime:    utils.ExtractTime("endTime", data),
+	}
+}
@@ -0,0 +1,83 @@
+package onchain
+
+import (
+	"time"
+)
+
+// Amounts are strings to preserve precision, as in the trading package.
+
+// Block is a block of a chain
+type Block struct {
+	Chain        string    `json:"chain"` // e.g. "ethereum", "bitcoin"
+	Number       uint64    `json:"number"`
+	Hash         string    `json:"hash"`
+	ParentHash   string    `json:"parentHash,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	TxCount      int       `json:"txCount"`
+	GasUsed      string    `json:"gasUsed,omitempty"`
+	BaseFee      string    `json:"baseFee,omitempty"` // In the chain's smallest unit
+	Miner        string    `json:"miner,omitempty"`
+	SizeBytes    int       `json:"sizeBytes,omitempty"`
+	Difficulty   string    `json:"difficulty,omitempty"`
+	TotalRewards string    `json:"totalRewards,omitempty"`
+}
+
+// Transfer is a movement of a native coin or token between addresses
+type Transfer struct {
+	Chain       string    `json:"chain"`
+	TxHash      string    `json:"txHash"`
+	LogIndex    int       `json:"logIndex,omitempty"` // Distinguishes transfers within one transaction
+	BlockNumber uint64    `json:"blockNumber"`
+	Timestamp   time.Time `json:"timestamp"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Asset       string    `json:"asset"`               // Symbol, e.g. "USDC"
+	Contract    string    `json:"contract,omitempty"`  // Token contract; empty for the native coin
+	Amount      string    `json:"amount"`              // In whole units, decimals applied
+	ValueUSD    string    `json:"valueUsd,omitempty"`  // At the time of the transfer
+	FromLabel   string    `json:"fromLabel,omitempty"` // e.g. "Binance Hot Wallet"
+	ToLabel     string    `json:"toLabel,omitempty"`
+}
+
+// HolderStats summarizes the holders of an asset at a point in time
+type HolderStats struct {
+	Chain         string    `json:"chain"`
+	Asset         string    `json:"asset"`
+	Contract      string    `json:"contract,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+	Holders       int64     `json:"holders"`
+	Supply        string    `json:"supply,omitempty"`
+	Top10Share    string    `json:"top10Share,omitempty"` // Fraction of supply, e.g. "0.42"
+	Top100Share   string    `json:"top100Share,omitempty"`
+	ExchangeShare string    `json:"exchangeShare,omitempty"` // Fraction held by known exchange addresses
+}
+
+// TVLPoint is the total value locked of a protocol at a point in time
+type TVLPoint struct {
+	Protocol  string    `json:"protocol"`        // e.g. "aave-v3"
+	Chain     string    `json:"chain,omitempty"` // Empty for the total across chains
+	Timestamp time.Time `json:"timestamp"`
+	TVLUSD    string    `json:"tvlUsd"`
+}
+
+// MetricPoint is a value of a generic on-chain metric series, e.g.
+// exchange net flow or active addresses
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     string    `json:"value"`
+}
+
+// MetricSeries is the response data of the getMetric command
+type MetricSeries struct {
+	Metric string        `json:"metric"`
+	Asset  string        `json:"asset"`
+	Unit   string        `json:"unit,omitempty"` // e.g. "USD", "BTC", "count"
+	Points []MetricPoint `json:"points"`
+}
+
+// TransfersPage is the response data of the getTransfers command. An empty
+// NextCursor means the last page has been reached.
+type TransfersPage struct {
+	Transfers  []Transfer `json:"transfers"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,203 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+)
+
+// MockWSHost plays the host side of a host-managed WebSocket stream in-process.
+//
+// Plugins never open sockets themselves: a command returns a StreamMarker, the
+// host connects, sends the initial messages and calls the plugin's
+// StreamHandler for every inbound message and connection event. MockWSHost
+// reproduces that loop so StreamHandler implementations can be tested with
+// scripted inbound messages and connection drops, and records everything the
+// host would have sent to the exchange.
+type MockWSHost struct {
+	handler plugin.StreamHandler
+	marker  stream.StreamMarker
+
+	connectionSeq int
+	connectionID  string
+	connected     bool
+	closed        bool
+	reconnects    int
+
+	sent    []string
+	emitted []plugin.StreamMessageResponse
+}
+
+// NewMockWSHost creates a mock host delivering messages to handler
+func NewMockWSHost(handler plugin.StreamHandler) *MockWSHost {
+	return &MockWSHost{handler: handler}
+}
+
+// Connect establishes the stream described by marker: the initial messages
+// are recorded as sent and a "connected" event is delivered to the handler.
+func (h *MockWSHost) Connect(marker stream.StreamMarker) error {
+	if err := marker.Validate(); err != nil {
+		return fmt.Errorf("invalid stream marker: %w", err)
+	}
+	h.marker = marker
+	h.closed = false
+	return h.open()
+}
+
+func (h *MockWSHost) open() error {
+	h.connectionSeq++
+	h.connectionID = fmt.Sprintf("mock-conn-%d", h.connectionSeq)
+	h.connected = true
+	h.sent = append(h.sent, h.marker.InitialMessages...)
+
+	_, err := h.deliverEvent("connected", "")
+	return err
+}
+
+// Receive delivers an inbound text message to the handler and applies the
+// returned action like the host would. App-level pings declared in the
+// marker's heartbeat spec are answered by the mock host and not forwarded.
+func (h *MockWSHost) Receive(message string) (plugin.StreamMessageResponse, error) {
+	if !h.connected {
+		return plugin.StreamMessageResponse{}, fmt.Errorf("stream %s is not connected", h.marker.StreamID)
+	}
+
+	if pong, ok := h.heartbeatReply(message); ok {
+		h.sent = append(h.sent, pong)
+		return plugin.IgnoreResponse(), nil
+	}
+
+	resp, err := h.handler.HandleStreamMessage(plugin.StreamMessageRequest{
+		StreamID:      h.marker.StreamID,
+		ConnectionID:  h.connectionID,
+		Message:       []byte(message),
+		MessageType:   "data",
+		StreamContext: h.marker.StreamContext,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, h.apply(resp.Action, resp.SendMessage, resp)
+}
+
+// ReceiveJSON marshals v and delivers it via Receive
+func (h *MockWSHost) ReceiveJSON(v any) (plugin.StreamMessageResponse, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return plugin.StreamMessageResponse{}, err
+	}
+	return h.Receive(string(data))
+}
+
+// Drop simulates a lost connection. The handler receives a "disconnected"
+// event (or "error" if err is not nil) and a reconnect is performed if it
+// asks for one.
+func (h *MockWSHost) Drop(err error) (plugin.StreamConnectionResponse, error) {
+	h.connected = false
+
+	eventType, msg := "disconnected", ""
+	if err != nil {
+		eventType, msg = "error", err.Error()
+	}
+
+	resp, herr := h.deliverEvent(eventType, msg)
+	if herr != nil {
+		return resp, herr
+	}
+	return resp, h.apply(resp.Action, "", plugin.StreamMessageResponse{})
+}
+
+func (h *MockWSHost) deliverEvent(eventType, errMsg string) (plugin.StreamConnectionResponse, error) {
+	return h.handler.HandleConnectionEvent(plugin.StreamConnectionEvent{
+		StreamID:     h.marker.StreamID,
+		ConnectionID: h.connectionID,
+		EventType:    eventType,
+		Error:        errMsg,
+	})
+}
+
+// apply executes a handler action the way the host does
+func (h *MockWSHost) apply(action, sendMessage string, resp plugin.StreamMessageResponse) error {
+	switch action {
+	case "data":
+		h.emitted = append(h.emitted, resp)
+	case "send":
+		h.sent = append(h.sent, sendMessage)
+	case "reconnect":
+		h.reconnects++
+		h.connected = false
+		return h.open()
+	case "close":
+		h.connected = false
+		h.closed = true
+	case "ignore", "":
+	default:
+		return fmt.Errorf("unknown stream action %q", action)
+	}
+	return nil
+}
+
+// heartbeatReply returns the pong for an app-level ping matching the marker's heartbeat spec
+func (h *MockWSHost) heartbeatReply(message string) (string, bool) {
+	if h.marker.Heartbeat == nil || h.marker.Heartbeat.App == nil {
+		return "", false
+	}
+	app := h.marker.Heartbeat.App
+
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(message), &payload); err != nil {
+		return "", false
+	}
+	if payload[app.MatchJSONField] != app.PingValue {
+		return "", false
+	}
+
+	payload[app.MatchJSONField] = app.PongValue
+	pong, _ := json.Marshal(payload)
+	return string(pong), true
+}
+
+// Sent returns all messages the host sent to the exchange: initial messages
+// (again after every reconnect), "send" actions and heartbeat replies
+func (h *MockWSHost) Sent() []string {
+	return h.sent
+}
+
+// Emitted returns all "data" responses the handler produced
+func (h *MockWSHost) Emitted() []plugin.StreamMessageResponse {
+	return h.emitted
+}
+
+// EmittedOfType returns the data payloads of all "data" responses with the given data type
+func (h *MockWSHost) EmittedOfType(dataType string) []any {
+	var data []any
+	for _, resp := range h.emitted {
+		if resp.DataType == dataType {
+			data = append(data, resp.Data)
+		}
+	}
+	return data
+}
+
+// Reconnects returns how often the handler requested a reconnect
+func (h *MockWSHost) Reconnects() int {
+	return h.reconnects
+}
+
+// Connected reports whether the mock connection is currently open
+func (h *MockWSHost) Connected() bool {
+	return h.connected
+}
+
+// Closed reports whether the handler closed the stream
+func (h *MockWSHost) Closed() bool {
+	return h.closed
+}
+
+// ConnectionID returns the ID of the current (or last) connection
+func (h *MockWSHost) ConnectionID() string {
+	return h.connectionID
+}
@@ -0,0 +1,142 @@
+package testing_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	datasrctesting "github.com/plusev-terminal/go-plugin-common/datasrc/testing"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+)
+
+type trade struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// tradeHandler is a minimal StreamHandler for a trade feed keyed by "event"
+type tradeHandler struct {
+	events []string
+}
+
+func (h *tradeHandler) HandleStreamMessage(req plugin.StreamMessageRequest) (plugin.StreamMessageResponse, error) {
+	var msg struct {
+		Event string `json:"event"`
+		trade
+	}
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return plugin.StreamMessageResponse{}, fmt.Errorf("decode message: %w", err)
+	}
+
+	switch msg.Event {
+	case "trade":
+		return plugin.StreamResponse("trade", msg.trade), nil
+	case "gap":
+		return plugin.SendResponse(`{"op":"resync"}`), nil
+	case "stale":
+		return plugin.ReconnectResponse("stale feed"), nil
+	case "bye":
+		return plugin.StreamMessageResponse{Success: true, Action: "close"}, nil
+	}
+	return plugin.IgnoreResponse(), nil
+}
+
+func (h *tradeHandler) HandleConnectionEvent(event plugin.StreamConnectionEvent) (plugin.StreamConnectionResponse, error) {
+	h.events = append(h.events, event.EventType+" "+event.ConnectionID)
+	return plugin.DefaultConnectionEventHandler(event), nil
+}
+
+func tradeMarker() stream.StreamMarker {
+	return stream.StreamMarker{
+		Stream:          true,
+		StreamID:        "trades-BTCUSDT",
+		WebSocketURL:    "wss://stream.example.com/ws",
+		InitialMessages: []string{`{"op":"subscribe","args":["trades.BTCUSDT"]}`},
+		Heartbeat: &stream.StreamHeartbeatSpec{
+			App: &stream.AppHeartbeatSpec{MatchJSONField: "event", PingValue: "ping", PongValue: "pong"},
+		},
+	}
+}
+
+func TestMockWSHost(t *testing.T) {
+	handler := &tradeHandler{}
+	host := datasrctesting.NewMockWSHost(handler)
+
+	if err := host.Connect(tradeMarker()); err != nil {
+		t.Fatal(err)
+	}
+	steps := []string{
+		`{"event":"subscribed"}`,
+		`{"event":"ping","ts":1}`,
+		`{"event":"trade","price":100.5,"size":2}`,
+		`{"event":"gap"}`,
+	}
+	for _, msg := range steps {
+		if _, err := host.Receive(msg); err != nil {
+			t.Fatalf("receive %s: %v", msg, err)
+		}
+	}
+	if _, err := host.ReceiveJSON(map[string]any{"event": "trade", "price": 101, "size": 0.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := host.EmittedOfType("trade")
+	want := []any{trade{Price: 100.5, Size: 2}, trade{Price: 101, Size: 0.5}}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected emitted trades %v, got %v", want, got)
+	}
+
+	if _, err := host.Receive(`{"event":"stale"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := host.Drop(errors.New("read timeout")); err != nil {
+		t.Fatal(err)
+	}
+	if host.Reconnects() != 2 || !host.Connected() || host.ConnectionID() != "mock-conn-3" {
+		t.Errorf("expected 2 reconnects onto mock-conn-3, got %d onto %s (connected %v)",
+			host.Reconnects(), host.ConnectionID(), host.Connected())
+	}
+
+	subscribe := tradeMarker().InitialMessages[0]
+	wantSent := []string{subscribe, `{"event":"pong","ts":1}`, `{"op":"resync"}`, subscribe, subscribe}
+	if !slices.Equal(host.Sent(), wantSent) {
+		t.Errorf("expected sent %v, got %v", wantSent, host.Sent())
+	}
+	wantEvents := []string{"connected mock-conn-1", "connected mock-conn-2", "error mock-conn-2", "connected mock-conn-3"}
+	if !slices.Equal(handler.events, wantEvents) {
+		t.Errorf("expected events %v, got %v", wantEvents, handler.events)
+	}
+
+	if _, err := host.Receive(`{"event":"bye"}`); err != nil {
+		t.Fatal(err)
+	}
+	if !host.Closed() || host.Connected() {
+		t.Error("expected the handler to close the stream")
+	}
+	if _, err := host.Receive(`{"event":"trade"}`); err == nil {
+		t.Error("expected an error receiving on a closed stream")
+	}
+}
+
+func TestMockWSHostRejectsInvalidMarker(t *testing.T) {
+	host := datasrctesting.NewMockWSHost(&tradeHandler{})
+	if err := host.Connect(stream.StreamMarker{Stream: true, StreamID: "trades"}); err == nil {
+		t.Error("expected an error for a marker without websocketUrl")
+	}
+}
+
+func TestMockWSHostHandlerError(t *testing.T) {
+	host := datasrctesting.NewMockWSHost(&tradeHandler{})
+	if err := host.Connect(tradeMarker()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := host.Receive("not json"); err == nil {
+		t.Error("expected the handler error to be returned")
+	}
+	if len(host.Emitted()) != 0 {
+		t.Errorf("expected nothing emitted, got %v", host.Emitted())
+	}
+}
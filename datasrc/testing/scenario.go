@@ -0,0 +1,146 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+)
+
+// TB is the subset of testing.TB used by the scenario driver
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// Scenario is a scripted sequence of inbound messages and connection events
+// with the expected handler reaction for each step.
+//
+// Example:
+//
+//	RunScenario(t, client, Scenario{
+//	    StreamID: "ohlcv-BTCUSDT-1m",
+//	    Steps: []Step{
+//	        {Message: `{"event":"subscribed"}`, Expect: Expect{Action: "ignore"}},
+//	        {Message: klineJSON, Expect: Expect{Action: "data", DataType: "ohlcv", Data: expectedCandle}},
+//	        {Event: "disconnected", Expect: Expect{Action: "reconnect"}},
+//	    },
+//	})
+type Scenario struct {
+	StreamID      string
+	ConnectionID  string         // Defaults to "conn-1"
+	StreamContext map[string]any // Passed with every message
+	Steps         []Step
+}
+
+// Step is either an inbound Message or a connection Event (e.g. "connected",
+// "disconnected", "error"). EventError is passed along with "error" events.
+type Step struct {
+	Name       string
+	Message    string
+	Event      string
+	EventError string
+	Expect     Expect
+}
+
+// Expect describes the expected response of a step. Empty fields are not
+// checked. Data is compared by its JSON representation, so typed structs
+// can be compared against the handler's output regardless of pointer/map form.
+type Expect struct {
+	Action      string
+	DataType    string
+	Data        any
+	SendMessage string
+	Error       bool // Handler returns an error or a response with Success=false
+}
+
+// RunScenario feeds the scenario steps to handler and reports mismatches on t
+func RunScenario(t TB, handler plugin.StreamHandler, scenario Scenario) {
+	t.Helper()
+
+	connectionID := scenario.ConnectionID
+	if connectionID == "" {
+		connectionID = "conn-1"
+	}
+
+	for i, step := range scenario.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i)
+		}
+
+		if step.Event != "" {
+			resp, err := handler.HandleConnectionEvent(plugin.StreamConnectionEvent{
+				StreamID:     scenario.StreamID,
+				ConnectionID: connectionID,
+				EventType:    step.Event,
+				Error:        step.EventError,
+			})
+			failed := err != nil || !resp.Success
+			checkStep(t, name, step.Expect, failed, err, resp.Action, "", nil, "")
+			continue
+		}
+
+		resp, err := handler.HandleStreamMessage(plugin.StreamMessageRequest{
+			StreamID:      scenario.StreamID,
+			ConnectionID:  connectionID,
+			Message:       []byte(step.Message),
+			MessageType:   "data",
+			StreamContext: scenario.StreamContext,
+		})
+		failed := err != nil || !resp.Success
+		checkStep(t, name, step.Expect, failed, err, resp.Action, resp.DataType, resp.Data, resp.SendMessage)
+	}
+}
+
+func checkStep(t TB, name string, expect Expect, failed bool, err error, action, dataType string, data any, sendMessage string) {
+	t.Helper()
+
+	if failed != expect.Error {
+		if expect.Error {
+			t.Errorf("%s: expected an error, got action %q", name, action)
+		} else {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		return
+	}
+	if expect.Action != "" && action != expect.Action {
+		t.Errorf("%s: expected action %q, got %q", name, expect.Action, action)
+	}
+	if expect.DataType != "" && dataType != expect.DataType {
+		t.Errorf("%s: expected data type %q, got %q", name, expect.DataType, dataType)
+	}
+	if expect.SendMessage != "" && sendMessage != expect.SendMessage {
+		t.Errorf("%s: expected send message %q, got %q", name, expect.SendMessage, sendMessage)
+	}
+	if expect.Data != nil {
+		equal, err := jsonEqual(expect.Data, data)
+		if err != nil {
+			t.Errorf("%s: failed to compare data: %v", name, err)
+		} else if !equal {
+			got, _ := json.Marshal(data)
+			want, _ := json.Marshal(expect.Data)
+			t.Errorf("%s: data mismatch\n got: %s\nwant: %s", name, got, want)
+		}
+	}
+}
+
+// jsonEqual compares two values by their JSON representation
+func jsonEqual(a, b any) (bool, error) {
+	var va, vb any
+	for _, pair := range []struct {
+		in  any
+		out *any
+	}{{a, &va}, {b, &vb}} {
+		data, err := json.Marshal(pair.in)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(data, pair.out); err != nil {
+			return false, err
+		}
+	}
+	return reflect.DeepEqual(va, vb), nil
+}
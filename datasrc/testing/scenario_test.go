@@ -0,0 +1,65 @@
+package testing_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	datasrctesting "github.com/plusev-terminal/go-plugin-common/datasrc/testing"
+)
+
+// recorder is a TB collecting reported failures
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestRunScenario(t *testing.T) {
+	datasrctesting.RunScenario(t, &tradeHandler{}, datasrctesting.Scenario{
+		StreamID: "trades-BTCUSDT",
+		Steps: []datasrctesting.Step{
+			{Event: "connected", Expect: datasrctesting.Expect{Action: "ignore"}},
+			{Message: `{"event":"subscribed"}`, Expect: datasrctesting.Expect{Action: "ignore"}},
+			{
+				Name:    "trade",
+				Message: `{"event":"trade","price":100.5,"size":2}`,
+				Expect:  datasrctesting.Expect{Action: "data", DataType: "trade", Data: map[string]any{"price": 100.5, "size": 2}},
+			},
+			{Message: `{"event":"gap"}`, Expect: datasrctesting.Expect{Action: "send", SendMessage: `{"op":"resync"}`}},
+			{Message: "not json", Expect: datasrctesting.Expect{Error: true}},
+			{Event: "error", EventError: "read timeout", Expect: datasrctesting.Expect{Action: "reconnect"}},
+		},
+	})
+}
+
+func TestRunScenarioReportsMismatches(t *testing.T) {
+	rec := &recorder{}
+	datasrctesting.RunScenario(rec, &tradeHandler{}, datasrctesting.Scenario{
+		StreamID: "trades-BTCUSDT",
+		Steps: []datasrctesting.Step{
+			{Name: "action", Message: `{"event":"subscribed"}`, Expect: datasrctesting.Expect{Action: "data"}},
+			{Name: "data", Message: `{"event":"trade","price":1,"size":1}`, Expect: datasrctesting.Expect{Data: trade{Price: 2, Size: 1}}},
+			{Name: "error", Message: `{"event":"trade"}`, Expect: datasrctesting.Expect{Error: true}},
+			{Name: "event", Event: "disconnected", Expect: datasrctesting.Expect{Action: "ignore"}},
+		},
+	})
+
+	prefixes := []string{"action: expected action", "data: data mismatch", "error: expected an error", "event: expected action"}
+	if len(rec.errors) != len(prefixes) {
+		t.Fatalf("expected %d failures, got %q", len(prefixes), rec.errors)
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(rec.errors[i], prefix) {
+			t.Errorf("failure %d: expected prefix %q, got %q", i, prefix, rec.errors[i])
+		}
+	}
+}
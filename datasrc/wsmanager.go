@@ -0,0 +1,451 @@
+// Package datasrc contains helpers shared by data source plugins. The
+// command contracts live in the subpackages, e.g. datasrc/exchange.
+package datasrc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// WSManager keeps the plugin-owned WebSocket connections (stream.Dial) of a
+// plugin alive. It remembers the subscription messages sent per connection,
+// reconnects with backoff when a connection drops and replays them, so
+// plugins only say what they want to receive.
+//
+//	m := datasrc.NewWSManager()
+//	err := m.Connect("public", stream.WSConnectRequest{URL: "wss://stream.example.com/ws"}, nil)
+//	...
+//	m.Subscribe("public", "trades.BTCUSDT", []byte(`{"op":"subscribe","args":["trades.BTCUSDT"]}`))
+//	err = m.Run(func(name string, msg []byte, binary bool) error {
+//	    return c.handleMessage(msg)
+//	})
+//
+// A WSManager is not safe for concurrent use; plugins run single-threaded.
+type WSManager struct {
+	conns []*managedConn
+	clock clock.Clock
+
+	// Backoff sets the delays between reconnect attempts; its Sleep, if set,
+	// replaces time.Sleep while Run waits for a reconnect
+	Backoff utils.Backoff
+	// MaxReconnects is the number of failed reconnects in a row after which
+	// a connection is given up, 0 retries forever
+	MaxReconnects int
+	// MinUptime is how long a connection must stay up to count as
+	// established. Drops before that count as failed reconnects, so servers
+	// that accept and then drop connections are redialed with backoff.
+	MinUptime time.Duration
+	// PollTimeout is how long one Run round waits for messages, split
+	// across the connections
+	PollTimeout time.Duration
+	// BatchSize is the number of messages read per connection and round
+	BatchSize int
+	// OnError receives connection failures and receive errors, may be nil
+	OnError func(name string, err error)
+
+	stopped bool
+}
+
+// WSStats are counters of one managed connection
+type WSStats struct {
+	Name             string    `json:"name"`
+	URL              string    `json:"url"`
+	Connected        bool      `json:"connected"`
+	ConnectedAt      time.Time `json:"connectedAt,omitzero"`
+	Reconnects       int       `json:"reconnects"`
+	Subscriptions    int       `json:"subscriptions"`
+	MessagesReceived int64     `json:"messagesReceived"`
+	BytesReceived    int64     `json:"bytesReceived"`
+	MessagesSent     int64     `json:"messagesSent"` // Through the manager, not by onConnect
+	BytesSent        int64     `json:"bytesSent"`
+	LastMessageAt    time.Time `json:"lastMessageAt,omitzero"`
+	LastError        string    `json:"lastError,omitempty"`
+}
+
+type subscription struct {
+	key     string
+	message []byte
+}
+
+type managedConn struct {
+	name      string
+	req       stream.WSConnectRequest
+	onConnect func(*stream.WSConnection) error
+	conn      *stream.WSConnection
+	subs      []subscription
+	stats     WSStats
+
+	failures    int       // Failed reconnects and early drops in a row
+	nextAttempt time.Time // Earliest time of the next reconnect
+	gaveUp      bool
+}
+
+// ErrUnknownConnection is returned for names not passed to Connect
+var ErrUnknownConnection = errors.New("unknown websocket connection")
+
+// DefaultMinUptime is the MinUptime of NewWSManager
+const DefaultMinUptime = 10 * time.Second
+
+// NewWSManager creates a manager using the host clock and DefaultBackoff
+func NewWSManager() *WSManager {
+	return &WSManager{
+		clock:       wasmutils.HostClock,
+		Backoff:     utils.DefaultBackoff,
+		MinUptime:   DefaultMinUptime,
+		PollTimeout: stream.DefaultPollTimeout,
+		BatchSize:   stream.DefaultBatchSize,
+	}
+}
+
+// SetClock replaces the clock used for reconnect delays and stats
+func (m *WSManager) SetClock(c clock.Clock) *WSManager {
+	m.clock = c
+	return m
+}
+
+// Connect opens a connection under name. onConnect, if not nil, runs after
+// every (re)connect before the subscriptions are replayed, e.g. to log in.
+// A failing first connect is returned and the connection is not managed.
+func (m *WSManager) Connect(name string, req stream.WSConnectRequest, onConnect func(*stream.WSConnection) error) error {
+	if m.find(name) != nil {
+		return fmt.Errorf("websocket connection %q already exists", name)
+	}
+	mc := &managedConn{name: name, req: req, onConnect: onConnect}
+	mc.stats.Name, mc.stats.URL = name, req.URL
+	if err := m.dial(mc); err != nil {
+		return err
+	}
+	m.conns = append(m.conns, mc)
+	return nil
+}
+
+// Subscribe sends message on the connection and replays it after every
+// reconnect. Subscribing to a key twice is a no-op. If the connection is
+// down the message is sent once it is back.
+func (m *WSManager) Subscribe(name, key string, message []byte) error {
+	mc := m.find(name)
+	if mc == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownConnection, name)
+	}
+	if slices.ContainsFunc(mc.subs, func(s subscription) bool { return s.key == key }) {
+		return nil
+	}
+	mc.subs = append(mc.subs, subscription{key: key, message: message})
+	mc.stats.Subscriptions = len(mc.subs)
+	return m.sendIfConnected(mc, message)
+}
+
+// Unsubscribe forgets the subscription key and sends message, if not nil,
+// to end it on the server
+func (m *WSManager) Unsubscribe(name, key string, message []byte) error {
+	mc := m.find(name)
+	if mc == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownConnection, name)
+	}
+	mc.subs = slices.DeleteFunc(mc.subs, func(s subscription) bool { return s.key == key })
+	mc.stats.Subscriptions = len(mc.subs)
+	if message == nil {
+		return nil
+	}
+	return m.sendIfConnected(mc, message)
+}
+
+// Subscriptions returns the subscription keys of a connection in the order
+// they are replayed
+func (m *WSManager) Subscriptions(name string) []string {
+	mc := m.find(name)
+	if mc == nil {
+		return nil
+	}
+	keys := make([]string, len(mc.subs))
+	for i, s := range mc.subs {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// Send sends a text message that is not replayed after reconnects
+func (m *WSManager) Send(name string, message []byte) error {
+	mc := m.find(name)
+	if mc == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownConnection, name)
+	}
+	if mc.conn == nil {
+		return fmt.Errorf("websocket connection %q is down", name)
+	}
+	return m.send(mc, message)
+}
+
+// Poll reads the pending messages of all connections without waiting and
+// reconnects dropped ones whose backoff elapsed. An error of onMessage is
+// returned; stream.ErrStopListening ends Poll without error.
+func (m *WSManager) Poll(onMessage func(name string, msg []byte, binary bool) error) error {
+	m.stopped = false
+	return m.round(0, onMessage)
+}
+
+// Run polls all connections until Stop is called, onMessage fails, or every
+// connection was closed or given up. Each round waits up to PollTimeout
+// for messages, so Run doesn't busy-wait.
+func (m *WSManager) Run(onMessage func(name string, msg []byte, binary bool) error) error {
+	m.stopped = false
+	for !m.stopped {
+		if !slices.ContainsFunc(m.conns, func(mc *managedConn) bool { return !mc.gaveUp }) {
+			return nil
+		}
+		m.waitForReconnect()
+		timeout := m.PollTimeout / time.Duration(len(m.conns))
+		if err := m.round(timeout, onMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForReconnect sleeps until the next reconnect attempt while no
+// connection is up, instead of spinning through empty rounds
+func (m *WSManager) waitForReconnect() {
+	var next time.Time
+	for _, mc := range m.conns {
+		if mc.conn != nil {
+			return
+		}
+		if !mc.gaveUp && (next.IsZero() || mc.nextAttempt.Before(next)) {
+			next = mc.nextAttempt
+		}
+	}
+	wait := next.Sub(m.clock.Now())
+	if next.IsZero() || wait <= 0 {
+		return
+	}
+	if m.Backoff.Sleep != nil {
+		m.Backoff.Sleep(wait)
+	} else {
+		time.Sleep(wait)
+	}
+}
+
+// Stop makes a running Run return after the current message
+func (m *WSManager) Stop() {
+	m.stopped = true
+}
+
+func (m *WSManager) round(timeout time.Duration, onMessage func(name string, msg []byte, binary bool) error) error {
+	for _, mc := range m.conns {
+		if m.stopped {
+			return nil
+		}
+		if err := m.poll(mc, timeout, onMessage); err != nil {
+			if errors.Is(err, stream.ErrStopListening) {
+				m.stopped = true
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// poll reads up to BatchSize messages of one connection
+func (m *WSManager) poll(mc *managedConn, timeout time.Duration, onMessage func(name string, msg []byte, binary bool) error) error {
+	if mc.conn == nil {
+		m.reconnect(mc)
+		if mc.conn == nil {
+			return nil
+		}
+	}
+
+	batchSize := m.BatchSize
+	if batchSize <= 0 {
+		batchSize = stream.DefaultBatchSize
+	}
+	for range batchSize {
+		msg, ok, err := mc.conn.Receive(timeout)
+		if err != nil {
+			if errors.Is(err, stream.ErrWSClosed) {
+				m.disconnected(mc, err)
+				m.reconnect(mc)
+			} else {
+				m.report(mc, err)
+			}
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+		timeout = 0
+
+		mc.stats.MessagesReceived++
+		mc.stats.BytesReceived += int64(len(msg.Data))
+		mc.stats.LastMessageAt = m.clock.Now()
+		if err := onMessage(mc.name, msg.Data, msg.Binary); err != nil {
+			return err
+		}
+		if m.stopped {
+			return nil
+		}
+	}
+	return nil
+}
+
+// dial opens the connection, runs onConnect and replays the subscriptions
+func (m *WSManager) dial(mc *managedConn) error {
+	conn, err := stream.Dial(mc.req)
+	if err != nil {
+		return err
+	}
+	mc.conn = conn
+	if mc.onConnect != nil {
+		if err := mc.onConnect(conn); err != nil {
+			m.drop(mc)
+			return fmt.Errorf("websocket connection %q: %w", mc.name, err)
+		}
+	}
+	for _, s := range mc.subs {
+		if err := m.send(mc, s.message); err != nil {
+			m.drop(mc)
+			return fmt.Errorf("websocket connection %q: replay %s: %w", mc.name, s.key, err)
+		}
+	}
+	mc.stats.Connected = true
+	mc.stats.ConnectedAt = m.clock.Now()
+	return nil
+}
+
+// reconnect dials a dropped connection once its backoff elapsed
+func (m *WSManager) reconnect(mc *managedConn) {
+	if mc.gaveUp || m.clock.Now().Before(mc.nextAttempt) {
+		return
+	}
+	if err := m.dial(mc); err != nil {
+		mc.failures++
+		m.report(mc, err)
+		if m.MaxReconnects > 0 && mc.failures >= m.MaxReconnects {
+			mc.gaveUp = true
+			m.report(mc, fmt.Errorf("giving up after %d failed reconnects", mc.failures))
+			return
+		}
+		mc.nextAttempt = m.clock.Now().Add(m.Backoff.Delay(mc.failures))
+		return
+	}
+	mc.nextAttempt = time.Time{}
+	mc.stats.Reconnects++
+}
+
+// disconnected handles a dropped connection. A connection that was up for
+// MinUptime is redialed right away, an earlier drop waits for the backoff.
+func (m *WSManager) disconnected(mc *managedConn, err error) {
+	uptime := m.clock.Now().Sub(mc.stats.ConnectedAt)
+	m.drop(mc)
+	m.report(mc, err)
+	if uptime >= m.MinUptime {
+		mc.failures = 0
+		mc.nextAttempt = time.Time{}
+		return
+	}
+	mc.failures++
+	mc.nextAttempt = m.clock.Now().Add(m.Backoff.Delay(mc.failures))
+}
+
+func (m *WSManager) drop(mc *managedConn) {
+	if mc.conn != nil {
+		mc.conn.Close()
+		mc.conn = nil
+	}
+	mc.stats.Connected = false
+}
+
+func (m *WSManager) report(mc *managedConn, err error) {
+	mc.stats.LastError = err.Error()
+	if m.OnError != nil {
+		m.OnError(mc.name, err)
+	}
+}
+
+func (m *WSManager) send(mc *managedConn, message []byte) error {
+	if err := mc.conn.Send(message); err != nil {
+		return err
+	}
+	mc.stats.MessagesSent++
+	mc.stats.BytesSent += int64(len(message))
+	return nil
+}
+
+// sendIfConnected sends message now, or leaves it to the replay after the
+// next reconnect if the connection is down
+func (m *WSManager) sendIfConnected(mc *managedConn, message []byte) error {
+	if mc.conn == nil {
+		return nil
+	}
+	err := m.send(mc, message)
+	if errors.Is(err, stream.ErrWSClosed) {
+		m.disconnected(mc, err)
+		return nil
+	}
+	return err
+}
+
+// Connection returns the current connection of name, nil while it is down
+func (m *WSManager) Connection(name string) *stream.WSConnection {
+	if mc := m.find(name); mc != nil {
+		return mc.conn
+	}
+	return nil
+}
+
+// Stats returns the counters of a connection
+func (m *WSManager) Stats(name string) (WSStats, bool) {
+	mc := m.find(name)
+	if mc == nil {
+		return WSStats{}, false
+	}
+	return mc.stats, true
+}
+
+// AllStats returns the counters of all connections in the order they were connected
+func (m *WSManager) AllStats() []WSStats {
+	stats := make([]WSStats, len(m.conns))
+	for i, mc := range m.conns {
+		stats[i] = mc.stats
+	}
+	return stats
+}
+
+// Close closes the connection and stops managing it
+func (m *WSManager) Close(name string) error {
+	i := slices.IndexFunc(m.conns, func(mc *managedConn) bool { return mc.name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownConnection, name)
+	}
+	if conn := m.conns[i].conn; conn != nil {
+		conn.Close()
+	}
+	m.conns = slices.Delete(m.conns, i, i+1)
+	return nil
+}
+
+// CloseAll closes all connections
+func (m *WSManager) CloseAll() {
+	for _, mc := range m.conns {
+		if mc.conn != nil {
+			mc.conn.Close()
+		}
+	}
+	m.conns = nil
+}
+
+func (m *WSManager) find(name string) *managedConn {
+	for _, mc := range m.conns {
+		if mc.name == name {
+			return mc
+		}
+	}
+	return nil
+}
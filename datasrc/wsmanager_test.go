@@ -0,0 +1,179 @@
+//go:build !wasm
+
+package datasrc_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/datasrc"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+const wsURL = "wss://stream.example.com/ws"
+
+func TestWSManagerResubscribe(t *testing.T) {
+	h := plugintest.New(t)
+	server := plugintest.NewWSServer()
+	h.WebSockets[wsURL] = server
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	m := datasrc.NewWSManager().SetClock(fake)
+	m.Backoff = utils.Backoff{Initial: time.Second, Max: 10 * time.Second}
+	var errs []error
+	m.OnError = func(name string, err error) { errs = append(errs, err) }
+
+	login := func(conn *stream.WSConnection) error { return conn.Send([]byte("login")) }
+	if err := m.Connect("public", stream.WSConnectRequest{URL: wsURL}, login); err != nil {
+		t.Fatal(err)
+	}
+	m.Subscribe("public", "trades", []byte("sub trades"))
+	m.Subscribe("public", "book", []byte("sub book"))
+	m.Subscribe("public", "trades", []byte("sub trades"))
+	m.Unsubscribe("public", "book", []byte("unsub book"))
+	if !slices.Equal(server.Sent(), []string{"login", "sub trades", "sub book", "unsub book"}) {
+		t.Fatalf("unexpected sent messages %v", server.Sent())
+	}
+
+	var got []string
+	collect := func(name string, msg []byte, binary bool) error {
+		got = append(got, name+":"+string(msg))
+		return nil
+	}
+	server.Push("t1")
+	server.Push("t2")
+	if err := m.Poll(collect); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop of an established connection with failing reconnects: the first
+	// attempt is immediate, the next one waits for the backoff
+	fake.Advance(datasrc.DefaultMinUptime)
+	server.Drop(1006, "abnormal closure")
+	server.FailDials(2)
+	m.Poll(collect)
+	m.Poll(collect)
+	if server.Dials() != 1 || len(errs) != 2 {
+		t.Fatalf("expected one immediate failed reconnect, got %d dials and errors %v", server.Dials(), errs)
+	}
+	fake.Advance(time.Second)
+	m.Poll(collect)
+	if server.Dials() != 1 {
+		t.Fatal("expected the second reconnect to fail as well")
+	}
+	fake.Advance(2 * time.Second)
+	m.Poll(collect)
+	if server.Dials() != 2 || !server.Connected() {
+		t.Fatalf("expected a reconnect after the backoff, got %d dials", server.Dials())
+	}
+	if sent := server.Sent(); !slices.Equal(sent[len(sent)-2:], []string{"login", "sub trades"}) {
+		t.Errorf("expected login and the remaining subscription to be replayed, got %v", sent)
+	}
+
+	server.Push("t3")
+	m.Poll(collect)
+	if !slices.Equal(got, []string{"public:t1", "public:t2", "public:t3"}) {
+		t.Errorf("unexpected messages %v", got)
+	}
+
+	stats, ok := m.Stats("public")
+	if !ok || !stats.Connected || stats.Reconnects != 1 || stats.MessagesReceived != 3 || stats.Subscriptions != 1 || stats.MessagesSent != 4 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestWSManagerRun(t *testing.T) {
+	h := plugintest.New(t)
+	a, b := plugintest.NewWSServer(), plugintest.NewWSServer()
+	h.WebSockets["wss://a.example.com"] = a
+	h.WebSockets["wss://b.example.com"] = b
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := datasrc.NewWSManager().SetClock(fake)
+	m.Backoff.Sleep = fake.Advance
+	m.MaxReconnects = 1
+	m.Connect("a", stream.WSConnectRequest{URL: "wss://a.example.com"}, nil)
+	m.Connect("b", stream.WSConnectRequest{URL: "wss://b.example.com"}, nil)
+	if err := m.Connect("a", stream.WSConnectRequest{URL: "wss://a.example.com"}, nil); err == nil {
+		t.Error("expected a duplicate name to be rejected")
+	}
+
+	a.Push("1")
+	b.Push("2")
+	b.Push("stop")
+	var got []string
+	err := m.Run(func(name string, msg []byte, binary bool) error {
+		if string(msg) == "stop" {
+			return stream.ErrStopListening
+		}
+		got = append(got, name+":"+string(msg))
+		return nil
+	})
+	if err != nil || !slices.Equal(got, []string{"a:1", "b:2"}) {
+		t.Fatalf("unexpected result %v %v", got, err)
+	}
+
+	// Run ends once every connection was given up
+	a.Drop(1001, "")
+	b.Drop(1001, "")
+	a.FailDials(1)
+	b.FailDials(1)
+	if err := m.Run(func(string, []byte, bool) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if stats := m.AllStats(); len(stats) != 2 || stats[0].Connected || stats[1].Connected {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if err := m.Send("x", nil); !errors.Is(err, datasrc.ErrUnknownConnection) {
+		t.Errorf("expected ErrUnknownConnection, got %v", err)
+	}
+	m.CloseAll()
+}
+
+func TestWSManagerBackoffAfterEarlyDrops(t *testing.T) {
+	h := plugintest.New(t)
+	server := plugintest.NewWSServer()
+	h.WebSockets[wsURL] = server
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	m := datasrc.NewWSManager().SetClock(fake)
+	m.Backoff = utils.Backoff{Initial: time.Second, Max: 10 * time.Second}
+	if err := m.Connect("public", stream.WSConnectRequest{URL: wsURL}, nil); err != nil {
+		t.Fatal(err)
+	}
+	m.Subscribe("public", "trades", []byte("sub trades"))
+	noop := func(string, []byte, bool) error { return nil }
+
+	// The server drops every connection right after the dial
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		server.Drop(1006, "abnormal closure")
+		m.Poll(noop)
+		fake.Advance(delay - time.Millisecond)
+		m.Poll(noop)
+		if server.Dials() != i+1 {
+			t.Fatalf("drop %d: expected no redial before %s, got %d dials", i+1, delay, server.Dials())
+		}
+		fake.Advance(time.Millisecond)
+		m.Poll(noop)
+		if server.Dials() != i+2 {
+			t.Fatalf("drop %d: expected a redial after %s, got %d dials", i+1, delay, server.Dials())
+		}
+	}
+	if n := len(server.Sent()); n != 4 {
+		t.Errorf("expected the subscription to be replayed once per dial, got %d sends", n)
+	}
+
+	// Once a connection stayed up, its drop is redialed right away
+	fake.Advance(datasrc.DefaultMinUptime)
+	server.Drop(1006, "abnormal closure")
+	m.Poll(noop)
+	if server.Dials() != 5 {
+		t.Fatalf("expected an immediate redial after a stable connection, got %d dials", server.Dials())
+	}
+}
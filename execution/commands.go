@@ -0,0 +1,9 @@
+package execution
+
+const (
+	CMD_SUBMIT_ORDER = "submitOrder"
+	CMD_AMEND_ORDER  = "amendOrder"
+	CMD_CANCEL_ORDER = "cancelOrder"
+	CMD_GET_ORDER    = "getOrder"
+	CMD_OPEN_ORDERS  = "openOrders"
+)
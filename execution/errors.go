@@ -0,0 +1,89 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorCode classifies a failed order operation so the host can react to it
+// without parsing venue specific error messages
+type ErrorCode string
+
+const (
+	ErrCodeInsufficientFunds ErrorCode = "insufficient_funds"
+	ErrCodeBadPrecision      ErrorCode = "bad_precision" // Price or quantity violates the market's tick size or limits
+	ErrCodeRateLimited       ErrorCode = "rate_limited"
+	ErrCodeInvalidOrder      ErrorCode = "invalid_order"
+	ErrCodeOrderNotFound     ErrorCode = "order_not_found"
+	ErrCodeRejected          ErrorCode = "rejected" // Refused by the venue for any other reason
+	ErrCodeUnsupported       ErrorCode = "unsupported"
+	ErrCodeUnavailable       ErrorCode = "unavailable" // Venue unreachable or in maintenance
+	ErrCodeInternal          ErrorCode = "internal"
+)
+
+// Error is an order operation failure with an error code
+type Error struct {
+	Code       ErrorCode
+	Retryable  bool
+	RetryAfter time.Duration // Only set for ErrCodeRateLimited
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// InsufficientFunds reports that the account cannot cover the order
+func InsufficientFunds(format string, args ...any) error {
+	return &Error{Code: ErrCodeInsufficientFunds, Err: fmt.Errorf(format, args...)}
+}
+
+// BadPrecision reports a price or quantity the market does not accept
+func BadPrecision(format string, args ...any) error {
+	return &Error{Code: ErrCodeBadPrecision, Err: fmt.Errorf(format, args...)}
+}
+
+// RateLimited reports that the venue throttled the request. The operation
+// may be retried after retryAfter; zero means unknown.
+func RateLimited(retryAfter time.Duration, err error) error {
+	return &Error{Code: ErrCodeRateLimited, Retryable: true, RetryAfter: retryAfter, Err: err}
+}
+
+// InvalidOrder reports an order that is malformed independent of the venue
+func InvalidOrder(format string, args ...any) error {
+	return &Error{Code: ErrCodeInvalidOrder, Err: fmt.Errorf(format, args...)}
+}
+
+// OrderNotFound reports an unknown order ID
+func OrderNotFound(symbol, id string) error {
+	return &Error{Code: ErrCodeOrderNotFound, Err: fmt.Errorf("order %s not found on %s", id, symbol)}
+}
+
+// Rejected marks err as a refusal by the venue
+func Rejected(err error) error {
+	return &Error{Code: ErrCodeRejected, Err: err}
+}
+
+// Unsupported reports an operation the router does not implement
+func Unsupported(op string) error {
+	return &Error{Code: ErrCodeUnsupported, Err: fmt.Errorf("%s is not supported", op)}
+}
+
+// Unavailable marks err as a temporary venue outage worth retrying
+func Unavailable(err error) error {
+	return &Error{Code: ErrCodeUnavailable, Retryable: true, Err: err}
+}
+
+// CodeOf returns the error code of err, or ErrCodeInternal if it has none
+func CodeOf(err error) ErrorCode {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ErrCodeInternal
+}
@@ -0,0 +1,132 @@
+package execution
+
+import (
+	"fmt"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// SubmitOrderParams contains parameters for the submitOrder command.
+// The host sends the same IdempotencyKey again when it retries a submit whose
+// outcome it does not know, e.g. after a timeout.
+type SubmitOrderParams struct {
+	IdempotencyKey string   `json:"idempotencyKey" mapstructure:"idempotencyKey" validate:"required"`
+	Order          tt.Order `json:"order" mapstructure:"order" validate:"required"`
+}
+
+func (p SubmitOrderParams) Validate() error {
+	if p.IdempotencyKey == "" {
+		return fmt.Errorf("idempotencyKey is required")
+	}
+	return validateOrder(p.Order)
+}
+
+// SubmitOrderParamsFromMap extracts SubmitOrderParams from validated map.
+// It fails if the order cannot be decoded.
+func SubmitOrderParamsFromMap(data map[string]any) (SubmitOrderParams, error) {
+	params := SubmitOrderParams{IdempotencyKey: utils.GetValue[string]("idempotencyKey", data)}
+	switch v := data["order"].(type) {
+	case nil:
+	case map[string]any:
+		if err := utils.MapToStruct(v, &params.Order); err != nil {
+			return params, fmt.Errorf("order: %w", err)
+		}
+	default:
+		return params, fmt.Errorf("order must be an object, got %T", v)
+	}
+	return params, nil
+}
+
+// AmendOrderParams contains parameters for the amendOrder command. Empty
+// Quantity or Price fields are left unchanged.
+type AmendOrderParams struct {
+	IdempotencyKey string `json:"idempotencyKey" mapstructure:"idempotencyKey" validate:"required"`
+	Symbol         string `json:"symbol" mapstructure:"symbol" validate:"required"`
+	OrderID        string `json:"orderId" mapstructure:"orderId" validate:"required"`
+	Quantity       string `json:"quantity,omitempty" mapstructure:"quantity"`
+	Price          string `json:"price,omitempty" mapstructure:"price"`
+}
+
+func (p AmendOrderParams) Validate() error {
+	if p.IdempotencyKey == "" {
+		return fmt.Errorf("idempotencyKey is required")
+	}
+	if p.Symbol == "" || p.OrderID == "" {
+		return fmt.Errorf("symbol and orderId are required")
+	}
+	if p.Quantity == "" && p.Price == "" {
+		return fmt.Errorf("quantity or price is required")
+	}
+	return nil
+}
+
+// AmendOrderParamsFromMap extracts AmendOrderParams from validated map
+func AmendOrderParamsFromMap(data map[string]any) AmendOrderParams {
+	return AmendOrderParams{
+		IdempotencyKey: utils.GetValue[string]("idempotencyKey", data),
+		Symbol:         utils.GetValue[string]("symbol", data),
+		OrderID:        utils.GetValue[string]("orderId", data),
+		Quantity:       utils.GetValue[string]("quantity", data),
+		Price:          utils.GetValue[string]("price", data),
+	}
+}
+
+// OrderRefParams contains parameters for the cancelOrder and getOrder commands
+type OrderRefParams struct {
+	Symbol  string `json:"symbol" mapstructure:"symbol" validate:"required"`
+	OrderID string `json:"orderId" mapstructure:"orderId" validate:"required"`
+}
+
+func (p OrderRefParams) Validate() error {
+	if p.Symbol == "" || p.OrderID == "" {
+		return fmt.Errorf("symbol and orderId are required")
+	}
+	return nil
+}
+
+// OrderRefParamsFromMap extracts OrderRefParams from validated map
+func OrderRefParamsFromMap(data map[string]any) OrderRefParams {
+	return OrderRefParams{
+		Symbol:  utils.GetValue[string]("symbol", data),
+		OrderID: utils.GetValue[string]("orderId", data),
+	}
+}
+
+// OpenOrdersParams contains parameters for the openOrders command. An empty
+// Symbol requests the open orders of all markets.
+type OpenOrdersParams struct {
+	Symbol string `json:"symbol,omitempty" mapstructure:"symbol"`
+}
+
+func (p OpenOrdersParams) Validate() error {
+	return nil
+}
+
+// OpenOrdersParamsFromMap extracts OpenOrdersParams from validated map
+func OpenOrdersParamsFromMap(data map[string]any) OpenOrdersParams {
+	return OpenOrdersParams{Symbol: utils.GetValue[string]("symbol", data)}
+}
+
+// validateOrder checks the venue independent fields of an order
+func validateOrder(o tt.Order) error {
+	if o.Symbol == "" {
+		return fmt.Errorf("order.symbol is required")
+	}
+	if o.Side != tt.SideBuy && o.Side != tt.SideSell {
+		return fmt.Errorf("order.side must be %q or %q", tt.SideBuy, tt.SideSell)
+	}
+	switch o.Type {
+	case tt.OrderTypeMarket:
+	case tt.OrderTypeLimit:
+		if o.Price == "" {
+			return fmt.Errorf("order.price is required for limit orders")
+		}
+	default:
+		return fmt.Errorf("order.type must be %q or %q", tt.OrderTypeMarket, tt.OrderTypeLimit)
+	}
+	if o.Quantity == "" {
+		return fmt.Errorf("order.quantity is required")
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Package execution defines the order routing contract between the host and
+// execution plugins. It is independent of data sourcing: any plugin that
+// registers the commands below can receive orders from the terminal.
+//
+//	func (p *MyBroker) RegisterCommands(router *plugin.CommandRouter) {
+//	    execution.RegisterCommands(router, p.router)
+//	}
+//
+// Failures are reported with an ErrorCode in the response data, so the host
+// can tell a rate limit from an insufficient balance without parsing venue
+// messages.
+package execution
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Router submits and manages orders on a venue. Errors should be created with
+// the constructors of this package; other errors are reported as
+// ErrCodeInternal.
+type Router interface {
+	// Submit places order. key is the idempotency key of the request and is
+	// passed as client order ID when order has none.
+	Submit(key string, order tt.Order) (tt.Order, error)
+	// Amend changes the quantity and/or price of an open order
+	Amend(req AmendOrderParams) (tt.Order, error)
+	// Cancel cancels an open order
+	Cancel(symbol, id string) error
+	// Query returns the current state of an order
+	Query(symbol, id string) (tt.Order, error)
+	// OpenOrders returns the open orders of symbol, or of all markets if symbol is empty
+	OpenOrders(symbol string) ([]tt.Order, error)
+}
+
+// ErrorInfo is the response data of a failed command
+type ErrorInfo struct {
+	Code      ErrorCode `json:"code"`
+	Retryable bool      `json:"retryable,omitempty"`
+}
+
+// SubmitResult is the response data of the submitOrder and amendOrder commands
+type SubmitResult struct {
+	Order     tt.Order `json:"order"`
+	Duplicate bool     `json:"duplicate,omitempty"` // The idempotency key was seen before; Order is the original result
+}
+
+// maxIdempotencyKeys bounds the remembered results. Hosts retry within
+// seconds, so only the most recent keys matter.
+const maxIdempotencyKeys = 1024
+
+// RegisterCommands registers the execution commands on router, served by r.
+// Successful submits and amends are remembered by idempotency key, so a
+// retried request returns the original order instead of trading twice.
+func RegisterCommands(router *plugin.CommandRouter, r Router) {
+	h := &handler{router: r, results: make(map[string]tt.Order)}
+	router.Register(CMD_SUBMIT_ORDER, h.submit)
+	router.Register(CMD_AMEND_ORDER, h.amend)
+	router.Register(CMD_CANCEL_ORDER, h.cancel)
+	router.Register(CMD_GET_ORDER, h.get)
+	router.Register(CMD_OPEN_ORDERS, h.openOrders)
+}
+
+type handler struct {
+	router Router
+
+	mu      sync.Mutex
+	results map[string]tt.Order
+	keys    []string // Insertion order of results, oldest first
+}
+
+func (h *handler) submit(params map[string]any) plugin.Response {
+	p, err := SubmitOrderParamsFromMap(params)
+	if err == nil {
+		err = p.Validate()
+	}
+	if err != nil {
+		return errorResponse(&Error{Code: ErrCodeInvalidOrder, Err: err})
+	}
+	if order, ok := h.lookup(p.IdempotencyKey); ok {
+		return plugin.SuccessResponse(SubmitResult{Order: order, Duplicate: true})
+	}
+
+	order, err := h.router.Submit(p.IdempotencyKey, p.Order)
+	if err != nil {
+		return errorResponse(err)
+	}
+	h.remember(p.IdempotencyKey, order)
+	return plugin.SuccessResponse(SubmitResult{Order: order})
+}
+
+func (h *handler) amend(params map[string]any) plugin.Response {
+	p := AmendOrderParamsFromMap(params)
+	if err := p.Validate(); err != nil {
+		return errorResponse(&Error{Code: ErrCodeInvalidOrder, Err: err})
+	}
+	if order, ok := h.lookup(p.IdempotencyKey); ok {
+		return plugin.SuccessResponse(SubmitResult{Order: order, Duplicate: true})
+	}
+
+	order, err := h.router.Amend(p)
+	if err != nil {
+		return errorResponse(err)
+	}
+	h.remember(p.IdempotencyKey, order)
+	return plugin.SuccessResponse(SubmitResult{Order: order})
+}
+
+func (h *handler) cancel(params map[string]any) plugin.Response {
+	p := OrderRefParamsFromMap(params)
+	if err := p.Validate(); err != nil {
+		return errorResponse(&Error{Code: ErrCodeInvalidOrder, Err: err})
+	}
+	if err := h.router.Cancel(p.Symbol, p.OrderID); err != nil {
+		return errorResponse(err)
+	}
+	return plugin.SuccessResponse(nil)
+}
+
+func (h *handler) get(params map[string]any) plugin.Response {
+	p := OrderRefParamsFromMap(params)
+	if err := p.Validate(); err != nil {
+		return errorResponse(&Error{Code: ErrCodeInvalidOrder, Err: err})
+	}
+	order, err := h.router.Query(p.Symbol, p.OrderID)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return plugin.SuccessResponse(order)
+}
+
+func (h *handler) openOrders(params map[string]any) plugin.Response {
+	p := OpenOrdersParamsFromMap(params)
+	orders, err := h.router.OpenOrders(p.Symbol)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if orders == nil {
+		orders = []tt.Order{}
+	}
+	return plugin.SuccessResponse(orders)
+}
+
+func (h *handler) lookup(key string) (tt.Order, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	order, ok := h.results[key]
+	return order, ok
+}
+
+func (h *handler) remember(key string, order tt.Order) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.results[key]; ok {
+		return
+	}
+	if len(h.keys) >= maxIdempotencyKeys {
+		delete(h.results, h.keys[0])
+		h.keys = h.keys[1:]
+	}
+	h.results[key] = order
+	h.keys = append(h.keys, key)
+}
+
+// errorResponse converts err into a failed response carrying its code.
+// Rate limit delays are passed on as RetryAfterSeconds.
+func errorResponse(err error) plugin.Response {
+	resp := plugin.ErrorResponse(err)
+	info := ErrorInfo{Code: ErrCodeInternal}
+
+	var coded *Error
+	if errors.As(err, &coded) {
+		info.Code = coded.Code
+		info.Retryable = coded.Retryable
+		if coded.RetryAfter > 0 {
+			seconds := int64(coded.RetryAfter.Seconds())
+			resp.RetryAfterSeconds = &seconds
+		}
+	}
+	resp.Data = info
+	return resp
+}
+
+// executorRouter adapts a trading.OrderExecutor to Router
+type executorRouter struct {
+	exec tt.OrderExecutor
+}
+
+// FromExecutor serves the execution commands with exec, e.g. a paper trading
+// account. OrderExecutor has no amend operation, so amendOrder fails with
+// ErrCodeUnsupported.
+func FromExecutor(exec tt.OrderExecutor) Router {
+	return executorRouter{exec: exec}
+}
+
+func (r executorRouter) Submit(key string, order tt.Order) (tt.Order, error) {
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = key
+	}
+	return r.exec.PlaceOrder(order)
+}
+
+func (r executorRouter) Amend(AmendOrderParams) (tt.Order, error) {
+	return tt.Order{}, Unsupported("amendOrder")
+}
+
+func (r executorRouter) Cancel(symbol, id string) error {
+	return r.exec.CancelOrder(symbol, id)
+}
+
+func (r executorRouter) Query(symbol, id string) (tt.Order, error) {
+	return r.exec.GetOrder(symbol, id)
+}
+
+func (r executorRouter) OpenOrders(symbol string) ([]tt.Order, error) {
+	return r.exec.OpenOrders(symbol)
+}
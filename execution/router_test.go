@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+type fakeRouter struct {
+	Router
+	submits int
+	err     error
+}
+
+func (r *fakeRouter) Submit(key string, order tt.Order) (tt.Order, error) {
+	if r.err != nil {
+		return order, r.err
+	}
+	r.submits++
+	order.ID = fmt.Sprint(r.submits)
+	order.Status = tt.OrderStatusOpen
+	return order, nil
+}
+
+func submitParams(key string) map[string]any {
+	return map[string]any{
+		"idempotencyKey": key,
+		"order": map[string]any{
+			"symbol": "BTCUSDT", "side": "buy", "type": "limit", "quantity": "0.1", "price": "50000",
+		},
+	}
+}
+
+func TestSubmitIsIdempotent(t *testing.T) {
+	r := &fakeRouter{}
+	router := plugin.NewCommandRouter()
+	RegisterCommands(router, r)
+
+	first := router.Handle(plugin.Command{Name: CMD_SUBMIT_ORDER, Params: submitParams("k1")})
+	retry := router.Handle(plugin.Command{Name: CMD_SUBMIT_ORDER, Params: submitParams("k1")})
+	if !first.Result || !retry.Result {
+		t.Fatal(first.Error, retry.Error)
+	}
+	if r.submits != 1 {
+		t.Fatalf("expected 1 submit, got %d", r.submits)
+	}
+	res := retry.Data.(SubmitResult)
+	if !res.Duplicate || res.Order.ID != "1" {
+		t.Fatalf("unexpected retry result %+v", res)
+	}
+}
+
+func TestSubmitErrorCodes(t *testing.T) {
+	r := &fakeRouter{err: RateLimited(3*time.Second, fmt.Errorf("too many requests"))}
+	router := plugin.NewCommandRouter()
+	RegisterCommands(router, r)
+
+	resp := router.Handle(plugin.Command{Name: CMD_SUBMIT_ORDER, Params: submitParams("k1")})
+	info := resp.Data.(ErrorInfo)
+	if resp.Result || info.Code != ErrCodeRateLimited || !info.Retryable {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.RetryAfterSeconds == nil || *resp.RetryAfterSeconds != 3 {
+		t.Fatalf("expected retry after 3s, got %v", resp.RetryAfterSeconds)
+	}
+
+	params := submitParams("k2")
+	params["order"].(map[string]any)["price"] = ""
+	resp = router.Handle(plugin.Command{Name: CMD_SUBMIT_ORDER, Params: params})
+	if resp.Data.(ErrorInfo).Code != ErrCodeInvalidOrder {
+		t.Fatalf("expected invalid order, got %+v", resp)
+	}
+
+	for name, order := range map[string]any{
+		"not an object":     "BTCUSDT buy 0.1",
+		"undecodable field": map[string]any{"symbol": "BTCUSDT", "side": "buy", "type": "market", "quantity": "0.1", "price": map[string]any{"v": 1}},
+	} {
+		params := submitParams("k3")
+		params["order"] = order
+		resp = router.Handle(plugin.Command{Name: CMD_SUBMIT_ORDER, Params: params})
+		if resp.Data.(ErrorInfo).Code != ErrCodeInvalidOrder {
+			t.Errorf("%s: expected invalid order, got %+v", name, resp)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package fix
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestSessionEncodeRoundTrip(t *testing.T) {
+	s := NewSession("CLIENT", "BROKER")
+	s.SetClock(clock.Func(func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }))
+
+	raw := s.Encode(Logon(30, "", ""))
+	want := "8=FIX.4.4|9=67|35=A|49=CLIENT|56=BROKER|34=1|52=20240501-12:00:00.000|98=0|108=30|10="
+	if got := strings.ReplaceAll(string(raw), "\x01", "|"); !strings.HasPrefix(got, want) {
+		t.Fatalf("unexpected encoding %s", got)
+	}
+
+	msg, err := Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MsgType() != MsgTypeLogon {
+		t.Fatalf("unexpected MsgType %q", msg.MsgType())
+	}
+	if n, _ := msg.GetInt(TagMsgSeqNum); n != 1 {
+		t.Fatalf("expected seq 1, got %d", n)
+	}
+}
+
+func TestNextSplitsStream(t *testing.T) {
+	a := Heartbeat("").Bytes()
+	b := TestRequest("t1").Bytes()
+	buf := append(append([]byte{}, a...), b[:5]...)
+
+	msg, rest, err := Next(buf)
+	if err != nil || msg.MsgType() != MsgTypeHeartbeat {
+		t.Fatalf("unexpected %v %v", msg, err)
+	}
+	if _, _, err := Next(rest); !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("expected ErrIncomplete, got %v", err)
+	}
+
+	msg, rest, err = Next(append(rest, b[5:]...))
+	if err != nil || len(rest) != 0 {
+		t.Fatal(err, len(rest))
+	}
+	if id, _ := msg.Get(TagTestReqID); id != "t1" {
+		t.Fatalf("unexpected TestReqID %q", id)
+	}
+
+	corrupt := append([]byte{}, a...)
+	corrupt[len(corrupt)-2] = '0'
+	if _, err := Decode(corrupt); err == nil || errors.Is(err, ErrIncomplete) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+}
+
+func TestParseExecutionReport(t *testing.T) {
+	m := NewMessage(MsgTypeExecutionReport).
+		Set(TagOrderID, "42").
+		Set(TagClOrdID, "c1").
+		Set(TagOrdStatus, "1").
+		Set(TagSymbol, "EUR/USD").
+		Set(TagSide, SideSell).
+		Set(TagOrderQty, "1000000").
+		Set(TagPrice, "1.0850").
+		Set(TagCumQty, "250000").
+		Set(TagTransactTime, "20240501-12:00:01.500")
+
+	msg, err := Decode(m.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseExecutionReport(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := r.Order()
+	if o.ID != "42" || o.Side != tt.SideSell || o.Status != tt.OrderStatusPartiallyFilled || o.Type != tt.OrderTypeLimit {
+		t.Fatalf("unexpected order %+v", o)
+	}
+	if o.CreatedAt != time.Date(2024, 5, 1, 12, 0, 1, 500e6, time.UTC).UnixMilli() {
+		t.Fatalf("unexpected time %d", o.CreatedAt)
+	}
+}
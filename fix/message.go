@@ -0,0 +1,219 @@
+// Package fix encodes and decodes FIX 4.4 tag=value messages. It covers the
+// session and market data subset institutional data and broker plugins need
+// (logon, heartbeat, market data request, execution report) and leaves the
+// transport to the host's WS/TCP streams, so plugins don't have to embed a
+// full FIX engine.
+//
+//	session := fix.NewSession("CLIENT", "BROKER")
+//	raw := session.Encode(fix.Logon(30, user, password))
+//
+//	// in the stream message handler
+//	msg, rest, err := fix.Next(buf)
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// SOH separates the fields of a message
+const SOH = '\x01'
+
+// BeginString of FIX 4.4 messages
+const BeginString = "FIX.4.4"
+
+// ErrIncomplete is returned by Next when the buffer does not hold a whole message yet
+var ErrIncomplete = errors.New("fix: incomplete message")
+
+// Field is a single tag=value pair
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// Message is an ordered list of body fields. The header fields BeginString,
+// BodyLength and CheckSum are added on encoding and stripped on decoding;
+// repeating groups are kept in order as repeated tags.
+type Message struct {
+	Fields []Field
+}
+
+// NewMessage creates a message of the given MsgType
+func NewMessage(msgType string) *Message {
+	return &Message{Fields: []Field{{Tag: TagMsgType, Value: msgType}}}
+}
+
+// Set appends a field and returns m for chaining
+func (m *Message) Set(tag int, value string) *Message {
+	m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+	return m
+}
+
+// SetInt appends an integer field
+func (m *Message) SetInt(tag, value int) *Message {
+	return m.Set(tag, strconv.Itoa(value))
+}
+
+// Get returns the first value of tag
+func (m *Message) Get(tag int) (string, bool) {
+	for _, f := range m.Fields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// GetInt returns the first value of tag as integer
+func (m *Message) GetInt(tag int) (int, error) {
+	v, ok := m.Get(tag)
+	if !ok {
+		return 0, fmt.Errorf("fix: tag %d missing", tag)
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("fix: tag %d: %w", tag, err)
+	}
+	return n, nil
+}
+
+// GetAll returns every value of tag, e.g. the entries of a repeating group
+func (m *Message) GetAll(tag int) []string {
+	var values []string
+	for _, f := range m.Fields {
+		if f.Tag == tag {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// MsgType returns the message type, e.g. MsgTypeHeartbeat
+func (m *Message) MsgType() string {
+	v, _ := m.Get(TagMsgType)
+	return v
+}
+
+// Bytes encodes m with BeginString, BodyLength and CheckSum. MsgType is
+// moved to the front of the body as the standard requires.
+func (m *Message) Bytes() []byte {
+	var body bytes.Buffer
+	if t := m.MsgType(); t != "" {
+		writeField(&body, TagMsgType, t)
+	}
+	for _, f := range m.Fields {
+		if f.Tag == TagMsgType {
+			continue
+		}
+		writeField(&body, f.Tag, f.Value)
+	}
+
+	var out bytes.Buffer
+	writeField(&out, TagBeginString, BeginString)
+	writeField(&out, TagBodyLength, strconv.Itoa(body.Len()))
+	out.Write(body.Bytes())
+	writeField(&out, TagCheckSum, fmt.Sprintf("%03d", checksum(out.Bytes())))
+	return out.Bytes()
+}
+
+// String renders m with | instead of SOH, for logging
+func (m *Message) String() string {
+	return string(bytes.ReplaceAll(m.Bytes(), []byte{SOH}, []byte{'|'}))
+}
+
+// Decode parses a single complete message and verifies its body length and
+// checksum
+func Decode(raw []byte) (*Message, error) {
+	msg, rest, err := Next(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("fix: %d trailing bytes", len(rest))
+	}
+	return msg, nil
+}
+
+// Next decodes the first message of buf and returns the remaining bytes.
+// Stream transports may split or merge messages, so handlers append incoming
+// data to a buffer and call Next until it returns ErrIncomplete.
+func Next(buf []byte) (*Message, []byte, error) {
+	begin, pos, err := readField(buf, 0)
+	if err != nil {
+		return nil, buf, err
+	}
+	if begin.Tag != TagBeginString {
+		return nil, buf, fmt.Errorf("fix: message must start with tag 8, got %d", begin.Tag)
+	}
+	length, pos, err := readField(buf, pos)
+	if err != nil {
+		return nil, buf, err
+	}
+	if length.Tag != TagBodyLength {
+		return nil, buf, fmt.Errorf("fix: expected tag 9 after BeginString, got %d", length.Tag)
+	}
+	bodyLen, err := strconv.Atoi(length.Value)
+	if err != nil || bodyLen < 0 {
+		return nil, buf, fmt.Errorf("fix: invalid BodyLength %q", length.Value)
+	}
+
+	end := pos + bodyLen
+	if end > len(buf) {
+		return nil, buf, ErrIncomplete
+	}
+	sum, next, err := readField(buf, end)
+	if err != nil {
+		return nil, buf, err
+	}
+	if sum.Tag != TagCheckSum {
+		return nil, buf, fmt.Errorf("fix: expected tag 10 after body, got %d", sum.Tag)
+	}
+	if want := fmt.Sprintf("%03d", checksum(buf[:end])); sum.Value != want {
+		return nil, buf, fmt.Errorf("fix: checksum %s, expected %s", sum.Value, want)
+	}
+
+	msg := &Message{}
+	for p := pos; p < end; {
+		var f Field
+		if f, p, err = readField(buf[:end], p); err != nil {
+			return nil, buf, err
+		}
+		msg.Fields = append(msg.Fields, f)
+	}
+	return msg, buf[next:], nil
+}
+
+func writeField(b *bytes.Buffer, tag int, value string) {
+	b.WriteString(strconv.Itoa(tag))
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte(SOH)
+}
+
+// readField parses the field starting at pos and returns the position after it
+func readField(buf []byte, pos int) (Field, int, error) {
+	rest := buf[pos:]
+	end := bytes.IndexByte(rest, SOH)
+	if end < 0 {
+		return Field{}, pos, ErrIncomplete
+	}
+	eq := bytes.IndexByte(rest[:end], '=')
+	if eq <= 0 {
+		return Field{}, pos, fmt.Errorf("fix: malformed field %q", rest[:end])
+	}
+	tag, err := strconv.Atoi(string(rest[:eq]))
+	if err != nil {
+		return Field{}, pos, fmt.Errorf("fix: malformed tag %q", rest[:eq])
+	}
+	return Field{Tag: tag, Value: string(rest[eq+1 : end])}, pos + end + 1, nil
+}
+
+func checksum(b []byte) int {
+	sum := 0
+	for _, c := range b {
+		sum += int(c)
+	}
+	return sum % 256
+}
@@ -0,0 +1,276 @@
+package fix
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// Session stamps outgoing messages with the CompIDs, sequence number and
+// sending time, and tracks the sequence number expected from the counterparty
+type Session struct {
+	SenderCompID string
+	TargetCompID string
+
+	mu       sync.Mutex
+	outSeq   int
+	inSeq    int
+	clock    clock.Clock
+	lastSent time.Time
+}
+
+// NewSession creates a session starting at sequence number 1 in both directions
+func NewSession(senderCompID, targetCompID string) *Session {
+	return &Session{
+		SenderCompID: senderCompID,
+		TargetCompID: targetCompID,
+		outSeq:       1,
+		inSeq:        1,
+		clock:        wasmutils.HostClock,
+	}
+}
+
+// SetClock replaces the clock used for SendingTime
+func (s *Session) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+// Encode adds the session header to m and encodes it. Every call consumes a
+// sequence number.
+func (s *Session) Encode(m *Message) []byte {
+	s.mu.Lock()
+	now := s.clock.Now().UTC()
+	seq := s.outSeq
+	s.outSeq++
+	s.lastSent = now
+	s.mu.Unlock()
+
+	out := NewMessage(m.MsgType())
+	out.Set(TagSenderCompID, s.SenderCompID).
+		Set(TagTargetCompID, s.TargetCompID).
+		SetInt(TagMsgSeqNum, seq).
+		Set(TagSendingTime, now.Format(timestampLayout))
+	for _, f := range m.Fields {
+		if f.Tag != TagMsgType {
+			out.Fields = append(out.Fields, f)
+		}
+	}
+	return out.Bytes()
+}
+
+// Receive checks the sequence number of an incoming message. A gap means
+// messages were lost and the session has to be reset or resent.
+func (s *Session) Receive(m *Message) error {
+	seq, err := m.GetInt(TagMsgSeqNum)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq != s.inSeq {
+		expected := s.inSeq
+		if seq > s.inSeq {
+			s.inSeq = seq + 1
+		}
+		return fmt.Errorf("fix: sequence gap, expected %d got %d", expected, seq)
+	}
+	s.inSeq++
+	return nil
+}
+
+// HeartbeatDue reports whether nothing was sent for interval, so a
+// Heartbeat has to be sent to keep the session alive
+func (s *Session) HeartbeatDue(interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clock.Now().Sub(s.lastSent) >= interval
+}
+
+// Reset restarts both sequence numbers at 1, e.g. for a Logon with ResetSeqNumFlag
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outSeq = 1
+	s.inSeq = 1
+}
+
+// Logon creates a Logon message without encryption. Credentials are
+// optional and omitted when empty.
+func Logon(heartbeatSeconds int, username, password string) *Message {
+	m := NewMessage(MsgTypeLogon).
+		Set(TagEncryptMethod, "0").
+		SetInt(TagHeartBtInt, heartbeatSeconds)
+	if username != "" {
+		m.Set(TagUsername, username)
+	}
+	if password != "" {
+		m.Set(TagPassword, password)
+	}
+	return m
+}
+
+// Logout creates a Logout message
+func Logout(text string) *Message {
+	m := NewMessage(MsgTypeLogout)
+	if text != "" {
+		m.Set(TagText, text)
+	}
+	return m
+}
+
+// Heartbeat creates a Heartbeat. testReqID must be set when answering a
+// TestRequest.
+func Heartbeat(testReqID string) *Message {
+	m := NewMessage(MsgTypeHeartbeat)
+	if testReqID != "" {
+		m.Set(TagTestReqID, testReqID)
+	}
+	return m
+}
+
+// TestRequest creates a TestRequest the counterparty answers with a Heartbeat
+func TestRequest(testReqID string) *Message {
+	return NewMessage(MsgTypeTestRequest).Set(TagTestReqID, testReqID)
+}
+
+// MarketDataRequest describes a MarketDataRequest message
+type MarketDataRequest struct {
+	ReqID        string
+	Subscription string   // SubscriptionSnapshot, SubscriptionSubscribe or SubscriptionUnsubscribe
+	Depth        int      // 0 is full book, 1 top of book
+	EntryTypes   []string // e.g. MDEntryBid, MDEntryOffer
+	Symbols      []string
+}
+
+// Message encodes the request as MarketDataRequest with incremental updates
+func (r MarketDataRequest) Message() *Message {
+	m := NewMessage(MsgTypeMarketDataRequest).
+		Set(TagMDReqID, r.ReqID).
+		Set(TagSubscriptionRequestType, r.Subscription).
+		SetInt(TagMarketDepth, r.Depth)
+	if r.Subscription == SubscriptionSubscribe {
+		m.Set(TagMDUpdateType, "1")
+	}
+	m.SetInt(TagNoMDEntryTypes, len(r.EntryTypes))
+	for _, t := range r.EntryTypes {
+		m.Set(TagMDEntryType, t)
+	}
+	m.SetInt(TagNoRelatedSym, len(r.Symbols))
+	for _, s := range r.Symbols {
+		m.Set(TagSymbol, s)
+	}
+	return m
+}
+
+// ExecutionReport is the subset of ExecutionReport fields describing an
+// order's state and its last fill
+type ExecutionReport struct {
+	OrderID      string
+	ClOrdID      string
+	ExecID       string
+	ExecType     string
+	OrdStatus    string
+	Symbol       string
+	Side         string // SideBuy or SideSell
+	OrderQty     string
+	Price        string
+	LastPx       string
+	LastQty      string
+	CumQty       string
+	LeavesQty    string
+	AvgPx        string
+	Text         string
+	TransactTime time.Time
+}
+
+// ParseExecutionReport extracts an ExecutionReport from m
+func ParseExecutionReport(m *Message) (ExecutionReport, error) {
+	if t := m.MsgType(); t != MsgTypeExecutionReport {
+		return ExecutionReport{}, fmt.Errorf("fix: expected ExecutionReport, got MsgType %q", t)
+	}
+	get := func(tag int) string {
+		v, _ := m.Get(tag)
+		return v
+	}
+	r := ExecutionReport{
+		OrderID:   get(TagOrderID),
+		ClOrdID:   get(TagClOrdID),
+		ExecID:    get(TagExecID),
+		ExecType:  get(TagExecType),
+		OrdStatus: get(TagOrdStatus),
+		Symbol:    get(TagSymbol),
+		Side:      get(TagSide),
+		OrderQty:  get(TagOrderQty),
+		Price:     get(TagPrice),
+		LastPx:    get(TagLastPx),
+		LastQty:   get(TagLastQty),
+		CumQty:    get(TagCumQty),
+		LeavesQty: get(TagLeavesQty),
+		AvgPx:     get(TagAvgPx),
+		Text:      get(TagText),
+	}
+	if r.OrderID == "" {
+		return r, fmt.Errorf("fix: ExecutionReport without OrderID")
+	}
+	if v := get(TagTransactTime); v != "" {
+		t, err := parseTimestamp(v)
+		if err != nil {
+			return r, fmt.Errorf("fix: TransactTime: %w", err)
+		}
+		r.TransactTime = t
+	}
+	return r, nil
+}
+
+// Order converts the report to the order state it describes
+func (r ExecutionReport) Order() tt.Order {
+	o := tt.Order{
+		ID:            r.OrderID,
+		ClientOrderID: r.ClOrdID,
+		Symbol:        r.Symbol,
+		Quantity:      r.OrderQty,
+		Price:         r.Price,
+		FilledQty:     r.CumQty,
+		Type:          tt.OrderTypeLimit,
+	}
+	if r.Price == "" {
+		o.Type = tt.OrderTypeMarket
+	}
+	switch r.Side {
+	case SideBuy:
+		o.Side = tt.SideBuy
+	case SideSell:
+		o.Side = tt.SideSell
+	}
+	switch r.OrdStatus {
+	case "0", "A", "E": // New, PendingNew, PendingReplace
+		o.Status = tt.OrderStatusOpen
+	case "1":
+		o.Status = tt.OrderStatusPartiallyFilled
+	case "2":
+		o.Status = tt.OrderStatusFilled
+	case "4", "C": // Canceled, Expired
+		o.Status = tt.OrderStatusCanceled
+	case "8":
+		o.Status = tt.OrderStatusRejected
+	}
+	if !r.TransactTime.IsZero() {
+		o.CreatedAt = r.TransactTime.UnixMilli()
+	}
+	return o
+}
+
+// parseTimestamp accepts UTCTimestamp values with and without milliseconds
+func parseTimestamp(v string) (time.Time, error) {
+	if t, err := time.Parse(timestampLayout, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102-15:04:05", v)
+}
@@ -0,0 +1,79 @@
+package fix
+
+// Tags used by the supported messages
+const (
+	TagAvgPx                   = 6
+	TagBeginString             = 8
+	TagBodyLength              = 9
+	TagCheckSum                = 10
+	TagClOrdID                 = 11
+	TagCumQty                  = 14
+	TagExecID                  = 17
+	TagLastPx                  = 31
+	TagLastQty                 = 32
+	TagMsgSeqNum               = 34
+	TagMsgType                 = 35
+	TagOrderID                 = 37
+	TagOrderQty                = 38
+	TagOrdStatus               = 39
+	TagOrdType                 = 40
+	TagPrice                   = 44
+	TagSenderCompID            = 49
+	TagSendingTime             = 52
+	TagSide                    = 54
+	TagSymbol                  = 55
+	TagTargetCompID            = 56
+	TagText                    = 58
+	TagTransactTime            = 60
+	TagEncryptMethod           = 98
+	TagHeartBtInt              = 108
+	TagTestReqID               = 112
+	TagResetSeqNumFlag         = 141
+	TagNoRelatedSym            = 146
+	TagExecType                = 150
+	TagLeavesQty               = 151
+	TagMDReqID                 = 262
+	TagSubscriptionRequestType = 263
+	TagMarketDepth             = 264
+	TagMDUpdateType            = 265
+	TagNoMDEntryTypes          = 267
+	TagMDEntryType             = 269
+	TagUsername                = 553
+	TagPassword                = 554
+)
+
+// Message types
+const (
+	MsgTypeHeartbeat          = "0"
+	MsgTypeTestRequest        = "1"
+	MsgTypeReject             = "3"
+	MsgTypeLogout             = "5"
+	MsgTypeExecutionReport    = "8"
+	MsgTypeLogon              = "A"
+	MsgTypeMarketDataRequest  = "V"
+	MsgTypeMarketDataSnapshot = "W"
+	MsgTypeMarketDataRefresh  = "X"
+)
+
+// Side values
+const (
+	SideBuy  = "1"
+	SideSell = "2"
+)
+
+// SubscriptionRequestType values
+const (
+	SubscriptionSnapshot    = "0"
+	SubscriptionSubscribe   = "1"
+	SubscriptionUnsubscribe = "2"
+)
+
+// MDEntryType values
+const (
+	MDEntryBid   = "0"
+	MDEntryOffer = "1"
+	MDEntryTrade = "2"
+)
+
+// timestampLayout is the UTCTimestamp format with milliseconds
+const timestampLayout = "20060102-15:04:05.000"
@@ -0,0 +1,145 @@
+// Package hostinfo tells plugins what the running host supports, so features
+// built on newer host functions can be skipped on older hosts instead of
+// failing.
+//
+// Hosts advertise their capabilities as JSON in the plugin config under
+// ConfigKey. A config entry is used rather than a host function because a
+// missing import would fail on exactly the hosts this package is meant to
+// handle. Hosts that predate capability negotiation advertise nothing; they
+// are assumed to provide the functions listed in Legacy.
+//
+//	if hostinfo.Has(hostinfo.ReportProgress) {
+//	    datapipe.ReportProgress(p)
+//	}
+package hostinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// ConfigKey is the host config key holding the JSON encoded Capabilities
+const ConfigKey = "host_capabilities"
+
+// Host functions that can be checked with Has
+const (
+	HTTPRequest       = host.HTTPRequest
+	HTTPRequestStream = host.HTTPRequestStream
+	HTTPStreamRead    = host.HTTPStreamRead
+	HTTPStreamClose   = host.HTTPStreamClose
+	HTTPClearCookies  = host.HTTPClearCookies
+	SignRequest       = host.SignRequest
+	LogRecord         = host.LogRecord
+	LogRecords        = host.LogRecords
+	TimeNow           = host.TimeNow
+	ReportProgress    = host.ReportProgress
+	AlertTrigger      = host.AlertTrigger
+	PluginCall        = host.PluginCall
+	WSConnect         = host.WSConnect
+	WSSend            = host.WSSend
+	WSReceive         = host.WSReceive
+	WSClose           = host.WSClose
+)
+
+// Wire formats of export input and output
+const (
+	WireJSON    = "json"
+	WireMsgpack = "msgpack"
+)
+
+// ErrUnsupported is returned by Require for host functions the host lacks
+var ErrUnsupported = errors.New("not supported by this host")
+
+// Legacy are the host functions of hosts without capability negotiation
+var Legacy = []string{
+	HTTPRequest,
+	LogRecord,
+	TimeNow,
+}
+
+// Limits are resource limits the host enforces. Zero means unknown or unlimited.
+type Limits struct {
+	MaxMemoryBytes       int64 `json:"maxMemoryBytes,omitempty"`
+	MaxHTTPResponseBytes int64 `json:"maxHttpResponseBytes,omitempty"`
+	CallTimeoutMs        int64 `json:"callTimeoutMs,omitempty"`
+	MaxStreams           int   `json:"maxStreams,omitempty"` // Concurrent WS/HTTP streams
+}
+
+// Capabilities describes the running host
+type Capabilities struct {
+	HostVersion     string   `json:"hostVersion,omitempty"`
+	ProtocolVersion int      `json:"protocolVersion"` // Incremented on breaking changes of export or host function payloads
+	Functions       []string `json:"functions"`       // Host functions available to the plugin
+	Limits          Limits   `json:"limits"`
+	// WireFormat is the format the host chose from the plugin's
+	// protocol_version export for commands and stream messages; empty means WireJSON
+	WireFormat string `json:"wireFormat,omitempty"`
+}
+
+// Has reports whether the host function name is available
+func (c Capabilities) Has(name string) bool {
+	return slices.Contains(c.Functions, name)
+}
+
+// Full returns the capabilities of a host providing every function this
+// library knows, as used by plugintest
+func Full() Capabilities {
+	return Capabilities{ProtocolVersion: 1, Functions: slices.Clone(host.Functions)}
+}
+
+type advertised struct {
+	caps Capabilities
+	ok   bool
+	wire string // resolved WireFormat
+}
+
+// current is read from the host config once per plugin instance
+var current = host.NewConfigValue(func() advertised {
+	legacy := advertised{caps: Capabilities{Functions: Legacy}, wire: WireJSON}
+	raw, found := host.Config(ConfigKey)
+	if !found || raw == "" {
+		return legacy
+	}
+	var caps Capabilities
+	if err := json.Unmarshal([]byte(raw), &caps); err != nil {
+		return legacy
+	}
+	wire := caps.WireFormat
+	if wire == "" {
+		wire = WireJSON
+	}
+	return advertised{caps: caps, ok: true, wire: wire}
+})
+
+// Get returns the capabilities of the running host. ok is false if the
+// host did not advertise any; Functions is then set to Legacy. The result
+// is shared and must not be modified.
+func Get() (caps Capabilities, ok bool) {
+	a := current.Get()
+	return a.caps, a.ok
+}
+
+// Has reports whether the running host provides the host function name
+func Has(name string) bool {
+	caps, _ := Get()
+	return caps.Has(name)
+}
+
+// Require returns an error wrapping ErrUnsupported if the running host does
+// not provide the host function name
+func Require(name string) error {
+	if !Has(name) {
+		return fmt.Errorf("host function %s: %w", name, ErrUnsupported)
+	}
+	return nil
+}
+
+// WireFormat returns the format of command and stream message payloads. It
+// is resolved with the capabilities, so exports can ask on every call.
+func WireFormat() string {
+	return current.Get().wire
+}
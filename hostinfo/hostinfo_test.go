@@ -0,0 +1,49 @@
+//go:build !wasm
+
+package hostinfo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/alerting"
+	"github.com/plusev-terminal/go-plugin-common/datapipe"
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/requester"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+func TestOlderHostFallbacks(t *testing.T) {
+	h := plugintest.New(t)
+	h.SetConfig(hostinfo.ConfigKey, "")
+
+	if _, ok := hostinfo.Get(); ok {
+		t.Fatal("expected no advertised capabilities")
+	}
+	if !hostinfo.Has(hostinfo.TimeNow) || hostinfo.Has(hostinfo.ReportProgress) {
+		t.Fatal("expected legacy function set")
+	}
+
+	if err := datapipe.ReportProgress(dt.Progress{Percent: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Progress()) != 0 {
+		t.Fatal("progress must be skipped on older hosts")
+	}
+	if err := alerting.Trigger(alerting.Alert{}); !errors.Is(err, hostinfo.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	if _, err := requester.HostSign(rt.SignRequest{}); !errors.Is(err, hostinfo.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported for sign_request, got %v", err)
+	}
+
+	h.SetCapabilities(hostinfo.Capabilities{ProtocolVersion: 1, Functions: []string{hostinfo.ReportProgress}})
+	if err := datapipe.ReportProgress(dt.Progress{Percent: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Progress()) != 1 {
+		t.Fatal("expected progress once advertised")
+	}
+}
@@ -0,0 +1,112 @@
+// Package indicator is the plugin category for chart indicators. The chart
+// asks describe_indicator for parameters and plots, then calls compute with
+// the visible candles, or compute_incremental with new candles and the state
+// of the previous call.
+//
+//	func init() {
+//	    indicator.Register(&RSI{})
+//	}
+package indicator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Indicator is implemented by indicator plugins. Params passed to Compute
+// are complete: missing values are filled with the declared defaults and all
+// values are checked against the declared parameters.
+type Indicator interface {
+	plugin.Plugin
+
+	Describe() Description
+	Compute(candles []tt.OHLCVRecord, params Params) ([]dt.Series, error)
+}
+
+// IncrementalIndicator is an Indicator that can continue a previous
+// computation from its state instead of recomputing all candles
+type IncrementalIndicator interface {
+	Indicator
+
+	ComputeIncremental(candles []tt.OHLCVRecord, params Params, state json.RawMessage) ([]dt.Series, json.RawMessage, error)
+}
+
+var registeredIndicator Indicator
+
+// Register registers an indicator plugin and generates all WASM exports.
+// Like plugin.RegisterPlugin it MUST be called in init().
+func Register(ind Indicator) {
+	registeredIndicator = ind
+	plugin.RegisterPlugin(ind)
+}
+
+// ============================================================================
+// WASM Exports - Auto-generated by Register
+// ============================================================================
+
+func init() {
+	host.RegisterExport("describe_indicator", describe_indicator)
+	host.RegisterExport("compute", compute)
+	host.RegisterExport("compute_incremental", compute_incremental)
+}
+
+//go:wasmexport describe_indicator
+func describe_indicator() int32 {
+	desc := registeredIndicator.Describe()
+	_, desc.Incremental = registeredIndicator.(IncrementalIndicator)
+	host.OutputJSON(desc)
+	return 0
+}
+
+//go:wasmexport compute
+func compute() int32 {
+	var req ComputeRequest
+	if err := host.InputJSON(&req); err != nil {
+		return computeError(fmt.Errorf("failed to parse compute request: %w", err))
+	}
+	params, err := resolve(req.Params, registeredIndicator.Describe().Parameters)
+	if err != nil {
+		return computeError(err)
+	}
+
+	series, err := registeredIndicator.Compute(req.Candles, params)
+	if err != nil {
+		return computeError(err)
+	}
+	host.OutputJSON(ComputeResponse{Success: true, Series: series})
+	return 0
+}
+
+//go:wasmexport compute_incremental
+func compute_incremental() int32 {
+	inc, ok := registeredIndicator.(IncrementalIndicator)
+	if !ok {
+		return computeError(fmt.Errorf("indicator does not support incremental computation"))
+	}
+
+	var req ComputeIncrementalRequest
+	if err := host.InputJSON(&req); err != nil {
+		return computeError(fmt.Errorf("failed to parse compute request: %w", err))
+	}
+	params, err := resolve(req.Params, inc.Describe().Parameters)
+	if err != nil {
+		return computeError(err)
+	}
+
+	series, state, err := inc.ComputeIncremental(req.Candles, params, req.State)
+	if err != nil {
+		return computeError(err)
+	}
+	host.OutputJSON(ComputeResponse{Success: true, Series: series, State: state})
+	return 0
+}
+
+func computeError(err error) int32 {
+	host.OutputJSON(ComputeResponse{Error: err.Error()})
+	return 1
+}
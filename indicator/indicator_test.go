@@ -0,0 +1,86 @@
+//go:build !wasm
+
+package indicator
+
+import (
+	"encoding/json"
+	"testing"
+
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// sma is a simple moving average used to exercise the exports
+type sma struct{}
+
+func (sma) GetMeta() m.Meta {
+	return m.Meta{PluginID: "sma", Name: "SMA", AppID: "indicators", Version: "1.0.0"}
+}
+func (sma) GetConfigFields() []plugin.ConfigField         { return nil }
+func (sma) GetRateLimits() []plugin.RateLimit             { return nil }
+func (sma) OnInit(*plugin.ConfigStore) error              { return nil }
+func (sma) OnShutdown() error                             { return nil }
+func (sma) RegisterCommands(router *plugin.CommandRouter) {}
+
+func (sma) Describe() Description {
+	one := 1.0
+	return Description{
+		ID:   "sma",
+		Name: "Simple Moving Average",
+		Parameters: []Parameter{
+			{Name: "length", Label: "Length", Type: ParamInteger, Default: 2.0, Min: &one},
+			{Name: "source", Label: "Source", Type: ParamSource, Default: "close"},
+		},
+		Outputs: []Output{{Name: "sma", Label: "SMA", Style: PlotLine}},
+		Overlay: true,
+	}
+}
+
+func (sma) Compute(candles []tt.OHLCVRecord, params Params) ([]dt.Series, error) {
+	values, err := Values(candles, Source(params.String("source")))
+	if err != nil {
+		return nil, err
+	}
+	n := params.Int("length")
+	out := dt.Series{Name: "sma"}
+	for i := n - 1; i < len(values); i++ {
+		sum := 0.0
+		for _, v := range values[i-n+1 : i+1] {
+			sum += v
+		}
+		out.Append(candles[i].OpenTime*1000, sum/float64(n))
+	}
+	return []dt.Series{out}, nil
+}
+
+func TestComputeExport(t *testing.T) {
+	Register(sma{})
+	h := plugintest.New(t)
+
+	candles := []tt.OHLCVRecord{{OpenTime: 1, Close: "1"}, {OpenTime: 2, Close: "3"}, {OpenTime: 3, Close: "5"}}
+	input, _ := json.Marshal(ComputeRequest{Candles: candles})
+	rc, output, err := h.Invoke("compute", input)
+	if err != nil || rc != 0 {
+		t.Fatalf("compute failed: %d, %v, %s", rc, err, output)
+	}
+
+	var resp ComputeResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		t.Fatal(err)
+	}
+	points := resp.Series[0].Points
+	if len(points) != 2 || points[0].Value != 2 || points[1].Value != 4 {
+		t.Fatalf("unexpected series %+v", resp.Series)
+	}
+
+	input, _ = json.Marshal(ComputeRequest{Candles: candles, Params: Params{"length": 0.0}})
+	if rc, _, _ := h.Invoke("compute", input); rc == 0 {
+		t.Fatal("expected length below minimum to be rejected")
+	}
+	if rc, _, _ := h.Invoke("compute_incremental", []byte("{}")); rc == 0 {
+		t.Fatal("expected non-incremental indicator to reject compute_incremental")
+	}
+}
@@ -0,0 +1,154 @@
+package indicator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// Params are the parameter values of a computation keyed by parameter name
+type Params map[string]any
+
+// Float returns a numeric parameter
+func (p Params) Float(name string) float64 {
+	v, _ := utils.ToFloat64(p[name])
+	return v
+}
+
+// Int returns an integer parameter
+func (p Params) Int(name string) int {
+	return int(p.Float(name))
+}
+
+// Bool returns a boolean parameter
+func (p Params) Bool(name string) bool {
+	v, _ := p[name].(bool)
+	return v
+}
+
+// String returns a select, source or color parameter
+func (p Params) String(name string) string {
+	v, _ := p[name].(string)
+	return v
+}
+
+// resolve fills missing parameters with their defaults and checks the values
+// against the declared parameters
+func resolve(params Params, declared []Parameter) (Params, error) {
+	out := make(Params, len(declared))
+	for _, d := range declared {
+		v, ok := params[d.Name]
+		if !ok || v == nil {
+			v = d.Default
+		}
+		out[d.Name] = v
+
+		switch d.Type {
+		case ParamNumber, ParamInteger:
+			n, ok := utils.ToFloat64(v)
+			if !ok {
+				return nil, fmt.Errorf("parameter %s must be a number", d.Name)
+			}
+			if d.Type == ParamInteger && n != float64(int64(n)) {
+				return nil, fmt.Errorf("parameter %s must be an integer", d.Name)
+			}
+			if d.Min != nil && n < *d.Min {
+				return nil, fmt.Errorf("parameter %s must be at least %v", d.Name, *d.Min)
+			}
+			if d.Max != nil && n > *d.Max {
+				return nil, fmt.Errorf("parameter %s must be at most %v", d.Name, *d.Max)
+			}
+		case ParamBool:
+			if _, ok := v.(bool); !ok {
+				return nil, fmt.Errorf("parameter %s must be a boolean", d.Name)
+			}
+		case ParamSelect:
+			if s, _ := v.(string); !slices.Contains(d.Options, s) {
+				return nil, fmt.Errorf("parameter %s must be one of %v", d.Name, d.Options)
+			}
+		case ParamSource:
+			if _, err := sourceFunc(Source(fmt.Sprint(v))); err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", d.Name, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Source selects the candle value an indicator is computed on
+type Source string
+
+const (
+	SourceOpen   Source = "open"
+	SourceHigh   Source = "high"
+	SourceLow    Source = "low"
+	SourceClose  Source = "close"
+	SourceVolume Source = "volume"
+	SourceHL2    Source = "hl2"
+	SourceHLC3   Source = "hlc3"
+	SourceOHLC4  Source = "ohlc4"
+)
+
+// Values extracts src from candles
+func Values(candles []tt.OHLCVRecord, src Source) ([]float64, error) {
+	fn, err := sourceFunc(src)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, len(candles))
+	for i, c := range candles {
+		if values[i], err = fn(c); err != nil {
+			return nil, fmt.Errorf("candle %d: %w", c.OpenTime, err)
+		}
+	}
+	return values, nil
+}
+
+func sourceFunc(src Source) (func(tt.OHLCVRecord) (float64, error), error) {
+	field := func(get func(tt.OHLCVRecord) string) func(tt.OHLCVRecord) (float64, error) {
+		return func(c tt.OHLCVRecord) (float64, error) {
+			return strconv.ParseFloat(get(c), 64)
+		}
+	}
+	avg := func(fields ...func(tt.OHLCVRecord) string) func(tt.OHLCVRecord) (float64, error) {
+		return func(c tt.OHLCVRecord) (float64, error) {
+			sum := 0.0
+			for _, get := range fields {
+				v, err := strconv.ParseFloat(get(c), 64)
+				if err != nil {
+					return 0, err
+				}
+				sum += v
+			}
+			return sum / float64(len(fields)), nil
+		}
+	}
+	open := func(c tt.OHLCVRecord) string { return c.Open }
+	high := func(c tt.OHLCVRecord) string { return c.High }
+	low := func(c tt.OHLCVRecord) string { return c.Low }
+	closeP := func(c tt.OHLCVRecord) string { return c.Close }
+
+	switch src {
+	case SourceOpen:
+		return field(open), nil
+	case SourceHigh:
+		return field(high), nil
+	case SourceLow:
+		return field(low), nil
+	case SourceClose, "":
+		return field(closeP), nil
+	case SourceVolume:
+		return field(func(c tt.OHLCVRecord) string { return c.Volume }), nil
+	case SourceHL2:
+		return avg(high, low), nil
+	case SourceHLC3:
+		return avg(high, low, closeP), nil
+	case SourceOHLC4:
+		return avg(open, high, low, closeP), nil
+	}
+	return nil, fmt.Errorf("unknown source %q", src)
+}
@@ -0,0 +1,95 @@
+package indicator
+
+import (
+	"encoding/json"
+
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// ParameterType is the type of an indicator parameter
+type ParameterType string
+
+const (
+	ParamNumber  ParameterType = "number"
+	ParamInteger ParameterType = "integer"
+	ParamBool    ParameterType = "bool"
+	ParamSelect  ParameterType = "select" // One of Options
+	ParamSource  ParameterType = "source" // A candle Source
+	ParamColor   ParameterType = "color"
+)
+
+// Parameter is a user-adjustable input of an indicator
+type Parameter struct {
+	Name    string        `json:"name"`
+	Label   string        `json:"label"`
+	Type    ParameterType `json:"type"`
+	Default any           `json:"default"`
+	Min     *float64      `json:"min,omitempty"`
+	Max     *float64      `json:"max,omitempty"`
+	Options []string      `json:"options,omitempty"`
+}
+
+// PlotStyle is how an output series is drawn
+type PlotStyle string
+
+const (
+	PlotLine      PlotStyle = "line"
+	PlotHistogram PlotStyle = "histogram"
+	PlotArea      PlotStyle = "area"
+	PlotColumns   PlotStyle = "columns"
+	PlotDots      PlotStyle = "dots"
+	PlotStep      PlotStyle = "step"
+)
+
+// Output is an output series of an indicator with its plot style
+type Output struct {
+	Name      string    `json:"name"`
+	Label     string    `json:"label"`
+	Style     PlotStyle `json:"style"`
+	Color     string    `json:"color,omitempty"` // e.g. "#2962ff"
+	LineWidth int       `json:"lineWidth,omitempty"`
+}
+
+// Level is a horizontal reference line, e.g. 70/30 for RSI
+type Level struct {
+	Value float64 `json:"value"`
+	Label string  `json:"label,omitempty"`
+	Color string  `json:"color,omitempty"`
+}
+
+// Description is the output of the describe_indicator export
+type Description struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	ShortName   string      `json:"shortName,omitempty"` // Legend label, e.g. "RSI"
+	Overlay     bool        `json:"overlay"`             // Draw on the price pane instead of a separate pane
+	Parameters  []Parameter `json:"parameters"`
+	Outputs     []Output    `json:"outputs"`
+	Levels      []Level     `json:"levels,omitempty"`
+	WarmUp      int         `json:"warmUp,omitempty"`      // Candles needed before the first value
+	Incremental bool        `json:"incremental,omitempty"` // Set automatically when compute_incremental is supported
+}
+
+// ComputeRequest is the input of the compute export
+type ComputeRequest struct {
+	Candles []tt.OHLCVRecord `json:"candles"`
+	Params  Params           `json:"params"`
+}
+
+// ComputeIncrementalRequest is the input of the compute_incremental export.
+// Candles are the candles after the previous call; the last one may be an
+// update of the previous call's last, still open candle.
+type ComputeIncrementalRequest struct {
+	Candles []tt.OHLCVRecord `json:"candles"`
+	Params  Params           `json:"params"`
+	State   json.RawMessage  `json:"state,omitempty"` // State returned by the previous call; empty on the first
+}
+
+// ComputeResponse is the output of the compute and compute_incremental exports
+type ComputeResponse struct {
+	Success bool            `json:"success"`
+	Series  []dt.Series     `json:"series,omitempty"` // One per Output, in the same order
+	State   json.RawMessage `json:"state,omitempty"`  // compute_incremental only
+	Error   string          `json:"error,omitempty"`
+}
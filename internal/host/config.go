@@ -0,0 +1,37 @@
+package host
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// configGen counts host config changes; it only moves in native builds
+var configGen atomic.Uint64
+
+// ConfigValue is a value derived from the host config. The config of a
+// plugin instance never changes, so load runs on first use only; native
+// builds run it again once the config changed (see ConfigChanged).
+type ConfigValue[T any] struct {
+	load func() T
+
+	mu     sync.Mutex
+	loaded bool
+	gen    uint64
+	value  T
+}
+
+// NewConfigValue returns a ConfigValue computed by load
+func NewConfigValue[T any](load func() T) *ConfigValue[T] {
+	return &ConfigValue[T]{load: load}
+}
+
+// Get returns the value, loading it on first use
+func (v *ConfigValue[T]) Get() T {
+	gen := configGen.Load()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.loaded || v.gen != gen {
+		v.value, v.gen, v.loaded = v.load(), gen, true
+	}
+	return v.value
+}
@@ -0,0 +1,121 @@
+// Package host is the single place where the library talks to the plugin
+// host. In WASM builds the calls go through the extism PDK and the
+// extism:host/user imports; in native builds they go to a Host
+// implementation installed with Set, which is how plugintest runs plugins
+// under go test.
+package host
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/msgpack"
+)
+
+// Host functions imported from extism:host/user
+const (
+	HTTPRequest       = "http_request"
+	HTTPRequestStream = "http_request_stream"
+	HTTPStreamRead    = "http_stream_read"
+	HTTPStreamClose   = "http_stream_close"
+	HTTPClearCookies  = "http_clear_cookies"
+	SignRequest       = "sign_request"
+	LogRecord         = "log_record"
+	LogRecords        = "log_records"
+	TimeNow           = "time_now"
+	ReportProgress    = "report_progress"
+	AlertTrigger      = "alert_trigger"
+	PluginCall        = "plugin_call"
+	WSConnect         = "ws_connect"
+	WSSend            = "ws_send"
+	WSReceive         = "ws_receive"
+	WSClose           = "ws_close"
+)
+
+// Functions lists every host function the library may import
+var Functions = []string{
+	HTTPRequest,
+	HTTPRequestStream,
+	HTTPStreamRead,
+	HTTPStreamClose,
+	HTTPClearCookies,
+	SignRequest,
+	LogRecord,
+	LogRecords,
+	TimeNow,
+	ReportProgress,
+	AlertTrigger,
+	PluginCall,
+	WSConnect,
+	WSSend,
+	WSReceive,
+	WSClose,
+}
+
+var (
+	exportsMu sync.RWMutex
+	exports   = make(map[string]func() int32)
+)
+
+// RegisterExport makes a WASM export callable by name from native builds
+func RegisterExport(name string, fn func() int32) {
+	exportsMu.Lock()
+	defer exportsMu.Unlock()
+	exports[name] = fn
+}
+
+// Export returns the export registered under name
+func Export(name string) (func() int32, bool) {
+	exportsMu.RLock()
+	defer exportsMu.RUnlock()
+	fn, ok := exports[name]
+	return fn, ok
+}
+
+// InputJSON decodes the export input into v
+func InputJSON(v any) error {
+	return json.Unmarshal(Input(), v)
+}
+
+// OutputJSON encodes v as export output
+func OutputJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	Output(data)
+	return nil
+}
+
+// InputMsgpack decodes the MessagePack encoded export input into v
+func InputMsgpack(v any) error {
+	return msgpack.Unmarshal(Input(), v)
+}
+
+// OutputMsgpack encodes v as MessagePack export output
+func OutputMsgpack(v any) error {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return err
+	}
+	Output(data)
+	return nil
+}
+
+// Func calls a host function with raw input and returns its answer, or nil
+// when the host returned a null pointer. The Call* wrappers are Funcs.
+type Func func(input []byte) []byte
+
+// CallJSON sends in as JSON to a host function and decodes the answer into out.
+// It returns false when the host returned nothing.
+func CallJSON(call Func, in any, out any) (bool, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return false, err
+	}
+	res := call(data)
+	if res == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(res, out)
+}
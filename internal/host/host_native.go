@@ -0,0 +1,171 @@
+//go:build !wasm
+
+package host
+
+import "sync"
+
+// Host stands in for the plugin host in native builds
+type Host interface {
+	// Input returns the input of the running export
+	Input() []byte
+	// Output receives the output of the running export
+	Output(data []byte)
+	// SetError receives export failures
+	SetError(err error)
+	// Call answers a host function call; nil means null pointer
+	Call(name string, input []byte) []byte
+	// Config returns a value of the host-provided plugin config
+	Config(key string) (string, bool)
+}
+
+var (
+	currentMu sync.RWMutex
+	current   Host
+)
+
+// Set installs h as host for native builds and returns a function restoring
+// the previous one
+func Set(h Host) (restore func()) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	prev := current
+	current = h
+	ConfigChanged()
+	return func() {
+		currentMu.Lock()
+		defer currentMu.Unlock()
+		current = prev
+		ConfigChanged()
+	}
+}
+
+// ConfigChanged makes ConfigValues reload, for hosts whose config changes
+// between calls
+func ConfigChanged() {
+	configGen.Add(1)
+}
+
+// Available reports whether a host is installed
+func Available() bool {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current != nil
+}
+
+func get() Host {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	if current == nil {
+		panic("no plugin host outside WASM: use the plugintest package to run plugins under go test")
+	}
+	return current
+}
+
+// Input returns the input of the running export
+func Input() []byte {
+	return get().Input()
+}
+
+// Output sets the output of the running export
+func Output(data []byte) {
+	get().Output(data)
+}
+
+// SetError reports an export failure to the host
+func SetError(err error) {
+	get().SetError(err)
+}
+
+// Config returns a value of the host-provided plugin config. Without an
+// installed host there is no config.
+func Config(key string) (string, bool) {
+	if !Available() {
+		return "", false
+	}
+	return get().Config(key)
+}
+
+// call invokes the named host function with input and returns its answer
+func call(name string, input []byte) []byte {
+	return get().Call(name, input)
+}
+
+// CallHTTPRequest calls the http_request host function
+func CallHTTPRequest(input []byte) []byte {
+	return call(HTTPRequest, input)
+}
+
+// CallHTTPRequestStream calls the http_request_stream host function
+func CallHTTPRequestStream(input []byte) []byte {
+	return call(HTTPRequestStream, input)
+}
+
+// CallHTTPStreamRead calls the http_stream_read host function
+func CallHTTPStreamRead(input []byte) []byte {
+	return call(HTTPStreamRead, input)
+}
+
+// CallHTTPStreamClose calls the http_stream_close host function
+func CallHTTPStreamClose(input []byte) []byte {
+	return call(HTTPStreamClose, input)
+}
+
+// CallHTTPClearCookies calls the http_clear_cookies host function
+func CallHTTPClearCookies(input []byte) []byte {
+	return call(HTTPClearCookies, input)
+}
+
+// CallSignRequest calls the sign_request host function
+func CallSignRequest(input []byte) []byte {
+	return call(SignRequest, input)
+}
+
+// CallLogRecord calls the log_record host function
+func CallLogRecord(input []byte) []byte {
+	return call(LogRecord, input)
+}
+
+// CallLogRecords calls the log_records host function
+func CallLogRecords(input []byte) []byte {
+	return call(LogRecords, input)
+}
+
+// CallTimeNow calls the time_now host function
+func CallTimeNow(input []byte) []byte {
+	return call(TimeNow, input)
+}
+
+// CallReportProgress calls the report_progress host function
+func CallReportProgress(input []byte) []byte {
+	return call(ReportProgress, input)
+}
+
+// CallAlertTrigger calls the alert_trigger host function
+func CallAlertTrigger(input []byte) []byte {
+	return call(AlertTrigger, input)
+}
+
+// CallPluginCall calls the plugin_call host function
+func CallPluginCall(input []byte) []byte {
+	return call(PluginCall, input)
+}
+
+// CallWSConnect calls the ws_connect host function
+func CallWSConnect(input []byte) []byte {
+	return call(WSConnect, input)
+}
+
+// CallWSSend calls the ws_send host function
+func CallWSSend(input []byte) []byte {
+	return call(WSSend, input)
+}
+
+// CallWSReceive calls the ws_receive host function
+func CallWSReceive(input []byte) []byte {
+	return call(WSReceive, input)
+}
+
+// CallWSClose calls the ws_close host function
+func CallWSClose(input []byte) []byte {
+	return call(WSClose, input)
+}
@@ -0,0 +1,178 @@
+//go:build wasm
+
+package host
+
+import "github.com/extism/go-pdk"
+
+// Each host function has its own wrapper so that only the imports a plugin
+// actually calls are linked in; hosts refuse modules with unknown imports.
+
+//go:wasmimport extism:host/user http_request
+func httpRequest(uint64) uint64
+
+// CallHTTPRequest calls the http_request host function
+func CallHTTPRequest(input []byte) []byte {
+	return call(httpRequest, input)
+}
+
+//go:wasmimport extism:host/user http_request_stream
+func httpRequestStream(uint64) uint64
+
+// CallHTTPRequestStream calls the http_request_stream host function
+func CallHTTPRequestStream(input []byte) []byte {
+	return call(httpRequestStream, input)
+}
+
+//go:wasmimport extism:host/user http_stream_read
+func httpStreamRead(uint64) uint64
+
+// CallHTTPStreamRead calls the http_stream_read host function
+func CallHTTPStreamRead(input []byte) []byte {
+	return call(httpStreamRead, input)
+}
+
+//go:wasmimport extism:host/user http_stream_close
+func httpStreamClose(uint64) uint64
+
+// CallHTTPStreamClose calls the http_stream_close host function
+func CallHTTPStreamClose(input []byte) []byte {
+	return call(httpStreamClose, input)
+}
+
+//go:wasmimport extism:host/user http_clear_cookies
+func httpClearCookies(uint64) uint64
+
+// CallHTTPClearCookies calls the http_clear_cookies host function
+func CallHTTPClearCookies(input []byte) []byte {
+	return call(httpClearCookies, input)
+}
+
+//go:wasmimport extism:host/user sign_request
+func signRequest(uint64) uint64
+
+// CallSignRequest calls the sign_request host function
+func CallSignRequest(input []byte) []byte {
+	return call(signRequest, input)
+}
+
+//go:wasmimport extism:host/user log_record
+func logRecord(uint64) uint64
+
+// CallLogRecord calls the log_record host function
+func CallLogRecord(input []byte) []byte {
+	return call(logRecord, input)
+}
+
+//go:wasmimport extism:host/user log_records
+func logRecords(uint64) uint64
+
+// CallLogRecords calls the log_records host function
+func CallLogRecords(input []byte) []byte {
+	return call(logRecords, input)
+}
+
+//go:wasmimport extism:host/user time_now
+func timeNow(uint64) uint64
+
+// CallTimeNow calls the time_now host function
+func CallTimeNow(input []byte) []byte {
+	return call(timeNow, input)
+}
+
+//go:wasmimport extism:host/user report_progress
+func reportProgress(uint64) uint64
+
+// CallReportProgress calls the report_progress host function
+func CallReportProgress(input []byte) []byte {
+	return call(reportProgress, input)
+}
+
+//go:wasmimport extism:host/user alert_trigger
+func alertTrigger(uint64) uint64
+
+// CallAlertTrigger calls the alert_trigger host function
+func CallAlertTrigger(input []byte) []byte {
+	return call(alertTrigger, input)
+}
+
+//go:wasmimport extism:host/user plugin_call
+func pluginCall(uint64) uint64
+
+// CallPluginCall calls the plugin_call host function
+func CallPluginCall(input []byte) []byte {
+	return call(pluginCall, input)
+}
+
+//go:wasmimport extism:host/user ws_connect
+func wsConnect(uint64) uint64
+
+// CallWSConnect calls the ws_connect host function
+func CallWSConnect(input []byte) []byte {
+	return call(wsConnect, input)
+}
+
+//go:wasmimport extism:host/user ws_send
+func wsSend(uint64) uint64
+
+// CallWSSend calls the ws_send host function
+func CallWSSend(input []byte) []byte {
+	return call(wsSend, input)
+}
+
+//go:wasmimport extism:host/user ws_receive
+func wsReceive(uint64) uint64
+
+// CallWSReceive calls the ws_receive host function
+func CallWSReceive(input []byte) []byte {
+	return call(wsReceive, input)
+}
+
+//go:wasmimport extism:host/user ws_close
+func wsClose(uint64) uint64
+
+// CallWSClose calls the ws_close host function
+func CallWSClose(input []byte) []byte {
+	return call(wsClose, input)
+}
+
+// Available reports whether a host is installed, which is always the case in WASM
+func Available() bool {
+	return true
+}
+
+// Input returns the input of the running export
+func Input() []byte {
+	return pdk.Input()
+}
+
+// Output sets the output of the running export
+func Output(data []byte) {
+	pdk.Output(data)
+}
+
+// SetError reports an export failure to the host
+func SetError(err error) {
+	pdk.SetError(err)
+}
+
+// Config returns a value of the host-provided plugin config (extism manifest config)
+func Config(key string) (string, bool) {
+	return pdk.GetConfig(key)
+}
+
+// call invokes a host function import with input and returns its answer,
+// or nil when the host returned a null pointer
+func call(fn func(uint64) uint64, input []byte) []byte {
+	var offset uint64
+	if input != nil {
+		mem := pdk.AllocateBytes(input)
+		defer mem.Free()
+		offset = mem.Offset()
+	}
+
+	ptr := fn(offset)
+	if ptr == 0 {
+		return nil
+	}
+	return pdk.FindMemory(ptr).ReadBytes()
+}
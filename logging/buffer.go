@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/timeutil"
+)
+
+// BufferedSink collects records and hands them to the next sink in batches,
+// saving a host call and JSON marshal per record in hot paths like stream
+// message handling.
+//
+// A batch is flushed when it holds maxRecords records or when a record
+// arrives and the oldest buffered record is older than maxAge. WASM plugins
+// have no timers, so the plugin package also flushes the default sink at the
+// end of every handle_command, handle_stream_message and
+// handle_connection_event export. Buffering thus batches the records of one
+// export call; plugins with their own exports call Flush before returning.
+type BufferedSink struct {
+	mu         sync.Mutex
+	next       Sink
+	maxRecords int
+	maxAge     time.Duration
+	clock      clock.Clock
+	records    []PluginLogRecord
+	oldest     time.Time
+}
+
+// NewBufferedSink creates a buffered sink in front of next. A maxAge of 0
+// disables time-based flushing.
+func NewBufferedSink(next Sink, maxRecords int, maxAge time.Duration) *BufferedSink {
+	if maxRecords < 1 {
+		maxRecords = 1
+	}
+	return &BufferedSink{
+		next:       next,
+		maxRecords: maxRecords,
+		maxAge:     maxAge,
+		clock:      timeutil.Default(),
+	}
+}
+
+// SetClock replaces the clock used for maxAge, e.g. with a clock.Fake in tests
+func (s *BufferedSink) SetClock(c clock.Clock) *BufferedSink {
+	s.clock = c
+	return s
+}
+
+// Write implements Sink
+func (s *BufferedSink) Write(record PluginLogRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if len(s.records) == 0 {
+		s.oldest = now
+	}
+	s.records = append(s.records, record)
+
+	if len(s.records) >= s.maxRecords || (s.maxAge > 0 && now.Sub(s.oldest) >= s.maxAge) {
+		return s.flush()
+	}
+	return nil
+}
+
+// Flush hands all buffered records to the next sink
+func (s *BufferedSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// Len returns the number of buffered records
+func (s *BufferedSink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+func (s *BufferedSink) flush() error {
+	if len(s.records) == 0 {
+		return nil
+	}
+	records := s.records
+	s.records = nil
+
+	if batch, ok := s.next.(BatchSink); ok {
+		return batch.WriteBatch(records)
+	}
+	for _, record := range records {
+		if err := s.next.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableBuffering makes a BufferedSink in front of the host the default sink
+// and returns it. The plugin's handler and shutdown exports flush it.
+//
+// Example:
+//
+//	func init() {
+//	    logging.EnableBuffering(100, 5*time.Second)
+//	}
+func EnableBuffering(maxRecords int, maxAge time.Duration) *BufferedSink {
+	sink := NewBufferedSink(hostSink{}, maxRecords, maxAge)
+	SetDefaultSink(sink)
+	return sink
+}
+
+// Flush flushes the default sink if it buffers records
+func Flush() error {
+	if flusher, ok := getDefaultSink().(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package logging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	loggingtesting "github.com/plusev-terminal/go-plugin-common/logging/testing"
+)
+
+func TestBufferedSinkFlushes(t *testing.T) {
+	next := loggingtesting.NewMockSink()
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	buffered := logging.NewBufferedSink(next, 3, time.Second).SetClock(c)
+	logger := logging.NewLogger("test").SetClock(c).SetSink(buffered)
+
+	logger.Info("one")
+	logger.Info("two")
+	if len(next.Records()) != 0 {
+		t.Fatal("expected records to be buffered")
+	}
+
+	logger.Info("three")
+	if len(next.Records()) != 3 || buffered.Len() != 0 {
+		t.Fatalf("expected size flush, got %d delivered and %d buffered", len(next.Records()), buffered.Len())
+	}
+
+	logger.Info("four")
+	c.Advance(time.Second)
+	logger.Info("five")
+	if len(next.Records()) != 5 {
+		t.Fatalf("expected age flush, got %d delivered", len(next.Records()))
+	}
+
+	logger.Info("six")
+	if err := buffered.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	next.AssertLogged(t, "info", "six")
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// Level is the severity of the built-in event types
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// LevelConfigKey is the host config key holding the minimum level, e.g. "warn".
+// The host sets it per plugin to toggle verbose logging without recompiling.
+const LevelConfigKey = "log_level"
+
+// String returns the event type of the level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLevel parses a level name as used for event types ("warning" is accepted too)
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelDebug, fmt.Errorf("unknown log level %q", s)
+}
+
+// SetLevel sets the minimum level of the logger, overriding the host config
+func (l *Logger) SetLevel(level Level) *Logger {
+	l.level = &level
+	return l
+}
+
+// Enabled reports whether records of level are recorded. Use it to skip
+// building expensive debug data.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.minLevel()
+}
+
+// hostLevel is the host-configured level, else debug. It is read once per
+// plugin instance rather than per record.
+var hostLevel = host.NewConfigValue(func() Level {
+	if value, ok := host.Config(LevelConfigKey); ok {
+		if level, err := ParseLevel(value); err == nil {
+			return level
+		}
+	}
+	return LevelDebug
+})
+
+// minLevel returns the logger's level, else the host-configured one
+func (l *Logger) minLevel() Level {
+	if l.level != nil {
+		return *l.level
+	}
+	return hostLevel.Get()
+}
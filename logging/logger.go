@@ -1,22 +1,22 @@
 package logging
 
 import (
-	"encoding/json"
-	"fmt"
-	"time"
+	"maps"
 
-	"github.com/extism/go-pdk"
-	utils "github.com/plusev-terminal/go-plugin-common/wasmutils"
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/timeutil"
+	"github.com/plusev-terminal/go-plugin-common/trace"
 )
 
-// Import the log_record host function
-//
-//go:wasmimport extism:host/user log_record
-func hostLogRecord(offset uint64) uint64
-
 // Logger provides logging functionality for plugins
 type Logger struct {
 	pluginID string
+	clock    clock.Clock
+	sink     Sink
+	level    *Level
+	name     string
+	fields   map[string]any
+	rules    map[string]*sampleRule
 }
 
 // NewLogger creates a new logger instance
@@ -24,23 +24,65 @@ type Logger struct {
 func NewLogger(pluginID string) *Logger {
 	return &Logger{
 		pluginID: pluginID,
+		clock:    timeutil.Default(),
 	}
 }
 
-// NewLogRecord creates a new log record with the current timestamp
-func (l *Logger) NewLogRecord(eventType string) *PluginLogRecord {
-	now, err := utils.Now()
-	if err != nil {
-		// If we can't get time from host, use a zero time
-		// The host will override this anyway
-		now = time.Time{}
+// SetClock replaces the clock used for record timestamps, e.g. with a clock.Fake in tests
+func (l *Logger) SetClock(c clock.Clock) *Logger {
+	l.clock = c
+	return l
+}
+
+// With returns a child logger attaching key=value to the data of every
+// record. Values set on a record take precedence over logger fields.
+//
+// Example:
+//
+//	log := logger.Named("stream").With("streamID", req.StreamID).With("symbol", symbol)
+//	log.Warn("sequence gap")
+func (l *Logger) With(key string, value any) *Logger {
+	child := l.clone()
+	child.fields[key] = value
+	return child
+}
+
+// Named returns a child logger whose name is appended to the parent's with a
+// dot, recorded as "logger" in the record data
+func (l *Logger) Named(name string) *Logger {
+	child := l.clone()
+	if child.name != "" {
+		name = child.name + "." + name
+	}
+	child.name = name
+	return child
+}
+
+// clone copies the logger with its own fields map
+func (l *Logger) clone() *Logger {
+	child := *l
+	child.fields = make(map[string]any, len(l.fields)+1)
+	for key, value := range l.fields {
+		child.fields[key] = value
 	}
+	return &child
+}
+
+// SetSink replaces where the logger's records go; nil uses the default sink
+func (l *Logger) SetSink(sink Sink) *Logger {
+	l.sink = sink
+	return l
+}
 
+// NewLogRecord creates a new log record with the current timestamp
+func (l *Logger) NewLogRecord(eventType string) *PluginLogRecord {
+	// The host clock reports zero time on failure; the host will override it anyway
 	return &PluginLogRecord{
 		PluginID:  l.pluginID,
 		EventType: eventType,
-		Timestamp: now,
+		Timestamp: l.clock.Now(),
 		Data:      make(map[string]any),
+		logger:    l,
 	}
 }
 
@@ -65,20 +107,63 @@ func (r *PluginLogRecord) AddData(key string, value any) *PluginLogRecord {
 	return r
 }
 
-// Record sends the log record to the host via the log_record host function
+// Record sends the log record to the logger's sink, by default the host's
+// log_record function. Records of the built-in levels below the logger's
+// minimum level are dropped; custom event types are always recorded.
 func (r *PluginLogRecord) Record() error {
-	data, err := json.Marshal(r)
-	if err != nil {
-		return fmt.Errorf("failed to marshal log record: %w", err)
+	var sink Sink
+	var suppressed int
+	if r.logger != nil {
+		if level, err := ParseLevel(r.EventType); err == nil && !r.logger.Enabled(level) {
+			return nil
+		}
+		var keep bool
+		if keep, suppressed = r.logger.sample(r.EventType); !keep {
+			return nil
+		}
+		sink = r.logger.sink
+	}
+	if sink == nil {
+		sink = getDefaultSink()
+	}
+	r.Data = r.extendedData(suppressed)
+	return sink.Write(*r)
+}
+
+// extendedData returns Data with the trace ID, the logger's name and fields
+// added without overwriting record values, and the suppressed count. Data
+// may be the caller's map (SetData), so a copy is extended.
+func (r *PluginLogRecord) extendedData(suppressed int) map[string]any {
+	traceID := trace.Current()
+	var name string
+	var fields map[string]any
+	if r.logger != nil {
+		name, fields = r.logger.name, r.logger.fields
+	}
+	if traceID == "" && name == "" && len(fields) == 0 && suppressed == 0 {
+		return r.Data
 	}
 
-	mem := pdk.AllocateBytes(data)
-	defer mem.Free()
-
-	// Call the log_record host function
-	hostLogRecord(mem.Offset())
-
-	return nil
+	data := make(map[string]any, len(r.Data)+len(fields)+3)
+	maps.Copy(data, r.Data)
+	setDefault := func(key string, value any) {
+		if _, ok := data[key]; !ok {
+			data[key] = value
+		}
+	}
+	if traceID != "" {
+		setDefault(trace.DataKey, traceID)
+	}
+	if name != "" {
+		setDefault("logger", name)
+	}
+	for key, value := range fields {
+		setDefault(key, value)
+	}
+	if suppressed > 0 {
+		data[SuppressedKey] = suppressed
+	}
+	return data
 }
 
 // Convenience methods for common log levels
@@ -0,0 +1,68 @@
+//go:build !wasm
+
+package logging_test
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	loggingtesting "github.com/plusev-terminal/go-plugin-common/logging/testing"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+func TestRecordLeavesCallerDataAlone(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	logger := logging.NewLogger("test").SetSink(sink).Named("orders").With("symbol", "BTCUSDT")
+
+	data := map[string]any{"orderId": "42"}
+	logger.InfoWithData("filled", data)
+
+	if len(data) != 1 {
+		t.Fatalf("expected caller data to be unchanged, got %v", data)
+	}
+	records := sink.Find("info", "filled")
+	if len(records) != 1 || records[0].Data["logger"] != "orders" || records[0].Data["symbol"] != "BTCUSDT" || records[0].Data["orderId"] != "42" {
+		t.Fatalf("expected fields on the recorded data, got %v", records)
+	}
+}
+
+func TestHostLevel(t *testing.T) {
+	h := plugintest.New(t)
+	h.SetConfig(logging.LevelConfigKey, "warn")
+	sink := loggingtesting.NewMockSink()
+	logger := logging.NewLogger("test").SetSink(sink)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if len(sink.Find("info", "hidden")) != 0 || len(sink.Find("warn", "shown")) != 1 {
+		t.Fatalf("expected host level warn to apply, got %v", sink.Records())
+	}
+
+	h.SetConfig(logging.LevelConfigKey, "")
+	logger.Debug("verbose")
+	if len(sink.Find("debug", "verbose")) != 1 {
+		t.Fatal("expected debug records without host level")
+	}
+}
+
+func TestTraceIDAddedToKeptRecordsOnly(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	logger := logging.NewLogger("test").SetSink(sink).SetLevel(logging.LevelInfo)
+	defer trace.Set("trace-1")()
+
+	data := map[string]any{"orderId": "42"}
+	logger.DebugWithData("dropped", data)
+	logger.InfoWithData("kept", data)
+
+	if len(data) != 1 {
+		t.Fatalf("expected caller data to be unchanged, got %v", data)
+	}
+	if len(sink.Find("debug", "dropped")) != 0 {
+		t.Fatal("expected debug record to be dropped")
+	}
+	records := sink.Find("info", "kept")
+	if len(records) != 1 || records[0].Data[trace.DataKey] != "trace-1" {
+		t.Fatalf("expected trace ID on the kept record, got %v", records)
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// SuppressedKey is the data key carrying the number of records dropped by
+// sampling or rate limiting since the last recorded one of the same event type
+const SuppressedKey = "suppressed"
+
+// sampleRule throttles records of one event type
+type sampleRule struct {
+	mu sync.Mutex
+
+	every  int // Keep one of every n records (sampling)
+	limit  int // Keep at most limit records per window (rate limiting)
+	window time.Duration
+
+	seen        int
+	windowStart time.Time
+	inWindow    int
+	pending     int // Suppressed since the last recorded record
+	total       int // Suppressed overall
+}
+
+// allow decides about the next record and returns the number of records
+// suppressed before it when it is let through
+func (r *sampleRule) allow(now time.Time) (bool, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keep := true
+	if r.every > 1 {
+		keep = r.seen%r.every == 0
+		r.seen++
+	}
+	if keep && r.limit > 0 {
+		if r.windowStart.IsZero() || now.Sub(r.windowStart) >= r.window {
+			r.windowStart = now
+			r.inWindow = 0
+		}
+		keep = r.inWindow < r.limit
+		if keep {
+			r.inWindow++
+		}
+	}
+
+	if !keep {
+		r.pending++
+		r.total++
+		return false, 0
+	}
+	suppressed := r.pending
+	r.pending = 0
+	return true, suppressed
+}
+
+// Sampled returns a child logger that records only one of every n records of
+// eventType ("" matches all event types). The first record is always kept.
+// Kept records carry the number of skipped ones under SuppressedKey.
+//
+// Example:
+//
+//	log := logger.Sampled("debug", 100)
+func (l *Logger) Sampled(eventType string, n int) *Logger {
+	return l.withRule(eventType, &sampleRule{every: n})
+}
+
+// RateLimited returns a child logger that records at most limit records of
+// eventType ("" matches all event types) per window. Kept records carry the
+// number of dropped ones under SuppressedKey.
+//
+// Example:
+//
+//	log := logger.RateLimited("warn", 10, time.Minute)
+func (l *Logger) RateLimited(eventType string, limit int, window time.Duration) *Logger {
+	return l.withRule(eventType, &sampleRule{limit: limit, window: window})
+}
+
+// Suppressed returns the number of records dropped per event type by the
+// sampling and rate limiting rules of this logger
+func (l *Logger) Suppressed() map[string]int {
+	counts := make(map[string]int, len(l.rules))
+	for eventType, rule := range l.rules {
+		rule.mu.Lock()
+		counts[eventType] = rule.total
+		rule.mu.Unlock()
+	}
+	return counts
+}
+
+func (l *Logger) withRule(eventType string, rule *sampleRule) *Logger {
+	child := l.clone()
+	child.rules = make(map[string]*sampleRule, len(l.rules)+1)
+	for key, existing := range l.rules {
+		child.rules[key] = existing
+	}
+	child.rules[eventType] = rule
+	return child
+}
+
+// sample applies the rule for eventType, falling back to the rule for all
+// event types, and returns the number of records dropped before a kept one
+func (l *Logger) sample(eventType string) (keep bool, suppressed int) {
+	rule, ok := l.rules[eventType]
+	if !ok {
+		if rule, ok = l.rules[""]; !ok {
+			return true, 0
+		}
+	}
+	return rule.allow(l.clock.Now())
+}
@@ -0,0 +1,47 @@
+package logging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	loggingtesting "github.com/plusev-terminal/go-plugin-common/logging/testing"
+)
+
+func TestSampledAndRateLimited(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	logger := logging.NewLogger("test").SetClock(c).SetSink(sink).
+		Sampled("debug", 3).
+		RateLimited("warn", 2, time.Minute)
+
+	for i := 0; i < 7; i++ {
+		logger.Debug("tick")
+	}
+	debug := sink.Find("debug", "tick")
+	if len(debug) != 3 {
+		t.Fatalf("expected 3 sampled debug records, got %d", len(debug))
+	}
+	if debug[1].Data[logging.SuppressedKey] != 2 {
+		t.Errorf("expected suppressed count on kept record, got %v", debug[1].Data)
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("lag")
+	}
+	c.Advance(time.Minute)
+	logger.Warn("lag")
+
+	warn := sink.Find("warn", "lag")
+	if len(warn) != 3 {
+		t.Fatalf("expected 3 rate limited warn records, got %d", len(warn))
+	}
+	if warn[2].Data[logging.SuppressedKey] != 3 {
+		t.Errorf("expected 3 suppressed before next window, got %v", warn[2].Data)
+	}
+
+	if got := logger.Suppressed(); got["debug"] != 4 || got["warn"] != 3 {
+		t.Errorf("unexpected suppressed counters %v", got)
+	}
+}
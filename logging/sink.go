@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// Sink receives finished log records
+type Sink interface {
+	Write(record PluginLogRecord) error
+}
+
+// BatchSink is a Sink that can take many records at once
+type BatchSink interface {
+	Sink
+	WriteBatch(records []PluginLogRecord) error
+}
+
+// hostSink sends records to the host via the log_record and log_records host functions
+type hostSink struct{}
+
+func (hostSink) Write(record PluginLogRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log record: %w", err)
+	}
+
+	host.CallLogRecord(data)
+	return nil
+}
+
+func (s hostSink) WriteBatch(records []PluginLogRecord) error {
+	if !hostinfo.Has(hostinfo.LogRecords) {
+		for _, record := range records {
+			if err := s.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log records: %w", err)
+	}
+
+	host.CallLogRecords(data)
+	return nil
+}
+
+var (
+	defaultSinkMu sync.RWMutex
+	defaultSink   Sink = hostSink{}
+)
+
+// SetDefaultSink replaces the sink of all loggers without an own sink and
+// returns a function restoring the previous one. Tests use it to capture
+// records of loggers created deep inside plugin code.
+func SetDefaultSink(sink Sink) (restore func()) {
+	defaultSinkMu.Lock()
+	defer defaultSinkMu.Unlock()
+	prev := defaultSink
+	defaultSink = sink
+	return func() {
+		defaultSinkMu.Lock()
+		defer defaultSinkMu.Unlock()
+		defaultSink = prev
+	}
+}
+
+func getDefaultSink() Sink {
+	defaultSinkMu.RLock()
+	defer defaultSinkMu.RUnlock()
+	return defaultSink
+}
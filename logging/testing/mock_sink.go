@@ -0,0 +1,129 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+)
+
+// TB is the subset of testing.TB used by the assertion helpers
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(fn func())
+}
+
+// MockSink captures log records in memory so tests can assert on them
+type MockSink struct {
+	mu      sync.Mutex
+	records []logging.PluginLogRecord
+}
+
+// NewMockSink creates an empty mock sink
+func NewMockSink() *MockSink {
+	return &MockSink{}
+}
+
+// Install creates a mock sink and makes it the default sink until the test ends
+//
+// Example:
+//
+//	logs := loggingtesting.Install(t)
+//	plugin.HandleSomething()
+//	logs.AssertLogged(t, "error", "rate limited")
+func Install(t TB) *MockSink {
+	sink := NewMockSink()
+	t.Cleanup(logging.SetDefaultSink(sink))
+	return sink
+}
+
+// Write implements logging.Sink
+func (s *MockSink) Write(record logging.PluginLogRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Records returns all captured records
+func (s *MockSink) Records() []logging.PluginLogRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]logging.PluginLogRecord(nil), s.records...)
+}
+
+// Find returns the records of eventType whose message or data values contain
+// substring. An empty eventType matches all event types.
+func (s *MockSink) Find(eventType, substring string) []logging.PluginLogRecord {
+	var found []logging.PluginLogRecord
+	for _, record := range s.Records() {
+		if eventType != "" && record.EventType != eventType {
+			continue
+		}
+		if contains(record, substring) {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless a matching record was captured
+func (s *MockSink) AssertLogged(t TB, eventType, substring string) bool {
+	t.Helper()
+	if len(s.Find(eventType, substring)) == 0 {
+		t.Errorf("expected %s log containing %q, got %s", eventTypeLabel(eventType), substring, s.summary())
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged fails the test if a matching record was captured
+func (s *MockSink) AssertNotLogged(t TB, eventType, substring string) bool {
+	t.Helper()
+	if found := s.Find(eventType, substring); len(found) > 0 {
+		t.Errorf("expected no %s log containing %q, got %q", eventTypeLabel(eventType), substring, found[0].Message)
+		return false
+	}
+	return true
+}
+
+// Reset drops all captured records
+func (s *MockSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = nil
+}
+
+func contains(record logging.PluginLogRecord, substring string) bool {
+	if strings.Contains(record.Message, substring) {
+		return true
+	}
+	for _, value := range record.Data {
+		if strings.Contains(fmt.Sprint(value), substring) {
+			return true
+		}
+	}
+	return false
+}
+
+func eventTypeLabel(eventType string) string {
+	if eventType == "" {
+		return "any"
+	}
+	return eventType
+}
+
+// summary lists the captured records for failure messages
+func (s *MockSink) summary() string {
+	records := s.Records()
+	if len(records) == 0 {
+		return "no records"
+	}
+	lines := make([]string, len(records))
+	for i, record := range records {
+		lines[i] = fmt.Sprintf("%s: %s", record.EventType, record.Message)
+	}
+	return "[" + strings.Join(lines, "; ") + "]"
+}
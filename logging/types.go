@@ -12,4 +12,6 @@ type PluginLogRecord struct {
 	Timestamp time.Time      `json:"timestamp"`
 	Message   string         `json:"message"`
 	Data      map[string]any `json:"data,omitempty"`
+
+	logger *Logger // Set by Logger.NewLogRecord
 }
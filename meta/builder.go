@@ -0,0 +1,313 @@
+package meta
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// Builder assembles a Meta and validates it in Build, so mistakes show up
+// at plugin init instead of at host registration
+//
+// Example:
+//
+//	var pluginMeta = meta.New("binance", "Binance", "plusev").
+//	    Version("1.2.0").
+//	    AllowNetwork("https://api.binance.com/*", "wss://stream.binance.com:9443/*").
+//	    Feature("getMarkets", "getOHLCV").
+//	    MustBuild()
+type Builder struct {
+	meta Meta
+}
+
+// New starts a Meta with the required identifiers
+func New(pluginID, name, appID string) *Builder {
+	return &Builder{meta: Meta{PluginID: pluginID, Name: name, AppID: appID}}
+}
+
+// Version sets the semantic version, e.g. "1.2.0"
+func (b *Builder) Version(version string) *Builder {
+	b.meta.Version = version
+	return b
+}
+
+// MinHostVersion sets the oldest host version the plugin works with
+func (b *Builder) MinHostVersion(version string) *Builder {
+	b.meta.MinHostVersion = version
+	return b
+}
+
+// Category sets the app specific plugin category
+func (b *Builder) Category(category string) *Builder {
+	b.meta.Category = category
+	return b
+}
+
+// Description sets the description
+func (b *Builder) Description(description string) *Builder {
+	b.meta.Description = description
+	return b
+}
+
+// Author sets the author
+func (b *Builder) Author(author string) *Builder {
+	b.meta.Author = author
+	return b
+}
+
+// Repository sets the source repository URL
+func (b *Builder) Repository(repository string) *Builder {
+	b.meta.Repository = repository
+	return b
+}
+
+// Tag adds tags
+func (b *Builder) Tag(tags ...string) *Builder {
+	b.meta.Tags = append(b.meta.Tags, tags...)
+	return b
+}
+
+// Contact adds an author contact, e.g. Contact("email", "dev@example.com")
+func (b *Builder) Contact(kind, value string) *Builder {
+	b.meta.Contacts = append(b.meta.Contacts, AuthorContact{Kind: kind, Value: value})
+	return b
+}
+
+// AllowNetwork allows requests to URLs matching the patterns, see
+// ValidateNetworkPattern
+func (b *Builder) AllowNetwork(patterns ...string) *Builder {
+	for _, pattern := range patterns {
+		b.meta.Resources.AllowedNetworkTargets = append(b.meta.Resources.AllowedNetworkTargets, NetworkTargetRule{Pattern: pattern})
+	}
+	return b
+}
+
+// AllowWebSocket allows streams to connect to ws(s) URLs matching the
+// patterns
+func (b *Builder) AllowWebSocket(patterns ...string) *Builder {
+	for _, pattern := range patterns {
+		b.meta.Resources.AllowedWebSocketTargets = append(b.meta.Resources.AllowedWebSocketTargets, NetworkTargetRule{Pattern: pattern})
+	}
+	return b
+}
+
+// Storage requests key-value storage; zero limits use the host defaults
+func (b *Builder) Storage(maxBytes int64, maxKeys int) *Builder {
+	b.meta.Resources.Storage = &StorageQuota{MaxBytes: maxBytes, MaxKeys: maxKeys}
+	return b
+}
+
+// Secret requests read access to a secret in the host vault
+func (b *Builder) Secret(name, description string) *Builder {
+	b.meta.Resources.Secrets = append(b.meta.Resources.Secrets, SecretAccess{Name: name, Description: description})
+	return b
+}
+
+// Scheduler requests running scheduled jobs
+func (b *Builder) Scheduler(maxJobs int, minInterval time.Duration) *Builder {
+	b.meta.Resources.Scheduler = &SchedulerAccess{MaxJobs: maxJobs, MinIntervalSeconds: int(minInterval / time.Second)}
+	return b
+}
+
+// Notifications requests sending user notifications
+func (b *Builder) Notifications() *Builder {
+	b.meta.Resources.Notifications = true
+	return b
+}
+
+// AllowFsWrite mounts the host directory at the guest directory
+func (b *Builder) AllowFsWrite(hostDir, guestDir string) *Builder {
+	if b.meta.Resources.FsWriteAccess == nil {
+		b.meta.Resources.FsWriteAccess = map[string]string{}
+	}
+	b.meta.Resources.FsWriteAccess[hostDir] = guestDir
+	return b
+}
+
+// AllowPlugin allows invoking commands of another plugin through the
+// bridge; no commands allows all of them
+func (b *Builder) AllowPlugin(pluginID string, commands ...string) *Builder {
+	b.meta.Resources.AllowedPlugins = append(b.meta.Resources.AllowedPlugins, PluginTargetRule{PluginID: pluginID, Commands: commands})
+	return b
+}
+
+// MaxMemory declares the memory the plugin needs at most
+func (b *Builder) MaxMemory(bytes int64) *Builder {
+	b.limits().MaxMemoryBytes = bytes
+	return b
+}
+
+// CommandTimeout declares the longest execution of a command; without
+// commands it applies to all commands without an own timeout
+func (b *Builder) CommandTimeout(timeout time.Duration, commands ...string) *Builder {
+	l := b.limits()
+	if len(commands) == 0 {
+		l.CommandTimeoutMs = timeout.Milliseconds()
+		return b
+	}
+	if l.CommandTimeoutsMs == nil {
+		l.CommandTimeoutsMs = map[string]int64{}
+	}
+	for _, cmd := range commands {
+		l.CommandTimeoutsMs[cmd] = timeout.Milliseconds()
+	}
+	return b
+}
+
+// MaxStreams declares the expected number of concurrent streams
+func (b *Builder) MaxStreams(n int) *Builder {
+	b.limits().MaxStreams = n
+	return b
+}
+
+func (b *Builder) limits() *Limits {
+	if b.meta.Limits == nil {
+		b.meta.Limits = &Limits{}
+	}
+	return b.meta.Limits
+}
+
+// Localize adds the name and description for a language tag
+func (b *Builder) Localize(lang, name, description string) *Builder {
+	if b.meta.Localizations == nil {
+		b.meta.Localizations = map[string]MetaText{}
+	}
+	b.meta.Localizations[lang] = MetaText{Name: name, Description: description}
+	return b
+}
+
+// ReleaseNote adds a changelog entry
+func (b *Builder) ReleaseNote(note ReleaseNote) *Builder {
+	b.meta.Changelog = append(b.meta.Changelog, note)
+	return b
+}
+
+// Feature adds supported features
+func (b *Builder) Feature(features ...string) *Builder {
+	b.meta.Features = append(b.meta.Features, features...)
+	return b
+}
+
+// Webhook declares a webhook endpoint
+func (b *Builder) Webhook(webhook Webhook) *Builder {
+	b.meta.Webhooks = append(b.meta.Webhooks, webhook)
+	return b
+}
+
+// Build validates and returns the Meta
+func (b *Builder) Build() (Meta, error) {
+	return b.meta, Validate(b.meta)
+}
+
+// MustBuild is Build for package level variables and GetMeta; it panics
+// if the Meta is invalid
+func (b *Builder) MustBuild() Meta {
+	m, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Validate checks the required fields, the semantic version and the
+// network patterns of m. Errors are a *utils.ValidationError keyed by the
+// json field paths, e.g. "version" or "resources.allowedNetworkTargets[0].pattern".
+func Validate(m Meta) error {
+	fields := utils.FieldErrors{}
+	var ve *utils.ValidationError
+	err := utils.ValidateStruct(m)
+	if err != nil && !errors.As(err, &ve) {
+		return err
+	}
+	if ve != nil {
+		for field, msg := range ve.Fields {
+			fields[field] = msg
+		}
+	}
+
+	for i, rule := range m.Resources.AllowedNetworkTargets {
+		if err := ValidateNetworkPattern(rule.Pattern); err != nil {
+			fields[fmt.Sprintf("resources.allowedNetworkTargets[%d].pattern", i)] = err.Error()
+		}
+	}
+	for i, rule := range m.Resources.AllowedWebSocketTargets {
+		field := fmt.Sprintf("resources.allowedWebSocketTargets[%d].pattern", i)
+		if err := ValidateNetworkPattern(rule.Pattern); err != nil {
+			fields[field] = err.Error()
+		} else if scheme, _, _ := strings.Cut(strings.ToLower(rule.Pattern), "://"); scheme != "ws" && scheme != "wss" {
+			fields[field] = "must use the ws or wss scheme"
+		}
+	}
+	seen := map[string]bool{}
+	for i, secret := range m.Resources.Secrets {
+		if seen[secret.Name] {
+			fields[fmt.Sprintf("resources.secrets[%d].name", i)] = "is declared twice"
+		}
+		seen[secret.Name] = true
+	}
+	versions := map[string]bool{}
+	for i, note := range m.Changelog {
+		if versions[note.Version] {
+			fields[fmt.Sprintf("changelog[%d].version", i)] = "is listed twice"
+		}
+		versions[note.Version] = true
+	}
+	for i, rule := range m.Resources.AllowedPlugins {
+		if rule.PluginID == "" {
+			fields[fmt.Sprintf("resources.allowedPlugins[%d].pluginId", i)] = "is required"
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	result := &utils.ValidationError{Fields: fields}
+	if ve != nil {
+		result.Err = ve.Err
+	}
+	return result
+}
+
+// ValidateNetworkPattern checks an allowed network target. Patterns are
+// http(s) or ws(s) URLs with a host; "*" may only start the host as
+// subdomain wildcard ("https://*.example.com/") or end the pattern
+// ("https://api.example.com/*").
+func ValidateNetworkPattern(pattern string) error {
+	if pattern == "" {
+		return errors.New("is required")
+	}
+
+	rest := strings.TrimSuffix(pattern, "*")
+	scheme, afterScheme, ok := strings.Cut(rest, "://")
+	if !ok {
+		return errors.New("must start with a scheme like https://")
+	}
+	if strings.HasPrefix(afterScheme, "*.") {
+		afterScheme = "wildcard" + afterScheme[1:]
+	}
+	if strings.Contains(afterScheme, "*") {
+		return errors.New(`may only use "*" at the end or as "*." subdomain wildcard`)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf("has unsupported scheme %q", scheme)
+	}
+
+	u, err := url.Parse(scheme + "://" + afterScheme)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+	if u.Hostname() == "" {
+		return errors.New("has no host")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("must not contain a query or fragment")
+	}
+	return nil
+}
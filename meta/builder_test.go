@@ -0,0 +1,195 @@
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+func TestBuilder(t *testing.T) {
+	m, err := New("binance", "Binance", "plusev").
+		Version("1.2.0").
+		AllowNetwork("https://api.binance.com/*", "wss://*.binance.com:9443/ws").
+		AllowFsWrite("/home/user/exports", "/exports").
+		AllowPlugin("fx-rates", "getRate").
+		Feature("getMarkets", "getOHLCV").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.PluginID != "binance" || m.Version != "1.2.0" || len(m.Features) != 2 {
+		t.Errorf("unexpected meta %+v", m)
+	}
+	if len(m.Resources.AllowedNetworkTargets) != 2 || m.Resources.FsWriteAccess["/home/user/exports"] != "/exports" {
+		t.Errorf("unexpected resources %+v", m.Resources)
+	}
+
+	_, err = New("binance", "", "plusev").
+		Version("v1").
+		AllowNetwork("https://api.binance.com/*", "api.binance.com", "https://*", "ftp://x.com", "https://a.*.com/", "https://x.com/?q=1").
+		Build()
+	fields := utils.FieldErrorsOf(err)
+	want := map[string]string{
+		"name":    "is required",
+		"version": "must be a semantic version",
+		"resources.allowedNetworkTargets[1].pattern": "must start with a scheme like https://",
+		"resources.allowedNetworkTargets[2].pattern": "has no host",
+		"resources.allowedNetworkTargets[3].pattern": `has unsupported scheme "ftp"`,
+		"resources.allowedNetworkTargets[4].pattern": `may only use "*" at the end or as "*." subdomain wildcard`,
+		"resources.allowedNetworkTargets[5].pattern": "must not contain a query or fragment",
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("expected %v, got %v", want, fields)
+	}
+	for field, msg := range want {
+		if fields[field] != msg {
+			t.Errorf("%s: expected %q, got %q", field, msg, fields[field])
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.10.0", -1},
+		{"v2.0.0", "1.99.99", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.9", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-1", 1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+	} {
+		if got, err := CompareVersions(tc.a, tc.b); err != nil || got != tc.want {
+			t.Errorf("%s vs %s: expected %d, got %d (%v)", tc.a, tc.b, tc.want, got, err)
+		}
+	}
+	if _, err := CompareVersions("1.2", "1.2.0"); err == nil {
+		t.Error("expected an error for an incomplete version")
+	}
+
+	m := New("p", "P", "app").Version("1.0.0").MinHostVersion("2.3.0").MustBuild()
+	if err := m.CheckHost("2.2.9"); err == nil {
+		t.Error("expected an older host to be refused")
+	}
+	for _, host := range []string{"2.3.0", "3.0.0", ""} {
+		if err := m.CheckHost(host); err != nil {
+			t.Errorf("expected host %q to pass: %v", host, err)
+		}
+	}
+	if _, err := New("p", "P", "app").Version("1.0.0").MinHostVersion("latest").Build(); err == nil {
+		t.Error("expected an invalid min host version to be rejected")
+	}
+}
+
+func TestBuilderPermissionScopes(t *testing.T) {
+	m, err := New("p", "P", "app").Version("1.0.0").
+		AllowWebSocket("wss://stream.example.com/*").
+		Storage(1<<20, 100).
+		Secret("binance_api_key", "Shared Binance key").
+		Scheduler(5, time.Minute).
+		Notifications().
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := m.Resources
+	if len(r.AllowedWebSocketTargets) != 1 || r.Storage.MaxKeys != 100 || r.Secrets[0].Name != "binance_api_key" ||
+		r.Scheduler.MinIntervalSeconds != 60 || !r.Notifications {
+		t.Errorf("unexpected resources %+v", r)
+	}
+
+	_, err = New("p", "P", "app").Version("1.0.0").
+		AllowWebSocket("https://api.example.com/*").
+		Secret("", "").
+		Secret("key", "").
+		Secret("key", "").
+		Storage(-1, 0).
+		Build()
+	want := map[string]string{
+		"resources.allowedWebSocketTargets[0].pattern": "must use the ws or wss scheme",
+		"resources.secrets[0].name":                    "is required",
+		"resources.secrets[2].name":                    "is declared twice",
+		"resources.storage.maxBytes":                   "must be at least 0",
+	}
+	fields := utils.FieldErrorsOf(err)
+	if len(fields) != len(want) {
+		t.Fatalf("expected %v, got %v", want, fields)
+	}
+	for field, msg := range want {
+		if fields[field] != msg {
+			t.Errorf("%s: expected %q, got %q", field, msg, fields[field])
+		}
+	}
+}
+
+func TestBuilderLimits(t *testing.T) {
+	m := New("p", "P", "app").Version("1.0.0").
+		MaxMemory(64<<20).
+		CommandTimeout(10*time.Second).
+		CommandTimeout(5*time.Minute, "backfill").
+		MaxStreams(20).
+		MustBuild()
+	if m.Limits.MaxMemoryBytes != 64<<20 || m.Limits.MaxStreams != 20 {
+		t.Errorf("unexpected limits %+v", m.Limits)
+	}
+	if d := m.Limits.CommandTimeout("getMarkets"); d != 10*time.Second {
+		t.Errorf("expected the default timeout, got %s", d)
+	}
+	if d := m.Limits.CommandTimeout("backfill"); d != 5*time.Minute {
+		t.Errorf("expected the backfill timeout, got %s", d)
+	}
+	var none *Limits
+	if none.CommandTimeout("x") != 0 {
+		t.Error("expected no timeout without limits")
+	}
+
+	_, err := New("p", "P", "app").Version("1.0.0").MaxStreams(-1).CommandTimeout(-time.Second, "x").Build()
+	fields := utils.FieldErrorsOf(err)
+	if fields["limits.maxStreams"] != "must be at least 0" || fields["limits.commandTimeoutsMs[x]"] != "must be at least 0" {
+		t.Errorf("unexpected field errors %v", fields)
+	}
+}
+
+func TestBuilderLocalization(t *testing.T) {
+	m := New("p", "Calendar", "app").Version("1.2.0").
+		Description("Economic calendar").
+		Localize("de", "Kalender", "Wirtschaftskalender").
+		Localize("fr", "", "Calendrier économique").
+		ReleaseNote(ReleaseNote{Version: "1.0.0", Date: "2026-01-10", Notes: "Initial release"}).
+		ReleaseNote(ReleaseNote{Version: "1.2.0", Notes: "Reminders", NotesI18n: map[string]string{"de": "Erinnerungen"}}).
+		ReleaseNote(ReleaseNote{Version: "1.1.0", Notes: "ICS import", Breaking: true}).
+		MustBuild()
+
+	if text := m.Text("de-AT"); text.Name != "Kalender" || text.Description != "Wirtschaftskalender" {
+		t.Errorf("unexpected german text %+v", text)
+	}
+	if text := m.Text("fr"); text.Name != "Calendar" || text.Description != "Calendrier économique" {
+		t.Errorf("expected the untranslated name as fallback, got %+v", text)
+	}
+	if text := m.Text("ja"); text.Name != "Calendar" {
+		t.Errorf("unexpected fallback %+v", text)
+	}
+
+	notes, err := m.ReleaseNotesSince("1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[0].Version != "1.2.0" || notes[1].Version != "1.1.0" {
+		t.Errorf("unexpected notes %+v", notes)
+	}
+	if notes[0].Text("de_DE") != "Erinnerungen" || notes[1].Text("de") != "ICS import" {
+		t.Errorf("unexpected translated notes")
+	}
+
+	_, err = New("p", "P", "app").Version("1.0.0").
+		ReleaseNote(ReleaseNote{Version: "1.0.0", Date: "10.01.2026"}).
+		ReleaseNote(ReleaseNote{Version: "1.0.0"}).
+		Build()
+	fields := utils.FieldErrorsOf(err)
+	if fields["changelog[1].version"] == "" || fields["changelog[0].date"] == "" {
+		t.Errorf("unexpected field errors %v", fields)
+	}
+}
@@ -1,5 +1,7 @@
 package meta
 
+import "time"
+
 type Meta struct {
 	PluginID    string          `json:"pluginId" validate:"required"`
 	Name        string          `json:"name" validate:"required"`
@@ -12,7 +14,50 @@ type Meta struct {
 	Tags        []string        `json:"tags"`
 	Contacts    []AuthorContact `json:"contacts"`
 	Resources   ResourceAccess  `json:"resources"`
-	Features    []string        `json:"features"` // List of supported features
+	Features    []string        `json:"features"`           // List of supported features
+	Webhooks    []Webhook       `json:"webhooks,omitempty"` // Endpoints the host routes to the handle_webhook export
+	Limits      *Limits         `json:"limits,omitempty"`   // Resource envelope the plugin runs in; nil uses the host defaults
+
+	// Marketplace texts. Localizations are keyed by language tag like "de"
+	// or "pt-BR", Changelog lists the release notes of past versions.
+	Localizations map[string]MetaText `json:"localizations,omitempty" validate:"omitempty,dive,keys,required,endkeys"`
+	Changelog     []ReleaseNote       `json:"changelog,omitempty" validate:"dive"`
+
+	// Compatibility. SDKVersion and ProtocolVersion are filled by the meta
+	// export when empty; hosts can refuse or warn about plugins they cannot run.
+	MinHostVersion  string `json:"minHostVersion,omitempty" validate:"omitempty,semver"` // Oldest host version the plugin works with
+	SDKVersion      string `json:"sdkVersion,omitempty"`                                 // Version of this library the plugin was built with
+	ProtocolVersion int    `json:"protocolVersion,omitempty"`                            // Export and host function protocol the plugin implements
+}
+
+// Webhook declares an endpoint the host exposes for the plugin. The host
+// derives the public URL from the connection and Name.
+type Webhook struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description,omitempty"`
+	Methods     []string `json:"methods,omitempty"`     // Allowed HTTP methods, defaults to POST
+	MaxBodySize int      `json:"maxBodySize,omitempty"` // In bytes; 0 uses the host default
+}
+
+// Limits declare the resources a plugin needs at most, so the host can
+// size the runtime and reject plugins that exceed them. Zero values leave
+// the host default; they mirror hostinfo.Limits.
+type Limits struct {
+	MaxMemoryBytes    int64            `json:"maxMemoryBytes,omitempty" validate:"gte=0"`
+	CommandTimeoutMs  int64            `json:"commandTimeoutMs,omitempty" validate:"gte=0"`                 // Longest execution of a single command
+	CommandTimeoutsMs map[string]int64 `json:"commandTimeoutsMs,omitempty" validate:"omitempty,dive,gte=0"` // Per command overrides, e.g. for backfills
+	MaxStreams        int              `json:"maxStreams,omitempty" validate:"gte=0"`                       // Expected concurrent WS/HTTP streams
+}
+
+// CommandTimeout returns the declared timeout of a command, 0 if none
+func (l *Limits) CommandTimeout(command string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	if ms, ok := l.CommandTimeoutsMs[command]; ok {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return time.Duration(l.CommandTimeoutMs) * time.Millisecond
 }
 
 type AuthorContact struct {
@@ -20,11 +65,47 @@ type AuthorContact struct {
 	Value string `json:"value"`
 }
 
+// ResourceAccess declares everything a plugin needs beyond computing, so the
+// host can show a precise permission prompt and grant nothing more
 type ResourceAccess struct {
-	AllowedNetworkTargets []NetworkTargetRule `json:"allowedNetworkTargets"`
-	FsWriteAccess         map[string]string   `json:"fsWriteAccess"`
+	AllowedNetworkTargets   []NetworkTargetRule `json:"allowedNetworkTargets"`
+	AllowedWebSocketTargets []NetworkTargetRule `json:"allowedWebSocketTargets,omitempty"` // ws(s) targets streams may connect to
+	FsWriteAccess           map[string]string   `json:"fsWriteAccess"`
+	AllowedPlugins          []PluginTargetRule  `json:"allowedPlugins,omitempty"` // Plugins this plugin may invoke through the bridge
+
+	Storage       *StorageQuota    `json:"storage,omitempty"`                 // Key-value storage; nil means none
+	Secrets       []SecretAccess   `json:"secrets,omitempty" validate:"dive"` // Secrets the plugin reads from the host vault
+	Scheduler     *SchedulerAccess `json:"scheduler,omitempty"`               // Scheduled jobs; nil means none
+	Notifications bool             `json:"notifications,omitempty"`           // Sends user notifications, e.g. triggered alerts
 }
 
 type NetworkTargetRule struct {
 	Pattern string `json:"pattern"`
 }
+
+// StorageQuota is the key-value storage the plugin requests. Zero values
+// use the host defaults.
+type StorageQuota struct {
+	MaxBytes int64 `json:"maxBytes,omitempty" validate:"gte=0"`
+	MaxKeys  int   `json:"maxKeys,omitempty" validate:"gte=0"`
+}
+
+// SecretAccess names a secret the user stores in the host, e.g. an API key
+// shared by several plugins
+type SecretAccess struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"` // Shown in the permission prompt
+}
+
+// SchedulerAccess requests running scheduled jobs
+type SchedulerAccess struct {
+	MaxJobs            int `json:"maxJobs,omitempty" validate:"gte=0"`            // Concurrently registered jobs; 0 uses the host default
+	MinIntervalSeconds int `json:"minIntervalSeconds,omitempty" validate:"gte=0"` // Shortest interval between runs the plugin uses
+}
+
+// PluginTargetRule allows invoking commands of another installed plugin.
+// An empty Commands list allows every command.
+type PluginTargetRule struct {
+	PluginID string   `json:"pluginId"`
+	Commands []string `json:"commands,omitempty"`
+}
@@ -0,0 +1,88 @@
+package meta
+
+import (
+	"slices"
+	"strings"
+)
+
+// MetaText is the translatable part of Meta. Empty fields fall back to
+// the untranslated Name and Description.
+type MetaText struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ReleaseNote describes the changes of one plugin version
+type ReleaseNote struct {
+	Version   string            `json:"version" validate:"required,semver"`
+	Date      string            `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Notes     string            `json:"notes"`               // Markdown
+	Breaking  bool              `json:"breaking,omitempty"`  // Users have to reconfigure after updating
+	NotesI18n map[string]string `json:"notesI18n,omitempty"` // Translated notes by language tag
+}
+
+// Text returns the name and description for a language tag. Region subtags
+// fall back to the language ("de-AT" uses "de"), missing translations to
+// the untranslated fields.
+func (m Meta) Text(lang string) MetaText {
+	text := MetaText{Name: m.Name, Description: m.Description}
+	loc, ok := lookupLang(m.Localizations, lang)
+	if !ok {
+		return text
+	}
+	if loc.Name != "" {
+		text.Name = loc.Name
+	}
+	if loc.Description != "" {
+		text.Description = loc.Description
+	}
+	return text
+}
+
+// ReleaseNotesSince returns the release notes of versions newer than
+// version, newest first. An empty version returns the whole changelog.
+func (m Meta) ReleaseNotesSince(version string) ([]ReleaseNote, error) {
+	var notes []ReleaseNote
+	for _, note := range m.Changelog {
+		if version != "" {
+			cmp, err := CompareVersions(note.Version, version)
+			if err != nil {
+				return nil, err
+			}
+			if cmp <= 0 {
+				continue
+			}
+		}
+		notes = append(notes, note)
+	}
+	var sortErr error
+	slices.SortStableFunc(notes, func(a, b ReleaseNote) int {
+		cmp, err := CompareVersions(b.Version, a.Version)
+		if err != nil {
+			sortErr = err
+		}
+		return cmp
+	})
+	return notes, sortErr
+}
+
+// Text returns the notes for a language tag with the same fallback as Meta.Text
+func (n ReleaseNote) Text(lang string) string {
+	if notes, ok := lookupLang(n.NotesI18n, lang); ok && notes != "" {
+		return notes
+	}
+	return n.Notes
+}
+
+func lookupLang[T any](values map[string]T, lang string) (T, bool) {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	for _, tag := range []string{lang, strings.SplitN(lang, "-", 2)[0]} {
+		for key, v := range values {
+			if strings.EqualFold(key, tag) {
+				return v, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,126 @@
+package meta
+
+import (
+	"cmp"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SDKModule is the module path of this library
+const SDKModule = "github.com/plusev-terminal/go-plugin-common"
+
+// SDKVersion returns the version of this library the plugin was built
+// with, read from the build info, e.g. "1.4.0" or a pseudo-version. It is
+// empty when the version is unknown, e.g. for a replaced module or in the
+// library's own tests.
+var SDKVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == SDKModule && dep.Replace == nil {
+			return strings.TrimPrefix(dep.Version, "v")
+		}
+	}
+	return ""
+})
+
+// CompareVersions compares two semantic versions and returns -1, 0 or +1.
+// A leading "v" and build metadata are ignored; a pre-release sorts before
+// its release.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range 3 {
+		if va.core[i] != vb.core[i] {
+			if va.core[i] < vb.core[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0, nil
+	case va.pre == "":
+		return 1, nil
+	case vb.pre == "":
+		return -1, nil
+	}
+	return comparePrerelease(va.pre, vb.pre), nil
+}
+
+// comparePrerelease orders pre-release identifiers by semver precedence:
+// numeric identifiers numerically and before alphanumeric ones
+func comparePrerelease(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return cmp.Compare(na, nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(pa), len(pb))
+}
+
+// CheckHost returns an error if hostVersion is older than m.MinHostVersion.
+// An empty version on either side passes, as not every host reports one.
+func (m Meta) CheckHost(hostVersion string) error {
+	if m.MinHostVersion == "" || hostVersion == "" {
+		return nil
+	}
+	c, err := CompareVersions(hostVersion, m.MinHostVersion)
+	if err != nil {
+		return fmt.Errorf("cannot check host version: %w", err)
+	}
+	if c < 0 {
+		return fmt.Errorf("plugin %s requires host version %s or newer, host is %s", m.PluginID, m.MinHostVersion, hostVersion)
+	}
+	return nil
+}
+
+type version struct {
+	core [3]int
+	pre  string
+}
+
+func parseVersion(s string) (version, error) {
+	var v version
+	rest, _, _ := strings.Cut(strings.TrimPrefix(s, "v"), "+")
+	rest, v.pre, _ = strings.Cut(rest, "-")
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.core[i] = n
+	}
+	return v, nil
+}
@@ -0,0 +1,255 @@
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	mapstructure "github.com/go-viper/mapstructure/v2"
+)
+
+// ErrShortBuffer is returned for truncated input
+var ErrShortBuffer = errors.New("msgpack: unexpected end of data")
+
+// Unmarshal decodes MessagePack data into v, using the json tags of structs
+func Unmarshal(data []byte, v any) error {
+	return unmarshal(data, v, false)
+}
+
+// UnmarshalUseNumber is Unmarshal with numbers in generic values (any,
+// map[string]any) decoded as json.Number, like json.Decoder.UseNumber
+func UnmarshalUseNumber(data []byte, v any) error {
+	return unmarshal(data, v, true)
+}
+
+func unmarshal(data []byte, v any, useNumber bool) error {
+	generic, err := decode(data, useNumber)
+	if err != nil {
+		return err
+	}
+
+	if out, ok := v.(*any); ok {
+		*out = generic
+		return nil
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+		),
+		Result:           v,
+		TagName:          "json",
+		Squash:           true,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return fmt.Errorf("msgpack: %w", err)
+	}
+	if err := decoder.Decode(generic); err != nil {
+		return fmt.Errorf("msgpack: %w", err)
+	}
+	return nil
+}
+
+// Decode decodes MessagePack data into generic values: nil, bool, float64,
+// string, []byte, []any and map[string]any. Like encoding/json, all numbers
+// become float64, so command handlers see the same params for both formats.
+func Decode(data []byte) (any, error) {
+	return decode(data, false)
+}
+
+// DecodeUseNumber is Decode with numbers decoded as json.Number, which
+// keeps int64 IDs beyond 2^53 exact
+func DecodeUseNumber(data []byte) (any, error) {
+	return decode(data, true)
+}
+
+func decode(data []byte, useNumber bool) (any, error) {
+	d := decoder{data: data, useNumber: useNumber}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data      []byte
+	pos       int
+	useNumber bool
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, ErrShortBuffer
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (d *decoder) value() (any, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return d.int(int64(c)), nil
+	case c >= 0xe0:
+		return d.int(int64(int8(c))), nil
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 0xca:
+		n, err := d.uint(4)
+		return d.float(float64(math.Float32frombits(uint32(n))), 32), err
+	case 0xcb:
+		n, err := d.uint(8)
+		return d.float(math.Float64frombits(n), 64), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if d.useNumber {
+			return json.Number(strconv.FormatUint(n, 10)), nil
+		}
+		return float64(n), nil
+	case 0xd0:
+		n, err := d.uint(1)
+		return d.int(int64(int8(n))), err
+	case 0xd1:
+		n, err := d.uint(2)
+		return d.int(int64(int16(n))), err
+	case 0xd2:
+		n, err := d.uint(4)
+		return d.int(int64(int32(n))), err
+	case 0xd3:
+		n, err := d.uint(8)
+		return d.int(int64(n)), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type byte 0x%02x", c)
+}
+
+func (d *decoder) int(n int64) any {
+	if d.useNumber {
+		return json.Number(strconv.FormatInt(n, 10))
+	}
+	return float64(n)
+}
+
+func (d *decoder) float(f float64, bitSize int) any {
+	if d.useNumber {
+		return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return f
+}
+
+func (d *decoder) str(n int) (string, error) {
+	b, err := d.next(n)
+	return string(b), err
+}
+
+func (d *decoder) array(n int) ([]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, ErrShortBuffer
+	}
+	out := make([]any, n)
+	for i := range out {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (d *decoder) mapValue(n int) (map[string]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, ErrShortBuffer
+	}
+	out := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
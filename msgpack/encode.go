@@ -0,0 +1,396 @@
+// Package msgpack is a compact MessagePack codec for plugin I/O. It follows
+// encoding/json conventions, so the existing json tags of commands, responses
+// and stream payloads apply unchanged: field names, omitempty, "-" and
+// flattened embedded structs. time.Time is encoded as RFC 3339 string and
+// values implementing json.Marshaler are encoded from their JSON form, so
+// both wire formats carry the same data.
+//
+// Payloads are decoded into generic values first and then mapped onto the
+// target, like datapipe.DecodePort does for port payloads.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Marshal returns the MessagePack encoding of v
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encoder writes MessagePack values to a buffer. The buffer can be reused
+// across messages with Reset to avoid allocations on hot paths.
+type Encoder struct {
+	buf     *bytes.Buffer
+	scratch [9]byte
+}
+
+// NewEncoder creates an encoder appending to buf
+func NewEncoder(buf *bytes.Buffer) *Encoder {
+	return &Encoder{buf: buf}
+}
+
+// Encode appends the encoding of v
+func (e *Encoder) Encode(v any) error {
+	return e.encode(reflect.ValueOf(v))
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	bytesType         = reflect.TypeOf([]byte(nil))
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (e *Encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf.WriteByte(0xc0)
+		return nil
+	}
+
+	t := v.Type()
+	switch {
+	case t == timeType:
+		e.writeString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return nil
+	case t == rawMessageType:
+		if v.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+		return e.encodeJSON(v)
+	case t.Implements(jsonMarshalerType) && t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface:
+		return e.encodeJSON(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf.WriteByte(0xc3)
+		} else {
+			e.buf.WriteByte(0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeUint(v.Uint())
+	case reflect.Float32:
+		e.buf.WriteByte(0xca)
+		binary.BigEndian.PutUint32(e.scratch[:4], math.Float32bits(float32(v.Float())))
+		e.buf.Write(e.scratch[:4])
+	case reflect.Float64:
+		e.buf.WriteByte(0xcb)
+		binary.BigEndian.PutUint64(e.scratch[:8], math.Float64bits(v.Float()))
+		e.buf.Write(e.scratch[:8])
+	case reflect.String:
+		e.writeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+		if t == bytesType || t.Elem().Kind() == reflect.Uint8 {
+			e.writeBin(v.Bytes())
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		e.writeArrayHeader(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", t)
+	}
+	return nil
+}
+
+// encodeJSON encodes v from its JSON form, for types with custom JSON encoding
+func (e *Encoder) encodeJSON(v reflect.Value) error {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	return e.encode(reflect.ValueOf(generic))
+}
+
+func (e *Encoder) encodeMap(v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("msgpack: unsupported map key type %s", v.Type().Key())
+	}
+	keys := v.MapKeys()
+	// Sorted keys keep the encoding deterministic, as with encoding/json
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	e.writeMapHeader(len(keys))
+	for _, k := range keys {
+		e.writeString(k.String())
+		if err := e.encode(v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value) error {
+	fields := cachedFields(v.Type())
+
+	n := 0
+	for _, f := range fields {
+		if fv, ok := fieldByIndex(v, f.index); ok && !(f.omitEmpty && isEmpty(fv)) {
+			n++
+		}
+	}
+
+	e.writeMapHeader(n)
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmpty(fv)) {
+			continue
+		}
+		e.writeString(f.name)
+		if err := e.encode(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) writeInt(n int64) {
+	switch {
+	case n >= 0:
+		e.writeUint(uint64(n))
+	case n >= -32:
+		e.buf.WriteByte(byte(int8(n)))
+	case n >= math.MinInt8:
+		e.buf.Write([]byte{0xd0, byte(int8(n))})
+	case n >= math.MinInt16:
+		e.buf.WriteByte(0xd1)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(int16(n)))
+		e.buf.Write(e.scratch[:2])
+	case n >= math.MinInt32:
+		e.buf.WriteByte(0xd2)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(int32(n)))
+		e.buf.Write(e.scratch[:4])
+	default:
+		e.buf.WriteByte(0xd3)
+		binary.BigEndian.PutUint64(e.scratch[:8], uint64(n))
+		e.buf.Write(e.scratch[:8])
+	}
+}
+
+func (e *Encoder) writeUint(n uint64) {
+	switch {
+	case n <= 0x7f:
+		e.buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		e.buf.Write([]byte{0xcc, byte(n)})
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xcd)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(n))
+		e.buf.Write(e.scratch[:2])
+	case n <= math.MaxUint32:
+		e.buf.WriteByte(0xce)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(n))
+		e.buf.Write(e.scratch[:4])
+	default:
+		e.buf.WriteByte(0xcf)
+		binary.BigEndian.PutUint64(e.scratch[:8], n)
+		e.buf.Write(e.scratch[:8])
+	}
+}
+
+func (e *Encoder) writeString(s string) {
+	n := len(s)
+	switch {
+	case n <= 31:
+		e.buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xda)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(n))
+		e.buf.Write(e.scratch[:2])
+	default:
+		e.buf.WriteByte(0xdb)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(n))
+		e.buf.Write(e.scratch[:4])
+	}
+	e.buf.WriteString(s)
+}
+
+func (e *Encoder) writeBin(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xc5)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(n))
+		e.buf.Write(e.scratch[:2])
+	default:
+		e.buf.WriteByte(0xc6)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(n))
+		e.buf.Write(e.scratch[:4])
+	}
+	e.buf.Write(b)
+}
+
+func (e *Encoder) writeArrayHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xdc)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(n))
+		e.buf.Write(e.scratch[:2])
+	default:
+		e.buf.WriteByte(0xdd)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(n))
+		e.buf.Write(e.scratch[:4])
+	}
+}
+
+func (e *Encoder) writeMapHeader(n int) {
+	switch {
+	case n <= 15:
+		e.buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(0xde)
+		binary.BigEndian.PutUint16(e.scratch[:2], uint16(n))
+		e.buf.Write(e.scratch[:2])
+	default:
+		e.buf.WriteByte(0xdf)
+		binary.BigEndian.PutUint32(e.scratch[:4], uint32(n))
+		e.buf.Write(e.scratch[:4])
+	}
+}
+
+// field is an encoded struct field
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []field
+
+// cachedFields returns the fields of t as encoding/json sees them. Fields of
+// embedded structs are flattened; on name conflicts the shallower field wins.
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+
+	var fields []field
+	seen := make(map[string]int) // name -> depth
+	var walk func(t reflect.Type, index []int, depth int)
+	walk = func(t reflect.Type, index []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(append([]int{}, index...), i)
+
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				walk(ft, idx, depth+1)
+				continue
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			if d, ok := seen[name]; ok && d <= depth {
+				continue
+			}
+			seen[name] = depth
+			fields = append(fields, field{name: name, index: idx, omitEmpty: strings.Contains(opts, "omitempty")})
+		}
+	}
+	walk(t, nil, 0)
+
+	// Drop fields shadowed by shallower ones found later in the walk
+	out := fields[:0]
+	for _, f := range fields {
+		if seen[f.name] == len(f.index)-1 {
+			out = append(out, f)
+		}
+	}
+	fieldCache.Store(t, out)
+	return out
+}
+
+// fieldByIndex returns the field at index, or false when an embedded
+// pointer on the way is nil
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package msgpack
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Source string `json:"source"`
+}
+
+type payload struct {
+	inner
+	Name     string         `json:"name"`
+	Count    int64          `json:"count"`
+	Ratio    float64        `json:"ratio"`
+	Tags     []string       `json:"tags,omitempty"`
+	At       time.Time      `json:"at"`
+	Optional *int           `json:"optional,omitempty"`
+	Extra    map[string]any `json:"extra"`
+	Raw      []byte         `json:"raw"`
+	Skipped  string         `json:"-"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := payload{
+		inner: inner{Source: "binance"},
+		Name:  "BTCUSDT",
+		Count: -70000,
+		Ratio: 0.25,
+		At:    time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC),
+		Extra: map[string]any{"depth": 20.0, "live": true, "levels": []any{"a", nil}},
+		Raw:   []byte{1, 2, 3},
+	}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out payload
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("round trip mismatch:\n%+v\n%+v", in, out)
+	}
+
+	jsonData, _ := json.Marshal(in)
+	if len(data) >= len(jsonData) {
+		t.Errorf("expected msgpack (%d bytes) to be smaller than JSON (%d bytes)", len(data), len(jsonData))
+	}
+}
+
+func TestDecodeMatchesJSONShape(t *testing.T) {
+	data, err := Marshal(map[string]any{"limit": 100, "symbols": []string{"BTC"}, "since": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want any
+	_ = json.Unmarshal([]byte(`{"limit": 100, "symbols": ["BTC"], "since": null}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := Decode(data[:len(data)-1]); err == nil {
+		t.Fatal("expected error for truncated input")
+	}
+}
@@ -2,4 +2,5 @@ package planner
 
 const (
 	CMD_IMPORT_EVENTS = "import_events"
+	CMD_SYNC_EVENTS   = "sync_events"
 )
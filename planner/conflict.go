@@ -0,0 +1,130 @@
+package planner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ConflictPolicy decides what happens when an imported event has the same
+// ExternalID as an event the host already has
+type ConflictPolicy string
+
+const (
+	SKIP      ConflictPolicy = "skip"      // Keep the existing event
+	OVERWRITE ConflictPolicy = "overwrite" // Replace the existing event
+	MERGE     ConflictPolicy = "merge"     // Set fields the import has, keep user additions like tags and reminders
+)
+
+// ImportData is the result of the import command. Re-importing an overlapping
+// range returns events the host already has; ContentHash lets it skip the
+// unchanged ones and ConflictPolicy says how to reconcile the others.
+type ImportData struct {
+	Events         []ImportEvent  `json:"events"`
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"` // Defaults to OVERWRITE
+}
+
+// NewImportData returns the import result with the content hash of every event set
+func NewImportData(events []ImportEvent, policy ConflictPolicy) ImportData {
+	for i := range events {
+		events[i].ContentHash = events[i].Hash()
+	}
+	return ImportData{Events: events, ConflictPolicy: policy}
+}
+
+func (d ImportData) Validate() error {
+	switch d.ConflictPolicy {
+	case "", SKIP, OVERWRITE, MERGE:
+	default:
+		return fmt.Errorf("unsupported conflict policy %q", d.ConflictPolicy)
+	}
+	for _, e := range d.Events {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("event %q: %w", e.Title, err)
+		}
+	}
+	return nil
+}
+
+// Hash returns a hex SHA-256 of the event content. ExternalID and ContentHash
+// are excluded and times are compared as instants, so the same event from a
+// feed in another zone hashes equal.
+func (e ImportEvent) Hash() string {
+	e.ExternalID = ""
+	e.ContentHash = ""
+	e.StartDate = e.StartDate.UTC()
+	e.EndDate = e.EndDate.UTC()
+	if r := e.Recurrence; r != nil {
+		utc := *r
+		if r.Until != nil {
+			until := r.Until.UTC()
+			utc.Until = &until
+		}
+		utc.Exceptions = make([]time.Time, len(r.Exceptions))
+		for i, ex := range r.Exceptions {
+			utc.Exceptions[i] = ex.UTC()
+		}
+		e.Recurrence = &utc
+	}
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Resolve reconciles an existing event with its re-import. It returns the
+// event to store and whether it differs from existing.
+func Resolve(existing, incoming ImportEvent, policy ConflictPolicy) (ImportEvent, bool) {
+	if existing.Hash() == incoming.Hash() {
+		return existing, false
+	}
+
+	switch policy {
+	case SKIP:
+		return existing, false
+	case MERGE:
+		merged := existing
+		merged.Title = incoming.Title
+		merged.StartDate, merged.EndDate, merged.AllDay = incoming.StartDate, incoming.EndDate, incoming.AllDay
+		if incoming.Notes != "" {
+			merged.Notes = incoming.Notes
+		}
+		if incoming.Timezone != "" {
+			merged.Timezone = incoming.Timezone
+		}
+		if incoming.Priority != "" {
+			merged.Priority = incoming.Priority
+		}
+		if incoming.Category != "" {
+			merged.Category = incoming.Category
+		}
+		if incoming.Color != "" {
+			merged.Color = incoming.Color
+		}
+		if incoming.Recurrence != nil {
+			merged.Recurrence = incoming.Recurrence
+		}
+		if len(incoming.RelatedMarkets) > 0 {
+			merged.RelatedMarkets = incoming.RelatedMarkets
+		}
+		merged.Tags = union(existing.Tags, incoming.Tags)
+		merged.Reminders = union(existing.Reminders, incoming.Reminders)
+		merged.ContentHash = merged.Hash()
+		return merged, merged.ContentHash != existing.Hash()
+	default:
+		incoming.ContentHash = incoming.Hash()
+		return incoming, true
+	}
+}
+
+func union[T comparable](a, b []T) []T {
+	result := slices.Clone(a)
+	for _, v := range b {
+		if !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
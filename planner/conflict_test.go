@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	at := time.Date(2026, 7, 29, 18, 0, 0, 0, time.UTC)
+	existing := ImportEvent{
+		ExternalID: "fomc",
+		Title:      "FOMC",
+		StartDate:  at,
+		Tags:       []string{"rates", "mine"},
+		Reminders:  []Reminder{{OffsetMinutes: 60}},
+	}
+	existing.ContentHash = existing.Hash()
+
+	same := existing
+	same.StartDate = at.In(time.FixedZone("EDT", -4*3600))
+	same.ContentHash = ""
+	if _, changed := Resolve(existing, same, OVERWRITE); changed {
+		t.Error("expected an unchanged event to be detected by its hash")
+	}
+
+	incoming := ImportEvent{ExternalID: "fomc", Title: "FOMC", StartDate: at.Add(time.Hour), Tags: []string{"rates", "usd"}, Priority: HIGH}
+
+	if got, changed := Resolve(existing, incoming, SKIP); changed || !got.StartDate.Equal(at) {
+		t.Errorf("expected skip to keep the existing event, got %+v", got)
+	}
+
+	got, changed := Resolve(existing, incoming, OVERWRITE)
+	if !changed || len(got.Reminders) != 0 || got.ContentHash != incoming.Hash() {
+		t.Errorf("expected overwrite to replace the event, got %+v", got)
+	}
+
+	got, changed = Resolve(existing, incoming, MERGE)
+	if !changed || !got.StartDate.Equal(at.Add(time.Hour)) || got.Priority != HIGH {
+		t.Errorf("expected merge to take the imported fields, got %+v", got)
+	}
+	if len(got.Tags) != 3 || got.Tags[2] != "usd" || len(got.Reminders) != 1 {
+		t.Errorf("expected merge to keep user tags and reminders, got %+v", got)
+	}
+	if got.ContentHash != got.Hash() {
+		t.Error("expected the merged event to carry its hash")
+	}
+}
+
+func TestNewImportData(t *testing.T) {
+	data := NewImportData([]ImportEvent{{Title: "CPI", StartDate: time.Now()}}, MERGE)
+	if err := data.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if data.Events[0].ContentHash == "" || data.Events[0].ContentHash != data.Events[0].Hash() {
+		t.Errorf("expected the content hash to be set, got %q", data.Events[0].ContentHash)
+	}
+	if (ImportData{ConflictPolicy: "replace"}).Validate() == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
@@ -0,0 +1,208 @@
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/planner"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// EncodeOption configures Encode and Marshal
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	clock clock.Clock
+}
+
+// WithClock sets the clock for the DTSTAMP of the events, by default
+// wasmutils.HostClock
+func WithClock(c clock.Clock) EncodeOption {
+	return func(o *encodeOptions) {
+		o.clock = c
+	}
+}
+
+// Encode writes events as an iCalendar feed. Events without an ExternalID
+// get a UID derived from their start and title, so re-exporting them yields
+// the same UID.
+func Encode(w io.Writer, events []planner.ImportEvent, opts ...EncodeOption) error {
+	o := encodeOptions{clock: wasmutils.HostClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	bw := bufio.NewWriter(w)
+	write := func(line string) {
+		writeFolded(bw, line)
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + defaultProdID)
+	write("CALSCALE:GREGORIAN")
+	now := o.clock.Now().UTC().Format(utcLayout)
+	for _, e := range events {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("event %q: %w", e.Title, err)
+		}
+		loc, err := eventLocation(e)
+		if err != nil {
+			return err
+		}
+
+		write("BEGIN:VEVENT")
+		write("UID:" + escapeText(eventUID(e)))
+		write("DTSTAMP:" + now)
+		write("SUMMARY:" + escapeText(e.Title))
+		write(formatTime("DTSTART", e.StartDate, e.AllDay, loc))
+		if !e.EndDate.IsZero() {
+			write(formatTime("DTEND", e.EndDate, e.AllDay, loc))
+		}
+		if e.Notes != "" {
+			write("DESCRIPTION:" + escapeText(e.Notes))
+		}
+		if len(e.Tags) > 0 {
+			tags := make([]string, len(e.Tags))
+			for i, tag := range e.Tags {
+				tags[i] = escapeText(tag)
+			}
+			write("CATEGORIES:" + strings.Join(tags, ","))
+		}
+		if n := priorityToICS(e.Priority); n > 0 {
+			write("PRIORITY:" + strconv.Itoa(n))
+		}
+		if r := e.Recurrence; r != nil {
+			write("RRULE:" + FormatRRule(*r))
+			for _, ex := range r.Exceptions {
+				write(formatTime("EXDATE", ex, e.AllDay, loc))
+			}
+		}
+		for _, reminder := range e.Reminders {
+			write("BEGIN:VALARM")
+			if reminder.Method == planner.SOUND {
+				write("ACTION:AUDIO")
+			} else {
+				write("ACTION:DISPLAY")
+				write("DESCRIPTION:" + escapeText(e.Title))
+			}
+			write("TRIGGER:" + formatDuration(-time.Duration(reminder.OffsetMinutes)*time.Minute))
+			write("END:VALARM")
+		}
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// Marshal returns events as an iCalendar feed
+func Marshal(events []planner.ImportEvent, opts ...EncodeOption) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, events, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// eventLocation returns the zone times are written in; nil writes UTC
+func eventLocation(e planner.ImportEvent) (*time.Location, error) {
+	if e.Timezone == "" || e.Timezone == "UTC" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("event %q: invalid timezone %q: %w", e.Title, e.Timezone, err)
+	}
+	return loc, nil
+}
+
+func eventUID(e planner.ImportEvent) string {
+	if e.ExternalID != "" {
+		return e.ExternalID
+	}
+	h := fnv.New32a()
+	h.Write([]byte(e.Title))
+	return fmt.Sprintf("%s-%x@plusev", e.StartDate.UTC().Format(utcLayout), h.Sum32())
+}
+
+func formatTime(name string, t time.Time, allDay bool, loc *time.Location) string {
+	switch {
+	case allDay:
+		return name + ";VALUE=DATE:" + t.Format(dateLayout)
+	case loc != nil:
+		return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format(localLayout)
+	default:
+		return name + ":" + t.UTC().Format(utcLayout)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days := d / (24 * time.Hour); days > 0 {
+		b.WriteString(strconv.Itoa(int(days)) + "D")
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		b.WriteString("T")
+		for _, unit := range []struct {
+			d time.Duration
+			s string
+		}{{time.Hour, "H"}, {time.Minute, "M"}, {time.Second, "S"}} {
+			if n := d / unit.d; n > 0 {
+				b.WriteString(strconv.Itoa(int(n)) + unit.s)
+				d -= n * unit.d
+			}
+		}
+	}
+	return b.String()
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+func priorityToICS(p planner.Priority) int {
+	switch p {
+	case planner.HIGH:
+		return 1
+	case planner.MEDIUM:
+		return 5
+	case planner.LOW:
+		return 9
+	}
+	return 0
+}
+
+// writeFolded writes a content line, folded at 75 octets without splitting
+// UTF-8 sequences
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,449 @@
+// Package ics converts iCalendar (RFC 5545) feeds from and to planner events,
+// for planner plugins that wrap an ICS URL.
+//
+//	req, _ := requester.NewRequest("GET", feedURL).Build()
+//	resp, err := requester.NewRequester().Send(req, nil)
+//	...
+//	events, err := ics.Parse(bytes.NewReader(resp.Body))
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/planner"
+)
+
+const (
+	utcLayout     = "20060102T150405Z"
+	localLayout   = "20060102T150405"
+	dateLayout    = "20060102"
+	maxLineLength = 75
+	recurrenceSep = "/"
+	defaultProdID = "-//plusev-terminal//go-plugin-common//EN"
+)
+
+// property is one content line, e.g. DTSTART;TZID=Europe/Berlin:20260101T090000
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of an iCalendar feed.
+//
+// TZID parameters must be IANA names like "America/New_York"; unknown ones
+// and floating times use the calendar's X-WR-TIMEZONE, or UTC without one.
+// Modified instances of a recurring event (RECURRENCE-ID) are returned as
+// separate events whose ExternalID is the UID and the original start joined
+// by "/", and the original start is added to the series' exceptions.
+func Parse(r io.Reader) ([]planner.ImportEvent, error) {
+	props, err := readProperties(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultLoc := time.UTC
+	for _, p := range props {
+		if p.name == "X-WR-TIMEZONE" {
+			if loc, err := time.LoadLocation(p.value); err == nil {
+				defaultLoc = loc
+			}
+		}
+	}
+
+	var events []planner.ImportEvent
+	var overrides []override
+	var current *eventBuilder
+	var depth []string
+	for _, p := range props {
+		switch p.name {
+		case "BEGIN":
+			depth = append(depth, strings.ToUpper(p.value))
+			switch depth[len(depth)-1] {
+			case "VEVENT":
+				current = &eventBuilder{loc: defaultLoc}
+			case "VALARM":
+				if current != nil {
+					current.alarm = &alarm{}
+				}
+			}
+			continue
+		case "END":
+			if len(depth) == 0 {
+				return nil, fmt.Errorf("unexpected END:%s", p.value)
+			}
+			component := depth[len(depth)-1]
+			depth = depth[:len(depth)-1]
+			switch {
+			case component == "VALARM" && current != nil:
+				current.endAlarm()
+			case component == "VEVENT" && current != nil:
+				e, err := current.build()
+				if err != nil {
+					return nil, err
+				}
+				if current.recurrenceID != nil {
+					overrides = append(overrides, override{uid: current.uid, start: *current.recurrenceID})
+					e.ExternalID = current.uid + recurrenceSep + current.recurrenceID.UTC().Format(utcLayout)
+				}
+				events = append(events, e)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		if err := current.set(p); err != nil {
+			return nil, fmt.Errorf("event %q: %s: %w", current.uid, p.name, err)
+		}
+	}
+
+	for _, o := range overrides {
+		for i := range events {
+			if events[i].ExternalID == o.uid && events[i].Recurrence != nil {
+				events[i].Recurrence.Exceptions = append(events[i].Recurrence.Exceptions, o.start)
+			}
+		}
+	}
+	return events, nil
+}
+
+type override struct {
+	uid   string
+	start time.Time
+}
+
+type alarm struct {
+	action  string
+	trigger *time.Duration
+}
+
+// eventBuilder collects the properties of a VEVENT
+type eventBuilder struct {
+	event        planner.ImportEvent
+	uid          string
+	loc          *time.Location
+	duration     *time.Duration
+	recurrenceID *time.Time
+	exceptions   []time.Time
+	alarm        *alarm
+}
+
+func (b *eventBuilder) set(p property) error {
+	if b.alarm != nil {
+		switch p.name {
+		case "ACTION":
+			b.alarm.action = strings.ToUpper(p.value)
+		case "TRIGGER":
+			if p.params["VALUE"] == "DATE-TIME" || strings.EqualFold(p.params["RELATED"], "END") {
+				return nil // Absolute and end relative triggers are ignored
+			}
+			d, err := parseDuration(p.value)
+			if err != nil {
+				return err
+			}
+			b.alarm.trigger = &d
+		}
+		return nil
+	}
+
+	e := &b.event
+	switch p.name {
+	case "UID":
+		b.uid = p.value
+		e.ExternalID = p.value
+	case "SUMMARY":
+		e.Title = unescapeText(p.value)
+	case "DESCRIPTION":
+		e.Notes = unescapeText(p.value)
+	case "CATEGORIES":
+		for _, tag := range splitText(p.value) {
+			if tag != "" {
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+	case "PRIORITY":
+		n, err := strconv.Atoi(p.value)
+		if err != nil {
+			return err
+		}
+		e.Priority = priorityFromICS(n)
+	case "DTSTART":
+		t, allDay, err := parseTime(p.value, p.params, b.loc)
+		if err != nil {
+			return err
+		}
+		e.StartDate, e.AllDay = t, allDay
+		if tzid := p.params["TZID"]; tzid != "" && t.Location().String() == tzid {
+			e.Timezone = tzid
+		}
+	case "DTEND":
+		t, _, err := parseTime(p.value, p.params, b.loc)
+		if err != nil {
+			return err
+		}
+		e.EndDate = t
+	case "DURATION":
+		d, err := parseDuration(p.value)
+		if err != nil {
+			return err
+		}
+		b.duration = &d
+	case "RRULE":
+		r, err := ParseRRule(p.value)
+		if err != nil {
+			return err
+		}
+		e.Recurrence = r
+	case "EXDATE":
+		for v := range strings.SplitSeq(p.value, ",") {
+			t, _, err := parseTime(v, p.params, b.loc)
+			if err != nil {
+				return err
+			}
+			b.exceptions = append(b.exceptions, t)
+		}
+	case "RECURRENCE-ID":
+		t, _, err := parseTime(p.value, p.params, b.loc)
+		if err != nil {
+			return err
+		}
+		b.recurrenceID = &t
+	}
+	return nil
+}
+
+func (b *eventBuilder) endAlarm() {
+	a := b.alarm
+	b.alarm = nil
+	if a.trigger == nil || *a.trigger > 0 {
+		return
+	}
+	method := planner.NOTIFICATION
+	if a.action == "AUDIO" {
+		method = planner.SOUND
+	}
+	b.event.Reminders = append(b.event.Reminders, planner.Reminder{
+		OffsetMinutes: int(-*a.trigger / time.Minute),
+		Method:        method,
+	})
+}
+
+func (b *eventBuilder) build() (planner.ImportEvent, error) {
+	e := b.event
+	if e.StartDate.IsZero() {
+		return e, fmt.Errorf("event %q has no DTSTART", b.uid)
+	}
+	switch {
+	case !e.EndDate.IsZero():
+	case b.duration != nil:
+		e.EndDate = e.StartDate.Add(*b.duration)
+	case e.AllDay:
+		e.EndDate = e.StartDate.AddDate(0, 0, 1)
+	default:
+		e.EndDate = e.StartDate
+	}
+	if e.Recurrence != nil {
+		e.Recurrence.Exceptions = append(e.Recurrence.Exceptions, b.exceptions...)
+	}
+	return e, nil
+}
+
+// readProperties unfolds and splits the content lines of a feed
+func readProperties(r io.Reader) ([]property, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var props []property
+	var line strings.Builder
+	flush := func() error {
+		if line.Len() == 0 {
+			return nil
+		}
+		p, err := parseProperty(line.String())
+		line.Reset()
+		if err != nil {
+			return err
+		}
+		props = append(props, p)
+		return nil
+	}
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line.WriteString(text[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		line.WriteString(text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func parseProperty(line string) (property, error) {
+	// The value starts at the first colon outside a quoted parameter value
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	p := property{value: line[colon+1:], params: map[string]string{}}
+	parts := strings.Split(line[:colon], ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return p, nil
+}
+
+// parseTime parses a DATE or DATE-TIME value; TZID overrides loc
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == len(dateLayout):
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse(utcLayout, value)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation(localLayout, value, loc)
+		return t, false, err
+	}
+}
+
+// parseDuration parses durations like "PT15M", "-P1DT2H" or "P1W"
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var d time.Duration
+	inTime := false
+	num := 0
+	digits := false
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num = num*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		var u time.Duration
+		switch {
+		case !inTime && c == 'W':
+			u = 7 * 24 * time.Hour
+		case !inTime && c == 'D':
+			u = 24 * time.Hour
+		case inTime && c == 'H':
+			u = time.Hour
+		case inTime && c == 'M':
+			u = time.Minute
+		case inTime && c == 'S':
+			u = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(num) * u
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * d, nil
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitText splits a comma separated TEXT list, keeping escaped commas
+func splitText(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, unescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescapeText(s[start:]))
+}
+
+// priorityFromICS maps the 1 (highest) to 9 (lowest) scale, 0 is undefined
+func priorityFromICS(n int) planner.Priority {
+	switch {
+	case n <= 0:
+		return ""
+	case n <= 4:
+		return planner.HIGH
+	case n == 5:
+		return planner.MEDIUM
+	default:
+		return planner.LOW
+	}
+}
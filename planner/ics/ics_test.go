@@ -0,0 +1,171 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/planner"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-TIMEZONE:Europe/Berlin\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:fomc@example.com\r\n" +
+	"SUMMARY:FOMC Rate Decision\\, Press Conference\r\n" +
+	"DESCRIPTION:Line one\\nLine two with a long text that is folded onto the\r\n" +
+	"  next line\r\n" +
+	"DTSTART;TZID=America/New_York:20260128T140000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"RRULE:FREQ=WEEKLY;INTERVAL=6;COUNT=8\r\n" +
+	"EXDATE;TZID=America/New_York:20260311T140000\r\n" +
+	"CATEGORIES:US,Rates\r\n" +
+	"PRIORITY:1\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:AUDIO\r\n" +
+	"TRIGGER;RELATED=START:-P1D\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:fomc@example.com\r\n" +
+	"RECURRENCE-ID;TZID=America/New_York:20260422T140000\r\n" +
+	"SUMMARY:FOMC Rate Decision (moved)\r\n" +
+	"DTSTART;TZID=America/New_York:20260423T140000\r\n" +
+	"DTEND;TZID=America/New_York:20260423T153000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday\r\n" +
+	"SUMMARY:Market Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20260403\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:floating\r\n" +
+	"SUMMARY:Ifo Business Climate\r\n" +
+	"DTSTART:20260126T100000\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	events, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+
+	fomc := events[0]
+	if fomc.ExternalID != "fomc@example.com" || fomc.Title != "FOMC Rate Decision, Press Conference" {
+		t.Errorf("unexpected event %+v", fomc)
+	}
+	if fomc.Notes != "Line one\nLine two with a long text that is folded onto the next line" {
+		t.Errorf("unexpected notes %q", fomc.Notes)
+	}
+	if !fomc.StartDate.Equal(time.Date(2026, 1, 28, 14, 0, 0, 0, ny)) || fomc.EndDate.Sub(fomc.StartDate) != 90*time.Minute || fomc.Timezone != "America/New_York" {
+		t.Errorf("unexpected times %s - %s (%s)", fomc.StartDate, fomc.EndDate, fomc.Timezone)
+	}
+	if fomc.Priority != planner.HIGH || len(fomc.Tags) != 2 || fomc.Tags[1] != "Rates" {
+		t.Errorf("unexpected priority or tags %q %v", fomc.Priority, fomc.Tags)
+	}
+	if len(fomc.Reminders) != 2 || fomc.Reminders[0].OffsetMinutes != 15 || fomc.Reminders[1] != (planner.Reminder{OffsetMinutes: 1440, Method: planner.SOUND}) {
+		t.Errorf("unexpected reminders %+v", fomc.Reminders)
+	}
+	r := fomc.Recurrence
+	if r == nil || r.Frequency != planner.WEEKLY || r.Interval != 6 || r.Count != 8 || len(r.Exceptions) != 2 {
+		t.Fatalf("unexpected recurrence %+v", r)
+	}
+
+	moved := events[1]
+	if moved.ExternalID != "fomc@example.com/20260422T180000Z" || moved.Recurrence != nil {
+		t.Errorf("unexpected override %+v", moved)
+	}
+	instances, err := planner.ExpandEvents(events[:2], time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var days []string
+	for _, e := range instances {
+		days = append(days, e.StartDate.Format(time.DateOnly))
+	}
+	if strings.Join(days, " ") != "2026-01-28 2026-04-23" {
+		t.Errorf("unexpected instances %v", days)
+	}
+
+	holiday := events[2]
+	if !holiday.AllDay || holiday.EndDate.Sub(holiday.StartDate) != 24*time.Hour {
+		t.Errorf("unexpected all-day event %+v", holiday)
+	}
+	if floating := events[3]; floating.StartDate.Location().String() != "Europe/Berlin" || floating.StartDate.Hour() != 10 {
+		t.Errorf("expected floating times in the calendar zone, got %s", floating.StartDate)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skip("no tzdata")
+	}
+	events, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events[0].Notes = strings.Repeat("Zinsentscheid über Leitzins; ", 5)
+
+	stamp := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	data, err := Marshal(events, WithClock(clock.NewFake(stamp)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\r\nDTSTAMP:20260301T123000Z\r\n")); n != len(events) {
+		t.Errorf("expected a DTSTAMP from the clock per event, found %d", n)
+	}
+	for line := range bytes.SplitSeq(data, []byte("\r\n")) {
+		if len(line) > maxLineLength {
+			t.Errorf("line longer than %d octets: %q", maxLineLength, line)
+		}
+	}
+
+	again, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != len(events) {
+		t.Fatalf("expected %d events, got %d", len(events), len(again))
+	}
+	for i := range events {
+		if events[i].AllDay {
+			// Dates are written without a zone and read back in UTC
+			y, m, d := events[i].StartDate.Date()
+			events[i].StartDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			events[i].EndDate = events[i].StartDate.AddDate(0, 0, 1)
+		}
+		if changes := planner.Diff(map[string]planner.ImportEvent{"e": events[i]}, map[string]planner.ImportEvent{"e": again[i]}); !changes.Empty() {
+			t.Errorf("event %d changed in the round trip:\n%+v\n%+v", i, events[i], again[i])
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:FREQ=MONTHLY;BYDAY=3FR;BYMONTH=3,6,9,12;UNTIL=20271231T000000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatRRule(*r); got != "FREQ=MONTHLY;UNTIL=20271231T000000Z;BYDAY=3FR;BYMONTH=3,6,9,12" {
+		t.Errorf("unexpected rule %q", got)
+	}
+	for _, rule := range []string{"FREQ=HOURLY", "FREQ=MONTHLY;BYSETPOS=-1", "FREQ=DAILY;COUNT=x"} {
+		if _, err := ParseRRule(rule); err == nil {
+			t.Errorf("expected %q to fail", rule)
+		}
+	}
+}
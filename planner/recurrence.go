@@ -0,0 +1,346 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the period a Recurrence repeats in, named like the RRULE FREQ values
+type Frequency string
+
+const (
+	DAILY   Frequency = "DAILY"
+	WEEKLY  Frequency = "WEEKLY"
+	MONTHLY Frequency = "MONTHLY"
+	YEARLY  Frequency = "YEARLY"
+)
+
+// Recurrence is the subset of an RFC 5545 RRULE calendar plugins need, e.g.
+// "every third Friday" or "every first business day of the quarter" for
+// option expiries and economic releases.
+//
+//	Recurrence{Frequency: MONTHLY, ByDay: []string{"3FR"}}
+//	Recurrence{Frequency: WEEKLY, Interval: 2, ByDay: []string{"TU", "TH"}, Count: 10}
+//
+// Occurrences keep the wall clock time of the first event in its Timezone,
+// so they don't shift by an hour across daylight saving changes.
+type Recurrence struct {
+	Frequency  Frequency   `json:"frequency"`
+	Interval   int         `json:"interval,omitempty"`   // Every n-th period; 0 and 1 mean every period
+	ByDay      []string    `json:"byDay,omitempty"`      // "MO".."SU", optionally with an ordinal in the month like "1MO" or "-1FR"
+	ByMonthDay []int       `json:"byMonthDay,omitempty"` // 1..31, negative values count from the end of the month
+	ByMonth    []int       `json:"byMonth,omitempty"`    // 1..12
+	Until      *time.Time  `json:"until,omitempty"`      // Inclusive end, exclusive with Count
+	Count      int         `json:"count,omitempty"`      // Number of occurrences including the first one
+	Exceptions []time.Time `json:"exceptions,omitempty"` // Start times of skipped occurrences (EXDATE)
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// byDay is a parsed ByDay entry; n is 0 for every weekday of the period
+type byDay struct {
+	n       int
+	weekday time.Weekday
+}
+
+func parseByDay(s string) (byDay, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return byDay{}, fmt.Errorf("invalid weekday %q", s)
+	}
+	weekday, ok := weekdayCodes[s[len(s)-2:]]
+	if !ok {
+		return byDay{}, fmt.Errorf("invalid weekday %q", s)
+	}
+	d := byDay{weekday: weekday}
+	if ordinal := s[:len(s)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return byDay{}, fmt.Errorf("invalid weekday ordinal %q", s)
+		}
+		d.n = n
+	}
+	return d, nil
+}
+
+func (r Recurrence) Validate() error {
+	switch r.Frequency {
+	case DAILY, WEEKLY, MONTHLY, YEARLY:
+	default:
+		return fmt.Errorf("unsupported frequency %q", r.Frequency)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("interval must be >= 0")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("count must be >= 0")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return fmt.Errorf("count and until are mutually exclusive")
+	}
+	for _, s := range r.ByDay {
+		d, err := parseByDay(s)
+		if err != nil {
+			return err
+		}
+		if d.n != 0 && (r.Frequency == DAILY || r.Frequency == WEEKLY) {
+			return fmt.Errorf("weekday ordinals like %q need a monthly or yearly frequency", s)
+		}
+	}
+	for _, day := range r.ByMonthDay {
+		if day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("invalid month day %d", day)
+		}
+	}
+	for _, month := range r.ByMonth {
+		if month < 1 || month > 12 {
+			return fmt.Errorf("invalid month %d", month)
+		}
+	}
+	return nil
+}
+
+// Occurrences returns the start times of the occurrences in [from, to) for a
+// series that starts at start. The first occurrence is always start itself.
+// The times are in loc, nil uses the location of start.
+func (r Recurrence) Occurrences(start, from, to time.Time, loc *time.Location) ([]time.Time, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = start.Location()
+	}
+	start = start.In(loc)
+	days := make([]byDay, 0, len(r.ByDay))
+	for _, s := range r.ByDay {
+		d, _ := parseByDay(s)
+		days = append(days, d)
+	}
+	interval := max(r.Interval, 1)
+
+	var result []time.Time
+	count := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if !t.Before(to) {
+			return false
+		}
+		count++
+		if r.Count > 0 && count > r.Count {
+			return false
+		}
+		if !t.Before(from) && !slices.ContainsFunc(r.Exceptions, t.Equal) {
+			result = append(result, t)
+		}
+		return true
+	}
+
+	if !emit(start) {
+		return result, nil
+	}
+	for period := 0; ; period += interval {
+		periodStart, dates := r.periodDates(start, period, days)
+		if !periodStart.Before(to) || (r.Until != nil && periodStart.After(*r.Until)) {
+			return result, nil
+		}
+		for _, date := range dates {
+			t := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), loc)
+			if t.Equal(start) {
+				continue
+			}
+			if !emit(t) {
+				return result, nil
+			}
+		}
+	}
+}
+
+// periodDates returns the first day of the n-th period after the one
+// containing start and the sorted candidate days of that period
+func (r Recurrence) periodDates(start time.Time, n int, days []byDay) (time.Time, []time.Time) {
+	y, m, d := start.Date()
+	var periodStart time.Time
+	var dates []time.Time
+
+	switch r.Frequency {
+	case DAILY:
+		periodStart = time.Date(y, m, d+n, 0, 0, 0, 0, time.UTC)
+		dates = []time.Time{periodStart}
+	case WEEKLY:
+		monday := d - (int(start.Weekday())+6)%7
+		periodStart = time.Date(y, m, monday+7*n, 0, 0, 0, 0, time.UTC)
+		weekdays := []time.Weekday{start.Weekday()}
+		if len(days) > 0 {
+			weekdays = weekdays[:0]
+			for _, day := range days {
+				weekdays = append(weekdays, day.weekday)
+			}
+		}
+		for i := range 7 {
+			date := periodStart.AddDate(0, 0, i)
+			if slices.Contains(weekdays, date.Weekday()) {
+				dates = append(dates, date)
+			}
+		}
+		return periodStart, r.filter(dates, nil)
+	case MONTHLY:
+		periodStart = time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+		dates = r.monthDates(periodStart, d, days)
+		return periodStart, r.filter(dates, nil)
+	case YEARLY:
+		periodStart = time.Date(y+n, 1, 1, 0, 0, 0, 0, time.UTC)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []int{int(m)}
+		}
+		for _, month := range slices.Sorted(slices.Values(months)) {
+			dates = append(dates, r.monthDates(time.Date(y+n, time.Month(month), 1, 0, 0, 0, 0, time.UTC), d, days)...)
+		}
+		return periodStart, dates
+	}
+	return periodStart, r.filter(dates, days)
+}
+
+// monthDates returns the days of a month matching ByMonthDay and ByDay.
+// Without either the day of month of the first occurrence is used and months
+// without that day are skipped, like RFC 5545 does.
+func (r Recurrence) monthDates(month time.Time, startDay int, days []byDay) []time.Time {
+	last := month.AddDate(0, 1, -1).Day()
+	var dates []time.Time
+	add := func(day int) {
+		if day >= 1 && day <= last {
+			dates = append(dates, month.AddDate(0, 0, day-1))
+		}
+	}
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = last + day + 1
+			}
+			add(day)
+		}
+		dates = r.filterWeekdays(dates, days)
+	case len(days) > 0:
+		for _, bd := range days {
+			first := (int(bd.weekday)-int(month.Weekday())+7)%7 + 1
+			var matches []int
+			for day := first; day <= last; day += 7 {
+				matches = append(matches, day)
+			}
+			switch {
+			case bd.n == 0:
+				for _, day := range matches {
+					add(day)
+				}
+			case bd.n > 0 && bd.n <= len(matches):
+				add(matches[bd.n-1])
+			case bd.n < 0 && -bd.n <= len(matches):
+				add(matches[len(matches)+bd.n])
+			}
+		}
+	default:
+		add(startDay)
+	}
+
+	slices.SortFunc(dates, time.Time.Compare)
+	return slices.CompactFunc(dates, time.Time.Equal)
+}
+
+// filter drops dates outside ByMonth and, when days is given, dates that are
+// not on one of the weekdays or month days
+func (r Recurrence) filter(dates []time.Time, days []byDay) []time.Time {
+	return slices.DeleteFunc(dates, func(date time.Time) bool {
+		if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(date.Month())) {
+			return true
+		}
+		if days == nil {
+			return false
+		}
+		if len(r.ByMonthDay) > 0 && !matchesMonthDay(date, r.ByMonthDay) {
+			return true
+		}
+		return len(r.filterWeekdays([]time.Time{date}, days)) == 0
+	})
+}
+
+func (r Recurrence) filterWeekdays(dates []time.Time, days []byDay) []time.Time {
+	if len(days) == 0 {
+		return dates
+	}
+	return slices.DeleteFunc(dates, func(date time.Time) bool {
+		return !slices.ContainsFunc(days, func(d byDay) bool { return d.weekday == date.Weekday() })
+	})
+}
+
+func matchesMonthDay(date time.Time, monthDays []int) bool {
+	last := date.AddDate(0, 1, -date.Day()).Day()
+	for _, day := range monthDays {
+		if day == date.Day() || last+day+1 == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand returns the instances of a recurring event that start in [from, to).
+// Instances keep the duration of the event and have no Recurrence. Events
+// without a Recurrence are returned as is when they start in the range.
+func (e ImportEvent) Expand(from, to time.Time) ([]ImportEvent, error) {
+	if e.Recurrence == nil {
+		if e.StartDate.Before(from) || !e.StartDate.Before(to) {
+			return nil, nil
+		}
+		return []ImportEvent{e}, nil
+	}
+
+	loc := e.StartDate.Location()
+	if e.Timezone != "" {
+		l, err := time.LoadLocation(e.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", e.Timezone, err)
+		}
+		loc = l
+	}
+	starts, err := e.Recurrence.Occurrences(e.StartDate, from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := e.EndDate.Sub(e.StartDate)
+	events := make([]ImportEvent, len(starts))
+	for i, start := range starts {
+		instance := e
+		instance.Recurrence = nil
+		instance.StartDate = start
+		instance.EndDate = start.Add(duration)
+		events[i] = instance
+	}
+	return events, nil
+}
+
+// ExpandEvents expands all recurring events in [from, to), for hosts that
+// can't store recurrence rules
+func ExpandEvents(events []ImportEvent, from, to time.Time) ([]ImportEvent, error) {
+	var result []ImportEvent
+	for _, e := range events {
+		instances, err := e.Expand(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", e.Title, err)
+		}
+		result = append(result, instances...)
+	}
+	return result, nil
+}
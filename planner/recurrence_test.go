@@ -0,0 +1,116 @@
+package planner
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func dates(times []time.Time) []string {
+	out := make([]string, len(times))
+	for i, t := range times {
+		out[i] = t.Format("2006-01-02 15:04 MST")
+	}
+	return out
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	until := time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC)
+	start := time.Date(2026, 1, 16, 9, 30, 0, 0, ny)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		rule  Recurrence
+		start time.Time
+		want  []string
+	}{
+		{
+			name:  "third friday keeps wall time across DST",
+			rule:  Recurrence{Frequency: MONTHLY, ByDay: []string{"3FR"}, Count: 4},
+			start: start,
+			want:  []string{"2026-01-16 09:30 EST", "2026-02-20 09:30 EST", "2026-03-20 09:30 EDT", "2026-04-17 09:30 EDT"},
+		},
+		{
+			name:  "biweekly on two days until",
+			rule:  Recurrence{Frequency: WEEKLY, Interval: 2, ByDay: []string{"TU", "TH"}, Until: &until},
+			start: time.Date(2026, 3, 3, 14, 0, 0, 0, time.UTC),
+			want:  []string{"2026-03-03 14:00 UTC", "2026-03-05 14:00 UTC", "2026-03-17 14:00 UTC", "2026-03-19 14:00 UTC", "2026-03-31 14:00 UTC"},
+		},
+		{
+			name:  "last day of quarter",
+			rule:  Recurrence{Frequency: MONTHLY, Interval: 3, ByMonthDay: []int{-1}},
+			start: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2026-03-31 00:00 UTC", "2026-06-30 00:00 UTC", "2026-09-30 00:00 UTC", "2026-12-31 00:00 UTC"},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  Recurrence{Frequency: MONTHLY, Count: 3},
+			start: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2026-01-31 00:00 UTC", "2026-03-31 00:00 UTC", "2026-05-31 00:00 UTC"},
+		},
+		{
+			name:  "weekdays with exception",
+			rule:  Recurrence{Frequency: DAILY, ByDay: []string{"MO", "TU", "WE", "TH", "FR"}, Count: 4, Exceptions: []time.Time{time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)}},
+			start: time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC),
+			want:  []string{"2026-01-01 08:00 UTC", "2026-01-02 08:00 UTC", "2026-01-06 08:00 UTC"},
+		},
+		{
+			name:  "yearly in march and september",
+			rule:  Recurrence{Frequency: YEARLY, ByMonth: []int{3, 9}, ByDay: []string{"-1WE"}},
+			start: time.Date(2026, 3, 25, 18, 0, 0, 0, time.UTC),
+			want:  []string{"2026-03-25 18:00 UTC", "2026-09-30 18:00 UTC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Occurrences(tt.start, from, to, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g := dates(got); !slices.Equal(g, tt.want) {
+				t.Errorf("got %v, want %v", g, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	until := time.Now()
+	invalid := []Recurrence{
+		{Frequency: "HOURLY"},
+		{Frequency: WEEKLY, ByDay: []string{"2MO"}},
+		{Frequency: MONTHLY, ByDay: []string{"XX"}},
+		{Frequency: MONTHLY, ByMonthDay: []int{0}},
+		{Frequency: YEARLY, ByMonth: []int{13}},
+		{Frequency: DAILY, Count: 2, Until: &until},
+	}
+	for _, r := range invalid {
+		if r.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", r)
+		}
+	}
+}
+
+func TestExpandEvents(t *testing.T) {
+	start := time.Date(2026, 1, 28, 19, 0, 0, 0, time.UTC)
+	events := []ImportEvent{
+		{Title: "FOMC", StartDate: start, EndDate: start.Add(30 * time.Minute), Recurrence: &Recurrence{Frequency: WEEKLY, Interval: 6}},
+		{Title: "CPI", StartDate: time.Date(2026, 2, 11, 13, 30, 0, 0, time.UTC)},
+	}
+	got, err := ExpandEvents(events, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].StartDate.Format(time.DateOnly) != "2026-03-11" || got[1].StartDate.Format(time.DateOnly) != "2026-04-22" || got[2].Title != "CPI" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	if got[0].Recurrence != nil || got[0].EndDate.Sub(got[0].StartDate) != 30*time.Minute {
+		t.Errorf("expected a plain instance with the original duration, got %+v", got[0])
+	}
+}
@@ -0,0 +1,109 @@
+package planner
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SyncRequest contains parameters for the sync command. Without SyncToken
+// the plugin returns all events in [From, To) as Created, with the token of
+// the previous result only what changed since.
+//
+// Plugins that can write to their source receive the events the user changed
+// in the host as Changes (two-way sync); others ignore them.
+type SyncRequest struct {
+	SyncToken string      `json:"syncToken,omitempty"`
+	From      time.Time   `json:"from" validate:"required"`
+	To        time.Time   `json:"to" validate:"required"`
+	Changes   SyncChanges `json:"changes"`
+}
+
+// SyncChanges is a set of event changes keyed by ExternalID
+type SyncChanges struct {
+	Created []ImportEvent `json:"created,omitempty"`
+	Updated []ImportEvent `json:"updated,omitempty"`
+	Deleted []string      `json:"deleted,omitempty"` // External IDs
+}
+
+// SyncResult is the response of the sync command. When the sync token
+// expired, Reset is set and Created holds the complete set of events; the
+// host drops the events it imported before.
+type SyncResult struct {
+	SyncChanges
+	NextSyncToken string `json:"nextSyncToken"` // Opaque, passed back in the next SyncRequest
+	Reset         bool   `json:"reset,omitempty"`
+}
+
+// Empty reports whether there are no changes
+func (c SyncChanges) Empty() bool {
+	return len(c.Created) == 0 && len(c.Updated) == 0 && len(c.Deleted) == 0
+}
+
+// Validate checks that every event has a unique ExternalID, without one the
+// host can't match events across syncs
+func (c SyncChanges) Validate() error {
+	seen := map[string]bool{}
+	for _, list := range [][]ImportEvent{c.Created, c.Updated} {
+		for _, e := range list {
+			if e.ExternalID == "" {
+				return fmt.Errorf("event %q has no external id", e.Title)
+			}
+			if seen[e.ExternalID] {
+				return fmt.Errorf("event %q is changed twice", e.ExternalID)
+			}
+			seen[e.ExternalID] = true
+		}
+	}
+	for _, id := range c.Deleted {
+		if seen[id] {
+			return fmt.Errorf("event %q is changed twice", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Apply applies the changes to events keyed by ExternalID
+func (r SyncResult) Apply(events map[string]ImportEvent) {
+	if r.Reset {
+		clear(events)
+	}
+	for _, e := range r.Created {
+		events[e.ExternalID] = e
+	}
+	for _, e := range r.Updated {
+		events[e.ExternalID] = e
+	}
+	for _, id := range r.Deleted {
+		delete(events, id)
+	}
+}
+
+// Diff returns the changes that turn previous into current, both keyed by
+// ExternalID. Plugins whose source has no change feed keep the last snapshot
+// and derive the sync result from it.
+func Diff(previous, current map[string]ImportEvent) SyncChanges {
+	var changes SyncChanges
+	for id, e := range current {
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			changes.Created = append(changes.Created, e)
+		case old.Hash() != e.Hash():
+			changes.Updated = append(changes.Updated, e)
+		}
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			changes.Deleted = append(changes.Deleted, id)
+		}
+	}
+
+	byID := func(a, b ImportEvent) int { return cmp.Compare(a.ExternalID, b.ExternalID) }
+	slices.SortFunc(changes.Created, byID)
+	slices.SortFunc(changes.Updated, byID)
+	slices.Sort(changes.Deleted)
+	return changes
+}
@@ -0,0 +1,56 @@
+package planner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyncDiffApply(t *testing.T) {
+	at := time.Date(2026, 3, 18, 18, 0, 0, 0, time.UTC)
+	previous := map[string]ImportEvent{
+		"fomc": {ExternalID: "fomc", Title: "FOMC", StartDate: at},
+		"cpi":  {ExternalID: "cpi", Title: "CPI", StartDate: at},
+		"nfp":  {ExternalID: "nfp", Title: "NFP", StartDate: at},
+	}
+	current := map[string]ImportEvent{
+		"fomc": {ExternalID: "fomc", Title: "FOMC", StartDate: at.In(time.FixedZone("EST", -5*3600))},
+		"cpi":  {ExternalID: "cpi", Title: "CPI", StartDate: at.Add(time.Hour)},
+		"ppi":  {ExternalID: "ppi", Title: "PPI", StartDate: at},
+	}
+
+	changes := Diff(previous, current)
+	if err := changes.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Created) != 1 || changes.Created[0].ExternalID != "ppi" ||
+		len(changes.Updated) != 1 || changes.Updated[0].ExternalID != "cpi" ||
+		len(changes.Deleted) != 1 || changes.Deleted[0] != "nfp" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	SyncResult{SyncChanges: changes}.Apply(previous)
+	if len(previous) != 3 || !previous["cpi"].StartDate.Equal(at.Add(time.Hour)) {
+		t.Errorf("unexpected events after apply %+v", previous)
+	}
+	if !Diff(previous, current).Empty() {
+		t.Error("expected no changes after apply")
+	}
+
+	SyncResult{SyncChanges: SyncChanges{Created: []ImportEvent{current["fomc"]}}, Reset: true}.Apply(previous)
+	if len(previous) != 1 {
+		t.Errorf("expected a reset to drop old events, got %+v", previous)
+	}
+}
+
+func TestSyncChangesValidate(t *testing.T) {
+	invalid := []SyncChanges{
+		{Created: []ImportEvent{{Title: "no id"}}},
+		{Created: []ImportEvent{{ExternalID: "a"}}, Updated: []ImportEvent{{ExternalID: "a"}}},
+		{Updated: []ImportEvent{{ExternalID: "a"}}, Deleted: []string{"a"}},
+	}
+	for _, c := range invalid {
+		if c.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", c)
+		}
+	}
+}
@@ -1,13 +1,141 @@
 package planner
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// Priority is the importance of an event, e.g. the expected market impact
+// of an economic release
+type Priority string
+
+const (
+	LOW    Priority = "low"
+	MEDIUM Priority = "medium"
+	HIGH   Priority = "high"
+)
+
+// Category groups events in the calendar and decides how charts mark them
+type Category string
+
+const (
+	EARNINGS     Category = "earnings"
+	ECONOMIC     Category = "economic"     // Macro releases like CPI or NFP
+	CENTRAL_BANK Category = "central_bank" // Rate decisions, minutes, speeches
+	DIVIDEND     Category = "dividend"
+	SPLIT        Category = "split"
+	IPO          Category = "ipo"
+	HOLIDAY      Category = "holiday" // Market closures
+	UNLOCK       Category = "unlock"  // Token unlocks and vesting
+	OTHER        Category = "other"
+)
+
+// ReminderMethod is how the terminal reminds the user of an event
+type ReminderMethod string
+
+const (
+	NOTIFICATION ReminderMethod = "notification" // Desktop notification
+	SOUND        ReminderMethod = "sound"        // Notification with an alert sound
+	POPUP        ReminderMethod = "popup"        // Modal dialog inside the terminal
+)
 
 // ImportEvent represents an event to be imported
 type ImportEvent struct {
-	Title     string    `json:"title"`
-	StartDate time.Time `json:"startDate"`
-	EndDate   time.Time `json:"endDate"`
-	Notes     string    `json:"notes"`
-	Timezone  string    `json:"timezone"`
-	Tags      []string  `json:"tags"`
+	ExternalID  string      `json:"externalId,omitempty"`  // Stable ID in the source, lets repeated imports update instead of duplicate
+	ContentHash string      `json:"contentHash,omitempty"` // Hash() of the event, set by NewImportData
+	Title       string      `json:"title"`
+	StartDate   time.Time   `json:"startDate"`
+	EndDate     time.Time   `json:"endDate"`
+	AllDay      bool        `json:"allDay,omitempty"` // Only the dates count; EndDate is the exclusive end day
+	Notes       string      `json:"notes"`
+	Timezone    string      `json:"timezone"`
+	Tags        []string    `json:"tags"`
+	Priority    Priority    `json:"priority,omitempty"`
+	Category    Category    `json:"category,omitempty"`
+	Color       string      `json:"color,omitempty"` // e.g. "#2962ff", overrides the category color
+	Reminders   []Reminder  `json:"reminders,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"` // Repeats the event; StartDate and EndDate are the first occurrence
+
+	// Markets the event affects; charts of these markets show the event
+	RelatedMarkets []trading.Market `json:"relatedMarkets,omitempty"`
+}
+
+// Reminder triggers a notification some time before an event starts
+type Reminder struct {
+	OffsetMinutes int            `json:"offsetMinutes"`    // Before the start, 0 reminds at the start
+	Method        ReminderMethod `json:"method,omitempty"` // Defaults to NOTIFICATION
+}
+
+// At returns when the reminder fires for an event starting at start
+func (r Reminder) At(start time.Time) time.Time {
+	return start.Add(-time.Duration(r.OffsetMinutes) * time.Minute)
+}
+
+func (r Reminder) Validate() error {
+	if r.OffsetMinutes < 0 {
+		return fmt.Errorf("reminder offset must be >= 0")
+	}
+	switch r.Method {
+	case "", NOTIFICATION, SOUND, POPUP:
+	default:
+		return fmt.Errorf("unsupported reminder method %q", r.Method)
+	}
+	return nil
+}
+
+func (e ImportEvent) Validate() error {
+	if e.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if e.StartDate.IsZero() {
+		return fmt.Errorf("startDate is required")
+	}
+	if !e.EndDate.IsZero() && e.EndDate.Before(e.StartDate) {
+		return fmt.Errorf("endDate must not be before startDate")
+	}
+	switch e.Priority {
+	case "", LOW, MEDIUM, HIGH:
+	default:
+		return fmt.Errorf("unsupported priority %q", e.Priority)
+	}
+	if e.Color != "" && !colorPattern.MatchString(e.Color) {
+		return fmt.Errorf("color must be a hex color like #2962ff")
+	}
+	for _, r := range e.Reminders {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	if e.Recurrence != nil {
+		return e.Recurrence.Validate()
+	}
+	return nil
+}
+
+// RelatesTo reports whether the event is linked to the market with symbol
+func (e ImportEvent) RelatesTo(symbol string) bool {
+	for _, m := range e.RelatedMarkets {
+		if strings.EqualFold(m.Symbol, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventsForMarket returns the events linked to the market with symbol, e.g.
+// to mark them on its chart
+func EventsForMarket(events []ImportEvent, symbol string) []ImportEvent {
+	var result []ImportEvent
+	for _, e := range events {
+		if e.RelatesTo(symbol) {
+			result = append(result, e)
+		}
+	}
+	return result
 }
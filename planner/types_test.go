@@ -0,0 +1,55 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestImportEventValidate(t *testing.T) {
+	start := time.Date(2026, 5, 6, 18, 0, 0, 0, time.UTC)
+	e := ImportEvent{
+		Title:     "FOMC rate decision",
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		Priority:  HIGH,
+		Category:  CENTRAL_BANK,
+		Color:     "#e91e63",
+		Reminders: []Reminder{{OffsetMinutes: 15}, {OffsetMinutes: 0, Method: SOUND}},
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if at := e.Reminders[0].At(e.StartDate); !at.Equal(start.Add(-15 * time.Minute)) {
+		t.Errorf("unexpected reminder time %s", at)
+	}
+
+	invalid := []func(*ImportEvent){
+		func(e *ImportEvent) { e.Title = "" },
+		func(e *ImportEvent) { e.EndDate = start.Add(-time.Hour) },
+		func(e *ImportEvent) { e.Priority = "urgent" },
+		func(e *ImportEvent) { e.Reminders = []Reminder{{OffsetMinutes: -5}} },
+		func(e *ImportEvent) { e.Reminders = []Reminder{{Method: "email"}} },
+		func(e *ImportEvent) { e.Color = "blue" },
+	}
+	for i, mutate := range invalid {
+		event := e
+		mutate(&event)
+		if event.Validate() == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestEventsForMarket(t *testing.T) {
+	events := []ImportEvent{
+		{Title: "AAPL earnings", Category: EARNINGS, RelatedMarkets: []trading.Market{{Symbol: "AAPL"}}},
+		{Title: "CPI", Category: ECONOMIC, RelatedMarkets: []trading.Market{{Symbol: "SPY"}, {Symbol: "aapl"}}},
+		{Title: "Holiday", Category: HOLIDAY},
+	}
+	got := EventsForMarket(events, "AAPL")
+	if len(got) != 2 || got[0].Title != "AAPL earnings" || got[1].Title != "CPI" {
+		t.Errorf("unexpected events %+v", got)
+	}
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/msgpack"
+)
+
+// ArrayWriter encodes the elements of a large response array one by one,
+// e.g. 100k markets or candles, instead of building the whole []T and
+// encoding it in one go. Only the encoded bytes are held, and WriteResponse
+// copies them into the output without encoding them again, which keeps the
+// peak WASM memory close to the size of the output.
+//
+// Example:
+//
+//	w := plugin.NewArrayWriter(0)
+//	for page := range pages {
+//	    for _, m := range page.Markets {
+//	        if err := w.Append(toMarket(m)); err != nil {
+//	            return plugin.ErrorResponse(err)
+//	        }
+//	    }
+//	}
+//	return w.Response()
+type ArrayWriter struct {
+	format string
+	buf    bytes.Buffer
+	json   *json.Encoder
+	mp     *msgpack.Encoder
+	n      int
+}
+
+// NewArrayWriter creates a writer for the negotiated wire format.
+// sizeHint preallocates the buffer in bytes; 0 lets it grow.
+func NewArrayWriter(sizeHint int) *ArrayWriter {
+	w := &ArrayWriter{format: hostinfo.WireFormat()}
+	w.buf.Grow(sizeHint)
+	if w.format == hostinfo.WireMsgpack {
+		// array32 header, the length is filled in by Response
+		w.buf.Write([]byte{0xdd, 0, 0, 0, 0})
+		w.mp = msgpack.NewEncoder(&w.buf)
+	} else {
+		w.format = hostinfo.WireJSON
+		w.buf.WriteByte('[')
+		w.json = json.NewEncoder(&w.buf)
+		w.json.SetEscapeHTML(false)
+	}
+	return w
+}
+
+// Append encodes v as next element
+func (w *ArrayWriter) Append(v any) error {
+	mark := w.buf.Len()
+	var err error
+	if w.mp != nil {
+		err = w.mp.Encode(v)
+	} else {
+		if w.n > 0 {
+			w.buf.WriteByte(',')
+		}
+		if err = w.json.Encode(v); err == nil {
+			// json.Encoder terminates every value with a newline
+			w.buf.Truncate(w.buf.Len() - 1)
+		}
+	}
+	if err != nil {
+		// drop partial output so the array stays valid
+		w.buf.Truncate(mark)
+		return fmt.Errorf("element %d: %w", w.n, err)
+	}
+	w.n++
+	return nil
+}
+
+// Len returns the number of elements appended
+func (w *ArrayWriter) Len() int {
+	return w.n
+}
+
+// Response returns a successful response carrying the array. The writer
+// must not be used afterwards.
+func (w *ArrayWriter) Response(cacheFor ...time.Duration) Response {
+	data := w.buf.Bytes()
+	if w.mp != nil {
+		binary.BigEndian.PutUint32(data[1:5], uint32(w.n))
+	} else {
+		w.buf.WriteByte(']')
+		data = w.buf.Bytes()
+	}
+	return SuccessResponse(encodedData{format: w.format, data: data}, cacheFor...)
+}
+
+// encodedData is response data already encoded in a wire format
+type encodedData struct {
+	format string
+	data   []byte
+}
+
+// MarshalJSON lets encoded data pass through json.Marshal, e.g. when a
+// handler is called directly in tests
+func (d encodedData) MarshalJSON() ([]byte, error) {
+	if d.format == hostinfo.WireJSON {
+		return d.data, nil
+	}
+	v, err := msgpack.Decode(d.data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// writeResponse writes resp in the negotiated wire format. Data from an
+// ArrayWriter is spliced into the encoded envelope instead of re-encoded.
+func writeResponse(resp Response) {
+	data, ok := resp.Data.(encodedData)
+	if !ok || data.format != hostinfo.WireFormat() {
+		writeOutput(resp)
+		return
+	}
+
+	resp.Data = nil
+	var out []byte
+	if data.format == hostinfo.WireMsgpack {
+		envelope, err := msgpack.Marshal(resp)
+		if err != nil || envelope[0]&0xf0 != 0x80 || envelope[0] == 0x8f {
+			writeOutput(ErrorResponseMsg("failed to encode response"))
+			return
+		}
+		out = make([]byte, 0, len(envelope)+len(data.data)+5)
+		out = append(out, envelope[0]+1) // One more entry in the fixmap
+		out = append(out, envelope[1:]...)
+		out = append(out, 0xa4, 'd', 'a', 't', 'a')
+		out = append(out, data.data...)
+	} else {
+		envelope, err := json.Marshal(resp)
+		if err != nil {
+			writeOutput(ErrorResponse(err))
+			return
+		}
+		out = make([]byte, 0, len(envelope)+len(data.data)+8)
+		out = append(out, envelope[:len(envelope)-1]...)
+		out = append(out, `,"data":`...)
+		out = append(out, data.data...)
+		out = append(out, '}')
+	}
+	host.Output(out)
+}
@@ -0,0 +1,82 @@
+//go:build !wasm
+
+package plugin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+)
+
+type market struct {
+	Symbol string `json:"symbol"`
+	Tick   string `json:"tick,omitempty"`
+}
+
+type marketsPlugin struct{}
+
+func (p *marketsPlugin) GetMeta() m.Meta                         { return m.Meta{Name: "markets"} }
+func (p *marketsPlugin) GetConfigFields() []plugin.ConfigField   { return nil }
+func (p *marketsPlugin) GetRateLimits() []plugin.RateLimit       { return nil }
+func (p *marketsPlugin) OnInit(config *plugin.ConfigStore) error { return nil }
+func (p *marketsPlugin) OnShutdown() error                       { return nil }
+
+func (p *marketsPlugin) RegisterCommands(router *plugin.CommandRouter) {
+	router.Register("markets", func(params map[string]any) plugin.Response {
+		w := plugin.NewArrayWriter(0)
+		for i := range 3 {
+			if err := w.Append(market{Symbol: fmt.Sprintf("SYM%d", i), Tick: "0.01"}); err != nil {
+				return plugin.ErrorResponse(err)
+			}
+		}
+		if err := w.Append(make(chan int)); err == nil {
+			return plugin.ErrorResponseMsg("expected channel to fail")
+		}
+		return w.Response()
+	})
+}
+
+func TestArrayWriterResponse(t *testing.T) {
+	plugin.RegisterPlugin(&marketsPlugin{})
+	want := []any{
+		map[string]any{"symbol": "SYM0", "tick": "0.01"},
+		map[string]any{"symbol": "SYM1", "tick": "0.01"},
+		map[string]any{"symbol": "SYM2", "tick": "0.01"},
+	}
+
+	for _, format := range []string{hostinfo.WireJSON, hostinfo.WireMsgpack} {
+		t.Run(format, func(t *testing.T) {
+			h := plugintest.New(t)
+			caps := hostinfo.Full()
+			caps.WireFormat = format
+			h.SetCapabilities(caps)
+
+			res := h.Command("markets", nil)
+			if !res.Result {
+				t.Fatalf("markets failed: %s", res.Error)
+			}
+			if !reflect.DeepEqual(res.Data, want) {
+				t.Fatalf("expected %v, got %v", want, res.Data)
+			}
+		})
+	}
+}
+
+func TestArrayWriterMarshalJSON(t *testing.T) {
+	plugintest.New(t)
+	w := plugin.NewArrayWriter(0)
+	resp := w.Response()
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"result":true,"data":[]}` {
+		t.Fatalf("unexpected output %s", out)
+	}
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// WithRetryAfter tells the host to pause scheduling commands for this source
+// until the delay has passed. Delays are rounded up to whole seconds.
+func (r Response) WithRetryAfter(delay time.Duration) Response {
+	if delay <= 0 {
+		return r
+	}
+	seconds := int64(math.Ceil(delay.Seconds()))
+	r.RetryAfterSeconds = &seconds
+	return r
+}
+
+// RateLimitedResponse creates an error response asking the host to back off
+//
+// Example:
+//
+//	if delay, ok := plugin.BackoffFromResponse(res, now); ok {
+//	    return plugin.RateLimitedResponse(fmt.Errorf("rate limited by exchange"), delay)
+//	}
+//
+// Weight headers such as X-MBX-USED-WEIGHT-1M are read with WeightUsageFromHeaders.
+func RateLimitedResponse(err error, retryAfter time.Duration) Response {
+	return ErrorResponse(err).WithRetryAfter(retryAfter)
+}
+
+// IsThrottled reports whether the HTTP status signals that the client is
+// being throttled (429, or 418 which Binance uses for IP bans)
+func IsThrottled(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusTeapot
+}
+
+// MaxRetryAfter caps the delays read by RetryAfterFromHeaders
+const MaxRetryAfter = 24 * time.Hour
+
+// RetryAfterFromHeaders parses the Retry-After header, which is either a
+// non-negative number of seconds or an HTTP date. now is used to convert
+// dates into a delay. Delays are capped at MaxRetryAfter.
+func RetryAfterFromHeaders(headers http.Header, now time.Time) (time.Duration, bool) {
+	value := strings.TrimSpace(headers.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(MaxRetryAfter/time.Second) {
+			return MaxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return min(max(at.Sub(now), 0), MaxRetryAfter), true
+	}
+
+	return 0, false
+}
+
+// BackoffFromResponse determines how long the host should back off after the
+// given exchange response. It returns false if the response does not indicate
+// throttling. Retry-After is honoured on throttled (see IsThrottled) and 5xx
+// responses such as 503 only; throttled responses without a usable header
+// fall back to a delay of one minute.
+func BackoffFromResponse(res *rt.Response, now time.Time) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	throttled := IsThrottled(res.Status)
+	if !throttled && res.Status < 500 {
+		return 0, false
+	}
+
+	if delay, ok := RetryAfterFromHeaders(res.Headers, now); ok {
+		return delay, true
+	}
+	if throttled {
+		return time.Minute, true
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+func TestBackoffFromResponse(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	retryAfter := http.Header{"Retry-After": {"30"}}
+	tests := []struct {
+		status  int
+		headers http.Header
+		delay   time.Duration
+		ok      bool
+	}{
+		{http.StatusTooManyRequests, retryAfter, 30 * time.Second, true},
+		{http.StatusTooManyRequests, nil, time.Minute, true},
+		{http.StatusTeapot, nil, time.Minute, true},
+		{http.StatusServiceUnavailable, retryAfter, 30 * time.Second, true},
+		{http.StatusServiceUnavailable, nil, 0, false},
+		{http.StatusOK, retryAfter, 0, false},
+		{http.StatusMovedPermanently, retryAfter, 0, false},
+		{http.StatusBadRequest, retryAfter, 0, false},
+	}
+	for _, tt := range tests {
+		delay, ok := BackoffFromResponse(&rt.Response{Status: tt.status, Headers: tt.headers}, now)
+		if delay != tt.delay || ok != tt.ok {
+			t.Errorf("status %d with %v: expected (%s, %v), got (%s, %v)", tt.status, tt.headers, tt.delay, tt.ok, delay, ok)
+		}
+	}
+}
+
+func TestRetryAfterFromHeaders(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"99999999999999999999", MaxRetryAfter, true},
+		{"1e300", 0, false},
+		{"1.5", 0, false},
+		{"inf", 0, false},
+		{"NaN", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		delay, ok := RetryAfterFromHeaders(http.Header{"Retry-After": {tt.value}}, now)
+		if delay != tt.delay || ok != tt.ok {
+			t.Errorf("Retry-After %q: expected (%s, %v), got (%s, %v)", tt.value, tt.delay, tt.ok, delay, ok)
+		}
+	}
+}
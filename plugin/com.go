@@ -1,15 +1,18 @@
 package plugin
 
 import (
+	"encoding/json"
 	"time"
 
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/utils"
 )
 
 // Command represents a request to a plugin
 type Command struct {
 	Name   string         `json:"name"`   // e.g., "process", "ohlcvStream", "getMarkets", "getBalance"
 	Params map[string]any `json:"params"` // Flexible parameters specific to each command
+
+	TraceID string `json:"traceId,omitempty"` // Host-supplied ID correlating plugin logs and requests with host logs
 }
 
 // Response represents the result of a command execution
@@ -19,6 +22,11 @@ type Response struct {
 	Data            any    `json:"data,omitempty"`            // Could be direct data or a channel for streams
 	Error           string `json:"error,omitempty"`           // Error message if Success is false
 	CacheForSeconds *int64 `json:"cacheForSeconds,omitempty"` // Optional: cache duration in seconds (wrapper converts to time.Duration)
+
+	WeightUsage       []WeightUsage `json:"weightUsage,omitempty"`       // Optional: weight consumed as reported by the exchange
+	RetryAfterSeconds *int64        `json:"retryAfterSeconds,omitempty"` // Optional: host should not call this source again before the delay passed
+
+	FieldErrors map[string]string `json:"fieldErrors,omitempty"` // Optional: invalid params keyed by field path, e.g. {"timeframe": "is required"}
 }
 
 // StreamData represents a single piece of data from a stream
@@ -45,13 +53,16 @@ type StreamSetupResponse struct {
 	Error           string            `json:"error,omitempty"`
 }
 
-// StreamMessageRequest represents the request sent to plugin for message processing
+// StreamMessageRequest represents the request sent to plugin for message processing.
+// Message is only valid until the handler returns and must be copied to be
+// retained; StreamContext is shared between messages and must not be modified.
 type StreamMessageRequest struct {
 	StreamID      string         `json:"streamId"`
 	ConnectionID  string         `json:"connectionId"`
 	Message       []byte         `json:"message"`
 	MessageType   string         `json:"messageType"` // "data", "error", "close"
 	StreamContext map[string]any `json:"streamContext,omitempty"`
+	TraceID       string         `json:"traceId,omitempty"` // Host-supplied ID correlating plugin logs and requests with host logs
 }
 
 // StreamMessageResponse represents plugin's response to a stream message
@@ -62,6 +73,8 @@ type StreamMessageResponse struct {
 	Data        any    `json:"data,omitempty"`     // Generic data payload
 	SendMessage string `json:"sendMessage,omitempty"`
 	Error       string `json:"error,omitempty"`
+
+	RawData json.RawMessage `json:"-"` // Data already encoded as JSON, takes precedence over Data; see StreamRawResponse
 }
 
 // StreamConnectionEvent represents a connection lifecycle event
@@ -70,6 +83,7 @@ type StreamConnectionEvent struct {
 	ConnectionID string `json:"connectionId"`
 	EventType    string `json:"eventType"` // "connected", "disconnected", "error"
 	Error        string `json:"error,omitempty"`
+	TraceID      string `json:"traceId,omitempty"`
 }
 
 // StreamConnectionResponse represents plugin's response to a connection event
@@ -82,13 +96,13 @@ type StreamConnectionResponse struct {
 // ReadCommand reads a command from plugin input (used in handle_command export)
 func ReadCommand() (Command, error) {
 	var cmd Command
-	err := pdk.InputJSON(&cmd)
+	err := readInput(&cmd)
 	return cmd, err
 }
 
 // WriteResponse writes a response to plugin output
 func WriteResponse(resp Response) int32 {
-	pdk.OutputJSON(resp)
+	writeResponse(resp)
 	if resp.Result {
 		return 0
 	}
@@ -119,11 +133,13 @@ func SuccessTypedResponse(responseType string, data any, cacheFor ...time.Durati
 	return resp
 }
 
-// ErrorResponse creates an error response
+// ErrorResponse creates an error response. Validation errors from
+// utils.MapToStruct also fill FieldErrors.
 func ErrorResponse(err error) Response {
 	return Response{
-		Result: false,
-		Error:  err.Error(),
+		Result:      false,
+		Error:       err.Error(),
+		FieldErrors: utils.FieldErrorsOf(err),
 	}
 }
 
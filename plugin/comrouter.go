@@ -1,13 +1,18 @@
 package plugin
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
 
 // CommandHandler is a function that handles a specific command
 type CommandHandler func(params map[string]any) Response
 
 // CommandRouter helps route commands to handlers
 type CommandRouter struct {
-	handlers map[string]CommandHandler
+	handlers  map[string]CommandHandler
+	useNumber bool
 }
 
 // NewCommandRouter creates a new command router
@@ -22,6 +27,14 @@ func (r *CommandRouter) Register(commandName string, handler CommandHandler) {
 	r.handlers[commandName] = handler
 }
 
+// UseNumber makes numbers in command params arrive as json.Number instead of
+// float64, so int64 order IDs and precise quantities are not rounded. Read
+// them with utils.GetValue, utils.ExtractInt64, utils.ExtractDecimal or
+// utils.MapToStruct, which all accept json.Number.
+func (r *CommandRouter) UseNumber() {
+	r.useNumber = true
+}
+
 // GetRegisteredCommands returns a list of all registered command names
 func (r *CommandRouter) GetRegisteredCommands() []string {
 	commands := make([]string, 0, len(r.handlers))
@@ -43,9 +56,16 @@ func (r *CommandRouter) Handle(cmd Command) Response {
 
 // HandleJSON reads command from input, routes it, and writes response
 func (r *CommandRouter) HandleJSON() int32 {
-	cmd, err := ReadCommand()
+	var cmd Command
+	var err error
+	if r.useNumber {
+		err = readInputUseNumber(&cmd)
+	} else {
+		err = readInput(&cmd)
+	}
 	if err != nil {
 		return WriteResponse(ErrorResponse(err))
 	}
+	defer trace.Set(cmd.TraceID)()
 	return WriteResponse(r.Handle(cmd))
 }
@@ -0,0 +1,106 @@
+//go:build !wasm
+
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/msgpack"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+type orderParams struct {
+	OrderID  int64     `mapstructure:"orderId"`
+	Quantity string    `mapstructure:"quantity"`
+	Price    float64   `mapstructure:"price"`
+	Since    time.Time `mapstructure:"since"`
+}
+
+type numbersPlugin struct{}
+
+func (p *numbersPlugin) GetMeta() m.Meta                         { return m.Meta{Name: "numbers"} }
+func (p *numbersPlugin) GetConfigFields() []plugin.ConfigField   { return nil }
+func (p *numbersPlugin) GetRateLimits() []plugin.RateLimit       { return nil }
+func (p *numbersPlugin) OnInit(config *plugin.ConfigStore) error { return nil }
+func (p *numbersPlugin) OnShutdown() error                       { return nil }
+
+func (p *numbersPlugin) RegisterCommands(router *plugin.CommandRouter) {
+	router.UseNumber()
+	router.Register("order", func(params map[string]any) plugin.Response {
+		if _, ok := params["orderId"].(json.Number); !ok {
+			return plugin.ErrorResponseMsg("expected json.Number params")
+		}
+		var parsed orderParams
+		if err := utils.MapToStruct(params, &parsed); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+		return plugin.SuccessResponse(map[string]any{
+			"orderId":  utils.ExtractInt64("orderId", params),
+			"parsedId": parsed.OrderID,
+			"quantity": utils.ExtractDecimal("quantity", params),
+			"parsedQ":  parsed.Quantity,
+			"price":    utils.GetValue[float64]("price", params),
+			"since":    parsed.Since.UnixMilli(),
+		})
+	})
+}
+
+func TestCommandRouterUseNumber(t *testing.T) {
+	plugin.RegisterPlugin(&numbersPlugin{})
+	input := []byte(`{"name":"order","params":{"orderId":9007199254740993,"quantity":0.12345678901234567891,"price":101.5,"since":1700000000123}}`)
+
+	for _, format := range []string{hostinfo.WireJSON, hostinfo.WireMsgpack} {
+		t.Run(format, func(t *testing.T) {
+			h := plugintest.New(t)
+			caps := hostinfo.Full()
+			caps.WireFormat = format
+			h.SetCapabilities(caps)
+
+			var res plugin.Response
+			if format == hostinfo.WireJSON {
+				rc, out, err := h.Invoke("handle_command", input)
+				if err != nil || rc != 0 {
+					t.Fatalf("handle_command failed: %d %v %s", rc, err, out)
+				}
+				if err := utils.DecodeJSON(out, &res); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				// msgpack carries int64 natively, the decimal quantity has to be a string
+				cmd, _ := msgpack.Marshal(plugin.Command{Name: "order", Params: map[string]any{
+					"orderId": int64(9007199254740993), "quantity": "0.12345678901234567891", "price": 101.5, "since": int64(1700000000123),
+				}})
+				rc, out, err := h.Invoke("handle_command", cmd)
+				if err != nil || rc != 0 {
+					t.Fatalf("handle_command failed: %d %v", rc, err)
+				}
+				if err := msgpack.UnmarshalUseNumber(out, &res); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !res.Result {
+				t.Fatalf("order failed: %s", res.Error)
+			}
+
+			data := res.Data.(map[string]any)
+			for key, want := range map[string]string{
+				"orderId":  "9007199254740993",
+				"parsedId": "9007199254740993",
+				"quantity": "0.12345678901234567891",
+				"parsedQ":  "0.12345678901234567891",
+				"price":    "101.5",
+				"since":    "1700000000123",
+			} {
+				if got := utils.ExtractDecimal(key, data); got != want {
+					t.Errorf("%s: expected %s, got %s", key, want, got)
+				}
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@ package plugin
 import (
 	"encoding/json"
 
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
 )
 
 // ConfigStore helps manage plugin configuration
@@ -21,7 +21,7 @@ func NewConfigStore() *ConfigStore {
 // Load loads configuration from JSON input
 func (cs *ConfigStore) Load() error {
 	var config map[string]any
-	err := pdk.InputJSON(&config)
+	err := host.InputJSON(&config)
 	if err != nil {
 		return err
 	}
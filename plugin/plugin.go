@@ -1,7 +1,9 @@
 package plugin
 
 import (
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/logging"
 	m "github.com/plusev-terminal/go-plugin-common/meta"
 )
 
@@ -67,6 +69,17 @@ func RegisterPlugin(plugin Plugin) {
 // WASM Exports - Auto-generated by RegisterPlugin
 // ============================================================================
 
+// Native builds have no WASM exports; register them so plugintest can invoke them
+func init() {
+	host.RegisterExport("meta", meta)
+	host.RegisterExport("get_configuration_fields", get_configuration_fields)
+	host.RegisterExport("get_rate_limits", get_rate_limits)
+	host.RegisterExport("get_weight_pools", get_weight_pools)
+	host.RegisterExport("init", initialize)
+	host.RegisterExport("handle_command", handle_command)
+	host.RegisterExport("shutdown", shutdown)
+}
+
 //go:wasmexport meta
 func meta() int32 {
 	pluginMeta := registeredPlugin.GetMeta()
@@ -89,7 +102,14 @@ func meta() int32 {
 		}
 	}
 
-	pdk.OutputJSON(pluginMeta)
+	if pluginMeta.SDKVersion == "" {
+		pluginMeta.SDKVersion = m.SDKVersion()
+	}
+	if pluginMeta.ProtocolVersion == 0 {
+		pluginMeta.ProtocolVersion = ProtocolVersion
+	}
+
+	host.OutputJSON(pluginMeta)
 	return 0
 }
 
@@ -100,13 +120,26 @@ func get_configuration_fields() int32 {
 
 //go:wasmexport get_rate_limits
 func get_rate_limits() int32 {
-	limits := registeredPlugin.GetRateLimits()
-	pdk.OutputJSON(limits)
+	host.OutputJSON(collectRateLimits(registeredPlugin))
+	return 0
+}
+
+//go:wasmexport get_weight_pools
+func get_weight_pools() int32 {
+	host.OutputJSON(collectWeightPools(collectRateLimits(registeredPlugin)))
 	return 0
 }
 
 //go:wasmexport init
 func initialize() int32 {
+	// Refuse hosts older than the plugin declared, instead of failing later
+	if caps, ok := hostinfo.Get(); ok {
+		if err := registeredPlugin.GetMeta().CheckHost(caps.HostVersion); err != nil {
+			host.SetError(err)
+			return 1
+		}
+	}
+
 	// Load configuration from backend
 	err := pluginConfig.Load()
 	if err != nil {
@@ -124,12 +157,18 @@ func initialize() int32 {
 
 //go:wasmexport handle_command
 func handle_command() int32 {
+	// Deliver buffered log records before the plugin goes idle
+	defer logging.Flush()
 	return pluginRouter.HandleJSON()
 }
 
 //go:wasmexport shutdown
 func shutdown() int32 {
 	err := registeredPlugin.OnShutdown()
+
+	// Deliver records still held by a buffered log sink
+	_ = logging.Flush()
+
 	if err != nil {
 		return 1
 	}
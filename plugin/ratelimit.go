@@ -1,6 +1,11 @@
 package plugin
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+)
 
 // RateLimitScope defines the scope at which rate limiting is enforced
 type RateLimitScope string
@@ -16,10 +21,16 @@ const (
 // RateLimit defines rate limit configuration for a command
 type RateLimit struct {
 	Command string           `json:"command"` // Command name or "*" for wildcard
-	Scope   []RateLimitScope `json:"scope"`   // Scope keys (e.g., []RateLimitScope{RateLimitScopeIP}, []RateLimitScope{RateLimitScopeAPIKey, "user_id"})
+	Scope   []RateLimitScope `json:"scope"`   // Scope keys (default: RateLimitScopeIP; e.g., []RateLimitScope{RateLimitScopeAPIKey, "user_id"})
 	RPS     float64          `json:"rps"`     // Requests per second (can be fractional, e.g., 0.1 = 1 req per 10 sec)
-	Burst   int              `json:"burst"`   // Burst allowance
+	Burst   int              `json:"burst"`   // Burst allowance (default: 1, no bursting)
 	Cost    int              `json:"cost"`    // Token cost per request (default: 1, for commands that make multiple API calls)
+
+	// Weight-based limiting (optional). When Pool is set, each request also
+	// consumes Weight units (default: 1) from the named WeightPool shared
+	// with other commands.
+	Pool   string `json:"pool,omitempty"`
+	Weight int    `json:"weight,omitempty"`
 }
 
 // CalculateRPS converts a request count and time duration to requests per second.
@@ -44,3 +55,73 @@ func CalculateRPS(requests int, duration time.Duration) float64 {
 
 	return float64(requests) / seconds
 }
+
+// Validate checks that the rate limit can be enforced by the host
+func (r RateLimit) Validate() error {
+	if r.Command == "" {
+		return fmt.Errorf("rate limit command is required (use \"*\" for all commands)")
+	}
+	if r.RPS <= 0 {
+		return fmt.Errorf("rate limit for %q: rps must be > 0", r.Command)
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("rate limit for %q: burst must be >= 0", r.Command)
+	}
+	if r.Cost < 0 {
+		return fmt.Errorf("rate limit for %q: cost must be >= 0", r.Command)
+	}
+	if r.Weight < 0 {
+		return fmt.Errorf("rate limit for %q: weight must be >= 0", r.Command)
+	}
+	return nil
+}
+
+// withDefaults fills in the documented defaults for unset fields
+func (r RateLimit) withDefaults() RateLimit {
+	if len(r.Scope) == 0 {
+		r.Scope = []RateLimitScope{RateLimitScopeIP}
+	}
+	if r.Burst == 0 {
+		r.Burst = 1
+	}
+	if r.Cost == 0 {
+		r.Cost = 1
+	}
+	if r.Pool != "" && r.Weight == 0 {
+		r.Weight = 1
+	}
+	return r
+}
+
+// Rate limits registered in addition to Plugin.GetRateLimits
+var registeredRateLimits []RateLimit
+
+// RegisterRateLimits registers rate limits in addition to the ones returned by
+// Plugin.GetRateLimits. This is useful for shared client packages that know the
+// limits of the endpoints they wrap.
+// Call it in init() together with RegisterPlugin.
+func RegisterRateLimits(limits ...RateLimit) {
+	registeredRateLimits = append(registeredRateLimits, limits...)
+}
+
+// collectRateLimits merges, validates and normalizes all rate limits declared
+// by the plugin. This is what the get_rate_limits export hands to the host.
+// Invalid limits are logged and skipped, so one bad entry doesn't drop the
+// others.
+func collectRateLimits(p Plugin) []RateLimit {
+	var limits []RateLimit
+	if p != nil {
+		limits = append(limits, p.GetRateLimits()...)
+	}
+	limits = append(limits, registeredRateLimits...)
+
+	result := make([]RateLimit, 0, len(limits))
+	for _, limit := range limits {
+		if err := limit.Validate(); err != nil {
+			logging.NewLogger("").WarnWithData("skipping invalid rate limit", map[string]any{"error": err.Error()})
+			continue
+		}
+		result = append(result, limit.withDefaults())
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+//go:build !wasm
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	loggingtesting "github.com/plusev-terminal/go-plugin-common/logging/testing"
+)
+
+func TestCollectRateLimitsSkipsInvalid(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	t.Cleanup(logging.SetDefaultSink(sink))
+	prev := registeredRateLimits
+	t.Cleanup(func() { registeredRateLimits = prev })
+
+	registeredRateLimits = []RateLimit{
+		{Command: "get_ohlcv", RPS: 10},
+		{Command: "get_markets", RPS: 0},
+	}
+	limits := collectRateLimits(nil)
+
+	if len(limits) != 1 || limits[0].Command != "get_ohlcv" {
+		t.Fatalf("expected only the valid limit, got %+v", limits)
+	}
+	if l := limits[0]; l.Burst != 1 || l.Cost != 1 || len(l.Scope) != 1 || l.Scope[0] != RateLimitScopeIP {
+		t.Errorf("expected documented defaults, got %+v", l)
+	}
+	sink.AssertLogged(t, "warn", "invalid rate limit")
+}
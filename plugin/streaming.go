@@ -1,7 +1,9 @@
 package plugin
 
 import (
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	"github.com/plusev-terminal/go-plugin-common/trace"
 )
 
 // StreamHandler is the interface that plugin developers implement to handle WebSocket streaming
@@ -44,11 +46,18 @@ func RegisterStreamHandler(handler StreamHandler) {
 // WASM Exports for Stream Handling - Auto-generated
 // ============================================================================
 
+func init() {
+	host.RegisterExport("handle_stream_message", handle_stream_message)
+	host.RegisterExport("handle_connection_event", handle_connection_event)
+}
+
 //go:wasmexport handle_stream_message
 func handle_stream_message() int32 {
+	defer logging.Flush()
+
 	// Check if stream handler is registered
 	if registeredStreamHandler == nil {
-		pdk.OutputJSON(StreamMessageResponse{
+		writeOutput(StreamMessageResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   "stream handler not registered",
@@ -58,8 +67,8 @@ func handle_stream_message() int32 {
 
 	// Read the incoming request
 	var req StreamMessageRequest
-	if err := pdk.InputJSON(&req); err != nil {
-		pdk.OutputJSON(StreamMessageResponse{
+	if err := readStreamMessage(&req); err != nil {
+		writeOutput(StreamMessageResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   "failed to parse stream message request",
@@ -68,9 +77,10 @@ func handle_stream_message() int32 {
 	}
 
 	// Call the registered handler
+	defer trace.Set(req.TraceID)()
 	resp, err := registeredStreamHandler.HandleStreamMessage(req)
 	if err != nil {
-		pdk.OutputJSON(StreamMessageResponse{
+		writeOutput(StreamMessageResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   err.Error(),
@@ -79,15 +89,17 @@ func handle_stream_message() int32 {
 	}
 
 	// Write the response
-	pdk.OutputJSON(resp)
+	writeStreamResponse(resp)
 	return 0
 }
 
 //go:wasmexport handle_connection_event
 func handle_connection_event() int32 {
+	defer logging.Flush()
+
 	// Check if stream handler is registered
 	if registeredStreamHandler == nil {
-		pdk.OutputJSON(StreamConnectionResponse{
+		writeOutput(StreamConnectionResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   "stream handler not registered",
@@ -97,8 +109,8 @@ func handle_connection_event() int32 {
 
 	// Read the incoming event
 	var event StreamConnectionEvent
-	if err := pdk.InputJSON(&event); err != nil {
-		pdk.OutputJSON(StreamConnectionResponse{
+	if err := readInput(&event); err != nil {
+		writeOutput(StreamConnectionResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   "failed to parse connection event",
@@ -106,10 +118,15 @@ func handle_connection_event() int32 {
 		return 1
 	}
 
+	if event.EventType == "disconnected" {
+		forgetStream(event.StreamID)
+	}
+
 	// Call the registered handler
+	defer trace.Set(event.TraceID)()
 	resp, err := registeredStreamHandler.HandleConnectionEvent(event)
 	if err != nil {
-		pdk.OutputJSON(StreamConnectionResponse{
+		writeOutput(StreamConnectionResponse{
 			Success: false,
 			Action:  "ignore",
 			Error:   err.Error(),
@@ -118,7 +135,7 @@ func handle_connection_event() int32 {
 	}
 
 	// Write the response
-	pdk.OutputJSON(resp)
+	writeOutput(resp)
 	return 0
 }
 
@@ -168,6 +185,20 @@ func StreamResponse(dataType string, data any) StreamMessageResponse {
 	}
 }
 
+// StreamRawResponse is StreamResponse with data that is already encoded as
+// JSON, e.g. built with strconv.Append* into a reused buffer. The bytes are
+// written to the host unchanged, so they must not be modified until the
+// handler returned. Invalid JSON is answered with an ignore response carrying
+// the error.
+func StreamRawResponse(dataType string, data []byte) StreamMessageResponse {
+	return StreamMessageResponse{
+		Success:  true,
+		Action:   "data",
+		DataType: dataType,
+		RawData:  data,
+	}
+}
+
 // IgnoreResponse is a helper to create ignore responses (for messages that don't need processing)
 func IgnoreResponse() StreamMessageResponse {
 	return StreamMessageResponse{
@@ -0,0 +1,131 @@
+//go:build !wasm
+
+package plugin_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+)
+
+type tickerHandler struct {
+	contexts []map[string]any
+	messages []string
+	raw      bool
+	buf      []byte
+}
+
+func (h *tickerHandler) HandleStreamMessage(req plugin.StreamMessageRequest) (plugin.StreamMessageResponse, error) {
+	h.contexts = append(h.contexts, req.StreamContext)
+	h.messages = append(h.messages, string(req.Message))
+	if !h.raw {
+		return plugin.StreamResponse("ticker", map[string]any{"price": "42.5", "size": len(req.Message)}), nil
+	}
+	h.buf = append(h.buf[:0], `{"price":"42.5","size":`...)
+	h.buf = strconv.AppendInt(h.buf, int64(len(req.Message)), 10)
+	h.buf = append(h.buf, '}')
+	return plugin.StreamRawResponse("ticker", h.buf), nil
+}
+
+func (h *tickerHandler) HandleConnectionEvent(event plugin.StreamConnectionEvent) (plugin.StreamConnectionResponse, error) {
+	return plugin.DefaultConnectionEventHandler(event), nil
+}
+
+func TestStreamMessageFastPath(t *testing.T) {
+	handler := &tickerHandler{raw: true}
+	plugin.RegisterStreamHandler(handler)
+	h := plugintest.New(t)
+
+	ctx := map[string]any{"symbol": "BTCUSDT"}
+	send := func() {
+		for _, msg := range []string{`{"p":1}`, `{"p":22}`} {
+			resp := h.StreamMessage(plugin.StreamMessageRequest{StreamID: "s1", Message: []byte(msg), StreamContext: ctx})
+			if !resp.Success || resp.DataType != "ticker" {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+			want := map[string]any{"price": "42.5", "size": float64(len(msg))}
+			if !reflect.DeepEqual(resp.Data, want) {
+				t.Fatalf("expected %v, got %v", want, resp.Data)
+			}
+		}
+		if handler.messages[0] != `{"p":1}` || handler.messages[1] != `{"p":22}` {
+			t.Fatalf("unexpected messages %q", handler.messages)
+		}
+	}
+
+	// Every message gets its own context by default
+	send()
+	if reflect.ValueOf(handler.contexts[0]).Pointer() == reflect.ValueOf(handler.contexts[1]).Pointer() {
+		t.Fatal("expected a context per message by default")
+	}
+
+	plugin.ReuseStreamMessages()
+	handler.contexts, handler.messages = nil, nil
+	send()
+	if reflect.ValueOf(handler.contexts[0]).Pointer() != reflect.ValueOf(handler.contexts[1]).Pointer() {
+		t.Fatal("expected the unchanged stream context to be reused")
+	}
+
+	resp := h.StreamMessage(plugin.StreamMessageRequest{StreamID: "s1", Message: []byte("x"), StreamContext: map[string]any{"symbol": "ETHUSDT"}})
+	if !resp.Success || handler.contexts[2]["symbol"] != "ETHUSDT" {
+		t.Fatalf("expected changed context to be decoded, got %v", handler.contexts[2])
+	}
+}
+
+type rawHandler struct {
+	data []byte
+}
+
+func (h *rawHandler) HandleStreamMessage(req plugin.StreamMessageRequest) (plugin.StreamMessageResponse, error) {
+	return plugin.StreamRawResponse("ticker", h.data), nil
+}
+
+func (h *rawHandler) HandleConnectionEvent(event plugin.StreamConnectionEvent) (plugin.StreamConnectionResponse, error) {
+	return plugin.DefaultConnectionEventHandler(event), nil
+}
+
+func TestStreamRawResponseValidated(t *testing.T) {
+	handler := &rawHandler{}
+	plugin.RegisterStreamHandler(handler)
+	h := plugintest.New(t)
+
+	handler.data = []byte{}
+	resp := h.StreamMessage(plugin.StreamMessageRequest{StreamID: "s1", Message: []byte("x")})
+	if !resp.Success || resp.Data != nil || resp.Error != "" {
+		t.Fatalf("expected empty raw data to be treated as unset, got %+v", resp)
+	}
+
+	handler.data = []byte(`{"price":`)
+	resp = h.StreamMessage(plugin.StreamMessageRequest{StreamID: "s1", Message: []byte("x")})
+	if resp.Success || resp.Action != "ignore" || !strings.Contains(resp.Error, "invalid raw data") {
+		t.Fatalf("expected invalid raw data to be rejected, got %+v", resp)
+	}
+}
+
+type loggingHandler struct {
+	rawHandler
+}
+
+func (h *loggingHandler) HandleStreamMessage(req plugin.StreamMessageRequest) (plugin.StreamMessageResponse, error) {
+	log := logging.NewLogger("")
+	log.Info("first")
+	log.Info("second")
+	return plugin.IgnoreResponse(), nil
+}
+
+func TestStreamMessageFlushesBufferedLogs(t *testing.T) {
+	plugin.RegisterStreamHandler(&loggingHandler{})
+	h := plugintest.New(t)
+	t.Cleanup(logging.SetDefaultSink(nil))
+	sink := logging.EnableBuffering(100, 0)
+
+	h.StreamMessage(plugin.StreamMessageRequest{StreamID: "s1", Message: []byte("x")})
+	if sink.Len() != 0 || len(h.Logs()) != 2 {
+		t.Fatalf("expected the buffered records to be flushed at the end of the export, got %d buffered and %d delivered", sink.Len(), len(h.Logs()))
+	}
+}
@@ -0,0 +1,245 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// Fast paths of handle_stream_message. At thousands of messages per second
+// the generic JSON path allocates a fresh StreamContext map, a fresh message
+// buffer and a reflection-encoded response for every message. The JSON path
+// below writes responses with raw data (StreamRawResponse) without
+// reflection and, after ReuseStreamMessages, reuses the decoded
+// StreamContext while the host sends the same bytes and decodes messages
+// into a reused buffer.
+
+// streamMessageWire is StreamMessageRequest with the fields that are decoded
+// lazily kept raw
+type streamMessageWire struct {
+	StreamID      string          `json:"streamId"`
+	ConnectionID  string          `json:"connectionId"`
+	Message       json.RawMessage `json:"message"`
+	MessageType   string          `json:"messageType"`
+	StreamContext json.RawMessage `json:"streamContext,omitempty"`
+	TraceID       string          `json:"traceId,omitempty"`
+}
+
+type cachedContext struct {
+	raw   []byte
+	value map[string]any
+}
+
+var streamWire = struct {
+	mu       sync.Mutex
+	reuse    bool                     // set by ReuseStreamMessages
+	contexts map[string]cachedContext // by stream ID
+	message  []byte                   // reused decode buffer of Message
+	out      bytes.Buffer             // reused response buffer
+}{contexts: make(map[string]cachedContext)}
+
+// ReuseStreamMessages saves two allocations per stream message: Message is
+// decoded into a buffer the next message overwrites, and StreamContext is
+// shared by all messages of a stream while its context doesn't change.
+// Handlers must then copy what they keep of Message and must not modify
+// StreamContext. Call it in init() next to RegisterStreamHandler.
+func ReuseStreamMessages() {
+	streamWire.mu.Lock()
+	defer streamWire.mu.Unlock()
+	streamWire.reuse = true
+}
+
+// readStreamMessage decodes the handle_stream_message input. After
+// ReuseStreamMessages, in the JSON wire format req.Message aliases a buffer
+// reused by the next message and req.StreamContext is shared between
+// messages of the same stream.
+func readStreamMessage(req *StreamMessageRequest) error {
+	if hostinfo.WireFormat() != hostinfo.WireJSON {
+		return readInput(req)
+	}
+
+	var wire streamMessageWire
+	if err := json.Unmarshal(host.Input(), &wire); err != nil {
+		return err
+	}
+
+	streamWire.mu.Lock()
+	defer streamWire.mu.Unlock()
+
+	*req = StreamMessageRequest{
+		StreamID:     wire.StreamID,
+		ConnectionID: wire.ConnectionID,
+		MessageType:  wire.MessageType,
+		TraceID:      wire.TraceID,
+	}
+
+	if !streamWire.reuse {
+		msg, err := decodeBytes(wire.Message, nil)
+		if err != nil {
+			return err
+		}
+		req.Message = msg
+		if raw := wire.StreamContext; len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+			return json.Unmarshal(raw, &req.StreamContext)
+		}
+		return nil
+	}
+
+	if msg, err := decodeBytes(wire.Message, streamWire.message[:0]); err != nil {
+		return err
+	} else {
+		streamWire.message = msg
+		req.Message = msg
+	}
+
+	raw := wire.StreamContext
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		delete(streamWire.contexts, wire.StreamID)
+		return nil
+	}
+	if cached, ok := streamWire.contexts[wire.StreamID]; ok && bytes.Equal(cached.raw, raw) {
+		req.StreamContext = cached.value
+		return nil
+	}
+	var ctx map[string]any
+	if err := json.Unmarshal(raw, &ctx); err != nil {
+		return err
+	}
+	streamWire.contexts[wire.StreamID] = cachedContext{raw: append([]byte(nil), raw...), value: ctx}
+	req.StreamContext = ctx
+	return nil
+}
+
+// forgetStream drops the cached context of a stream
+func forgetStream(streamID string) {
+	streamWire.mu.Lock()
+	defer streamWire.mu.Unlock()
+	delete(streamWire.contexts, streamID)
+}
+
+// decodeBytes decodes a JSON encoded []byte (a base64 string or null) into buf
+func decodeBytes(raw json.RawMessage, buf []byte) ([]byte, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		var b []byte
+		return b, json.Unmarshal(raw, &b)
+	}
+	src := raw[1 : len(raw)-1]
+	n := base64.StdEncoding.DecodedLen(len(src))
+	if cap(buf) < n {
+		buf = make([]byte, n)
+	}
+	buf = buf[:n]
+	n, err := base64.StdEncoding.Decode(buf, src)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// writeStreamResponse writes the handle_stream_message output. Responses
+// with RawData are written without reflection in the JSON wire format.
+// Empty RawData counts as unset; invalid RawData is answered with an error.
+func writeStreamResponse(resp StreamMessageResponse) {
+	if len(resp.RawData) == 0 {
+		resp.RawData = nil
+		writeOutput(resp)
+		return
+	}
+	if hostinfo.WireFormat() != hostinfo.WireJSON {
+		// Other formats cannot embed JSON bytes, decode them once
+		var data any
+		if err := json.Unmarshal(resp.RawData, &data); err != nil {
+			writeOutput(StreamMessageResponse{Action: "ignore", Error: "invalid raw data: " + err.Error()})
+			return
+		}
+		resp.Data, resp.RawData = data, nil
+		writeOutput(resp)
+		return
+	}
+	if !json.Valid(resp.RawData) {
+		writeOutput(StreamMessageResponse{Action: "ignore", Error: "invalid raw data: not valid JSON"})
+		return
+	}
+
+	streamWire.mu.Lock()
+	defer streamWire.mu.Unlock()
+	b := &streamWire.out
+	b.Reset()
+	b.WriteString(`{"success":`)
+	b.WriteString(strconv.FormatBool(resp.Success))
+	b.WriteString(`,"action":`)
+	appendJSONString(b, resp.Action)
+	if resp.DataType != "" {
+		b.WriteString(`,"dataType":`)
+		appendJSONString(b, resp.DataType)
+	}
+	b.WriteString(`,"data":`)
+	b.Write(resp.RawData)
+	if resp.SendMessage != "" {
+		b.WriteString(`,"sendMessage":`)
+		appendJSONString(b, resp.SendMessage)
+	}
+	if resp.Error != "" {
+		b.WriteString(`,"error":`)
+		appendJSONString(b, resp.Error)
+	}
+	b.WriteByte('}')
+	host.Output(b.Bytes())
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString writes s as JSON string. Invalid UTF-8 is replaced with
+// U+FFFD, as encoding/json does.
+func appendJSONString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(s[start:i])
+			b.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
@@ -0,0 +1,105 @@
+//go:build !wasm
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// benchHost replays one export input and discards the output, so the
+// benchmarks measure the plugin side of handle_stream_message only
+type benchHost struct {
+	input []byte
+}
+
+func (h *benchHost) Input() []byte                    { return h.input }
+func (h *benchHost) Output([]byte)                    {}
+func (h *benchHost) SetError(error)                   {}
+func (h *benchHost) Call(string, []byte) []byte       { return nil }
+func (h *benchHost) Config(key string) (string, bool) { return "", false }
+
+func benchStreamInput(b *testing.B) {
+	input, _ := json.Marshal(StreamMessageRequest{
+		StreamID:      "s1",
+		Message:       []byte(`{"e":"trade","s":"BTCUSDT","p":"42000.10","q":"0.015","T":1714564800000}`),
+		MessageType:   "data",
+		StreamContext: map[string]any{"symbol": "BTCUSDT", "timeframe": "1m", "depth": 20},
+	})
+	b.Cleanup(host.Set(&benchHost{input: input}))
+	b.ReportAllocs()
+}
+
+// BenchmarkStreamMessageGeneric is the path used before the fast paths:
+// full request decoding and reflection-encoded response data
+func BenchmarkStreamMessageGeneric(b *testing.B) {
+	benchStreamInput(b)
+	for b.Loop() {
+		var req StreamMessageRequest
+		if err := readInput(&req); err != nil {
+			b.Fatal(err)
+		}
+		writeOutput(StreamResponse("trade", map[string]any{"price": "42000.10", "size": len(req.Message)}))
+	}
+}
+
+func BenchmarkStreamMessageFast(b *testing.B) {
+	benchStreamInput(b)
+	streamWire.reuse = true
+	b.Cleanup(func() { streamWire.reuse = false })
+	var buf []byte
+	for b.Loop() {
+		var req StreamMessageRequest
+		if err := readStreamMessage(&req); err != nil {
+			b.Fatal(err)
+		}
+		buf = append(buf[:0], `{"price":"42000.10","size":`...)
+		buf = strconv.AppendInt(buf, int64(len(req.Message)), 10)
+		buf = append(buf, '}')
+		writeStreamResponse(StreamRawResponse("trade", buf))
+	}
+}
+
+// configCountingHost counts host config lookups
+type configCountingHost struct {
+	benchHost
+	lookups int
+}
+
+func (h *configCountingHost) Config(key string) (string, bool) {
+	h.lookups++
+	return `{"protocolVersion":1,"wireFormat":"json"}`, key == "host_capabilities"
+}
+
+func TestWireFormatResolvedOnce(t *testing.T) {
+	input, _ := json.Marshal(StreamMessageRequest{StreamID: "s1", Message: []byte(`{"p":1}`)})
+	h := &configCountingHost{benchHost: benchHost{input: input}}
+	t.Cleanup(host.Set(h))
+
+	for range 10 {
+		var req StreamMessageRequest
+		if err := readStreamMessage(&req); err != nil {
+			t.Fatal(err)
+		}
+		writeStreamResponse(StreamRawResponse("trade", []byte(`{}`)))
+		writeOutput(StreamResponse("trade", nil))
+	}
+	if h.lookups != 1 {
+		t.Fatalf("expected 1 config lookup, got %d", h.lookups)
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{"plain", "quote\" back\\slash", "ctrl\x01\n\t", "<html>&", "ünïcode", "bad\xffutf8"} {
+		var b bytes.Buffer
+		appendJSONString(&b, s)
+		want, _ := json.Marshal(s)
+		if b.String() != string(want) {
+			t.Errorf("%q: expected %s, got %s", s, want, b.String())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+)
+
+// WeightPool defines a weight budget shared by several commands, as used by
+// exchanges like Binance where every endpoint has a request weight and the
+// total weight per window is limited.
+type WeightPool struct {
+	Name          string           `json:"name"`
+	Scope         []RateLimitScope `json:"scope"`
+	Budget        int              `json:"budget"`        // Total weight allowed per window
+	WindowSeconds int              `json:"windowSeconds"` // Length of the window
+}
+
+// NewWeightPool creates a weight pool with the given budget per window.
+// Scope defaults to RateLimitScopeIP.
+//
+// Example usage:
+//
+//	NewWeightPool("rest", 6000, time.Minute)                       // 6000 weight per minute per IP
+//	NewWeightPool("orders", 100, 10*time.Second, RateLimitScopeAPIKey)
+func NewWeightPool(name string, budget int, window time.Duration, scope ...RateLimitScope) WeightPool {
+	if len(scope) == 0 {
+		scope = []RateLimitScope{RateLimitScopeIP}
+	}
+	return WeightPool{
+		Name:          name,
+		Scope:         scope,
+		Budget:        budget,
+		WindowSeconds: int(window.Seconds()),
+	}
+}
+
+// Validate checks that the weight pool can be enforced by the host
+func (p WeightPool) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("weight pool name is required")
+	}
+	if p.Budget <= 0 {
+		return fmt.Errorf("weight pool %q: budget must be > 0", p.Name)
+	}
+	if p.WindowSeconds <= 0 {
+		return fmt.Errorf("weight pool %q: windowSeconds must be > 0", p.Name)
+	}
+	return nil
+}
+
+// Weight pools registered by RegisterWeightPools
+var registeredWeightPools []WeightPool
+
+// RegisterWeightPools declares weight pools referenced by RateLimit.Pool.
+// Call it in init() together with RegisterPlugin.
+func RegisterWeightPools(pools ...WeightPool) {
+	registeredWeightPools = append(registeredWeightPools, pools...)
+}
+
+// collectWeightPools validates the registered pools and checks that every
+// rate limit referencing a pool points to a declared one. This is what the
+// get_weight_pools export hands to the host. Like collectRateLimits, invalid
+// pools and references to unknown pools are logged and skipped.
+func collectWeightPools(limits []RateLimit) []WeightPool {
+	log := logging.NewLogger("")
+	names := make(map[string]bool, len(registeredWeightPools))
+	result := make([]WeightPool, 0, len(registeredWeightPools))
+	for _, pool := range registeredWeightPools {
+		if err := pool.Validate(); err != nil {
+			log.WarnWithData("skipping invalid weight pool", map[string]any{"error": err.Error()})
+			continue
+		}
+		names[pool.Name] = true
+		result = append(result, pool)
+	}
+
+	for _, limit := range limits {
+		if limit.Pool != "" && !names[limit.Pool] {
+			log.WarnWithData("rate limit references unknown weight pool", map[string]any{"command": limit.Command, "pool": limit.Pool})
+		}
+	}
+
+	return result
+}
+
+// WeightUsage reports the weight an exchange says has been consumed in the
+// current window of a pool. The host uses it to correct its own accounting.
+type WeightUsage struct {
+	Pool string `json:"pool"`
+	Used int    `json:"used"`
+}
+
+// WeightUsageFromHeaders reads the consumed weight from an exchange response
+// header, e.g. "X-MBX-USED-WEIGHT-1M" for Binance.
+// Returns false if the header is missing or not a number.
+func WeightUsageFromHeaders(pool string, headers http.Header, headerName string) (WeightUsage, bool) {
+	value := headers.Get(headerName)
+	if value == "" {
+		return WeightUsage{}, false
+	}
+	used, err := strconv.Atoi(value)
+	if err != nil {
+		return WeightUsage{}, false
+	}
+	return WeightUsage{Pool: pool, Used: used}, true
+}
+
+// WithWeightUsage attaches weight usage reports to a response
+func (r Response) WithWeightUsage(usage ...WeightUsage) Response {
+	r.WeightUsage = append(r.WeightUsage, usage...)
+	return r
+}
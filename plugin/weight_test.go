@@ -0,0 +1,46 @@
+//go:build !wasm
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	loggingtesting "github.com/plusev-terminal/go-plugin-common/logging/testing"
+)
+
+func TestCollectWeightPoolsSkipsInvalid(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	t.Cleanup(logging.SetDefaultSink(sink))
+	prev := registeredWeightPools
+	t.Cleanup(func() { registeredWeightPools = prev })
+
+	registeredWeightPools = []WeightPool{
+		{Name: "rest", Budget: 6000, WindowSeconds: 60},
+		{Name: "orders", Budget: 0, WindowSeconds: 10},
+	}
+	pools := collectWeightPools(nil)
+
+	if len(pools) != 1 || pools[0].Name != "rest" {
+		t.Fatalf("expected only the valid pool, got %+v", pools)
+	}
+	sink.AssertLogged(t, "warn", "invalid weight pool")
+}
+
+func TestCollectWeightPoolsUnknownPool(t *testing.T) {
+	sink := loggingtesting.NewMockSink()
+	t.Cleanup(logging.SetDefaultSink(sink))
+	prev := registeredWeightPools
+	t.Cleanup(func() { registeredWeightPools = prev })
+
+	registeredWeightPools = []WeightPool{{Name: "rest", Budget: 6000, WindowSeconds: 60}}
+	pools := collectWeightPools([]RateLimit{
+		{Command: "get_ohlcv", RPS: 10, Pool: "rest"},
+		{Command: "place_order", RPS: 10, Pool: "orders"},
+	})
+
+	if len(pools) != 1 || pools[0].Name != "rest" {
+		t.Fatalf("expected the declared pool, got %+v", pools)
+	}
+	sink.AssertLogged(t, "warn", "unknown weight pool")
+}
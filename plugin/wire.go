@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/msgpack"
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// ProtocolVersion is the version of the export and host function payloads
+// implemented by this library
+const ProtocolVersion = 1
+
+// ProtocolInfo is the output of the protocol_version export. The host picks
+// one of WireFormats and announces it as hostinfo.Capabilities.WireFormat;
+// hosts that never call the export keep using JSON.
+type ProtocolInfo struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	WireFormats     []string `json:"wireFormats"`
+}
+
+func init() {
+	host.RegisterExport("protocol_version", protocol_version)
+}
+
+// protocol_version always answers in JSON, as it runs before negotiation
+//
+//go:wasmexport protocol_version
+func protocol_version() int32 {
+	host.OutputJSON(ProtocolInfo{
+		ProtocolVersion: ProtocolVersion,
+		WireFormats:     []string{hostinfo.WireJSON, hostinfo.WireMsgpack},
+	})
+	return 0
+}
+
+// readInput decodes the export input in the negotiated wire format. The
+// format is read from the host config once per instance, see
+// hostinfo.WireFormat.
+func readInput(v any) error {
+	if hostinfo.WireFormat() == hostinfo.WireMsgpack {
+		return host.InputMsgpack(v)
+	}
+	return host.InputJSON(v)
+}
+
+// readInputUseNumber is readInput with numbers in generic values decoded as
+// json.Number
+func readInputUseNumber(v any) error {
+	if hostinfo.WireFormat() == hostinfo.WireMsgpack {
+		return msgpack.UnmarshalUseNumber(host.Input(), v)
+	}
+	return utils.DecodeJSON(host.Input(), v)
+}
+
+// writeOutput encodes v as export output in the negotiated wire format
+func writeOutput(v any) error {
+	if hostinfo.WireFormat() == hostinfo.WireMsgpack {
+		return host.OutputMsgpack(v)
+	}
+	return host.OutputJSON(v)
+}
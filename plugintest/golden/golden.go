@@ -0,0 +1,164 @@
+// Package golden compares JSON outputs of plugins against checked-in golden
+// files. Outputs are normalized (sorted keys, indentation) before comparing,
+// so only real changes show up in diffs.
+//
+// Run the tests with GOLDEN_UPDATE=1 to write the current outputs as new
+// golden files:
+//
+//	GOLDEN_UPDATE=1 go test ./... -run TestParseMarkets
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that makes Assert write golden files
+// instead of comparing. An environment variable rather than a flag, so test
+// binaries can still define their own -update flag.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// Dir is the directory golden files are read from and written to
+var Dir = "testdata"
+
+// Option tweaks normalization
+type Option func(*options)
+
+type options struct {
+	ignore map[string]bool
+}
+
+// IgnoreFields drops object keys with the given names at any depth, e.g.
+// timestamps that change between runs
+func IgnoreFields(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			o.ignore[name] = true
+		}
+	}
+}
+
+// Assert marshals got to JSON and compares it with the golden file name
+//
+// Example:
+//
+//	markets, err := parseMarkets(fixture)
+//	...
+//	golden.Assert(t, "binance_markets", markets)
+func Assert(t testing.TB, name string, got any, opts ...Option) {
+	t.Helper()
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("golden: failed to marshal %s: %v", name, err)
+	}
+	AssertJSON(t, name, data, opts...)
+}
+
+// AssertJSON compares raw JSON with the golden file name
+func AssertJSON(t testing.TB, name string, got []byte, opts ...Option) {
+	t.Helper()
+
+	normalized, err := Normalize(got, opts...)
+	if err != nil {
+		t.Fatalf("golden: invalid JSON for %s: %v", name, err)
+	}
+
+	path := filepath.Join(Dir, name+".golden.json")
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+		if err := os.WriteFile(path, normalized, 0o644); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden: %v (run with %s=1 to create it)", err, UpdateEnv)
+	}
+	if diff := Diff(want, normalized); diff != "" {
+		t.Errorf("golden: %s differs from %s (run with %s=1 to accept):\n%s", name, path, UpdateEnv, diff)
+	}
+}
+
+// Normalize re-encodes JSON with sorted keys and two-space indentation.
+// Numbers keep their original representation.
+func Normalize(data []byte, opts ...Option) ([]byte, error) {
+	o := options{ignore: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if len(o.ignore) > 0 {
+		v = dropFields(v, o.ignore)
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Diff returns a line diff of want and got, or "" when they are equal
+func Diff(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < max(len(wantLines), len(gotLines)) && shown < 20; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&b, "line %d:\n  - %s\n  + %s\n", i+1, w, g)
+		shown++
+	}
+	if shown == 0 {
+		return "whitespace differs"
+	}
+	return b.String()
+}
+
+func dropFields(v any, ignore map[string]bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			if ignore[key] {
+				delete(val, key)
+				continue
+			}
+			val[key] = dropFields(child, ignore)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = dropFields(child, ignore)
+		}
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize([]byte(`{"b": 1.50, "a": [{"ts": 1, "x": "y"}]}`), IgnoreFields("ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n  \"a\": [\n    {\n      \"x\": \"y\"\n    }\n  ],\n  \"b\": 1.50\n}\n"
+	if diff := Diff([]byte(want), got); diff != "" {
+		t.Errorf("unexpected normalization:\n%s", diff)
+	}
+}
+
+// recorder captures failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestAssertUpdateThenCompare(t *testing.T) {
+	prev := Dir
+	Dir = t.TempDir()
+	t.Cleanup(func() { Dir = prev })
+
+	markets := []map[string]any{{"symbol": "BTCUSDT", "tick": "0.01"}}
+	t.Setenv(UpdateEnv, "1")
+	Assert(t, "markets", markets)
+	data, err := os.ReadFile(filepath.Join(Dir, "markets.golden.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[\n  {\n    \"symbol\": \"BTCUSDT\",\n    \"tick\": \"0.01\"\n  }\n]\n"; string(data) != want {
+		t.Fatalf("unexpected golden file:\n%s", data)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	rec := &recorder{TB: t}
+	Assert(rec, "markets", markets)
+	AssertJSON(rec, "markets", []byte(`[{"tick":"0.01","symbol":"BTCUSDT"}]`))
+	if len(rec.errors) != 0 {
+		t.Fatalf("expected matching outputs to pass, got %v", rec.errors)
+	}
+}
+
+func TestAssertJSONMismatch(t *testing.T) {
+	prev := Dir
+	Dir = t.TempDir()
+	t.Cleanup(func() { Dir = prev })
+	t.Setenv(UpdateEnv, "")
+
+	if err := os.WriteFile(filepath.Join(Dir, "ticker.golden.json"), []byte("{\n  \"price\": \"1\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec := &recorder{TB: t}
+	AssertJSON(rec, "ticker", []byte(`{"price":"2"}`))
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `+   "price": "2"`) {
+		t.Fatalf("expected a diff, got %v", rec.errors)
+	}
+
+	rec = &recorder{TB: t}
+	AssertJSON(rec, "missing", []byte(`{}`))
+	if len(rec.errors) == 0 || !strings.Contains(rec.errors[0], UpdateEnv) {
+		t.Fatalf("expected a missing golden file error, got %v", rec.errors)
+	}
+}
@@ -0,0 +1,248 @@
+//go:build !wasm
+
+package plugintest
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/alerting"
+	"github.com/plusev-terminal/go-plugin-common/bridge"
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	"github.com/plusev-terminal/go-plugin-common/requester/signing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// defaultChunkSize is used for http_stream_read calls without MaxBytes
+const defaultChunkSize = 64 * 1024
+
+// Input implements the host side of pdk.Input
+func (h *Harness) Input() []byte {
+	return h.input
+}
+
+// Output implements the host side of pdk.Output
+func (h *Harness) Output(data []byte) {
+	h.output = data
+}
+
+// SetError implements the host side of pdk.SetError
+func (h *Harness) SetError(err error) {
+	h.err = err
+}
+
+// Config answers host config lookups from HostConfig
+func (h *Harness) Config(key string) (string, bool) {
+	value, ok := h.HostConfig[key]
+	return value, ok
+}
+
+// Call answers the host imports
+func (h *Harness) Call(name string, input []byte) []byte {
+	switch name {
+	case host.HTTPRequest:
+		return h.httpRequest(input)
+	case host.HTTPRequestStream:
+		return h.httpRequestStream(input)
+	case host.HTTPStreamRead:
+		return h.httpStreamRead(input)
+	case host.HTTPStreamClose:
+		h.mu.Lock()
+		delete(h.streams, string(input))
+		h.mu.Unlock()
+		return nil
+	case host.HTTPClearCookies:
+		if clearer, ok := h.HTTP.(interface{ ClearCookies(name string) }); ok {
+			clearer.ClearCookies(string(input))
+		}
+		return nil
+	case host.SignRequest:
+		return h.signRequest(input)
+	case host.LogRecord:
+		var record logging.PluginLogRecord
+		if err := json.Unmarshal(input, &record); err != nil {
+			h.t.Errorf("plugintest: invalid log record: %v", err)
+			return nil
+		}
+		h.mu.Lock()
+		h.logs = append(h.logs, record)
+		h.mu.Unlock()
+		return nil
+	case host.LogRecords:
+		var records []logging.PluginLogRecord
+		if err := json.Unmarshal(input, &records); err != nil {
+			h.t.Errorf("plugintest: invalid log records: %v", err)
+			return nil
+		}
+		h.mu.Lock()
+		h.logs = append(h.logs, records...)
+		h.mu.Unlock()
+		return nil
+	case host.TimeNow:
+		return mustJSON(h.Now())
+	case host.AlertTrigger:
+		var alert alerting.Alert
+		if err := json.Unmarshal(input, &alert); err != nil {
+			h.t.Errorf("plugintest: invalid alert: %v", err)
+			return nil
+		}
+		h.mu.Lock()
+		h.alerts = append(h.alerts, alert)
+		h.mu.Unlock()
+		return nil
+	case host.ReportProgress:
+		var progress dt.Progress
+		if err := json.Unmarshal(input, &progress); err != nil {
+			h.t.Errorf("plugintest: invalid progress: %v", err)
+			return nil
+		}
+		h.mu.Lock()
+		h.progress = append(h.progress, progress)
+		h.mu.Unlock()
+		return nil
+	case host.PluginCall:
+		return h.pluginCall(input)
+	case host.WSConnect:
+		return h.wsConnect(input)
+	case host.WSSend:
+		return h.wsSend(input)
+	case host.WSReceive:
+		return h.wsReceive(input)
+	case host.WSClose:
+		return h.wsClose(input)
+	default:
+		h.t.Errorf("plugintest: unsupported host function %s", name)
+		return nil
+	}
+}
+
+func (h *Harness) httpRequest(input []byte) []byte {
+	res, err := h.send(input)
+	if err != nil {
+		return mustJSON(rt.Response{Error: err.Error()})
+	}
+	return mustJSON(res)
+}
+
+func (h *Harness) httpRequestStream(input []byte) []byte {
+	res, err := h.send(input)
+	if err != nil {
+		return mustJSON(rt.StreamOpenResponse{Error: err.Error()})
+	}
+
+	h.mu.Lock()
+	h.nextID++
+	id := fmt.Sprintf("stream-%d", h.nextID)
+	h.streams[id] = res.Body
+	h.mu.Unlock()
+
+	return mustJSON(rt.StreamOpenResponse{StreamID: id, Status: res.Status, Headers: res.Headers})
+}
+
+func (h *Harness) httpStreamRead(input []byte) []byte {
+	var req rt.StreamReadRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(rt.StreamChunk{Error: err.Error()})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	body, ok := h.streams[req.StreamID]
+	if !ok {
+		return mustJSON(rt.StreamChunk{Error: fmt.Sprintf("unknown stream %s", req.StreamID)})
+	}
+
+	size := req.MaxBytes
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	size = min(size, len(body))
+	h.streams[req.StreamID] = body[size:]
+
+	return mustJSON(rt.StreamChunk{Data: body[:size], EOF: size == len(body)})
+}
+
+// send decodes the request and hands it to the HTTP backend
+func (h *Harness) send(input []byte) (*rt.Response, error) {
+	var req rt.Request
+	if err := json.Unmarshal(input, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	return h.HTTP.Send(&req, nil)
+}
+
+func (h *Harness) signRequest(input []byte) []byte {
+	var req rt.SignRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(rt.SignResponse{Error: err.Error()})
+	}
+
+	signature, err := h.sign(req)
+	if err != nil {
+		return mustJSON(rt.SignResponse{Error: err.Error()})
+	}
+	return mustJSON(rt.SignResponse{Signature: signature})
+}
+
+// sign computes the signature like the host would, using h.Secrets
+func (h *Harness) sign(req rt.SignRequest) ([]byte, error) {
+	secret, ok := h.Secrets[req.CredentialRef]
+	if !ok {
+		return nil, fmt.Errorf("unknown credential %q", req.CredentialRef)
+	}
+	if req.SecretBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(string(secret))
+		if err != nil {
+			return nil, fmt.Errorf("credential %q is not base64: %w", req.CredentialRef, err)
+		}
+		secret = decoded
+	}
+
+	var algorithm signing.Algorithm
+	switch req.Algorithm {
+	case rt.SignHMACSHA256:
+		algorithm = signing.HMACSHA256(secret)
+	case rt.SignHMACSHA512:
+		algorithm = signing.HMACSHA512(secret)
+	case rt.SignEd25519:
+		switch len(secret) {
+		case ed25519.SeedSize:
+			algorithm = signing.Ed25519(ed25519.NewKeyFromSeed(secret))
+		case ed25519.PrivateKeySize:
+			algorithm = signing.Ed25519(ed25519.PrivateKey(secret))
+		default:
+			return nil, fmt.Errorf("credential %q is not an ed25519 key", req.CredentialRef)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", req.Algorithm)
+	}
+	return algorithm(req.Payload)
+}
+
+func mustJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("plugintest: failed to marshal host response: %v", err))
+	}
+	return data
+}
+
+func (h *Harness) pluginCall(input []byte) []byte {
+	var req bridge.CallRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		h.t.Errorf("plugintest: invalid plugin call: %v", err)
+		return nil
+	}
+
+	h.mu.Lock()
+	handler, ok := h.Plugins[req.PluginID]
+	h.mu.Unlock()
+	if !ok {
+		return mustJSON(bridge.CallResult{Code: bridge.CodeNotFound, Error: "plugin " + req.PluginID + " not installed"})
+	}
+	return mustJSON(bridge.CallResult{Response: handler(req.Command)})
+}
@@ -0,0 +1,299 @@
+//go:build !wasm
+
+// Package plugintest runs a plugin in-process under go test, without
+// compiling to WASM or starting extism. It installs a fake host that answers
+// every host import (http_request, http streams, sign_request, log_record,
+// time_now, report_progress, alert_trigger, plugin_call, ...) and drives the plugin's exports directly.
+//
+// The plugin registers itself as usual in init(); a test then creates a
+// harness and calls the exports:
+//
+//	func TestGetMarkets(t *testing.T) {
+//		h := plugintest.New(t)
+//		h.Mock.SetMockResponse("/api/v3/exchangeInfo", `{"symbols": []}`)
+//
+//		if err := h.Init(map[string]any{"apiKey": "key"}); err != nil {
+//			t.Fatal(err)
+//		}
+//		res := h.Command("getMarkets", nil)
+//		if !res.Result {
+//			t.Fatal(res.Error)
+//		}
+//	}
+//
+// The host is process-global, so tests using a harness must not run in parallel.
+package plugintest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/alerting"
+	dt "github.com/plusev-terminal/go-plugin-common/datapipe/types"
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/msgpack"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	requestertesting "github.com/plusev-terminal/go-plugin-common/requester/testing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+	"github.com/plusev-terminal/go-plugin-common/timeutil"
+)
+
+// DefaultNow is the initial clock of a new harness
+var DefaultNow = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Harness is the fake host for one test
+type Harness struct {
+	t testing.TB
+
+	// Mock is the default HTTP backend
+	Mock *requestertesting.MockRequester
+	// HTTP answers http_request and http_request_stream calls. Defaults to Mock.
+	HTTP rt.RequestDoer
+	// Secrets holds the credentials sign_request signs with, keyed by credential reference
+	Secrets map[string][]byte
+	// HostConfig is the host-provided plugin config, e.g. logging.LevelConfigKey.
+	// It advertises hostinfo.Full under hostinfo.ConfigKey; use SetCapabilities
+	// to simulate an older host. Values the library caches are reloaded with
+	// the next export call, or right away when set with SetConfig.
+	HostConfig map[string]string
+
+	// Plugins answers plugin_call host calls, keyed by plugin ID. Calls to
+	// other plugins fail with bridge.ErrPluginNotFound.
+	Plugins map[string]func(plugin.Command) plugin.Response
+
+	// WebSockets answers ws_connect calls, keyed by URL. Dials to other URLs fail.
+	WebSockets map[string]*WSServer
+
+	mu       sync.Mutex
+	now      time.Time
+	logs     []logging.PluginLogRecord
+	progress []dt.Progress
+	alerts   []alerting.Alert
+	streams  map[string][]byte
+	wsConns  map[string]*wsConn
+	nextID   int
+
+	input  []byte
+	output []byte
+	err    error
+}
+
+// New creates a harness and installs it as host until the test ends
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	mock := requestertesting.NewMockRequester()
+	h := &Harness{
+		t:          t,
+		Mock:       mock,
+		HTTP:       mock,
+		Secrets:    make(map[string][]byte),
+		HostConfig: make(map[string]string),
+		Plugins:    make(map[string]func(plugin.Command) plugin.Response),
+		now:        DefaultNow,
+		WebSockets: make(map[string]*WSServer),
+		streams:    make(map[string][]byte),
+		wsConns:    make(map[string]*wsConn),
+	}
+	h.SetCapabilities(hostinfo.Full())
+	t.Cleanup(host.Set(h))
+	timeutil.Default().Sync()
+	return h
+}
+
+// SetCapabilities sets the capabilities the harness advertises to hostinfo
+func (h *Harness) SetCapabilities(caps hostinfo.Capabilities) {
+	data, err := json.Marshal(caps)
+	if err != nil {
+		h.t.Fatalf("marshal capabilities: %v", err)
+	}
+	h.SetConfig(hostinfo.ConfigKey, string(data))
+}
+
+// SetConfig sets a host config value; an empty value removes it
+func (h *Harness) SetConfig(key, value string) {
+	if value == "" {
+		delete(h.HostConfig, key)
+	} else {
+		h.HostConfig[key] = value
+	}
+	host.ConfigChanged()
+}
+
+// Now returns the time reported by time_now
+func (h *Harness) Now() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.now
+}
+
+// SetNow sets the time reported by time_now
+func (h *Harness) SetNow(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.now = now
+	timeutil.Default().Sync()
+}
+
+// Advance moves the clock forward by d
+func (h *Harness) Advance(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.now = h.now.Add(d)
+	timeutil.Default().Sync()
+}
+
+// Logs returns all records the plugin sent via log_record
+func (h *Harness) Logs() []logging.PluginLogRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]logging.PluginLogRecord(nil), h.logs...)
+}
+
+// Progress returns all updates the plugin sent via report_progress
+func (h *Harness) Progress() []dt.Progress {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]dt.Progress(nil), h.progress...)
+}
+
+// Alerts returns all alerts the plugin sent via alert_trigger
+func (h *Harness) Alerts() []alerting.Alert {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]alerting.Alert(nil), h.alerts...)
+}
+
+// Meta calls the meta export
+func (h *Harness) Meta() (m.Meta, error) {
+	var meta m.Meta
+	_, err := h.invoke("meta", nil, &meta)
+	return meta, err
+}
+
+// ConfigFields calls the get_configuration_fields export
+func (h *Harness) ConfigFields() ([]plugin.ConfigField, error) {
+	var fields []plugin.ConfigField
+	_, err := h.invoke("get_configuration_fields", nil, &fields)
+	return fields, err
+}
+
+// RateLimits calls the get_rate_limits export
+func (h *Harness) RateLimits() ([]plugin.RateLimit, error) {
+	var limits []plugin.RateLimit
+	_, err := h.invoke("get_rate_limits", nil, &limits)
+	return limits, err
+}
+
+// Init calls the init export with the given user configuration
+func (h *Harness) Init(config map[string]any) error {
+	if config == nil {
+		config = map[string]any{}
+	}
+	rc, err := h.invoke("init", config, nil)
+	if err == nil && rc != 0 {
+		err = fmt.Errorf("init failed with code %d", rc)
+	}
+	return err
+}
+
+// Command calls the handle_command export
+func (h *Harness) Command(name string, params map[string]any) plugin.Response {
+	var res plugin.Response
+	if _, err := h.invokeWire("handle_command", plugin.Command{Name: name, Params: params}, &res); err != nil {
+		return plugin.ErrorResponse(err)
+	}
+	return res
+}
+
+// StreamMessage calls the handle_stream_message export
+func (h *Harness) StreamMessage(req plugin.StreamMessageRequest) plugin.StreamMessageResponse {
+	var res plugin.StreamMessageResponse
+	if _, err := h.invokeWire("handle_stream_message", req, &res); err != nil {
+		return plugin.StreamMessageResponse{Action: "ignore", Error: err.Error()}
+	}
+	return res
+}
+
+// ConnectionEvent calls the handle_connection_event export
+func (h *Harness) ConnectionEvent(event plugin.StreamConnectionEvent) plugin.StreamConnectionResponse {
+	var res plugin.StreamConnectionResponse
+	if _, err := h.invokeWire("handle_connection_event", event, &res); err != nil {
+		return plugin.StreamConnectionResponse{Action: "ignore", Error: err.Error()}
+	}
+	return res
+}
+
+// Shutdown calls the shutdown export
+func (h *Harness) Shutdown() error {
+	rc, err := h.invoke("shutdown", nil, nil)
+	if err == nil && rc != 0 {
+		err = fmt.Errorf("shutdown failed with code %d", rc)
+	}
+	return err
+}
+
+// Invoke runs the named export with raw input and returns its exit code and
+// output, like extism's Plugin.Call
+func (h *Harness) Invoke(name string, input []byte) (uint32, []byte, error) {
+	fn, ok := host.Export(name)
+	if !ok {
+		return 0, nil, fmt.Errorf("export %s is not registered", name)
+	}
+
+	host.ConfigChanged()
+	h.input, h.output, h.err = input, nil, nil
+	rc := uint32(fn())
+	return rc, h.output, h.err
+}
+
+// invokeWire calls an export whose payloads use the wire format advertised
+// in the harness capabilities
+func (h *Harness) invokeWire(name string, input any, out any) (uint32, error) {
+	if hostinfo.WireFormat() != hostinfo.WireMsgpack {
+		return h.invoke(name, input, out)
+	}
+
+	data, err := msgpack.Marshal(input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal %s input: %w", name, err)
+	}
+	rc, output, err := h.Invoke(name, data)
+	if err != nil {
+		return rc, err
+	}
+	if len(output) > 0 {
+		if err := msgpack.Unmarshal(output, out); err != nil {
+			return rc, fmt.Errorf("failed to unmarshal %s output: %w", name, err)
+		}
+	}
+	return rc, nil
+}
+
+// invoke runs the named export with input as JSON and decodes its output into out
+func (h *Harness) invoke(name string, input any, out any) (uint32, error) {
+	data := []byte("null")
+	if input != nil {
+		var err error
+		if data, err = json.Marshal(input); err != nil {
+			return 0, fmt.Errorf("failed to marshal %s input: %w", name, err)
+		}
+	}
+
+	rc, output, err := h.Invoke(name, data)
+	if err != nil {
+		return rc, err
+	}
+	if out != nil && len(output) > 0 {
+		if err := json.Unmarshal(output, out); err != nil {
+			return rc, fmt.Errorf("failed to unmarshal %s output: %w", name, err)
+		}
+	}
+	return rc, nil
+}
@@ -0,0 +1,126 @@
+//go:build !wasm
+
+package plugintest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/logging"
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/requester"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+type pingPlugin struct {
+	apiKey string
+}
+
+func (p *pingPlugin) GetMeta() m.Meta {
+	return m.Meta{PluginID: "ping", Name: "Ping", AppID: "test", Version: "1.0.0"}
+}
+
+func (p *pingPlugin) GetConfigFields() []plugin.ConfigField { return nil }
+func (p *pingPlugin) GetRateLimits() []plugin.RateLimit     { return nil }
+func (p *pingPlugin) OnShutdown() error                     { return nil }
+
+func (p *pingPlugin) OnInit(config *plugin.ConfigStore) error {
+	p.apiKey = config.GetString("apiKey")
+	return nil
+}
+
+func (p *pingPlugin) RegisterCommands(router *plugin.CommandRouter) {
+	router.Register("ping", func(params map[string]any) plugin.Response {
+		var body struct {
+			Pong bool `json:"pong"`
+		}
+		req := &rt.Request{Method: "GET", URL: "https://api.example.com/ping", Headers: map[string]string{"X-Key": p.apiKey}}
+		if _, err := requester.NewRequester().Send(req, &body); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+
+		var download bytes.Buffer
+		dl := &rt.Request{Method: "GET", URL: "https://api.example.com/dump"}
+		if _, err := requester.NewRequester().SendStream(dl, func(chunk []byte) error {
+			download.Write(chunk)
+			return nil
+		}); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+
+		logging.NewLogger("ping").Info("pinged")
+		return plugin.SuccessResponse(map[string]any{
+			"pong": body.Pong,
+			"dump": download.String(),
+			"at":   requester.HostNow(),
+		})
+	})
+}
+
+func TestHarnessRunsCommands(t *testing.T) {
+	plugin.RegisterPlugin(&pingPlugin{})
+	h := New(t)
+	h.Mock.On("/ping").Header("X-Key", "secret").Respond(`{"pong": true}`)
+	h.Mock.SetMockResponse("/dump", `"0123456789"`)
+	h.SetNow(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	meta, err := h.Meta()
+	if err != nil {
+		t.Fatalf("meta failed: %v", err)
+	}
+	if len(meta.Features) != 1 || meta.Features[0] != "ping" {
+		t.Errorf("expected registered command in features, got %v", meta.Features)
+	}
+	if meta.ProtocolVersion != plugin.ProtocolVersion {
+		t.Errorf("expected protocol version %d, got %d", plugin.ProtocolVersion, meta.ProtocolVersion)
+	}
+
+	if err := h.Init(map[string]any{"apiKey": "secret"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	res := h.Command("ping", nil)
+	if !res.Result {
+		t.Fatalf("ping failed: %s", res.Error)
+	}
+	data := res.Data.(map[string]any)
+	if data["pong"] != true {
+		t.Errorf("expected pong, got %v", data["pong"])
+	}
+	if data["dump"] != `"0123456789"` {
+		t.Errorf("unexpected streamed body %v", data["dump"])
+	}
+	if data["at"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("expected host time, got %v", data["at"])
+	}
+
+	logs := h.Logs()
+	if len(logs) != 1 || logs[0].Message != "pinged" {
+		t.Errorf("expected one log record, got %+v", logs)
+	}
+
+	if res := h.Command("unknown", nil); res.Result {
+		t.Error("expected unknown command to fail")
+	}
+}
+
+func TestHarnessMsgpackWire(t *testing.T) {
+	plugin.RegisterPlugin(&pingPlugin{})
+	h := New(t)
+	caps := hostinfo.Full()
+	caps.WireFormat = hostinfo.WireMsgpack
+	h.SetCapabilities(caps)
+	h.Mock.On("/ping").Respond(`{"pong": true}`)
+	h.Mock.SetMockResponse("/dump", `"x"`)
+
+	res := h.Command("ping", nil)
+	if !res.Result {
+		t.Fatalf("ping failed: %s", res.Error)
+	}
+	if data := res.Data.(map[string]any); data["pong"] != true || data["dump"] != `"x"` {
+		t.Errorf("unexpected data %v", data)
+	}
+}
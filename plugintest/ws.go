@@ -0,0 +1,247 @@
+//go:build !wasm
+
+package plugintest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/stream"
+)
+
+// WSServer is the remote end of plugin-owned WebSocket connections
+// (stream.Dial). Register it in Harness.WebSockets under its URL, queue
+// inbound messages with Push and inspect what the plugin sent with Sent.
+//
+// ws_receive answers immediately: with the queued messages, or with none if
+// the queue is empty, as if the poll timeout passed.
+type WSServer struct {
+	mu        sync.Mutex
+	conn      *wsConn
+	dials     int
+	failDials int
+	sent      []stream.WSMessage
+	headers   map[string]string
+}
+
+type wsConn struct {
+	server *WSServer
+	inbox  []stream.WSMessage
+	closed bool
+	code   int
+	reason string
+}
+
+// NewWSServer creates a server accepting every dial
+func NewWSServer() *WSServer {
+	return &WSServer{}
+}
+
+// Push queues a text message for the current connection
+func (s *WSServer) Push(message string) {
+	s.push(stream.WSMessage{Data: []byte(message)})
+}
+
+// PushBinary queues a binary message for the current connection
+func (s *WSServer) PushBinary(data []byte) {
+	s.push(stream.WSMessage{Data: data, Binary: true})
+}
+
+// PushJSON marshals v and queues it as text message
+func (s *WSServer) PushJSON(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("plugintest: marshal ws message: %v", err))
+	}
+	s.push(stream.WSMessage{Data: data})
+}
+
+func (s *WSServer) push(msg stream.WSMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil || s.conn.closed {
+		panic("plugintest: push to a websocket without open connection")
+	}
+	s.conn.inbox = append(s.conn.inbox, msg)
+}
+
+// Drop closes the current connection from the server side. Queued messages
+// are still delivered before the plugin sees the close.
+func (s *WSServer) Drop(code int, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.closed, s.conn.code, s.conn.reason = true, code, reason
+	}
+}
+
+// FailDials makes the next n dials fail
+func (s *WSServer) FailDials(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failDials = n
+}
+
+// Dials returns the number of successful dials
+func (s *WSServer) Dials() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dials
+}
+
+// CloseStatus returns the close code and reason of the current connection,
+// zero while it is open
+func (s *WSServer) CloseStatus() (code int, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil || !s.conn.closed {
+		return 0, ""
+	}
+	return s.conn.code, s.conn.reason
+}
+
+// Connected reports whether a connection is open
+func (s *WSServer) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn != nil && !s.conn.closed
+}
+
+// Headers returns the headers of the last dial
+func (s *WSServer) Headers() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers
+}
+
+// Sent returns the text of all messages the plugin sent, across connections
+func (s *WSServer) Sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sent := make([]string, len(s.sent))
+	for i, msg := range s.sent {
+		sent[i] = string(msg.Data)
+	}
+	return sent
+}
+
+func (h *Harness) wsConnect(input []byte) []byte {
+	var req stream.WSConnectRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(stream.WSConnectResponse{Error: err.Error()})
+	}
+
+	h.mu.Lock()
+	server, ok := h.WebSockets[req.URL]
+	h.mu.Unlock()
+	if !ok {
+		return mustJSON(stream.WSConnectResponse{Error: "no websocket server at " + req.URL})
+	}
+
+	server.mu.Lock()
+	if server.failDials > 0 {
+		server.failDials--
+		server.mu.Unlock()
+		return mustJSON(stream.WSConnectResponse{Error: "connection refused"})
+	}
+	if server.conn != nil {
+		server.conn.closed = true
+	}
+	conn := &wsConn{server: server}
+	server.conn = conn
+	server.dials++
+	server.headers = req.Headers
+	server.mu.Unlock()
+
+	h.mu.Lock()
+	h.nextID++
+	id := fmt.Sprintf("ws-%d", h.nextID)
+	h.wsConns[id] = conn
+	h.mu.Unlock()
+
+	var subprotocol string
+	if len(req.Subprotocols) > 0 {
+		subprotocol = req.Subprotocols[0]
+	}
+	return mustJSON(stream.WSConnectResponse{ConnectionID: id, Subprotocol: subprotocol})
+}
+
+func (h *Harness) wsConn(id string) (*wsConn, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conn, ok := h.wsConns[id]
+	if !ok {
+		return nil, errors.New("unknown connection " + id)
+	}
+	return conn, nil
+}
+
+func (h *Harness) wsSend(input []byte) []byte {
+	var req stream.WSSendRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(stream.WSResult{Error: err.Error()})
+	}
+	conn, err := h.wsConn(req.ConnectionID)
+	if err != nil {
+		return mustJSON(stream.WSResult{Error: err.Error()})
+	}
+
+	s := conn.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if conn.closed {
+		return mustJSON(stream.WSResult{Error: "connection closed"})
+	}
+	s.sent = append(s.sent, stream.WSMessage{Data: req.Data, Binary: req.Binary})
+	return mustJSON(stream.WSResult{})
+}
+
+func (h *Harness) wsReceive(input []byte) []byte {
+	var req stream.WSReceiveRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(stream.WSReceiveResponse{Error: err.Error()})
+	}
+	conn, err := h.wsConn(req.ConnectionID)
+	if err != nil {
+		return mustJSON(stream.WSReceiveResponse{Error: err.Error()})
+	}
+
+	s := conn.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(conn.inbox)
+	if req.MaxMessages > 0 {
+		n = min(n, req.MaxMessages)
+	}
+	res := stream.WSReceiveResponse{Messages: conn.inbox[:n]}
+	conn.inbox = conn.inbox[n:]
+	if conn.closed && len(conn.inbox) == 0 {
+		res.Closed, res.CloseCode, res.CloseReason = true, conn.code, conn.reason
+	}
+	return mustJSON(res)
+}
+
+func (h *Harness) wsClose(input []byte) []byte {
+	var req stream.WSCloseRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return mustJSON(stream.WSResult{Error: err.Error()})
+	}
+	conn, err := h.wsConn(req.ConnectionID)
+	if err != nil {
+		return mustJSON(stream.WSResult{Error: err.Error()})
+	}
+
+	s := conn.server
+	s.mu.Lock()
+	if !conn.closed {
+		conn.closed, conn.code, conn.reason = true, req.Code, req.Reason
+	}
+	s.mu.Unlock()
+
+	h.mu.Lock()
+	delete(h.wsConns, req.ConnectionID)
+	h.mu.Unlock()
+	return mustJSON(stream.WSResult{})
+}
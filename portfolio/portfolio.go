@@ -0,0 +1,273 @@
+// Package portfolio tracks holdings with cost basis and values them against
+// latest prices. Its JSON types are the response data of the getPortfolio
+// command, so the host can aggregate portfolios of different plugins.
+//
+// Amounts are strings in the JSON types to preserve precision, as in the
+// trading package; calculations use float64.
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+const CMD_GET_PORTFOLIO = "getPortfolio"
+
+// CostBasisMethod selects how sells are matched against earlier buys
+type CostBasisMethod string
+
+const (
+	FIFO    CostBasisMethod = "fifo"    // Sells consume the oldest lots first
+	Average CostBasisMethod = "average" // All units share one average cost
+)
+
+// Transaction is a buy or sell of an asset
+type Transaction struct {
+	Asset         string `json:"asset"`
+	QuoteCurrency string `json:"quoteCurrency"` // Currency of Price and Fee
+	Side          string `json:"side"`          // trading.SideBuy or trading.SideSell
+	Quantity      string `json:"quantity"`
+	Price         string `json:"price"`
+	Fee           string `json:"fee,omitempty"`
+	Timestamp     int64  `json:"timestamp"` // Unix timestamp in milliseconds
+}
+
+// Lot is an open buy of a FIFO holding. Price includes the buy fee.
+type Lot struct {
+	Quantity  string `json:"quantity"`
+	Price     string `json:"price"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Holding is the position in one asset, per quote currency
+type Holding struct {
+	Asset         string `json:"asset"`
+	QuoteCurrency string `json:"quoteCurrency"`
+	Quantity      string `json:"quantity"`
+	CostBasis     string `json:"costBasis"` // Total cost of the held quantity, fees included
+	AverageCost   string `json:"averageCost"`
+	RealizedPnL   string `json:"realizedPnl"`
+	Lots          []Lot  `json:"lots,omitempty"` // FIFO only
+
+	// Set by Value
+	Price         string `json:"price,omitempty"`
+	Value         string `json:"value,omitempty"`
+	UnrealizedPnL string `json:"unrealizedPnl,omitempty"`
+}
+
+// Totals sums the holdings of one quote currency
+type Totals struct {
+	Value         string `json:"value"`
+	CostBasis     string `json:"costBasis"`
+	UnrealizedPnL string `json:"unrealizedPnl"`
+	RealizedPnL   string `json:"realizedPnl"`
+}
+
+// Valuation is the response data of the getPortfolio command
+type Valuation struct {
+	Method   CostBasisMethod   `json:"method"`
+	Holdings []Holding         `json:"holdings"`
+	Totals   map[string]Totals `json:"totals"` // Keyed by quote currency
+	AsOf     int64             `json:"asOf"`   // Unix timestamp in milliseconds
+}
+
+// Prices are the latest prices keyed by quote currency, then asset
+type Prices map[string]map[string]string
+
+// Set stores the price of asset in quote
+func (p Prices) Set(asset, quote, price string) {
+	if p[quote] == nil {
+		p[quote] = make(map[string]string)
+	}
+	p[quote][asset] = price
+}
+
+type lot struct {
+	qty   float64
+	price float64
+	ts    int64
+}
+
+type position struct {
+	asset    string
+	quote    string
+	qty      float64
+	cost     float64
+	realized float64
+	lots     []lot
+}
+
+// Portfolio tracks holdings from transactions
+type Portfolio struct {
+	method    CostBasisMethod
+	positions map[string]*position
+}
+
+// New creates an empty portfolio using method for cost basis
+func New(method CostBasisMethod) *Portfolio {
+	return &Portfolio{method: method, positions: make(map[string]*position)}
+}
+
+// Apply books a transaction. Selling more than is held is an error.
+func (p *Portfolio) Apply(tx Transaction) error {
+	qty, err := parse("quantity", tx.Quantity)
+	if err != nil {
+		return err
+	}
+	price, err := parse("price", tx.Price)
+	if err != nil {
+		return err
+	}
+	fee := 0.0
+	if tx.Fee != "" {
+		if fee, err = parse("fee", tx.Fee); err != nil {
+			return err
+		}
+	}
+	if qty <= 0 {
+		return fmt.Errorf("quantity must be positive, got %s", tx.Quantity)
+	}
+
+	key := tx.Asset + "/" + tx.QuoteCurrency
+	pos, ok := p.positions[key]
+	if !ok {
+		pos = &position{asset: tx.Asset, quote: tx.QuoteCurrency}
+		p.positions[key] = pos
+	}
+
+	switch tx.Side {
+	case tt.SideBuy:
+		pos.qty += qty
+		pos.cost += price*qty + fee
+		if p.method == FIFO {
+			pos.lots = append(pos.lots, lot{qty: qty, price: (price*qty + fee) / qty, ts: tx.Timestamp})
+		}
+	case tt.SideSell:
+		if qty > pos.qty+1e-12 {
+			return fmt.Errorf("cannot sell %s %s, holding %g", tx.Quantity, tx.Asset, pos.qty)
+		}
+		sold := p.consume(pos, qty)
+		pos.realized += price*qty - fee - sold
+		pos.qty -= qty
+		pos.cost -= sold
+		if pos.qty < 1e-12 {
+			pos.qty, pos.cost = 0, 0
+		}
+	default:
+		return fmt.Errorf("invalid side %q", tx.Side)
+	}
+	return nil
+}
+
+// consume removes qty from pos and returns its cost basis
+func (p *Portfolio) consume(pos *position, qty float64) float64 {
+	if p.method != FIFO {
+		return pos.cost / pos.qty * qty
+	}
+
+	cost := 0.0
+	for qty > 1e-12 && len(pos.lots) > 0 {
+		l := &pos.lots[0]
+		take := min(qty, l.qty)
+		cost += take * l.price
+		l.qty -= take
+		qty -= take
+		if l.qty < 1e-12 {
+			pos.lots = pos.lots[1:]
+		}
+	}
+	return cost
+}
+
+// Holdings returns the holdings sorted by asset and quote currency, including
+// closed positions with realized PnL
+func (p *Portfolio) Holdings() []Holding {
+	holdings := make([]Holding, 0, len(p.positions))
+	for _, pos := range p.positions {
+		h := Holding{
+			Asset:         pos.asset,
+			QuoteCurrency: pos.quote,
+			Quantity:      format(pos.qty),
+			CostBasis:     format(pos.cost),
+			AverageCost:   "0",
+			RealizedPnL:   format(pos.realized),
+		}
+		if pos.qty > 0 {
+			h.AverageCost = format(pos.cost / pos.qty)
+		}
+		for _, l := range pos.lots {
+			h.Lots = append(h.Lots, Lot{Quantity: format(l.qty), Price: format(l.price), Timestamp: l.ts})
+		}
+		holdings = append(holdings, h)
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].Asset != holdings[j].Asset {
+			return holdings[i].Asset < holdings[j].Asset
+		}
+		return holdings[i].QuoteCurrency < holdings[j].QuoteCurrency
+	})
+	return holdings
+}
+
+// Value values the holdings at prices. Holdings without a price keep empty
+// valuation fields and count with their cost basis in the totals.
+func (p *Portfolio) Value(prices Prices, asOf int64) (Valuation, error) {
+	type sums struct{ value, cost, unrealized, realized float64 }
+	totals := make(map[string]*sums)
+
+	holdings := p.Holdings()
+	for i := range holdings {
+		h := &holdings[i]
+		pos := p.positions[h.Asset+"/"+h.QuoteCurrency]
+
+		s, ok := totals[h.QuoteCurrency]
+		if !ok {
+			s = &sums{}
+			totals[h.QuoteCurrency] = s
+		}
+		s.cost += pos.cost
+		s.realized += pos.realized
+
+		raw, ok := prices[h.QuoteCurrency][h.Asset]
+		if !ok {
+			s.value += pos.cost
+			continue
+		}
+		price, err := parse("price of "+h.Asset, raw)
+		if err != nil {
+			return Valuation{}, err
+		}
+		value := price * pos.qty
+		h.Price = raw
+		h.Value = format(value)
+		h.UnrealizedPnL = format(value - pos.cost)
+		s.value += value
+		s.unrealized += value - pos.cost
+	}
+
+	v := Valuation{Method: p.method, Holdings: holdings, Totals: make(map[string]Totals, len(totals)), AsOf: asOf}
+	for quote, s := range totals {
+		v.Totals[quote] = Totals{
+			Value:         format(s.value),
+			CostBasis:     format(s.cost),
+			UnrealizedPnL: format(s.unrealized),
+			RealizedPnL:   format(s.realized),
+		}
+	}
+	return v, nil
+}
+
+func parse(name, s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,39 @@
+package portfolio
+
+import "testing"
+
+func TestCostBasisMethods(t *testing.T) {
+	txs := []Transaction{
+		{Asset: "BTC", QuoteCurrency: "USD", Side: "buy", Quantity: "1", Price: "100"},
+		{Asset: "BTC", QuoteCurrency: "USD", Side: "buy", Quantity: "1", Price: "200"},
+		{Asset: "BTC", QuoteCurrency: "USD", Side: "sell", Quantity: "1", Price: "250"},
+	}
+
+	for method, wantRealized := range map[CostBasisMethod]string{FIFO: "150", Average: "100"} {
+		p := New(method)
+		for _, tx := range txs {
+			if err := p.Apply(tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		prices := Prices{}
+		prices.Set("BTC", "USD", "300")
+		v, err := p.Value(prices, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := v.Holdings[0]
+		if h.Quantity != "1" || h.RealizedPnL != wantRealized || h.Value != "300" {
+			t.Fatalf("%s: unexpected holding %+v", method, h)
+		}
+		if v.Totals["USD"].RealizedPnL != wantRealized {
+			t.Fatalf("%s: unexpected totals %+v", method, v.Totals)
+		}
+	}
+
+	p := New(FIFO)
+	if err := p.Apply(Transaction{Asset: "ETH", QuoteCurrency: "USD", Side: "sell", Quantity: "1", Price: "1"}); err == nil {
+		t.Fatal("expected selling without holding to fail")
+	}
+}
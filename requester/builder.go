@@ -0,0 +1,201 @@
+package requester
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// RequestBuilder builds an rt.Request step by step, taking care of URL and
+// query encoding. Errors are collected and returned by Build.
+//
+// Example:
+//
+//	req, err := requester.NewRequest("GET", "https://api.binance.com").
+//	    Path("/api/v3/klines").
+//	    Query("symbol", "BTCUSDT").
+//	    Query("interval", "1m").
+//	    Build()
+type RequestBuilder struct {
+	method       string
+	base         string
+	path         string
+	query        url.Values
+	headers      map[string]string
+	body         []byte
+	timeout      time.Duration
+	follow       *bool
+	maxRedirects int
+	cookieJar    string
+	err          error
+}
+
+// NewRequest starts building a request for method against the base URL
+func NewRequest(method, base string) *RequestBuilder {
+	return &RequestBuilder{
+		method:  strings.ToUpper(method),
+		base:    base,
+		query:   url.Values{},
+		headers: make(map[string]string),
+	}
+}
+
+// Path appends a path to the base URL, separated by a single slash. The path
+// is used as-is, so callers must escape dynamic segments with url.PathEscape.
+func (b *RequestBuilder) Path(path string) *RequestBuilder {
+	b.path += path
+	return b
+}
+
+// Query adds a query parameter. Values are escaped, so symbols like
+// "BTC/USDT" are sent correctly.
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// QueryIf adds a query parameter only if the condition is true
+func (b *RequestBuilder) QueryIf(condition bool, key, value string) *RequestBuilder {
+	if condition {
+		b.query.Add(key, value)
+	}
+	return b
+}
+
+// Header sets a request header
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.headers[key] = value
+	return b
+}
+
+// BearerAuth sets the Authorization header to a bearer token
+func (b *RequestBuilder) BearerAuth(token string) *RequestBuilder {
+	return b.Header("Authorization", "Bearer "+token)
+}
+
+// BasicAuth sets the Authorization header for HTTP basic auth
+func (b *RequestBuilder) BasicAuth(username, password string) *RequestBuilder {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return b.Header("Authorization", "Basic "+credentials)
+}
+
+// AcceptCompressed asks the server for a gzip or deflate encoded response.
+// The requester decompresses the body transparently.
+func (b *RequestBuilder) AcceptCompressed() *RequestBuilder {
+	return b.Header("Accept-Encoding", rt.AcceptCompressed)
+}
+
+// Body sets a raw body with its content type
+func (b *RequestBuilder) Body(body []byte, contentType string) *RequestBuilder {
+	b.body = body
+	if contentType != "" {
+		b.headers["Content-Type"] = contentType
+	}
+	return b
+}
+
+// JSONBody marshals v as the request body and sets the JSON content type
+func (b *RequestBuilder) JSONBody(v any) *RequestBuilder {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.err = fmt.Errorf("failed to marshal request body: %w", err)
+		return b
+	}
+	return b.Body(data, "application/json")
+}
+
+// FormBody sets an application/x-www-form-urlencoded body
+func (b *RequestBuilder) FormBody(values url.Values) *RequestBuilder {
+	return b.Body([]byte(values.Encode()), "application/x-www-form-urlencoded")
+}
+
+// MultipartBody sets a multipart/form-data body from fields and files
+func (b *RequestBuilder) MultipartBody(fields map[string]string, files ...rt.MultipartFile) *RequestBuilder {
+	var tmp rt.Request
+	if err := tmp.SetMultipartBody(fields, files...); err != nil {
+		b.err = err
+		return b
+	}
+	return b.Body(tmp.Body, tmp.Headers["Content-Type"])
+}
+
+// Timeout bounds how long the host waits for the response
+func (b *RequestBuilder) Timeout(timeout time.Duration) *RequestBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// NoRedirects makes the host return 3xx responses instead of following them.
+// Signed requests should use this, since a redirect would replay the signature
+// against a different URL.
+func (b *RequestBuilder) NoRedirects() *RequestBuilder {
+	follow := false
+	b.follow = &follow
+	return b
+}
+
+// MaxRedirects limits the number of redirects the host follows
+func (b *RequestBuilder) MaxRedirects(n int) *RequestBuilder {
+	b.maxRedirects = n
+	return b
+}
+
+// CookieJar stores and sends cookies via the named host-managed jar
+func (b *RequestBuilder) CookieJar(name string) *RequestBuilder {
+	b.cookieJar = name
+	return b
+}
+
+// Build assembles the request. A query already in the base URL is kept
+// byte for byte, so signatures over it stay valid; parameters added with
+// Query are appended after it. The builder can be reused, later calls don't
+// change requests built before.
+func (b *RequestBuilder) Build() (*rt.Request, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	u, err := url.Parse(b.base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request url: %w", err)
+	}
+
+	path := u.EscapedPath()
+	if b.path != "" {
+		path = strings.TrimSuffix(path, "/") + "/" + strings.TrimPrefix(b.path, "/")
+	}
+	query := u.RawQuery
+	if extra := b.query.Encode(); extra != "" {
+		if query != "" {
+			query += "&"
+		}
+		query += extra
+	}
+
+	u.Path, u.RawPath, u.RawQuery, u.Fragment = "", "", "", ""
+	raw := u.String() + path
+	if query != "" {
+		raw += "?" + query
+	}
+	if _, err := url.Parse(raw); err != nil {
+		return nil, fmt.Errorf("invalid request url: %w", err)
+	}
+
+	return &rt.Request{
+		URL:     raw,
+		Method:  b.method,
+		Headers: maps.Clone(b.headers),
+		Body:    b.body,
+
+		TimeoutMs:       int(b.timeout.Milliseconds()),
+		FollowRedirects: b.follow,
+		MaxRedirects:    b.maxRedirects,
+		CookieJar:       b.cookieJar,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package requester_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/requester"
+)
+
+func TestRequestBuilderURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *requester.RequestBuilder
+		want    string
+	}{
+		{"path without slash", requester.NewRequest("GET", "https://api.example.com").Path("api/v3/klines"), "https://api.example.com/api/v3/klines"},
+		{"base with slash", requester.NewRequest("GET", "https://api.example.com/").Path("/api/v3/klines"), "https://api.example.com/api/v3/klines"},
+		{"base with path", requester.NewRequest("GET", "https://api.example.com/v2").Path("orders"), "https://api.example.com/v2/orders"},
+		{"no path", requester.NewRequest("GET", "https://api.example.com/v2"), "https://api.example.com/v2"},
+		{"escaped segment", requester.NewRequest("GET", "https://api.example.com").Path("/markets/" + url.PathEscape("BTC/USDT")), "https://api.example.com/markets/BTC%2FUSDT"},
+		{"query", requester.NewRequest("GET", "https://api.example.com").Path("/ticker").Query("symbol", "BTC/USDT"), "https://api.example.com/ticker?symbol=BTC%2FUSDT"},
+		{
+			"base query kept",
+			requester.NewRequest("GET", "https://api.example.com/account?timestamp=1&recvWindow=5000&signature=ab%2Bc").Query("limit", "10"),
+			"https://api.example.com/account?timestamp=1&recvWindow=5000&signature=ab%2Bc&limit=10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.URL != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, req.URL)
+			}
+		})
+	}
+
+	if _, err := requester.NewRequest("GET", "://bad").Build(); err == nil {
+		t.Error("expected an invalid base url to fail")
+	}
+}
+
+func TestRequestBuilderHeadersCopied(t *testing.T) {
+	b := requester.NewRequest("POST", "https://api.example.com").BearerAuth("first").JSONBody(map[string]int{"n": 1})
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.BearerAuth("second").Header("X-Extra", "1").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Headers["Authorization"] != "Bearer first" || first.Headers["X-Extra"] != "" {
+		t.Errorf("built request changed by later builder calls: %v", first.Headers)
+	}
+	if second.Headers["Authorization"] != "Bearer second" || second.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected headers %v", second.Headers)
+	}
+	if first.Method != "POST" || string(first.Body) != `{"n":1}` {
+		t.Errorf("unexpected request %+v", first)
+	}
+}
@@ -0,0 +1,158 @@
+package conditional
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// ErrNotModified is returned by Doer.Send when the server answered 304 Not
+// Modified. The caller should keep using the data it fetched previously.
+var ErrNotModified = errors.New("not modified")
+
+// Validators are the cache validators a server returned for a URL
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Body         []byte `json:"body,omitempty"` // Body of the answer, kept by Doer.CacheBodies
+}
+
+// IsZero reports whether no validator is set
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Store keeps validators per URL
+type Store interface {
+	Get(url string) (Validators, bool)
+	Set(url string, v Validators)
+}
+
+// MemoryStore is an in-memory Store, suitable for the lifetime of a plugin instance
+type MemoryStore struct {
+	mu         sync.Mutex
+	validators map[string]Validators
+}
+
+// NewMemoryStore creates an empty in-memory validator store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		validators: make(map[string]Validators),
+	}
+}
+
+// Get returns the validators stored for url
+func (s *MemoryStore) Get(url string) (Validators, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.validators[url]
+	return v, ok
+}
+
+// Set stores the validators for url
+func (s *MemoryStore) Set(url string, v Validators) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.validators[url] = v
+}
+
+// Doer is a RequestDoer that sends conditional GET requests
+//
+// Example:
+//
+//	doer := conditional.NewDoer(requester.NewRequester(), conditional.NewMemoryStore())
+//	_, err := doer.Send(req, &instruments)
+//	if errors.Is(err, conditional.ErrNotModified) {
+//	    return cachedMarkets
+//	}
+type Doer struct {
+	next        rt.RequestDoer
+	store       Store
+	cacheBodies bool
+}
+
+// NewDoer wraps next so GET requests carry If-None-Match/If-Modified-Since
+// headers from store
+func NewDoer(next rt.RequestDoer, store Store) *Doer {
+	return &Doer{
+		next:  next,
+		store: store,
+	}
+}
+
+// CacheBodies makes the doer keep the body of each answer with its
+// validators and serve it on a 304: v is decoded from the cached body and
+// no error is returned; the response keeps status 304 so callers can still
+// skip reprocessing.
+func (d *Doer) CacheBodies() *Doer {
+	d.cacheBodies = true
+	return d
+}
+
+// Send sends the request, adding stored validators for GET and HEAD requests.
+// A 304 answer is returned together with ErrNotModified and v is left
+// untouched, unless CacheBodies is enabled.
+func (d *Doer) Send(req *rt.Request, v any) (*rt.Response, error) {
+	method := strings.ToUpper(req.Method)
+	if method != http.MethodGet && method != http.MethodHead {
+		return d.next.Send(req, v)
+	}
+
+	conditional := *req
+	conditional.Headers = make(map[string]string, len(req.Headers)+2)
+	for k, val := range req.Headers {
+		conditional.Headers[k] = val
+	}
+	validators, cached := d.store.Get(req.URL)
+	if cached {
+		if validators.ETag != "" {
+			conditional.Headers["If-None-Match"] = validators.ETag
+		}
+		if validators.LastModified != "" {
+			conditional.Headers["If-Modified-Since"] = validators.LastModified
+		}
+	}
+
+	// Decode ourselves: a 304 has no body to unmarshal
+	res, err := d.next.Send(&conditional, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status == http.StatusNotModified {
+		if !d.cacheBodies || !cached || validators.Body == nil {
+			return res, ErrNotModified
+		}
+		res.Body = slices.Clone(validators.Body)
+	} else if res.Status >= 200 && res.Status < 300 {
+		validators := Validators{
+			ETag:         res.Headers.Get("ETag"),
+			LastModified: res.Headers.Get("Last-Modified"),
+		}
+		if !validators.IsZero() {
+			if d.cacheBodies {
+				validators.Body = res.Body
+			}
+			d.store.Set(req.URL, validators)
+		}
+	}
+
+	if v != nil {
+		if err := res.DecodeJSON(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Middleware returns a requester middleware that sends conditional requests, see NewDoer
+func Middleware(store Store) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return NewDoer(next, store)
+	}
+}
@@ -0,0 +1,88 @@
+package conditional
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// etagServer answers 304 when If-None-Match carries its ETag, else 200
+type etagServer struct {
+	etag     string
+	body     string
+	requests []rt.Request
+}
+
+func (s *etagServer) Send(req *rt.Request, _ any) (*rt.Response, error) {
+	s.requests = append(s.requests, *req)
+	if req.Headers["If-None-Match"] == s.etag {
+		return &rt.Response{Status: http.StatusNotModified, Headers: http.Header{"Etag": {s.etag}}}, nil
+	}
+	return &rt.Response{
+		Status:  http.StatusOK,
+		Headers: http.Header{"Etag": {s.etag}, "Last-Modified": {"Mon, 01 Jan 2024 00:00:00 GMT"}},
+		Body:    []byte(s.body),
+	}, nil
+}
+
+func TestDoerConditionalGet(t *testing.T) {
+	server := &etagServer{etag: `"v1"`, body: `{"markets":["BTCUSDT"]}`}
+	store := NewMemoryStore()
+	doer := NewDoer(server, store)
+	req := &rt.Request{Method: "GET", URL: "https://api.example.com/markets"}
+
+	var first struct{ Markets []string }
+	if _, err := doer.Send(req, &first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Markets) != 1 {
+		t.Fatalf("expected decoded body, got %+v", first)
+	}
+	stored, ok := store.Get(req.URL)
+	if !ok || stored.ETag != `"v1"` || stored.LastModified == "" {
+		t.Fatalf("expected validators of the 200 to be stored, got %+v", stored)
+	}
+	if stored.Body != nil {
+		t.Error("expected no body to be kept by default")
+	}
+
+	var second struct{ Markets []string }
+	res, err := doer.Send(req, &second)
+	if !errors.Is(err, ErrNotModified) || res.Status != http.StatusNotModified {
+		t.Fatalf("expected ErrNotModified, got %v", err)
+	}
+	if second.Markets != nil {
+		t.Errorf("expected v untouched on 304, got %+v", second)
+	}
+	sent := server.requests[1].Headers
+	if sent["If-None-Match"] != `"v1"` || sent["If-Modified-Since"] != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("expected validators on the second request, got %v", sent)
+	}
+	if _, ok := req.Headers["If-None-Match"]; ok {
+		t.Error("expected the caller's request to be left alone")
+	}
+}
+
+func TestDoerServesCachedBody(t *testing.T) {
+	server := &etagServer{etag: `"v1"`, body: `{"markets":["BTCUSDT"]}`}
+	doer := NewDoer(server, NewMemoryStore()).CacheBodies()
+	req := &rt.Request{Method: "GET", URL: "https://api.example.com/markets"}
+
+	if _, err := doer.Send(req, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cached struct{ Markets []string }
+	res, err := doer.Send(req, &cached)
+	if err != nil {
+		t.Fatalf("expected the cached body, got %v", err)
+	}
+	if res.Status != http.StatusNotModified || string(res.Body) != server.body {
+		t.Errorf("expected 304 with the cached body, got %d %s", res.Status, res.Body)
+	}
+	if len(cached.Markets) != 1 || cached.Markets[0] != "BTCUSDT" {
+		t.Errorf("expected v decoded from the cache, got %+v", cached)
+	}
+}
@@ -0,0 +1,44 @@
+package requester
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// WithCookieJar returns a middleware that routes every request through the
+// named host-managed cookie jar, e.g. to keep a broker login session alive.
+// Hosts without cookie jars would silently drop the session cookies, so
+// requests naming a jar fail there with hostinfo.ErrUnsupported.
+func WithCookieJar(name string) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return rt.DoerFunc(func(req *rt.Request, v any) (*rt.Response, error) {
+			if req.CookieJar == "" {
+				withJar := *req
+				withJar.CookieJar = name
+				req = &withJar
+			}
+			return next.Send(req, v)
+		})
+	}
+}
+
+// ClearCookies drops all cookies of the named jar on the host, e.g. on logout.
+// Hosts without http_clear_cookies keep no jars, so it is a no-op there.
+func ClearCookies(name string) {
+	if !hostinfo.Has(hostinfo.HTTPClearCookies) {
+		return
+	}
+	host.CallHTTPClearCookies([]byte(name))
+}
+
+// requireCookieJars checks that the host keeps cookie jars. Hosts advertise
+// them together with http_clear_cookies.
+func requireCookieJars() error {
+	if err := hostinfo.Require(hostinfo.HTTPClearCookies); err != nil {
+		return fmt.Errorf("cookie jars: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build !wasm
+
+package requester_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/requester"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// cookieServer sets cookies on /login and /expire and echoes the Cookie
+// header on every other path
+func cookieServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Header().Add("Set-Cookie", "session=abc; Path=/api")
+			w.Header().Add("Set-Cookie", "pref=dark; Path=/")
+			w.Header().Add("Set-Cookie", "foreign=1; Path=/; Domain=example.com")
+		case "/expire":
+			w.Header().Add("Set-Cookie", "pref=; Path=/; Expires=Thu, 01 Jan 1970 00:00:00 GMT")
+		default:
+			w.Write([]byte(r.Header.Get("Cookie")))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, r *requester.Requester, url string) string {
+	t.Helper()
+	res, err := r.Send(&rt.Request{Method: "GET", URL: url}, nil)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return string(res.Body)
+}
+
+func TestCookieJar(t *testing.T) {
+	plugintest.New(t)
+	server := cookieServer(t)
+	r := requester.NewRequester().Use(requester.WithCookieJar("broker"))
+
+	get(t, r, server.URL+"/login")
+	if got := get(t, r, server.URL+"/api/orders"); got != "session=abc; pref=dark" {
+		t.Errorf("expected session and pref cookies on /api, got %q", got)
+	}
+	if got := get(t, r, server.URL+"/public"); got != "pref=dark" {
+		t.Errorf("expected only the pref cookie outside /api, got %q", got)
+	}
+	if got := get(t, requester.NewRequester(), server.URL+"/api/orders"); got != "" {
+		t.Errorf("expected no cookies without a jar, got %q", got)
+	}
+
+	get(t, r, server.URL+"/expire")
+	if got := get(t, r, server.URL+"/api/orders"); got != "session=abc" {
+		t.Errorf("expected the expired pref cookie to be dropped, got %q", got)
+	}
+
+	requester.ClearCookies("broker")
+	if got := get(t, r, server.URL+"/api/orders"); got != "" {
+		t.Errorf("expected no cookies after ClearCookies, got %q", got)
+	}
+}
+
+func TestCookieJarRequiresHostSupport(t *testing.T) {
+	h := plugintest.New(t)
+	h.SetCapabilities(hostinfo.Capabilities{ProtocolVersion: 1, Functions: hostinfo.Legacy})
+
+	r := requester.NewRequester().Use(requester.WithCookieJar("broker"))
+	_, err := r.Send(&rt.Request{Method: "GET", URL: "https://api.example.com"}, nil)
+	if !errors.Is(err, hostinfo.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+	if n := len(h.Mock.GetCalls()); n != 0 {
+		t.Errorf("expected no request to reach the host, got %d", n)
+	}
+}
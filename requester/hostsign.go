@@ -0,0 +1,47 @@
+package requester
+
+import (
+	"errors"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/requester/signing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// HostSign asks the host to sign the payload with the referenced credential
+// and returns the raw signature
+func HostSign(req rt.SignRequest) ([]byte, error) {
+	if err := hostinfo.Require(hostinfo.SignRequest); err != nil {
+		return nil, err
+	}
+	var res rt.SignResponse
+	if err := callHostJSON(host.SignRequest, host.CallSignRequest, req, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+
+	return res.Signature, nil
+}
+
+// HostAlgorithm returns a signing.Algorithm backed by the sign_request host
+// function. Use it in place of signing.HMACSHA256 & co. so the plugin only
+// handles a reference to the secret.
+//
+// Example:
+//
+//	scheme := signing.Binance(apiKey, "", 5*time.Second)
+//	scheme.Algorithm = requester.HostAlgorithm("apiSecret", rt.SignHMACSHA256, false)
+func HostAlgorithm(credentialRef string, algorithm rt.SignAlgorithm, secretBase64 bool) signing.Algorithm {
+	return func(payload []byte) ([]byte, error) {
+		return HostSign(rt.SignRequest{
+			CredentialRef: credentialRef,
+			Algorithm:     algorithm,
+			Payload:       payload,
+			SecretBase64:  secretBase64,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// ErrRateLimited is returned by a non-blocking Doer when the host's budget is exhausted
+var ErrRateLimited = errors.New("client-side rate limit exceeded")
+
+// Doer is a RequestDoer that enforces a HostLimiter before sending
+type Doer struct {
+	next    rt.RequestDoer
+	limiter *HostLimiter
+	sleep   func(time.Duration)
+}
+
+// NewDoer wraps next with limiter. If sleep is nil requests over the limit
+// fail with ErrRateLimited, otherwise sleep is called to wait for a token.
+func NewDoer(next rt.RequestDoer, limiter *HostLimiter, sleep func(time.Duration)) *Doer {
+	return &Doer{
+		next:    next,
+		limiter: limiter,
+		sleep:   sleep,
+	}
+}
+
+// Send waits for (or checks) the host's budget and passes the request on
+func (d *Doer) Send(req *rt.Request, v any) (*rt.Response, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request url: %w", err)
+	}
+
+	if d.sleep == nil {
+		if !d.limiter.Allow(u.Host) {
+			return nil, fmt.Errorf("%w for %s", ErrRateLimited, u.Host)
+		}
+	} else if wait := d.limiter.Reserve(u.Host); wait > 0 {
+		d.sleep(wait)
+	}
+
+	return d.next.Send(req, v)
+}
+
+// Middleware returns a requester middleware that enforces limiter, see NewDoer
+func Middleware(limiter *HostLimiter, sleep func(time.Duration)) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return NewDoer(next, limiter, sleep)
+	}
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit configures a token bucket. RPS <= 0 disables limiting.
+type Limit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// HostLimiter is a token bucket rate limiter keyed by host name.
+// A single HostLimiter is meant to be shared by all requesters of a plugin
+// instance so that every code path draws from the same budget.
+type HostLimiter struct {
+	mu           sync.Mutex
+	defaultLimit Limit
+	limits       map[string]Limit
+	buckets      map[string]*bucket
+	now          func() time.Time
+}
+
+// NewHostLimiter creates a limiter applying defaultLimit to every host
+// without an explicit limit. now provides the current time.
+func NewHostLimiter(defaultLimit Limit, now func() time.Time) *HostLimiter {
+	return &HostLimiter{
+		defaultLimit: defaultLimit,
+		limits:       make(map[string]Limit),
+		buckets:      make(map[string]*bucket),
+		now:          now,
+	}
+}
+
+// SetLimit overrides the limit for a single host (e.g. "api.binance.com")
+func (l *HostLimiter) SetLimit(host string, limit Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits[host] = limit
+	delete(l.buckets, host)
+}
+
+// Reserve takes a token for host and returns how long the caller has to wait
+// before the request may be sent. A zero duration means it may be sent now.
+func (l *HostLimiter) Reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, b := l.refill(host)
+	if b == nil {
+		return 0
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / limit.RPS * float64(time.Second))
+}
+
+// Allow takes a token for host only if one is available right now
+func (l *HostLimiter) Allow(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, b := l.refill(host)
+	if b == nil {
+		return true
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill returns the host's limit and bucket with tokens topped up to now.
+// The bucket is nil if the host is not limited.
+func (l *HostLimiter) refill(host string) (Limit, *bucket) {
+	limit, ok := l.limits[host]
+	if !ok {
+		limit = l.defaultLimit
+	}
+	if limit.RPS <= 0 {
+		return limit, nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := l.now()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[host] = b
+		return limit, b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * limit.RPS
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+	return limit, b
+}
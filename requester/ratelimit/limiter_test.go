@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHostLimiter_Burst(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewHostLimiter(Limit{RPS: 2, Burst: 2}, func() time.Time { return now })
+
+	if !limiter.Allow("a.com") || !limiter.Allow("a.com") {
+		t.Fatalf("Expected burst of 2 to be allowed")
+	}
+	if limiter.Allow("a.com") {
+		t.Fatalf("Expected third request to be rejected")
+	}
+	if !limiter.Allow("b.com") {
+		t.Fatalf("Expected other host to have its own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.Allow("a.com") {
+		t.Fatalf("Expected token to be refilled after 500ms")
+	}
+}
+
+func TestHostLimiter_Reserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewHostLimiter(Limit{}, func() time.Time { return now })
+	limiter.SetLimit("a.com", Limit{RPS: 4, Burst: 1})
+
+	if wait := limiter.Reserve("a.com"); wait != 0 {
+		t.Fatalf("Expected no wait, got %v", wait)
+	}
+	if wait := limiter.Reserve("a.com"); wait != 250*time.Millisecond {
+		t.Fatalf("Expected 250ms wait, got %v", wait)
+	}
+	if wait := limiter.Reserve("unlimited.com"); wait != 0 {
+		t.Fatalf("Expected unlimited host to never wait, got %v", wait)
+	}
+}
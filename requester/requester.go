@@ -1,51 +1,120 @@
 package requester
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
+	"time"
 
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/requester/ratelimit"
+	"github.com/plusev-terminal/go-plugin-common/requester/signing"
 	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
 )
 
-//go:wasmimport extism:host/user http_request
-func httpRequest(uint64) uint64
-
 // Requester is the default requester that uses the host functions
-type Requester struct{}
+type Requester struct {
+	middleware []rt.Middleware
+	chain      rt.RequestDoer
+}
 
 // NewRequester creates a new default requester
 func NewRequester() *Requester {
 	return &Requester{}
 }
 
-// Send sends the request to the host and returns the response.
-// If v is not nil, the response body will be unmarshaled into it.
+// Use appends middleware to the requester. Middleware registered first is
+// the outermost, i.e. it sees the request first and the response last.
+//
+// Example:
+//
+//	req := requester.NewRequester()
+//	req.Use(ratelimit.Middleware(limiter, time.Sleep))
+//	req.Use(signing.Middleware(signer, requester.HostNow))
+func (d *Requester) Use(middleware ...rt.Middleware) *Requester {
+	d.middleware = append(d.middleware, middleware...)
+	d.chain = nil
+	return d
+}
+
+// Send sends the request through the middleware chain to the host and
+// returns the response. If v is not nil, the response body will be
+// unmarshaled into it.
 func (d *Requester) Send(req *rt.Request, v any) (*rt.Response, error) {
-	mem, err := pdk.AllocateJSON(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to allocate memory for request: %w", err)
+	if d.chain == nil {
+		var chain rt.RequestDoer = rt.DoerFunc(sendToHost)
+		for i := len(d.middleware) - 1; i >= 0; i-- {
+			chain = d.middleware[i](chain)
+		}
+		d.chain = chain
 	}
+	return d.chain.Send(req, v)
+}
 
-	ptr := httpRequest(mem.Offset())
-	rmem := pdk.FindMemory(ptr)
-	respData := rmem.ReadBytes()
+// sendToHost performs the http_request host call
+func sendToHost(req *rt.Request, v any) (*rt.Response, error) {
+	if req.CookieJar != "" {
+		if err := requireCookieJars(); err != nil {
+			return nil, err
+		}
+	}
 
 	var res rt.Response
-	if err := json.Unmarshal(respData, &res); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := callHostJSON(host.HTTPRequest, host.CallHTTPRequest, withTrace(req), &res); err != nil {
+		return nil, err
 	}
 
 	if res.Error != "" {
 		return nil, errors.New(res.Error)
 	}
 
+	if err := res.Decompress(); err != nil {
+		return nil, err
+	}
+
 	if v != nil {
-		if err := json.Unmarshal(res.Body, v); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response body into target struct: %w", err)
+		if err := res.DecodeJSON(v); err != nil {
+			return nil, err
 		}
 	}
 
 	return &res, nil
 }
+
+// withTrace returns req carrying the current trace ID
+func withTrace(req *rt.Request) *rt.Request {
+	id := trace.Current()
+	if req.TraceID != "" || id == "" {
+		return req
+	}
+	traced := *req
+	traced.TraceID = id
+	return &traced
+}
+
+// NewSigningRequester creates a requester that signs every request with
+// signer, using the host clock for timestamps
+//
+// Example:
+//
+//	req := requester.NewSigningRequester(signing.Binance(apiKey, secret, 5*time.Second))
+func NewSigningRequester(signer signing.Signer) *Requester {
+	return NewRequester().Use(signing.Middleware(signer, HostNow))
+}
+
+// NewRateLimitedRequester creates a requester that waits for limiter before
+// every request. Share the limiter between all requesters of the plugin.
+//
+// Example:
+//
+//	limiter := ratelimit.NewHostLimiter(ratelimit.Limit{RPS: 10, Burst: 20}, requester.HostNow)
+//	req := requester.NewRateLimitedRequester(limiter)
+func NewRateLimitedRequester(limiter *ratelimit.HostLimiter) *Requester {
+	return NewRequester().Use(ratelimit.Middleware(limiter, time.Sleep))
+}
+
+// HostNow returns the host time, ignoring errors. It fits the now function
+// parameters of the signing and ratelimit packages.
+func HostNow() time.Time {
+	return wasmutils.HostClock.Now()
+}
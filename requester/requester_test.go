@@ -0,0 +1,60 @@
+package requester_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/requester"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// recording returns a middleware that logs the request and response path
+func recording(name string, calls *[]string) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return rt.DoerFunc(func(req *rt.Request, v any) (*rt.Response, error) {
+			*calls = append(*calls, name+" request")
+			res, err := next.Send(req, v)
+			*calls = append(*calls, name+" response")
+			return res, err
+		})
+	}
+}
+
+// shortCircuit answers without calling the next doer
+func shortCircuit(name string, calls *[]string) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return rt.DoerFunc(func(req *rt.Request, v any) (*rt.Response, error) {
+			*calls = append(*calls, name+" answered")
+			return &rt.Response{Status: 200, Body: []byte(`{}`)}, nil
+		})
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	r := requester.NewRequester().
+		Use(recording("outer", &calls), recording("inner", &calls)).
+		Use(shortCircuit("cache", &calls))
+
+	res, err := r.Send(&rt.Request{Method: "GET", URL: "https://api.example.com"}, nil)
+	if err != nil || res.Status != 200 {
+		t.Fatalf("unexpected result %v, %v", res, err)
+	}
+	want := []string{"outer request", "inner request", "cache answered", "inner response", "outer response"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var calls []string
+	r := requester.NewRequester().Use(recording("outer", &calls), shortCircuit("cache", &calls), recording("skipped", &calls))
+
+	if _, err := r.Send(&rt.Request{Method: "GET", URL: "https://api.example.com"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer request", "cache answered", "outer response"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
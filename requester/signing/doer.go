@@ -0,0 +1,46 @@
+package signing
+
+import (
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// Doer is a RequestDoer that signs every request before passing it on
+type Doer struct {
+	next   rt.RequestDoer
+	signer Signer
+	now    func() time.Time
+}
+
+// NewDoer wraps next so that every request is signed by signer.
+// now provides the timestamp used for signing; inside a plugin this is
+// usually backed by the host clock (see requester.NewSigningRequester).
+func NewDoer(next rt.RequestDoer, signer Signer, now func() time.Time) *Doer {
+	return &Doer{
+		next:   next,
+		signer: signer,
+		now:    now,
+	}
+}
+
+// Send signs a copy of req and sends it through the wrapped doer
+func (d *Doer) Send(req *rt.Request, v any) (*rt.Response, error) {
+	signed := *req
+	signed.Headers = make(map[string]string, len(req.Headers))
+	for k, val := range req.Headers {
+		signed.Headers[k] = val
+	}
+
+	if err := d.signer.Sign(&signed, d.now()); err != nil {
+		return nil, err
+	}
+	return d.next.Send(&signed, v)
+}
+
+// Middleware returns a requester middleware that signs every request, see NewDoer
+func Middleware(signer Signer, now func() time.Time) rt.Middleware {
+	return func(next rt.RequestDoer) rt.RequestDoer {
+		return NewDoer(next, signer, now)
+	}
+}
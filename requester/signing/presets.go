@@ -0,0 +1,128 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// binanceScheme adds timestamp (and recvWindow) to the query, signs query+body
+// and appends the signature as query parameter
+func binanceScheme(apiKey string, algorithm Algorithm, encode Encoding, recvWindow time.Duration) Scheme {
+	return Scheme{
+		Prepare: func(req *rt.Request, now time.Time) error {
+			if recvWindow > 0 {
+				if err := setQueryParam(req, "recvWindow", strconv.FormatInt(recvWindow.Milliseconds(), 10)); err != nil {
+					return err
+				}
+			}
+			return setQueryParam(req, "timestamp", strconv.FormatInt(now.UnixMilli(), 10))
+		},
+		Canonicalize: QueryAndBody,
+		Algorithm:    algorithm,
+		Encode:       encode,
+		Apply: func(req *rt.Request, signature string, _ time.Time) error {
+			req.Headers["X-MBX-APIKEY"] = apiKey
+			return setQueryParam(req, "signature", signature)
+		},
+	}
+}
+
+// Binance signs requests the way Binance (and Binance-compatible exchanges
+// like MEXC) expect for HMAC API keys. A zero recvWindow leaves it unset.
+func Binance(apiKey, secret string, recvWindow time.Duration) Scheme {
+	return binanceScheme(apiKey, HMACSHA256([]byte(secret)), HexEncoding, recvWindow)
+}
+
+// BinanceEd25519 signs requests for Binance ed25519 API keys
+func BinanceEd25519(apiKey string, key ed25519.PrivateKey, recvWindow time.Duration) Scheme {
+	return binanceScheme(apiKey, Ed25519(key), Base64Encoding, recvWindow)
+}
+
+// OKX signs requests with the OK-ACCESS-* headers
+func OKX(apiKey, secret, passphrase string) Scheme {
+	formatTs := func(t time.Time) string {
+		return t.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	return Scheme{
+		Canonicalize: TimestampMethodPathBody(formatTs),
+		Algorithm:    HMACSHA256([]byte(secret)),
+		Encode:       Base64Encoding,
+		Apply: func(req *rt.Request, signature string, now time.Time) error {
+			req.Headers["OK-ACCESS-KEY"] = apiKey
+			req.Headers["OK-ACCESS-SIGN"] = signature
+			req.Headers["OK-ACCESS-TIMESTAMP"] = formatTs(now)
+			req.Headers["OK-ACCESS-PASSPHRASE"] = passphrase
+			return nil
+		},
+	}
+}
+
+// Coinbase signs requests with the CB-ACCESS-* headers used by the Coinbase
+// Exchange API. secret is the base64 encoded API secret.
+func Coinbase(apiKey, secret, passphrase string) (Scheme, error) {
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return Scheme{}, fmt.Errorf("invalid coinbase secret: %w", err)
+	}
+	formatTs := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	return Scheme{
+		Canonicalize: TimestampMethodPathBody(formatTs),
+		Algorithm:    HMACSHA256(key),
+		Encode:       Base64Encoding,
+		Apply: func(req *rt.Request, signature string, now time.Time) error {
+			req.Headers["CB-ACCESS-KEY"] = apiKey
+			req.Headers["CB-ACCESS-SIGN"] = signature
+			req.Headers["CB-ACCESS-TIMESTAMP"] = formatTs(now)
+			req.Headers["CB-ACCESS-PASSPHRASE"] = passphrase
+			return nil
+		},
+	}, nil
+}
+
+// Kraken signs private REST requests. A nonce is added to the form encoded
+// body if missing. secret is the base64 encoded API secret.
+func Kraken(apiKey, secret string) (Scheme, error) {
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return Scheme{}, fmt.Errorf("invalid kraken secret: %w", err)
+	}
+	return Scheme{
+		Prepare: func(req *rt.Request, now time.Time) error {
+			form, err := url.ParseQuery(string(req.Body))
+			if err != nil {
+				return fmt.Errorf("kraken requests need a form encoded body: %w", err)
+			}
+			if form.Get("nonce") == "" {
+				form.Set("nonce", strconv.FormatInt(now.UnixMilli(), 10))
+				req.Body = []byte(form.Encode())
+			}
+			req.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			return nil
+		},
+		Canonicalize: func(req *rt.Request, _ time.Time) ([]byte, error) {
+			u, err := url.Parse(req.URL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid request url: %w", err)
+			}
+			form, _ := url.ParseQuery(string(req.Body))
+			digest := sha256.Sum256([]byte(form.Get("nonce") + string(req.Body)))
+			return append([]byte(u.Path), digest[:]...), nil
+		},
+		Algorithm: HMACSHA512(key),
+		Encode:    Base64Encoding,
+		Apply: func(req *rt.Request, signature string, _ time.Time) error {
+			req.Headers["API-Key"] = apiKey
+			req.Headers["API-Sign"] = signature
+			return nil
+		},
+	}, nil
+}
@@ -0,0 +1,63 @@
+package signing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+func TestHMACSHA256_BinanceExample(t *testing.T) {
+	// Example from the Binance API documentation
+	secret := "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+	payload := "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559"
+
+	sig, err := HMACSHA256([]byte(secret))([]byte(payload))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+	if got := HexEncoding(sig); got != expected {
+		t.Fatalf("Expected signature %s, got %s", expected, got)
+	}
+}
+
+func TestBinance_SignsQuery(t *testing.T) {
+	req := &rt.Request{Method: "GET", URL: "https://api.binance.com/api/v3/account?omitZeroBalances=true"}
+	now := time.UnixMilli(1499827319559)
+
+	if err := Binance("key", "secret", 5*time.Second).Sign(req, now); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if req.Headers["X-MBX-APIKEY"] != "key" {
+		t.Fatalf("Expected X-MBX-APIKEY header to be set")
+	}
+	if !strings.Contains(req.URL, "?omitZeroBalances=true&recvWindow=5000&timestamp=1499827319559&signature=") {
+		t.Fatalf("Unexpected signed url: %s", req.URL)
+	}
+}
+
+func TestKraken_DocumentationExample(t *testing.T) {
+	// Example from the Kraken REST API documentation
+	scheme, err := Kraken("key", "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg==")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := &rt.Request{
+		Method: "POST",
+		URL:    "https://api.kraken.com/0/private/AddOrder",
+		Body:   []byte("nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25"),
+	}
+	if err := scheme.Sign(req, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ=="
+	if got := req.Headers["API-Sign"]; got != expected {
+		t.Fatalf("Expected API-Sign %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,89 @@
+package requester
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// StreamChunkSize is the chunk size requested from the host by SendStream
+var StreamChunkSize = 256 * 1024
+
+// SendStream sends the request and hands the response body to onChunk piece
+// by piece instead of loading it into memory at once. Use it for large
+// downloads such as historical CSV or zip dumps.
+//
+// The request passes through the middleware chain like Send, so signing and
+// rate limiting apply. The returned response carries status and headers but
+// no body. Returning an error from onChunk aborts the download.
+//
+// Compressed bodies are not decoded; set no Accept-Encoding header or
+// decompress the chunks yourself.
+func (d *Requester) SendStream(req *rt.Request, onChunk func(chunk []byte) error) (*rt.Response, error) {
+	var chain rt.RequestDoer = rt.DoerFunc(func(req *rt.Request, _ any) (*rt.Response, error) {
+		return streamFromHost(req, onChunk)
+	})
+	for i := len(d.middleware) - 1; i >= 0; i-- {
+		chain = d.middleware[i](chain)
+	}
+	return chain.Send(req, nil)
+}
+
+// streamFromHost opens the stream and pumps all chunks into onChunk
+func streamFromHost(req *rt.Request, onChunk func(chunk []byte) error) (*rt.Response, error) {
+	if err := hostinfo.Require(hostinfo.HTTPRequestStream); err != nil {
+		return nil, err
+	}
+	var opened rt.StreamOpenResponse
+	if err := callHostJSON(host.HTTPRequestStream, host.CallHTTPRequestStream, withTrace(req), &opened); err != nil {
+		return nil, err
+	}
+	if opened.Error != "" {
+		return nil, errors.New(opened.Error)
+	}
+	defer closeStream(opened.StreamID)
+
+	res := &rt.Response{
+		Status:  opened.Status,
+		Headers: opened.Headers,
+	}
+
+	for {
+		var chunk rt.StreamChunk
+		err := callHostJSON(host.HTTPStreamRead, host.CallHTTPStreamRead, rt.StreamReadRequest{StreamID: opened.StreamID, MaxBytes: StreamChunkSize}, &chunk)
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Error != "" {
+			return nil, errors.New(chunk.Error)
+		}
+		if len(chunk.Data) > 0 {
+			if err := onChunk(chunk.Data); err != nil {
+				return nil, err
+			}
+		}
+		if chunk.EOF {
+			return res, nil
+		}
+	}
+}
+
+// closeStream releases the host side of a stream
+func closeStream(streamID string) {
+	host.CallHTTPStreamClose([]byte(streamID))
+}
+
+// callHostJSON sends in as JSON to a host function and decodes the JSON answer into out
+func callHostJSON(name string, call host.Func, in any, out any) error {
+	ok, err := host.CallJSON(call, in, out)
+	if err != nil {
+		return fmt.Errorf("host call %s failed: %w", name, err)
+	}
+	if !ok {
+		return fmt.Errorf("host call %s returned no response", name)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package testing_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	requestertesting "github.com/plusev-terminal/go-plugin-common/requester/testing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+func TestFixtureRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"price":"42000.1"}`))
+	}))
+	dir := t.TempDir()
+	req := &rt.Request{Method: "GET", URL: server.URL + "/api/v3/ticker?symbol=BTCUSDT"}
+
+	recorder := requestertesting.NewMockRequester()
+	recorder.RecordTo(dir)
+	recorded, err := recorder.Send(req, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	server.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, requestertesting.FixtureKey(req))); err != nil {
+		t.Fatalf("expected fixture file: %v", err)
+	}
+
+	replayer := requestertesting.NewMockRequester()
+	replayer.ReplayFrom(dir)
+	var out struct {
+		Price string `json:"price"`
+	}
+	replayed, err := replayer.Send(req, &out)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Status != http.StatusCreated || string(replayed.Body) != string(recorded.Body) {
+		t.Errorf("expected recorded response, got %d %s", replayed.Status, replayed.Body)
+	}
+	if replayed.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("expected recorded headers, got %v", replayed.Headers)
+	}
+	if out.Price != "42000.1" {
+		t.Errorf("expected decoded body, got %+v", out)
+	}
+}
+
+func TestFixtureMissing(t *testing.T) {
+	mock := requestertesting.NewMockRequester()
+	mock.ReplayFrom(t.TempDir())
+
+	_, err := mock.Send(&rt.Request{Method: "GET", URL: "https://api.example.com/unknown"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no fixture for GET https://api.example.com/unknown") {
+		t.Fatalf("expected missing fixture error, got %v", err)
+	}
+}
+
+func TestFixtureIgnoredParamsAndBinaryBody(t *testing.T) {
+	payload := []byte{0x1f, 0x8b, 0xff, 0x00, 'o', 'k'}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(payload)
+	}))
+	dir := t.TempDir()
+
+	recorder := requestertesting.NewMockRequester()
+	recorder.RecordTo(dir)
+	recorder.IgnoreFixtureParams("timestamp", "signature")
+	if _, err := recorder.Send(&rt.Request{Method: "GET", URL: server.URL + "/api/v3/account?timestamp=1&signature=aaa"}, nil); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	server.Close()
+
+	replayer := requestertesting.NewMockRequester()
+	replayer.ReplayFrom(dir)
+	replayer.IgnoreFixtureParams("timestamp", "signature")
+	res, err := replayer.Send(&rt.Request{Method: "GET", URL: server.URL + "/api/v3/account?timestamp=2&signature=bbb"}, nil)
+	if err != nil {
+		t.Fatalf("replay with new signature: %v", err)
+	}
+	if !bytes.Equal(res.Body, payload) {
+		t.Errorf("expected binary body %x, got %x", payload, res.Body)
+	}
+}
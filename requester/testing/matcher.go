@@ -0,0 +1,210 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+// MockRoute is a mock response bound to a request matcher. Routes are
+// created with MockRequester.On and are checked before the plain URL
+// patterns of SetMockResponse/SetMockError, in registration order.
+type MockRoute struct {
+	pattern   string
+	method    string
+	headers   map[string]func(string) bool
+	body      func([]byte) bool
+	responses []mockResponse
+	err       error
+	calls     int
+}
+
+type mockResponse struct {
+	status  int
+	headers http.Header
+	body    string
+}
+
+// On registers a route for requests whose URL matches pattern (same rules as
+// SetMockResponse) and returns it for further configuration:
+//
+//	mock.On("/api/v3/order").
+//		Method("POST").
+//		HeaderMatch("X-MBX-APIKEY", func(v string) bool { return v != "" }).
+//		BodyJSON(map[string]any{"symbol": "BTCUSDT"}).
+//		Respond(`{"orderId": 1}`)
+func (m *MockRequester) On(pattern string) *MockRoute {
+	route := &MockRoute{pattern: pattern, headers: make(map[string]func(string) bool)}
+	m.routes = append(m.routes, route)
+	return route
+}
+
+// Method restricts the route to the given HTTP method
+func (r *MockRoute) Method(method string) *MockRoute {
+	r.method = strings.ToUpper(method)
+	return r
+}
+
+// Header restricts the route to requests carrying the header with exactly value
+func (r *MockRoute) Header(name, value string) *MockRoute {
+	return r.HeaderMatch(name, func(v string) bool { return v == value })
+}
+
+// HeaderMatch restricts the route to requests whose header value satisfies match.
+// A missing header is passed as empty string.
+func (r *MockRoute) HeaderMatch(name string, match func(string) bool) *MockRoute {
+	r.headers[http.CanonicalHeaderKey(name)] = match
+	return r
+}
+
+// Body restricts the route to requests whose body satisfies match
+func (r *MockRoute) Body(match func([]byte) bool) *MockRoute {
+	r.body = match
+	return r
+}
+
+// BodyJSON restricts the route to requests whose JSON body equals expected
+// after decoding both sides, so key order and whitespace don't matter
+func (r *MockRoute) BodyJSON(expected any) *MockRoute {
+	want, err := normalizeJSON(expected)
+	return r.Body(func(body []byte) bool {
+		if err != nil {
+			return false
+		}
+		var got any
+		if json.Unmarshal(body, &got) != nil {
+			return false
+		}
+		return reflect.DeepEqual(want, got)
+	})
+}
+
+// Respond answers matching requests with a 200 JSON response
+func (r *MockRoute) Respond(jsonResponse string) *MockRoute {
+	return r.RespondStatus(http.StatusOK, jsonResponse)
+}
+
+// RespondStatus answers matching requests with the given status and body
+func (r *MockRoute) RespondStatus(status int, body string) *MockRoute {
+	r.responses = append(r.responses, mockResponse{
+		status:  status,
+		headers: http.Header{"Content-Type": []string{"application/json"}},
+		body:    body,
+	})
+	return r
+}
+
+// RespondSequence answers the n-th matching request with the n-th response,
+// which is handy for simulating pagination. Once the sequence is exhausted
+// the last response is repeated.
+func (r *MockRoute) RespondSequence(jsonResponses ...string) *MockRoute {
+	for _, resp := range jsonResponses {
+		r.Respond(resp)
+	}
+	return r
+}
+
+// RespondError makes matching requests fail with err
+func (r *MockRoute) RespondError(err error) *MockRoute {
+	r.err = err
+	return r
+}
+
+// CallCount returns how many requests matched the route
+func (r *MockRoute) CallCount() int {
+	return r.calls
+}
+
+// matches checks the request against all configured conditions
+func (r *MockRoute) matches(req *rt.Request) bool {
+	if !matchesPattern(req.URL, r.pattern) {
+		return false
+	}
+	if r.method != "" && !strings.EqualFold(req.Method, r.method) {
+		return false
+	}
+	for name, match := range r.headers {
+		if !match(headerValue(req.Headers, name)) {
+			return false
+		}
+	}
+	if r.body != nil && !r.body(req.Body) {
+		return false
+	}
+	return true
+}
+
+// respond produces the response for the current call
+func (r *MockRoute) respond(response interface{}) (*rt.Response, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.responses) == 0 {
+		return nil, fmt.Errorf("mock route %q has no response configured", r.pattern)
+	}
+
+	resp := r.responses[min(r.calls, len(r.responses))-1]
+	if response != nil && len(resp.body) > 0 {
+		if err := json.Unmarshal([]byte(resp.body), response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal mock response: %w", err)
+		}
+	}
+
+	return &rt.Response{
+		Status:  resp.status,
+		Headers: resp.headers.Clone(),
+		Body:    []byte(resp.body),
+	}, nil
+}
+
+// CallCount returns how many requests were sent to URLs matching pattern
+func (m *MockRequester) CallCount(pattern string) int {
+	count := 0
+	for _, url := range m.calls {
+		if matchesPattern(url, pattern) {
+			count++
+		}
+	}
+	return count
+}
+
+// GetRequests returns copies of all requests sent during testing
+func (m *MockRequester) GetRequests() []rt.Request {
+	return m.requests
+}
+
+// headerValue looks up a header case-insensitively
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeJSON round-trips v through JSON so it compares equal to decoded bodies
+func normalizeJSON(v any) (any, error) {
+	var data []byte
+	switch val := v.(type) {
+	case string:
+		data = []byte(val)
+	case []byte:
+		data = val
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var out any
+	err := json.NewDecoder(bytes.NewReader(data)).Decode(&out)
+	return out, err
+}
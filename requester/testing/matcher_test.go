@@ -0,0 +1,129 @@
+package testing_test
+
+import (
+	"strings"
+	"testing"
+
+	requestertesting "github.com/plusev-terminal/go-plugin-common/requester/testing"
+	rt "github.com/plusev-terminal/go-plugin-common/requester/types"
+)
+
+func TestMockRouteMatching(t *testing.T) {
+	const orderURL = "https://api.example.com/api/v3/order?symbol=BTCUSDT&side=BUY"
+	tests := []struct {
+		name    string
+		route   func(m *requestertesting.MockRequester) *requestertesting.MockRoute
+		req     rt.Request
+		matches bool
+	}{
+		{
+			name: "method",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").Method("post")
+			},
+			req:     rt.Request{Method: "POST", URL: orderURL},
+			matches: true,
+		},
+		{
+			name: "other method",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").Method("DELETE")
+			},
+			req: rt.Request{Method: "POST", URL: orderURL},
+		},
+		{
+			name: "path wildcard",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("https://api.example.com/api/v3/*")
+			},
+			req:     rt.Request{Method: "GET", URL: orderURL},
+			matches: true,
+		},
+		{
+			name:  "other path",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute { return m.On("/api/v3/account") },
+			req:   rt.Request{Method: "GET", URL: orderURL},
+		},
+		{
+			name: "query",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order?symbol=BTCUSDT")
+			},
+			req:     rt.Request{Method: "GET", URL: orderURL},
+			matches: true,
+		},
+		{
+			name: "other query",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order?symbol=ETHUSDT")
+			},
+			req: rt.Request{Method: "GET", URL: orderURL},
+		},
+		{
+			name: "header, case-insensitive name",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").Header("X-MBX-APIKEY", "key")
+			},
+			req:     rt.Request{Method: "POST", URL: orderURL, Headers: map[string]string{"x-mbx-apikey": "key"}},
+			matches: true,
+		},
+		{
+			name: "other header value",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").Header("X-MBX-APIKEY", "key")
+			},
+			req: rt.Request{Method: "POST", URL: orderURL, Headers: map[string]string{"X-MBX-APIKEY": "other"}},
+		},
+		{
+			name: "missing header",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").HeaderMatch("X-MBX-APIKEY", func(v string) bool { return v != "" })
+			},
+			req: rt.Request{Method: "POST", URL: orderURL},
+		},
+		{
+			name: "JSON body ignores key order",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").BodyJSON(map[string]any{"symbol": "BTCUSDT", "qty": 1})
+			},
+			req:     rt.Request{Method: "POST", URL: orderURL, Body: []byte(`{ "qty": 1, "symbol": "BTCUSDT" }`)},
+			matches: true,
+		},
+		{
+			name: "other JSON body",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").BodyJSON(map[string]any{"symbol": "BTCUSDT", "qty": 1})
+			},
+			req: rt.Request{Method: "POST", URL: orderURL, Body: []byte(`{"qty":2,"symbol":"BTCUSDT"}`)},
+		},
+		{
+			name: "body predicate",
+			route: func(m *requestertesting.MockRequester) *requestertesting.MockRoute {
+				return m.On("/api/v3/order").Body(func(b []byte) bool { return strings.Contains(string(b), "LIMIT") })
+			},
+			req:     rt.Request{Method: "POST", URL: orderURL, Body: []byte("type=LIMIT")},
+			matches: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := requestertesting.NewMockRequester()
+			mock.SetMockResponse("*", `{"fallback":true}`)
+			route := tt.route(mock).Respond(`{"matched":true}`)
+
+			res, err := mock.Send(&tt.req, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wantCalls := 0
+			if tt.matches {
+				wantCalls = 1
+			}
+			matched := string(res.Body) == `{"matched":true}`
+			if matched != tt.matches || route.CallCount() != wantCalls {
+				t.Errorf("expected match=%v, got body %s and %d route calls", tt.matches, res.Body, route.CallCount())
+			}
+		})
+	}
+}
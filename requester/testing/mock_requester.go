@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"strings"
 	"time"
 
@@ -19,6 +20,12 @@ type MockRequester struct {
 	responses map[string]string // URL pattern -> JSON response
 	errors    map[string]error  // URL pattern -> error
 	calls     []string          // Track all calls made
+	jars      map[string]http.CookieJar
+	routes    []*MockRoute // Matchers registered with On
+	requests  []rt.Request // Copies of all requests sent
+	recordDir string       // Store real responses as fixtures (see RecordTo)
+	replayDir string       // Serve responses from fixtures (see ReplayFrom)
+	ignored   []string     // Query params left out of fixture keys
 }
 
 // NewMockRequester creates a new mock requester for testing
@@ -30,6 +37,7 @@ func NewMockRequester() *MockRequester {
 		responses: make(map[string]string),
 		errors:    make(map[string]error),
 		calls:     make([]string, 0),
+		jars:      make(map[string]http.CookieJar),
 	}
 }
 
@@ -47,6 +55,14 @@ func (m *MockRequester) SetMockError(urlPattern string, err error) {
 // Send implements requester.Interface for testing
 func (m *MockRequester) Send(req *rt.Request, response interface{}) (*rt.Response, error) {
 	m.calls = append(m.calls, req.URL)
+	m.requests = append(m.requests, copyRequest(req))
+
+	// Routes with full matchers take precedence over plain URL patterns
+	for _, route := range m.routes {
+		if route.matches(req) {
+			return route.respond(response)
+		}
+	}
 
 	// Check for mock errors first
 	for pattern, err := range m.errors {
@@ -73,8 +89,31 @@ func (m *MockRequester) Send(req *rt.Request, response interface{}) (*rt.Respons
 		}
 	}
 
+	// Serve recorded fixtures instead of hitting the network
+	if m.replayDir != "" {
+		res, err := m.replay(req)
+		if err != nil {
+			return nil, err
+		}
+		if response != nil {
+			if err := res.DecodeJSON(response); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
 	// If no mock is set, make a real HTTP request (useful for integration tests)
-	return m.makeRealRequest(req, response)
+	res, err := m.makeRealRequest(req, response)
+	if err != nil {
+		return nil, err
+	}
+	if m.recordDir != "" {
+		if err := m.record(req, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
 // makeRealRequest makes an actual HTTP request using net/http
@@ -95,7 +134,8 @@ func (m *MockRequester) makeRealRequest(req *rt.Request, response interface{}) (
 		httpReq.Header.Set(key, value)
 	}
 
-	resp, err := m.client.Do(httpReq)
+	client := m.clientFor(req)
+	resp, err := client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -107,6 +147,17 @@ func (m *MockRequester) makeRealRequest(req *rt.Request, response interface{}) (
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Mirror the host: compressed bodies are handed to the plugin decoded
+	res := &rt.Response{
+		Status:  resp.StatusCode,
+		Headers: resp.Header,
+		Body:    respBody,
+	}
+	if err := res.Decompress(); err != nil {
+		return nil, err
+	}
+	respBody = res.Body
+
 	// If response interface is provided, unmarshal the JSON
 	if response != nil {
 		if err := json.Unmarshal(respBody, response); err != nil {
@@ -114,11 +165,41 @@ func (m *MockRequester) makeRealRequest(req *rt.Request, response interface{}) (
 		}
 	}
 
-	return &rt.Response{
-		Status:  resp.StatusCode,
-		Headers: resp.Header,
-		Body:    respBody,
-	}, nil
+	return res, nil
+}
+
+// clientFor returns an http.Client honoring the request's timeout and redirect settings
+func (m *MockRequester) clientFor(req *rt.Request) *http.Client {
+	if req.TimeoutMs == 0 && req.FollowRedirects == nil && req.MaxRedirects == 0 && req.CookieJar == "" {
+		return m.client
+	}
+
+	client := *m.client
+	if req.CookieJar != "" {
+		jar, ok := m.jars[req.CookieJar]
+		if !ok {
+			jar, _ = cookiejar.New(nil)
+			m.jars[req.CookieJar] = jar
+		}
+		client.Jar = jar
+	}
+	if req.TimeoutMs > 0 {
+		client.Timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	client.CheckRedirect = func(_ *http.Request, via []*http.Request) error {
+		if req.FollowRedirects != nil && !*req.FollowRedirects {
+			return http.ErrUseLastResponse
+		}
+		maxRedirects := req.MaxRedirects
+		if maxRedirects == 0 {
+			maxRedirects = 10 // net/http default
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return &client
 }
 
 // GetCalls returns all URLs that were called during testing
@@ -126,11 +207,30 @@ func (m *MockRequester) GetCalls() []string {
 	return m.calls
 }
 
-// Reset clears all mock responses and call history
+// ClearCookies drops all cookies of the named jar
+func (m *MockRequester) ClearCookies(name string) {
+	delete(m.jars, name)
+}
+
+// Reset clears all mock responses, routes and call history
 func (m *MockRequester) Reset() {
 	m.responses = make(map[string]string)
 	m.errors = make(map[string]error)
 	m.calls = make([]string, 0)
+	m.routes = nil
+	m.requests = nil
+	m.jars = make(map[string]http.CookieJar)
+}
+
+// copyRequest snapshots req so later mutations by the caller don't affect recorded requests
+func copyRequest(req *rt.Request) rt.Request {
+	cp := *req
+	cp.Headers = make(map[string]string, len(req.Headers))
+	for key, value := range req.Headers {
+		cp.Headers[key] = value
+	}
+	cp.Body = bytes.Clone(req.Body)
+	return cp
 }
 
 // matchesPattern checks if URL matches the pattern
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MultipartFile is a file part of a multipart/form-data body
+type MultipartFile struct {
+	FieldName   string
+	FileName    string
+	ContentType string // Defaults to application/octet-stream
+	Data        []byte
+}
+
+// SetFormBody encodes values as application/x-www-form-urlencoded body
+func (r *Request) SetFormBody(values url.Values) {
+	r.Body = []byte(values.Encode())
+	r.setHeader("Content-Type", "application/x-www-form-urlencoded")
+}
+
+// SetMultipartBody encodes fields and files as multipart/form-data body.
+// Fields are written in key order so the body is reproducible.
+func (r *Request) SetMultipartBody(fields map[string]string, files ...MultipartFile) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := w.WriteField(k, fields[k]); err != nil {
+			return fmt.Errorf("failed to write multipart field %q: %w", k, err)
+		}
+	}
+
+	for _, f := range files {
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(f.FieldName), quoteEscaper.Replace(f.FileName)))
+		header.Set("Content-Type", contentType)
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("failed to create multipart file %q: %w", f.FileName, err)
+		}
+		if _, err := part.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to write multipart file %q: %w", f.FileName, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finish multipart body: %w", err)
+	}
+
+	r.Body = buf.Bytes()
+	r.setHeader("Content-Type", w.FormDataContentType())
+	return nil
+}
+
+// quoteEscaper escapes quoted header parameters the way mime/multipart does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (r *Request) setHeader(key, value string) {
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	r.Headers[key] = value
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSetFormBody(t *testing.T) {
+	var req Request
+	req.SetFormBody(url.Values{"symbol": {"BTC/USDT"}, "side": {"buy"}})
+
+	if string(req.Body) != "side=buy&symbol=BTC%2FUSDT" {
+		t.Errorf("unexpected body %q", req.Body)
+	}
+	if req.Headers["Content-Type"] != "application/x-www-form-urlencoded" {
+		t.Errorf("unexpected content type %q", req.Headers["Content-Type"])
+	}
+}
+
+func TestSetMultipartBody(t *testing.T) {
+	var req Request
+	name := "Bericht\u00a0ü \"Q1\".csv"
+	err := req.SetMultipartBody(
+		map[string]string{"b": "2", "a": "1"},
+		MultipartFile{FieldName: "file", FileName: name, Data: []byte("x,y\n")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(req.Body, []byte("filename=\"Bericht\u00a0ü \\\"Q1\\\".csv\"")) {
+		t.Errorf("expected MIME quoting of the file name, got %q", req.Body)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Headers["Content-Type"])
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("unexpected content type %q: %v", req.Headers["Content-Type"], err)
+	}
+	r := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"])
+	var parts []string
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(part)
+		parts = append(parts, part.FormName()+"|"+part.FileName()+"|"+part.Header.Get("Content-Type")+"|"+string(data))
+	}
+
+	want := []string{"a|||1", "b|||2", "file|" + name + "|application/octet-stream|x,y\n"}
+	if strings.Join(parts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected parts:\n%q\nwant\n%q", parts, want)
+	}
+}
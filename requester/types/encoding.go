@@ -0,0 +1,105 @@
+package types
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// AcceptCompressed is the Accept-Encoding value for the encodings DecodedBody supports
+const AcceptCompressed = "gzip, deflate"
+
+// ErrUnsupportedEncoding is returned by DecodedBody for content encodings
+// other than gzip and deflate, e.g. br
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// DecodedBody returns the response body decompressed according to its
+// Content-Encoding header. Bodies without (or with an identity) encoding are
+// returned unchanged, as are empty bodies of HEAD, 204 or 304 responses.
+// Multiple encodings ("deflate, gzip") are undone in reverse order.
+func (r *Response) DecodedBody() ([]byte, error) {
+	encodings := contentEncodings(r.Headers.Get("Content-Encoding"))
+	for _, encoding := range encodings {
+		if !decodable(encoding) {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+		}
+	}
+	if len(r.Body) == 0 {
+		return r.Body, nil
+	}
+
+	body := r.Body
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		if body, err = decode(encodings[i], body); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// Decompress replaces the body with its decoded form and drops the
+// Content-Encoding header, so later reads see plain data. Bodies in an
+// encoding DecodedBody doesn't support are left untouched, header included,
+// for the plugin to decode itself.
+func (r *Response) Decompress() error {
+	encodings := contentEncodings(r.Headers.Get("Content-Encoding"))
+	if len(encodings) == 0 || slices.ContainsFunc(encodings, func(e string) bool { return !decodable(e) }) {
+		return nil
+	}
+	body, err := r.DecodedBody()
+	if err != nil {
+		return err
+	}
+	r.Body = body
+	if r.Headers != nil {
+		r.Headers.Del("Content-Encoding")
+		r.Headers.Del("Content-Length")
+	}
+	return nil
+}
+
+// contentEncodings splits a Content-Encoding header, skipping identity
+func contentEncodings(header string) []string {
+	var encodings []string
+	for encoding := range strings.SplitSeq(header, ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding != "" && encoding != "identity" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return encodings
+}
+
+func decodable(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "deflate":
+		return true
+	}
+	return false
+}
+
+func decode(encoding string, body []byte) ([]byte, error) {
+	if encoding == "deflate" {
+		// Most servers send zlib-wrapped data for "deflate", some send raw deflate
+		if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+			defer zr.Close()
+			return io.ReadAll(zr)
+		}
+		fr := flate.NewReader(bytes.NewReader(body))
+		defer fr.Close()
+		return io.ReadAll(fr)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip body: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
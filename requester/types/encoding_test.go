@@ -0,0 +1,70 @@
+package types
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func deflated(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	plain := []byte(`{"ok":true}`)
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     []byte
+		keepsHdr bool
+	}{
+		{"none", "", plain, plain, false},
+		{"gzip", "gzip", gzipped(t, plain), plain, false},
+		{"layered", "deflate, gzip", gzipped(t, deflated(t, plain)), plain, false},
+		{"empty gzip", "gzip", nil, nil, false},
+		{"brotli untouched", "br", []byte("brotli"), []byte("brotli"), true},
+		{"mixed untouched", "gzip, br", []byte("mixed"), []byte("mixed"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &Response{Status: 200, Headers: http.Header{}, Body: tt.body}
+			if tt.encoding != "" {
+				res.Headers.Set("Content-Encoding", tt.encoding)
+			}
+			if err := res.Decompress(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(res.Body, tt.want) {
+				t.Errorf("expected body %q, got %q", tt.want, res.Body)
+			}
+			if kept := res.Headers.Get("Content-Encoding") != ""; kept != tt.keepsHdr {
+				t.Errorf("expected Content-Encoding kept=%v, got %q", tt.keepsHdr, res.Headers.Get("Content-Encoding"))
+			}
+		})
+	}
+
+	res := &Response{Headers: http.Header{"Content-Encoding": {"br"}}}
+	if _, err := res.DecodedBody(); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("expected ErrUnsupportedEncoding, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxBodySnippet is the number of body bytes included in decode errors
+const maxBodySnippet = 256
+
+// IsSuccess reports whether the status code is 2xx
+func (r *Response) IsSuccess() bool {
+	return r.Status >= 200 && r.Status < 300
+}
+
+// Header returns the first value of the named header
+func (r *Response) Header(name string) string {
+	return r.Headers.Get(name)
+}
+
+// DecodeJSON unmarshals the body into v. The error includes the status code
+// and the beginning of the body, which usually tells whether the exchange
+// returned an error page or an unexpected payload.
+func (r *Response) DecodeJSON(v any) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to decode response (status %d): %w; body: %s", r.Status, err, r.BodySnippet())
+	}
+	return nil
+}
+
+// BodySnippet returns the body truncated for use in error messages
+func (r *Response) BodySnippet() string {
+	if len(r.Body) <= maxBodySnippet {
+		return string(r.Body)
+	}
+	snippet := r.Body[:maxBodySnippet]
+	// Do not cut a multi-byte character in half
+	for len(snippet) > 0 && !utf8.Valid(snippet) {
+		snippet = snippet[:len(snippet)-1]
+	}
+	return string(snippet) + "..."
+}
+
+// RateLimitInfo holds the values of the common X-RateLimit-* headers
+type RateLimitInfo struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"` // Raw header value: epoch seconds or seconds until reset, depending on the API
+}
+
+// RateLimitInfo parses X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset (also without the X- prefix). Returns false if none is set.
+func (r *Response) RateLimitInfo() (RateLimitInfo, bool) {
+	var info RateLimitInfo
+	found := false
+
+	if v, ok := r.intHeader("X-RateLimit-Limit", "RateLimit-Limit"); ok {
+		info.Limit = int(v)
+		found = true
+	}
+	if v, ok := r.intHeader("X-RateLimit-Remaining", "RateLimit-Remaining"); ok {
+		info.Remaining = int(v)
+		found = true
+	}
+	if v, ok := r.intHeader("X-RateLimit-Reset", "RateLimit-Reset"); ok {
+		info.Reset = v
+		found = true
+	}
+
+	return info, found
+}
+
+// intHeader returns the first of the named headers that holds an integer
+func (r *Response) intHeader(names ...string) (int64, bool) {
+	for _, name := range names {
+		value := r.Headers.Get(name)
+		if value == "" {
+			continue
+		}
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v, true
+		}
+	}
+	return 0, false
+}
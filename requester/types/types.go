@@ -8,12 +8,39 @@ type RequestDoer interface {
 	Send(req *Request, v any) (*Response, error)
 }
 
+// DoerFunc adapts a plain function to the RequestDoer interface
+type DoerFunc func(req *Request, v any) (*Response, error)
+
+// Send calls f(req, v)
+func (f DoerFunc) Send(req *Request, v any) (*Response, error) {
+	return f(req, v)
+}
+
+// Middleware wraps a RequestDoer to add cross-cutting behavior such as
+// signing, rate limiting, logging or retries
+type Middleware func(next RequestDoer) RequestDoer
+
 // Request is the request to be sent to the host
 type Request struct {
 	URL     string            `json:"url"`
 	Method  string            `json:"method"`
 	Headers map[string]string `json:"headers"`
 	Body    []byte            `json:"body"`
+
+	// Optional transport settings honored by the host
+	TimeoutMs       int   `json:"timeoutMs,omitempty"`       // Per-request timeout, 0 uses the host default
+	FollowRedirects *bool `json:"followRedirects,omitempty"` // nil follows redirects (host default)
+	MaxRedirects    int   `json:"maxRedirects,omitempty"`    // 0 uses the host default
+
+	// CookieJar opts into host-managed cookie persistence. Requests naming the
+	// same jar share cookies across commands for the lifetime of the plugin
+	// instance. Empty disables cookies.
+	CookieJar string `json:"cookieJar,omitempty"`
+
+	// TraceID links the request to the command or stream message that caused
+	// it in the host's logs. Filled from trace.Current when empty; it is not
+	// sent to the remote server.
+	TraceID string `json:"traceId,omitempty"`
 }
 
 // Response is the response from the host
@@ -23,3 +50,50 @@ type Response struct {
 	Body    []byte      `json:"body"`
 	Error   string      `json:"error,omitempty"`
 }
+
+// SignAlgorithm names a signature algorithm the host can compute
+type SignAlgorithm string
+
+const (
+	SignHMACSHA256 SignAlgorithm = "hmac-sha256"
+	SignHMACSHA512 SignAlgorithm = "hmac-sha512"
+	SignEd25519    SignAlgorithm = "ed25519"
+)
+
+// SignRequest asks the host to sign a payload with a secret it holds.
+// CredentialRef is the name of the encrypted ConfigField containing the secret,
+// so the secret itself never enters plugin memory.
+type SignRequest struct {
+	CredentialRef string        `json:"credentialRef"`
+	Algorithm     SignAlgorithm `json:"algorithm"`
+	Payload       []byte        `json:"payload"`
+	SecretBase64  bool          `json:"secretBase64,omitempty"` // Decode the stored secret from base64 before use (Kraken, Coinbase)
+}
+
+// SignResponse is the host's answer to a SignRequest
+type SignResponse struct {
+	Signature []byte `json:"signature"` // Raw signature bytes
+	Error     string `json:"error,omitempty"`
+}
+
+// StreamOpenResponse is the host's answer to an http_request_stream call.
+// The body is not included; it is read chunk by chunk via http_stream_read.
+type StreamOpenResponse struct {
+	StreamID string      `json:"streamId"`
+	Status   int         `json:"status"`
+	Headers  http.Header `json:"headers"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// StreamReadRequest asks the host for the next chunk of a response body
+type StreamReadRequest struct {
+	StreamID string `json:"streamId"`
+	MaxBytes int    `json:"maxBytes,omitempty"` // 0 uses the host default chunk size
+}
+
+// StreamChunk is a single chunk of a streamed response body
+type StreamChunk struct {
+	Data  []byte `json:"data"`
+	EOF   bool   `json:"eof"` // No more data after this chunk
+	Error string `json:"error,omitempty"`
+}
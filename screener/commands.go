@@ -0,0 +1,5 @@
+package screener
+
+const (
+	CMD_SCAN = "scan"
+)
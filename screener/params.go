@@ -0,0 +1,49 @@
+package screener
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// UniverseFilter restricts the markets a scan considers. Empty fields do not filter.
+type UniverseFilter struct {
+	Symbols    []string `json:"symbols,omitempty" mapstructure:"symbols"`
+	AssetTypes []string `json:"assetTypes,omitempty" mapstructure:"assetTypes"` // e.g. "spot", "perpetual"
+	Quotes     []string `json:"quotes,omitempty" mapstructure:"quotes"`         // Quote currencies, e.g. "USDT"
+	Category   string   `json:"category,omitempty" mapstructure:"category"`     // Plugin-defined market category
+}
+
+// SortKey orders scan results by a column
+type SortKey struct {
+	Column     string `json:"column" mapstructure:"column"`
+	Descending bool   `json:"descending,omitempty" mapstructure:"descending"`
+}
+
+// ScanRequest contains parameters for the scan command
+type ScanRequest struct {
+	Universe  UniverseFilter `json:"universe" mapstructure:"universe"`
+	Metrics   []string       `json:"metrics" mapstructure:"metrics"` // Column keys to compute; empty means the plugin's defaults
+	Timeframe string         `json:"timeframe,omitempty" mapstructure:"timeframe"`
+	Sort      []SortKey      `json:"sort,omitempty" mapstructure:"sort"`
+	Limit     int            `json:"limit,omitempty" mapstructure:"limit"` // 0 returns all rows
+}
+
+func (p ScanRequest) Validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	for i, key := range p.Sort {
+		if key.Column == "" {
+			return fmt.Errorf("sort[%d].column is required", i)
+		}
+	}
+	return nil
+}
+
+// ScanRequestFromMap extracts ScanRequest from validated map
+func ScanRequestFromMap(data map[string]any) ScanRequest {
+	var params ScanRequest
+	_ = utils.MapToStruct(data, &params)
+	return params
+}
@@ -0,0 +1,57 @@
+// Package screener defines the scan command contract of screener plugins and
+// the wiring to serve it. The plugin computes rows with typed columns; the
+// host renders them as a sortable table.
+//
+//	func (p *MyScreener) RegisterCommands(router *plugin.CommandRouter) {
+//	    router.Register(screener.CMD_SCAN, screener.HandleScan(p.scan))
+//	}
+package screener
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+)
+
+// ScanFunc computes the rows of a scan
+type ScanFunc func(req ScanRequest) (ScanResult, error)
+
+// HandleScan adapts fn to a command handler. The request is validated before
+// fn is called, and the result is sorted and truncated by the request's Sort
+// and Limit, so fn only has to compute rows.
+func HandleScan(fn ScanFunc) plugin.CommandHandler {
+	return func(params map[string]any) plugin.Response {
+		req := ScanRequestFromMap(params)
+		if err := req.Validate(); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+
+		result, err := fn(req)
+		if err != nil {
+			return plugin.ErrorResponse(err)
+		}
+		if err := checkColumns(result); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+
+		result.SortRows(req.Sort)
+		result.Truncate(req.Limit)
+		return plugin.SuccessResponse(result)
+	}
+}
+
+// checkColumns rejects row values without a declared column
+func checkColumns(result ScanResult) error {
+	declared := make(map[string]bool, len(result.Columns))
+	for _, c := range result.Columns {
+		declared[c.Key] = true
+	}
+	for _, row := range result.Rows {
+		for key := range row.Values {
+			if !declared[key] {
+				return fmt.Errorf("row %s: value for undeclared column %q", row.Symbol, key)
+			}
+		}
+	}
+	return nil
+}
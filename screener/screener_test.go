@@ -0,0 +1,33 @@
+package screener
+
+import "testing"
+
+func TestHandleScanSortsAndLimits(t *testing.T) {
+	handler := HandleScan(func(req ScanRequest) (ScanResult, error) {
+		return ScanResult{
+			Columns: []Column{{Key: "change", Label: "Change", Type: ColumnPercent}},
+			Rows: []Row{
+				{Symbol: "A", Values: map[string]any{"change": 1.5}},
+				{Symbol: "B", Values: map[string]any{}},
+				{Symbol: "C", Values: map[string]any{"change": 4.0}},
+				{Symbol: "D", Values: map[string]any{"change": -2.0}},
+			},
+		}, nil
+	})
+
+	resp := handler(map[string]any{
+		"sort":  []any{map[string]any{"column": "change", "descending": true}},
+		"limit": 3,
+	})
+	if !resp.Result {
+		t.Fatal(resp.Error)
+	}
+
+	result := resp.Data.(ScanResult)
+	if result.Total != 4 || len(result.Rows) != 3 {
+		t.Fatalf("expected 3 of 4 rows, got %d of %d", len(result.Rows), result.Total)
+	}
+	if got := result.Rows[0].Symbol + result.Rows[1].Symbol + result.Rows[2].Symbol; got != "CAD" {
+		t.Fatalf("unexpected order %s", got)
+	}
+}
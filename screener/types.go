@@ -0,0 +1,123 @@
+package screener
+
+import (
+	"sort"
+)
+
+// ColumnType tells the host table how to render and sort a column
+type ColumnType string
+
+const (
+	ColumnString  ColumnType = "string"
+	ColumnNumber  ColumnType = "number"
+	ColumnPrice   ColumnType = "price"   // Decimal string in quote currency
+	ColumnPercent ColumnType = "percent" // Number where 1 means 1%
+	ColumnVolume  ColumnType = "volume"
+	ColumnSymbol  ColumnType = "symbol"
+	ColumnTime    ColumnType = "time" // Unix timestamp in milliseconds
+	ColumnBool    ColumnType = "bool"
+)
+
+// Column describes one column of a scan result
+type Column struct {
+	Key         string     `json:"key"`
+	Label       string     `json:"label"`
+	Type        ColumnType `json:"type"`
+	Description string     `json:"description,omitempty"`
+	Decimals    int        `json:"decimals,omitempty"` // Display precision for numeric columns
+}
+
+// Row is one market of a scan result. Values are keyed by column key.
+type Row struct {
+	Symbol string         `json:"symbol"`
+	Values map[string]any `json:"values"`
+}
+
+// ScanResult is the response data of the scan command
+type ScanResult struct {
+	Columns []Column `json:"columns"`
+	Rows    []Row    `json:"rows"`
+	Total   int      `json:"total"` // Rows matching before Limit was applied
+	AsOf    int64    `json:"asOf"`  // Unix timestamp in milliseconds
+}
+
+// SortRows orders the rows by keys. Numbers sort numerically, strings
+// lexically; rows missing a value sort last.
+func (r *ScanResult) SortRows(keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareValues(r.Rows[i].Values[key.Column], r.Rows[j].Values[key.Column])
+			if c == 0 {
+				continue
+			}
+			if key.Descending {
+				// Missing values stay last
+				if r.Rows[i].Values[key.Column] == nil || r.Rows[j].Values[key.Column] == nil {
+					return c < 0
+				}
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// Truncate sets Total and keeps the first limit rows; 0 keeps all
+func (r *ScanResult) Truncate(limit int) {
+	r.Total = len(r.Rows)
+	if limit > 0 && len(r.Rows) > limit {
+		r.Rows = r.Rows[:limit]
+	}
+}
+
+// compareValues orders a before b (-1), after b (1) or equal (0); nil sorts last
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	sa, _ := a.(string)
+	sb, _ := b.(string)
+	switch {
+	case sa < sb:
+		return -1
+	case sa > sb:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,103 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Context gives a strategy the account state of the current event and
+// collects its order actions. It implements trading.OrderExecutor, so order
+// logic can be shared with code running against a paper or live executor;
+// orders placed here are submitted by the host after the event returns.
+type Context struct {
+	HostContext
+
+	actions Actions
+}
+
+var _ tt.OrderExecutor = (*Context)(nil)
+
+func newContext(hc HostContext) *Context {
+	return &Context{HostContext: hc}
+}
+
+// Now returns the event time
+func (c *Context) Now() time.Time {
+	return time.UnixMilli(c.Timestamp).UTC()
+}
+
+// Position returns the position in symbol; zero quantity if none is held
+func (c *Context) Position(symbol string) Position {
+	for _, p := range c.Positions {
+		if p.Symbol == symbol {
+			return p
+		}
+	}
+	return Position{Symbol: symbol, Quantity: "0"}
+}
+
+// PlaceOrder queues an order for submission. The symbol defaults to the
+// event's market and a ClientOrderID is assigned if missing.
+func (c *Context) PlaceOrder(order tt.Order) (tt.Order, error) {
+	if order.Symbol == "" {
+		order.Symbol = c.Symbol
+	}
+	if order.Side != tt.SideBuy && order.Side != tt.SideSell {
+		return order, fmt.Errorf("invalid side %q", order.Side)
+	}
+	if order.Quantity == "" {
+		return order, fmt.Errorf("quantity is required")
+	}
+	if order.Type == tt.OrderTypeLimit && order.Price == "" {
+		return order, fmt.Errorf("limit orders require a price")
+	}
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = "s" + strconv.FormatInt(c.Timestamp, 36) + "-" + strconv.Itoa(len(c.actions.Place)+1)
+	}
+	order.CreatedAt = c.Timestamp
+	c.actions.Place = append(c.actions.Place, order)
+	return order, nil
+}
+
+// CancelOrder queues a pending order for cancellation
+func (c *Context) CancelOrder(symbol, id string) error {
+	if _, err := c.GetOrder(symbol, id); err != nil {
+		return err
+	}
+	c.actions.Cancel = append(c.actions.Cancel, CancelRequest{Symbol: symbol, ID: id})
+	return nil
+}
+
+// GetOrder returns a pending order by venue ID or client order ID
+func (c *Context) GetOrder(symbol, id string) (tt.Order, error) {
+	for _, o := range c.PendingOrders {
+		if o.Symbol == symbol && (o.ID == id || o.ClientOrderID == id) {
+			return o, nil
+		}
+	}
+	return tt.Order{}, fmt.Errorf("unknown order %s", id)
+}
+
+// OpenOrders returns the pending orders of symbol, or all if symbol is empty
+func (c *Context) OpenOrders(symbol string) ([]tt.Order, error) {
+	var orders []tt.Order
+	for _, o := range c.PendingOrders {
+		if symbol == "" || o.Symbol == symbol {
+			orders = append(orders, o)
+		}
+	}
+	return orders, nil
+}
+
+// Balances returns the account balances sent by the host
+func (c *Context) Balances() ([]tt.Balance, error) {
+	return c.HostContext.Balances, nil
+}
+
+// Actions returns the actions queued so far
+func (c *Context) Actions() Actions {
+	return c.actions
+}
@@ -0,0 +1,111 @@
+// Package strategy is the plugin category for automated trading. The host
+// feeds candles, ticks and order updates of the subscribed markets into the
+// on_candle, on_tick and on_order_update exports together with the account
+// state; the strategy answers with orders to place and cancel.
+//
+//	type MyStrategy struct {
+//	    strategy.BaseStrategy
+//	    // plugin.Plugin methods ...
+//	}
+//
+//	func (s *MyStrategy) OnCandle(ctx *strategy.Context, candle tt.OHLCVRecord) error {
+//	    _, err := ctx.PlaceOrder(tt.Order{Side: tt.SideBuy, Type: tt.OrderTypeMarket, Quantity: "0.1"})
+//	    return err
+//	}
+//
+//	func init() {
+//	    strategy.Register(&MyStrategy{})
+//	}
+package strategy
+
+import (
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Strategy is implemented by strategy plugins. Configuration and lifecycle
+// (OnInit, OnShutdown, ...) come from plugin.Plugin.
+type Strategy interface {
+	plugin.Plugin
+
+	OnCandle(ctx *Context, candle tt.OHLCVRecord) error
+	OnTick(ctx *Context, trade tt.TradeRecord) error
+	OnOrderUpdate(ctx *Context, order tt.Order, fill *tt.Fill) error
+}
+
+// BaseStrategy implements the event callbacks with no-ops; embed it to
+// implement only the callbacks a strategy needs
+type BaseStrategy struct{}
+
+func (BaseStrategy) OnCandle(*Context, tt.OHLCVRecord) error          { return nil }
+func (BaseStrategy) OnTick(*Context, tt.TradeRecord) error            { return nil }
+func (BaseStrategy) OnOrderUpdate(*Context, tt.Order, *tt.Fill) error { return nil }
+
+var registeredStrategy Strategy
+
+// Register registers a strategy plugin and generates all WASM exports. Like
+// plugin.RegisterPlugin it MUST be called in init().
+func Register(s Strategy) {
+	registeredStrategy = s
+	plugin.RegisterPlugin(s)
+}
+
+// ============================================================================
+// WASM Exports - Auto-generated by Register
+// ============================================================================
+
+func init() {
+	host.RegisterExport("on_candle", on_candle)
+	host.RegisterExport("on_tick", on_tick)
+	host.RegisterExport("on_order_update", on_order_update)
+}
+
+//go:wasmexport on_candle
+func on_candle() int32 {
+	var ev CandleEvent
+	return handleEvent(&ev, func() (HostContext, func(*Context) error) {
+		return ev.Context, func(ctx *Context) error { return registeredStrategy.OnCandle(ctx, ev.Candle) }
+	})
+}
+
+//go:wasmexport on_tick
+func on_tick() int32 {
+	var ev TickEvent
+	return handleEvent(&ev, func() (HostContext, func(*Context) error) {
+		return ev.Context, func(ctx *Context) error { return registeredStrategy.OnTick(ctx, ev.Trade) }
+	})
+}
+
+//go:wasmexport on_order_update
+func on_order_update() int32 {
+	var ev OrderUpdateEvent
+	return handleEvent(&ev, func() (HostContext, func(*Context) error) {
+		return ev.Context, func(ctx *Context) error { return registeredStrategy.OnOrderUpdate(ctx, ev.Order, ev.Fill) }
+	})
+}
+
+// handleEvent decodes the export input into ev, runs the callback and writes
+// the queued actions
+func handleEvent(ev any, bind func() (HostContext, func(*Context) error)) int32 {
+	if registeredStrategy == nil {
+		host.OutputJSON(Actions{Error: "strategy not registered"})
+		return 1
+	}
+	if err := host.InputJSON(ev); err != nil {
+		host.OutputJSON(Actions{Error: "failed to parse event"})
+		return 1
+	}
+
+	hc, call := bind()
+	defer trace.Set(hc.TraceID)()
+
+	ctx := newContext(hc)
+	if err := call(ctx); err != nil {
+		host.OutputJSON(Actions{Error: err.Error()})
+		return 1
+	}
+	host.OutputJSON(ctx.Actions())
+	return 0
+}
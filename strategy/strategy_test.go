@@ -0,0 +1,68 @@
+//go:build !wasm
+
+package strategy
+
+import (
+	"encoding/json"
+	"testing"
+
+	m "github.com/plusev-terminal/go-plugin-common/meta"
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+type breakout struct {
+	BaseStrategy
+}
+
+func (s *breakout) GetMeta() m.Meta {
+	return m.Meta{PluginID: "breakout", Name: "Breakout", AppID: "strategies", Version: "1.0.0"}
+}
+func (s *breakout) GetConfigFields() []plugin.ConfigField         { return nil }
+func (s *breakout) GetRateLimits() []plugin.RateLimit             { return nil }
+func (s *breakout) OnInit(*plugin.ConfigStore) error              { return nil }
+func (s *breakout) OnShutdown() error                             { return nil }
+func (s *breakout) RegisterCommands(router *plugin.CommandRouter) {}
+
+func (s *breakout) OnCandle(ctx *Context, candle tt.OHLCVRecord) error {
+	if ctx.Position(ctx.Symbol).Quantity != "0" || candle.Close <= candle.Open {
+		return nil
+	}
+	for _, o := range ctx.PendingOrders {
+		if err := ctx.CancelOrder(o.Symbol, o.ID); err != nil {
+			return err
+		}
+	}
+	_, err := ctx.PlaceOrder(tt.Order{Side: tt.SideBuy, Type: tt.OrderTypeMarket, Quantity: "1"})
+	return err
+}
+
+func TestOnCandleExport(t *testing.T) {
+	Register(&breakout{})
+	h := plugintest.New(t)
+
+	input, _ := json.Marshal(CandleEvent{
+		Context: HostContext{
+			Symbol:        "BTCUSDT",
+			Timestamp:     1000,
+			PendingOrders: []tt.Order{{ID: "7", Symbol: "BTCUSDT", Side: tt.SideSell, Type: tt.OrderTypeLimit, Price: "10", Quantity: "1"}},
+		},
+		Candle: tt.OHLCVRecord{Open: "1", Close: "2"},
+	})
+	rc, output, err := h.Invoke("on_candle", input)
+	if err != nil || rc != 0 {
+		t.Fatalf("on_candle failed: %d, %v, %s", rc, err, output)
+	}
+
+	var actions Actions
+	if err := json.Unmarshal(output, &actions); err != nil {
+		t.Fatal(err)
+	}
+	if len(actions.Place) != 1 || actions.Place[0].Symbol != "BTCUSDT" || actions.Place[0].ClientOrderID == "" {
+		t.Fatalf("unexpected orders %+v", actions.Place)
+	}
+	if len(actions.Cancel) != 1 || actions.Cancel[0].ID != "7" {
+		t.Fatalf("unexpected cancels %+v", actions.Cancel)
+	}
+}
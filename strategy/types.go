@@ -0,0 +1,58 @@
+package strategy
+
+import (
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Position is an open position held by the account
+type Position struct {
+	Symbol        string `json:"symbol"`
+	Quantity      string `json:"quantity"` // Negative when short
+	EntryPrice    string `json:"entryPrice"`
+	UnrealizedPnL string `json:"unrealizedPnl,omitempty"`
+}
+
+// HostContext is the account state the host sends with every event
+type HostContext struct {
+	Symbol        string       `json:"symbol"`    // Market the event belongs to
+	Timeframe     string       `json:"timeframe"` // Timeframe of candle events
+	Positions     []Position   `json:"positions"`
+	Balances      []tt.Balance `json:"balances"`
+	PendingOrders []tt.Order   `json:"pendingOrders"`
+	Timestamp     int64        `json:"timestamp"` // Unix timestamp in milliseconds
+	TraceID       string       `json:"traceId,omitempty"`
+}
+
+// CandleEvent is the input of the on_candle export
+type CandleEvent struct {
+	Context HostContext    `json:"context"`
+	Candle  tt.OHLCVRecord `json:"candle"`
+}
+
+// TickEvent is the input of the on_tick export
+type TickEvent struct {
+	Context HostContext    `json:"context"`
+	Trade   tt.TradeRecord `json:"trade"`
+}
+
+// OrderUpdateEvent is the input of the on_order_update export. Fill is set
+// when the update was caused by an execution.
+type OrderUpdateEvent struct {
+	Context HostContext `json:"context"`
+	Order   tt.Order    `json:"order"`
+	Fill    *tt.Fill    `json:"fill,omitempty"`
+}
+
+// CancelRequest identifies an order to cancel
+type CancelRequest struct {
+	Symbol string `json:"symbol"`
+	ID     string `json:"id"`
+}
+
+// Actions is the output of the event exports: the orders the host should
+// place and cancel
+type Actions struct {
+	Place  []tt.Order      `json:"place,omitempty"`
+	Cancel []CancelRequest `json:"cancel,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
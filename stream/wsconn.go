@@ -0,0 +1,352 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+)
+
+// Listen defaults
+const (
+	DefaultPollTimeout = time.Second
+	DefaultBatchSize   = 32
+	DefaultMaxErrors   = 5
+)
+
+var (
+	// ErrWSClosed is matched by the error of operations on a closed connection
+	ErrWSClosed = errors.New("websocket connection closed")
+	// ErrStopListening is returned by a Listen callback to end the loop without error
+	ErrStopListening = errors.New("stop listening")
+)
+
+// CloseError reports that the connection was closed, by the server or Close
+type CloseError struct {
+	Code   int    // WebSocket close code, 0 if the connection was lost without one
+	Reason string // Close reason or the transport error
+}
+
+func (e *CloseError) Error() string {
+	if e.Code == 0 && e.Reason == "" {
+		return ErrWSClosed.Error()
+	}
+	return fmt.Sprintf("%s: %d %s", ErrWSClosed, e.Code, e.Reason)
+}
+
+func (e *CloseError) Is(target error) bool {
+	return target == ErrWSClosed
+}
+
+// WSConnectRequest is the input of the ws_connect host function
+type WSConnectRequest struct {
+	URL          string            `json:"url"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Subprotocols []string          `json:"subprotocols,omitempty"`
+	TimeoutMs    int64             `json:"timeoutMs,omitempty"` // Handshake timeout; 0 uses the host default
+}
+
+// WSConnectResponse is the answer of the ws_connect host function
+type WSConnectResponse struct {
+	ConnectionID string `json:"connectionId"`
+	Subprotocol  string `json:"subprotocol,omitempty"` // Negotiated subprotocol
+	Error        string `json:"error,omitempty"`
+}
+
+// WSSendRequest is the input of the ws_send host function
+type WSSendRequest struct {
+	ConnectionID string `json:"connectionId"`
+	Data         []byte `json:"data"`
+	Binary       bool   `json:"binary,omitempty"`
+}
+
+// WSCloseRequest is the input of the ws_close host function
+type WSCloseRequest struct {
+	ConnectionID string `json:"connectionId"`
+	Code         int    `json:"code,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// WSReceiveRequest is the input of the ws_receive host function. The host
+// answers as soon as a message is pending and otherwise waits up to
+// TimeoutMs; 0 answers immediately.
+type WSReceiveRequest struct {
+	ConnectionID string `json:"connectionId"`
+	MaxMessages  int    `json:"maxMessages"`
+	TimeoutMs    int64  `json:"timeoutMs"`
+}
+
+// WSMessage is an inbound WebSocket message
+type WSMessage struct {
+	Data   []byte `json:"data"`
+	Binary bool   `json:"binary,omitempty"`
+}
+
+// WSReceiveResponse is the answer of the ws_receive host function. Closed is
+// set once the connection is gone and all pending messages were returned.
+type WSReceiveResponse struct {
+	Messages    []WSMessage `json:"messages,omitempty"`
+	Closed      bool        `json:"closed,omitempty"`
+	CloseCode   int         `json:"closeCode,omitempty"`
+	CloseReason string      `json:"closeReason,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// WSResult is the answer of the ws_send and ws_close host functions
+type WSResult struct {
+	Error string `json:"error,omitempty"`
+}
+
+// WSConnection is a WebSocket the plugin owns, as opposed to the
+// host-managed streams started with a StreamMarker. The host keeps the
+// socket and buffers inbound messages until the plugin receives them; the
+// target must be allowed in Meta.Resources.AllowedWebSocketTargets.
+//
+//	conn, err := stream.Dial(stream.WSConnectRequest{URL: "wss://stream.example.com/ws"})
+//	...
+//	conn.SendJSON(map[string]any{"op": "subscribe", "args": []string{"trades.BTCUSDT"}})
+//	err = conn.Listen(func(msg []byte, binary bool) error {
+//	    return c.handleMessage(msg)
+//	}, func(err error) {
+//	    c.log.Warn("receive failed: " + err.Error())
+//	})
+type WSConnection struct {
+	id          string
+	url         string
+	subprotocol string
+	pending     []WSMessage
+	closeErr    *CloseError
+	stopped     bool
+
+	// PollTimeout is how long each receive call waits on the host for
+	// messages. The host blocks, so Listen doesn't busy-wait.
+	PollTimeout time.Duration
+	// BatchSize is the number of messages Listen fetches per host call
+	BatchSize int
+	// NonBlocking makes Listen return once no message is pending instead of
+	// waiting, for plugins that drain the socket from a command or timer
+	NonBlocking bool
+	// MaxErrors is the number of consecutive receive errors after which
+	// Listen gives up
+	MaxErrors int
+}
+
+// Dial opens a WebSocket connection through the host
+func Dial(req WSConnectRequest) (*WSConnection, error) {
+	if err := hostinfo.Require(hostinfo.WSConnect); err != nil {
+		return nil, err
+	}
+	var resp WSConnectResponse
+	if err := callHostJSON(host.WSConnect, host.CallWSConnect, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("connect %s: %s", req.URL, resp.Error)
+	}
+	return &WSConnection{id: resp.ConnectionID, url: req.URL, subprotocol: resp.Subprotocol}, nil
+}
+
+// ID returns the host's ID of the connection
+func (c *WSConnection) ID() string {
+	return c.id
+}
+
+// URL returns the URL the connection was opened with
+func (c *WSConnection) URL() string {
+	return c.url
+}
+
+// Subprotocol returns the subprotocol negotiated during the handshake
+func (c *WSConnection) Subprotocol() string {
+	return c.subprotocol
+}
+
+// Send sends a text message
+func (c *WSConnection) Send(data []byte) error {
+	return c.send(data, false)
+}
+
+// SendBinary sends a binary message
+func (c *WSConnection) SendBinary(data []byte) error {
+	return c.send(data, true)
+}
+
+// SendJSON marshals v and sends it as text message
+func (c *WSConnection) SendJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.send(data, false)
+}
+
+func (c *WSConnection) send(data []byte, binary bool) error {
+	if c.closeErr != nil {
+		return c.closeErr
+	}
+	var res WSResult
+	if err := callHostJSON(host.WSSend, host.CallWSSend, WSSendRequest{ConnectionID: c.id, Data: data, Binary: binary}, &res); err != nil {
+		return err
+	}
+	if res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+// Receive returns the next message, waiting up to timeout for one. ok is
+// false if none arrived in time. After the connection closed, the remaining
+// messages are returned first, then a *CloseError.
+func (c *WSConnection) Receive(timeout time.Duration) (msg WSMessage, ok bool, err error) {
+	if len(c.pending) == 0 {
+		c.pending, err = c.ReceiveBatch(DefaultBatchSize, timeout)
+		if err != nil || len(c.pending) == 0 {
+			return WSMessage{}, false, err
+		}
+	}
+	msg = c.pending[0]
+	c.pending = c.pending[1:]
+	return msg, true, nil
+}
+
+// ReceiveBatch returns up to max messages in one host call, waiting up to
+// timeout if none is pending. Messages buffered by Receive come first.
+func (c *WSConnection) ReceiveBatch(max int, timeout time.Duration) ([]WSMessage, error) {
+	if len(c.pending) > 0 {
+		n := min(max, len(c.pending))
+		batch := c.pending[:n:n]
+		c.pending = c.pending[n:]
+		return batch, nil
+	}
+	if c.closeErr != nil {
+		return nil, c.closeErr
+	}
+
+	var res WSReceiveResponse
+	req := WSReceiveRequest{ConnectionID: c.id, MaxMessages: max, TimeoutMs: timeout.Milliseconds()}
+	if err := callHostJSON(host.WSReceive, host.CallWSReceive, req, &res); err != nil {
+		return nil, err
+	}
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+	if res.Closed {
+		c.closeErr = &CloseError{Code: res.CloseCode, Reason: res.CloseReason}
+		if len(res.Messages) == 0 {
+			return nil, c.closeErr
+		}
+	}
+	return res.Messages, nil
+}
+
+// Listen receives messages and passes them to onMessage until the
+// connection closes, onMessage fails or Stop is called. Receive errors go
+// to onError, which may be nil, and Listen keeps going until MaxErrors
+// errors occurred in a row.
+//
+// Returning ErrStopListening from onMessage ends Listen with a nil error,
+// any other error ends it with that error. When the connection closes,
+// Listen returns a *CloseError; reconnecting is up to the caller.
+func (c *WSConnection) Listen(onMessage func(msg []byte, binary bool) error, onError func(error)) error {
+	timeout := c.PollTimeout
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+	if c.NonBlocking {
+		timeout = 0
+	}
+	batchSize := c.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	maxErrors := c.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxErrors
+	}
+
+	c.stopped = false
+	errorsInRow := 0
+	for !c.stopped {
+		batch, err := c.ReceiveBatch(batchSize, timeout)
+		if err != nil {
+			if errors.Is(err, ErrWSClosed) {
+				return err
+			}
+			errorsInRow++
+			if onError != nil {
+				onError(err)
+			}
+			if errorsInRow >= maxErrors {
+				return fmt.Errorf("%d receive errors in a row: %w", errorsInRow, err)
+			}
+			continue
+		}
+		errorsInRow = 0
+
+		if len(batch) == 0 && c.NonBlocking {
+			return nil
+		}
+		for i, msg := range batch {
+			if err := onMessage(msg.Data, msg.Binary); err != nil {
+				// Keep what the callback didn't see for the next Receive
+				c.pending = append(batch[i+1:len(batch):len(batch)], c.pending...)
+				if errors.Is(err, ErrStopListening) {
+					return nil
+				}
+				return err
+			}
+			if c.stopped {
+				c.pending = append(batch[i+1:len(batch):len(batch)], c.pending...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Stop makes a running Listen return after the current message
+func (c *WSConnection) Stop() {
+	c.stopped = true
+}
+
+// Closed reports whether the connection is known to be closed
+func (c *WSConnection) Closed() bool {
+	return c.closeErr != nil
+}
+
+// Close closes the connection with code 1000. Closing a closed connection
+// is a no-op. The connection counts as closed even if the host reports an
+// error.
+func (c *WSConnection) Close() error {
+	if c.closeErr != nil {
+		return nil
+	}
+	closeErr := &CloseError{Code: 1000, Reason: "closed by plugin"}
+	c.closeErr = closeErr
+	c.pending = nil
+
+	var res WSResult
+	req := WSCloseRequest{ConnectionID: c.id, Code: closeErr.Code, Reason: closeErr.Reason}
+	if err := callHostJSON(host.WSClose, host.CallWSClose, req, &res); err != nil {
+		return err
+	}
+	if res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+// callHostJSON sends in as JSON to a host function and decodes the JSON answer into out
+func callHostJSON(name string, call host.Func, in any, out any) error {
+	ok, err := host.CallJSON(call, in, out)
+	if err != nil {
+		return fmt.Errorf("host call %s failed: %w", name, err)
+	}
+	if !ok {
+		return fmt.Errorf("host call %s returned no response", name)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+//go:build !wasm
+
+package stream_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/hostinfo"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+	"github.com/plusev-terminal/go-plugin-common/stream"
+)
+
+const wsURL = "wss://stream.example.com/ws"
+
+func dial(t *testing.T) (*plugintest.Harness, *plugintest.WSServer, *stream.WSConnection) {
+	t.Helper()
+	h := plugintest.New(t)
+	server := plugintest.NewWSServer()
+	h.WebSockets[wsURL] = server
+	conn, err := stream.Dial(stream.WSConnectRequest{URL: wsURL, Subprotocols: []string{"v2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h, server, conn
+}
+
+func TestWSConnectionListen(t *testing.T) {
+	_, server, conn := dial(t)
+	if conn.Subprotocol() != "v2" {
+		t.Errorf("unexpected subprotocol %q", conn.Subprotocol())
+	}
+	if err := conn.SendJSON(map[string]string{"op": "subscribe"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := server.Sent(); len(sent) != 1 || sent[0] != `{"op":"subscribe"}` {
+		t.Errorf("unexpected sent messages %v", sent)
+	}
+
+	server.Push("a")
+	server.PushBinary([]byte{1, 2})
+	server.Push("b")
+	server.Push("stop")
+	server.Push("c")
+
+	conn.BatchSize = 2
+	var got []string
+	err := conn.Listen(func(msg []byte, binary bool) error {
+		if string(msg) == "stop" {
+			return stream.ErrStopListening
+		}
+		if binary {
+			got = append(got, "bin")
+		} else {
+			got = append(got, string(msg))
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"a", "bin", "b"}) {
+		t.Errorf("unexpected messages %v", got)
+	}
+
+	// The message after the stop is kept for the next receive
+	msg, ok, err := conn.Receive(0)
+	if err != nil || !ok || string(msg.Data) != "c" {
+		t.Errorf("expected the remaining message, got %q %v %v", msg.Data, ok, err)
+	}
+}
+
+func TestWSConnectionNonBlockingAndClose(t *testing.T) {
+	_, server, conn := dial(t)
+	conn.NonBlocking = true
+
+	server.Push("a")
+	count := 0
+	if err := conn.Listen(func([]byte, bool) error { count++; return nil }, nil); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected Listen to drain one message and return, got %d", count)
+	}
+
+	server.Push("last")
+	server.Drop(1001, "going away")
+	count = 0
+	err := conn.Listen(func([]byte, bool) error { count++; return nil }, nil)
+	var closeErr *stream.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != 1001 || !errors.Is(err, stream.ErrWSClosed) {
+		t.Fatalf("expected a close error, got %v", err)
+	}
+	if count != 1 || !conn.Closed() {
+		t.Errorf("expected the pending message before the close, got %d", count)
+	}
+	if err := conn.Send([]byte("x")); !errors.Is(err, stream.ErrWSClosed) {
+		t.Errorf("expected send on a closed connection to fail, got %v", err)
+	}
+}
+
+func TestWSConnectionCallbackError(t *testing.T) {
+	_, server, conn := dial(t)
+	server.Push("a")
+	boom := errors.New("boom")
+	if err := conn.Listen(func([]byte, bool) error { return boom }, nil); !errors.Is(err, boom) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if server.Connected() {
+		t.Error("expected Close to close the server side")
+	}
+	if code, reason := server.CloseStatus(); code != 1000 || reason != "closed by plugin" {
+		t.Errorf("expected close 1000 \"closed by plugin\", got %d %q", code, reason)
+	}
+	if err := conn.Close(); err != nil {
+		t.Errorf("expected closing twice to be a no-op, got %v", err)
+	}
+}
+
+func TestDialUnsupportedHost(t *testing.T) {
+	h := plugintest.New(t)
+	h.SetCapabilities(hostinfo.Capabilities{ProtocolVersion: 1, Functions: hostinfo.Legacy})
+	if _, err := stream.Dial(stream.WSConnectRequest{URL: wsURL}); !errors.Is(err, hostinfo.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,6 @@
+package table
+
+const (
+	CMD_LIST_TABLES = "listTables"
+	CMD_QUERY_TABLE = "queryTable"
+)
@@ -0,0 +1,80 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/plusev-terminal/go-plugin-common/utils"
+)
+
+// FilterOp is the comparison of a Filter
+type FilterOp string
+
+const (
+	OpEq       FilterOp = "eq"
+	OpNe       FilterOp = "ne"
+	OpLt       FilterOp = "lt"
+	OpLte      FilterOp = "lte"
+	OpGt       FilterOp = "gt"
+	OpGte      FilterOp = "gte"
+	OpIn       FilterOp = "in"       // Value is a list
+	OpContains FilterOp = "contains" // Case-insensitive substring match
+)
+
+// Filter restricts the rows of a query. All filters of a query must match.
+type Filter struct {
+	Column string   `json:"column" mapstructure:"column"`
+	Op     FilterOp `json:"op" mapstructure:"op"`
+	Value  any      `json:"value" mapstructure:"value"`
+}
+
+// SortKey orders query results by a column
+type SortKey struct {
+	Column     string `json:"column" mapstructure:"column"`
+	Descending bool   `json:"descending,omitempty" mapstructure:"descending"`
+}
+
+// QueryRequest contains parameters for the queryTable command
+type QueryRequest struct {
+	Table   string    `json:"table" mapstructure:"table" validate:"required"`
+	Columns []string  `json:"columns,omitempty" mapstructure:"columns"` // Empty selects all columns
+	Filters []Filter  `json:"filters,omitempty" mapstructure:"filters"`
+	Sort    []SortKey `json:"sort,omitempty" mapstructure:"sort"`
+	Limit   int       `json:"limit,omitempty" mapstructure:"limit"` // 0 lets the plugin choose the page size
+	Cursor  string    `json:"cursor,omitempty" mapstructure:"cursor"`
+}
+
+func (p QueryRequest) Validate() error {
+	if p.Table == "" {
+		return fmt.Errorf("table is required")
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	for i, f := range p.Filters {
+		if f.Column == "" {
+			return fmt.Errorf("filters[%d].column is required", i)
+		}
+		switch f.Op {
+		case OpEq, OpNe, OpLt, OpLte, OpGt, OpGte, OpContains:
+		case OpIn:
+			if _, ok := f.Value.([]any); !ok {
+				return fmt.Errorf("filters[%d].value must be a list for op in", i)
+			}
+		default:
+			return fmt.Errorf("filters[%d].op %q is not supported", i, f.Op)
+		}
+	}
+	for i, key := range p.Sort {
+		if key.Column == "" {
+			return fmt.Errorf("sort[%d].column is required", i)
+		}
+	}
+	return nil
+}
+
+// QueryRequestFromMap extracts QueryRequest from validated map
+func QueryRequestFromMap(data map[string]any) QueryRequest {
+	var params QueryRequest
+	_ = utils.MapToStruct(data, &params)
+	return params
+}
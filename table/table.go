@@ -0,0 +1,229 @@
+// Package table defines a generic tabular query contract. Analytics plugins
+// expose named tables with a column schema; the host renders and exports
+// them without knowing their shape in advance.
+//
+//	func (p *MyPlugin) RegisterCommands(router *plugin.CommandRouter) {
+//	    router.Register(table.CMD_LIST_TABLES, table.HandleList(p.tables))
+//	    router.Register(table.CMD_QUERY_TABLE, table.HandleQuery(p.query))
+//	}
+//
+// Plugins holding their data in memory can answer queries with Query, which
+// applies filters, sorting, projection and paging.
+package table
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plusev-terminal/go-plugin-common/plugin"
+)
+
+// QueryFunc answers a validated query
+type QueryFunc func(req QueryRequest) (QueryResult, error)
+
+// HandleList adapts a table list to the listTables command handler
+func HandleList(tables func() []TableInfo) plugin.CommandHandler {
+	return func(params map[string]any) plugin.Response {
+		return plugin.SuccessResponse(tables())
+	}
+}
+
+// HandleQuery adapts fn to the queryTable command handler. The request is
+// validated before fn is called and the result is checked to have one value
+// per column in every row.
+func HandleQuery(fn QueryFunc) plugin.CommandHandler {
+	return func(params map[string]any) plugin.Response {
+		req := QueryRequestFromMap(params)
+		if err := req.Validate(); err != nil {
+			return plugin.ErrorResponse(err)
+		}
+
+		result, err := fn(req)
+		if err != nil {
+			return plugin.ErrorResponse(err)
+		}
+		for i, row := range result.Rows {
+			if len(row) != len(result.Columns) {
+				return plugin.ErrorResponse(fmt.Errorf("row %d has %d values for %d columns", i, len(row), len(result.Columns)))
+			}
+		}
+		if result.Rows == nil {
+			result.Rows = [][]any{}
+		}
+		return plugin.SuccessResponse(result)
+	}
+}
+
+// Query answers req from rows held in memory. Cursors are row offsets.
+func Query(req QueryRequest, info TableInfo, rows [][]any) (QueryResult, error) {
+	index := make(map[string]int, len(info.Columns))
+	for i, c := range info.Columns {
+		index[c.Key] = i
+	}
+	column := func(key string) (int, error) {
+		i, ok := index[key]
+		if !ok {
+			return 0, fmt.Errorf("unknown column %q", key)
+		}
+		return i, nil
+	}
+
+	matched := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		ok := true
+		for _, f := range req.Filters {
+			i, err := column(f.Column)
+			if err != nil {
+				return QueryResult{}, err
+			}
+			if !f.Match(row[i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, row)
+		}
+	}
+
+	for _, key := range req.Sort {
+		if _, err := column(key.Column); err != nil {
+			return QueryResult{}, err
+		}
+	}
+	if len(req.Sort) > 0 {
+		sort.SliceStable(matched, func(a, b int) bool {
+			for _, key := range req.Sort {
+				i := index[key.Column]
+				c := compare(matched[a][i], matched[b][i])
+				if c == 0 {
+					continue
+				}
+				if key.Descending {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	offset := 0
+	if req.Cursor != "" {
+		n, err := strconv.Atoi(req.Cursor)
+		if err != nil || n < 0 {
+			return QueryResult{}, fmt.Errorf("invalid cursor %q", req.Cursor)
+		}
+		offset = min(n, len(matched))
+	}
+	end := len(matched)
+	if req.Limit > 0 && offset+req.Limit < end {
+		end = offset + req.Limit
+	}
+
+	selected := make([]int, 0, len(info.Columns))
+	result := QueryResult{Table: info.Name, Total: len(matched)}
+	if len(req.Columns) == 0 {
+		for i := range info.Columns {
+			selected = append(selected, i)
+		}
+	} else {
+		for _, key := range req.Columns {
+			i, err := column(key)
+			if err != nil {
+				return QueryResult{}, err
+			}
+			selected = append(selected, i)
+		}
+	}
+	for _, i := range selected {
+		result.Columns = append(result.Columns, info.Columns[i])
+	}
+
+	result.Rows = make([][]any, 0, end-offset)
+	for _, row := range matched[offset:end] {
+		out := make([]any, len(selected))
+		for j, i := range selected {
+			out[j] = row[i]
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	if end < len(matched) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+// Match reports whether v satisfies the filter. Numbers, including decimal
+// strings, compare numerically; other values compare as strings.
+func (f Filter) Match(v any) bool {
+	switch f.Op {
+	case OpEq:
+		return compare(v, f.Value) == 0
+	case OpNe:
+		return compare(v, f.Value) != 0
+	case OpLt:
+		return v != nil && compare(v, f.Value) < 0
+	case OpLte:
+		return v != nil && compare(v, f.Value) <= 0
+	case OpGt:
+		return v != nil && compare(v, f.Value) > 0
+	case OpGte:
+		return v != nil && compare(v, f.Value) >= 0
+	case OpIn:
+		list, _ := f.Value.([]any)
+		for _, item := range list {
+			if compare(v, item) == 0 {
+				return true
+			}
+		}
+		return false
+	case OpContains:
+		return strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(fmt.Sprint(f.Value)))
+	}
+	return false
+}
+
+// compare orders a before b (-1), after b (1) or equal (0); nil sorts last
+func compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
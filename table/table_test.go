@@ -0,0 +1,64 @@
+package table
+
+import "testing"
+
+var trades = TableInfo{
+	Name: "trades",
+	Columns: []Column{
+		{Key: "symbol", Type: ColumnSymbol},
+		{Key: "pnl", Type: ColumnDecimal},
+		{Key: "time", Type: ColumnTime},
+	},
+}
+
+var tradeRows = [][]any{
+	{"BTC", "12.5", int64(3)},
+	{"ETH", "-4", int64(1)},
+	{"BTC", "100", int64(2)},
+	{"SOL", "7", int64(4)},
+}
+
+func TestQueryFiltersSortsAndPages(t *testing.T) {
+	req := QueryRequestFromMap(map[string]any{
+		"table":   "trades",
+		"columns": []any{"pnl", "symbol"},
+		"filters": []any{map[string]any{"column": "pnl", "op": "gt", "value": 0}},
+		"sort":    []any{map[string]any{"column": "pnl", "descending": true}},
+		"limit":   2,
+	})
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Query(req, trades, tradeRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 || len(result.Rows) != 2 || result.NextCursor != "2" {
+		t.Fatalf("unexpected page %+v", result)
+	}
+	if result.Columns[0].Key != "pnl" || result.Rows[0][0] != "100" || result.Rows[1][1] != "BTC" {
+		t.Fatalf("unexpected rows %v", result.Rows)
+	}
+
+	req.Cursor = result.NextCursor
+	result, err = Query(req, trades, tradeRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][1] != "SOL" || result.NextCursor != "" {
+		t.Fatalf("unexpected last page %+v", result)
+	}
+}
+
+func TestHandleQueryChecksRowWidth(t *testing.T) {
+	handler := HandleQuery(func(req QueryRequest) (QueryResult, error) {
+		return QueryResult{Table: req.Table, Columns: trades.Columns, Rows: [][]any{{"BTC"}}}, nil
+	})
+	if resp := handler(map[string]any{"table": "trades"}); resp.Result {
+		t.Fatal("expected error for short row")
+	}
+	if resp := handler(map[string]any{}); resp.Result {
+		t.Fatal("expected error for missing table")
+	}
+}
@@ -0,0 +1,46 @@
+package table
+
+// ColumnType tells the host how to render, sort and export a column
+type ColumnType string
+
+const (
+	ColumnString  ColumnType = "string"
+	ColumnNumber  ColumnType = "number"
+	ColumnDecimal ColumnType = "decimal" // Decimal string, e.g. a price or amount
+	ColumnPercent ColumnType = "percent" // Number where 1 means 1%
+	ColumnTime    ColumnType = "time"    // Unix timestamp in milliseconds
+	ColumnBool    ColumnType = "bool"
+	ColumnSymbol  ColumnType = "symbol"
+)
+
+// Column describes one column of a table
+type Column struct {
+	Key         string     `json:"key"`
+	Label       string     `json:"label"`
+	Type        ColumnType `json:"type"`
+	Description string     `json:"description,omitempty"`
+	Unit        string     `json:"unit,omitempty"`     // e.g. "USD", shown in the header and exports
+	Decimals    int        `json:"decimals,omitempty"` // Display precision for numeric columns
+	Filterable  bool       `json:"filterable,omitempty"`
+	Sortable    bool       `json:"sortable,omitempty"`
+}
+
+// TableInfo describes a table a plugin exposes. It is the response data of
+// the listTables command.
+type TableInfo struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Description string   `json:"description,omitempty"`
+	Columns     []Column `json:"columns"`
+}
+
+// QueryResult is the response data of the queryTable command. Rows hold
+// their values in the order of Columns. An empty NextCursor means the last
+// page has been reached.
+type QueryResult struct {
+	Table      string   `json:"table"`
+	Columns    []Column `json:"columns"`
+	Rows       [][]any  `json:"rows"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+	Total      int      `json:"total,omitempty"` // Rows matching the filters, 0 if unknown
+}
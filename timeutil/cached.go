@@ -0,0 +1,95 @@
+// Package timeutil provides a cheap host-synchronized time source.
+//
+// wasmutils.Now performs a host call and a JSON decode on every read, which
+// adds up in stream handlers that timestamp every message. CachedClock reads
+// the host time once, advances it with the monotonic clock of the runtime and
+// re-syncs periodically.
+package timeutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// DefaultResync is the re-sync interval of the package-level clock
+const DefaultResync = time.Minute
+
+// CachedClock is a clock.Clock serving host time from a monotonic offset
+type CachedClock struct {
+	mu        sync.Mutex
+	source    clock.Clock
+	monotonic clock.Clock
+	resync    time.Duration
+
+	base     time.Time // Source time at the last sync
+	baseMono time.Time // Monotonic reading at the last sync
+}
+
+// NewCachedClock creates a clock that syncs with source at most every
+// resync. A resync of 0 syncs only once.
+func NewCachedClock(source clock.Clock, resync time.Duration) *CachedClock {
+	return &CachedClock{
+		source:    source,
+		monotonic: clock.System,
+		resync:    resync,
+	}
+}
+
+// SetMonotonic replaces the monotonic clock, e.g. with a clock.Fake in tests
+func (c *CachedClock) SetMonotonic(monotonic clock.Clock) *CachedClock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.monotonic = monotonic
+	c.base = time.Time{}
+	return c
+}
+
+// Now implements clock.Clock
+func (c *CachedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mono := c.monotonic.Now()
+	elapsed := mono.Sub(c.baseMono)
+	if c.base.IsZero() || (c.resync > 0 && elapsed >= c.resync) {
+		c.sync(mono)
+		elapsed = 0
+	}
+	return c.base.Add(elapsed)
+}
+
+// Sync forces a re-sync with the source on the next read
+func (c *CachedClock) Sync() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base = time.Time{}
+}
+
+func (c *CachedClock) sync(mono time.Time) {
+	base := c.source.Now()
+	if base.IsZero() {
+		// Source failed; keep advancing the previous base, or fall back to
+		// the monotonic clock's own time, and retry after the next interval
+		base = mono
+		if !c.base.IsZero() {
+			base = c.base.Add(mono.Sub(c.baseMono))
+		}
+	}
+	c.base = base
+	c.baseMono = mono
+}
+
+var defaultClock = NewCachedClock(wasmutils.HostClock, DefaultResync)
+
+// Default returns the package-level cached host clock
+func Default() *CachedClock {
+	return defaultClock
+}
+
+// Now returns the host time from the package-level cached clock
+func Now() time.Time {
+	return defaultClock.Now()
+}
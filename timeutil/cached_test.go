@@ -0,0 +1,39 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+)
+
+func TestCachedClock(t *testing.T) {
+	hostTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	source := clock.Func(func() time.Time {
+		calls++
+		return hostTime
+	})
+	mono := clock.NewFake(time.Unix(0, 0))
+	c := NewCachedClock(source, time.Minute).SetMonotonic(mono)
+
+	if got := c.Now(); !got.Equal(hostTime) {
+		t.Fatalf("expected host time, got %s", got)
+	}
+	mono.Advance(30 * time.Second)
+	if got := c.Now(); !got.Equal(hostTime.Add(30 * time.Second)) {
+		t.Errorf("expected monotonic offset, got %s", got)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single host call, got %d", calls)
+	}
+
+	hostTime = hostTime.Add(2 * time.Minute) // host clock drifted ahead
+	mono.Advance(30 * time.Second)
+	if got := c.Now(); !got.Equal(hostTime) {
+		t.Errorf("expected re-sync after interval, got %s", got)
+	}
+	if calls != 2 {
+		t.Errorf("expected re-sync host call, got %d calls", calls)
+	}
+}
@@ -0,0 +1,38 @@
+// Package trace carries the host-supplied trace ID of the command or stream
+// message being handled, so logs and outgoing requests of the plugin can be
+// correlated with the host's logs.
+//
+// Plugins don't need to call Set: the command and stream exports set the ID
+// from the incoming Command or StreamMessageRequest for the duration of the
+// handler, and logging and requester pick it up automatically.
+package trace
+
+import "sync"
+
+// DataKey is the log record data key carrying the trace ID
+const DataKey = "traceId"
+
+var (
+	mu      sync.RWMutex
+	current string
+)
+
+// Set makes id the current trace ID and returns a function restoring the previous one
+func Set(id string) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := current
+	current = id
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		current = prev
+	}
+}
+
+// Current returns the trace ID of the handler running now, or ""
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
@@ -0,0 +1,195 @@
+// Package depth aggregates order books for DOM ladders, depth charts and
+// heatmaps: price bucketing, cumulative depth, imbalance and top-N views.
+// Exchange plugins emit raw trading.Orderbook snapshots; the host or an
+// analysis node turns them into a Snapshot.
+//
+//	snap, err := depth.Build(book, market.PriceTick, depth.Options{Multiple: 10, Levels: 20})
+package depth
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/trading/utils"
+)
+
+// Bucket is the aggregated quantity of a price range. Price is the bucket
+// boundary closest to the spread: the lower bound for bids, the upper bound
+// for asks.
+type Bucket struct {
+	Price      string `json:"price"`
+	Quantity   string `json:"quantity"`
+	Cumulative string `json:"cumulative"` // Quantity of this and all better buckets
+	Levels     int    `json:"levels"`     // Order book levels merged into the bucket
+}
+
+// Snapshot is an aggregated order book as consumed by the host charts
+type Snapshot struct {
+	Symbol     string   `json:"symbol"`
+	Timestamp  int64    `json:"timestamp"` // Unix timestamp in milliseconds
+	BucketSize string   `json:"bucketSize"`
+	Bids       []Bucket `json:"bids"` // Best (highest) first
+	Asks       []Bucket `json:"asks"` // Best (lowest) first
+	BidDepth   string   `json:"bidDepth"`
+	AskDepth   string   `json:"askDepth"`
+	Imbalance  float64  `json:"imbalance"` // (bid - ask) / (bid + ask) over the buckets shown, -1 to 1
+}
+
+// Options configures Build
+type Options struct {
+	Multiple int // Bucket size in ticks; 0 or 1 keeps every tick
+	Levels   int // Buckets per side; 0 keeps all
+}
+
+// Build buckets book by tick*opts.Multiple, keeps the best opts.Levels
+// buckets per side and computes cumulative depth and imbalance
+func Build(book tt.Orderbook, tick string, opts Options) (Snapshot, error) {
+	t, err := strconv.ParseFloat(tick, 64)
+	if err != nil || t <= 0 {
+		return Snapshot{}, fmt.Errorf("invalid tick %q", tick)
+	}
+	multiple := max(opts.Multiple, 1)
+	size := t * float64(multiple)
+	decimals := utils.TickDecimals(tick)
+
+	bids, err := aggregate(book.Bids, size, decimals, false)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("bids: %w", err)
+	}
+	asks, err := aggregate(book.Asks, size, decimals, true)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("asks: %w", err)
+	}
+	if opts.Levels > 0 {
+		bids = bids[:min(len(bids), opts.Levels)]
+		asks = asks[:min(len(asks), opts.Levels)]
+	}
+
+	snap := Snapshot{
+		Symbol:     book.Symbol,
+		Timestamp:  book.Timestamp,
+		BucketSize: strconv.FormatFloat(size, 'f', decimals, 64),
+		Bids:       bids,
+		Asks:       asks,
+		BidDepth:   "0",
+		AskDepth:   "0",
+	}
+	if len(bids) > 0 {
+		snap.BidDepth = bids[len(bids)-1].Cumulative
+	}
+	if len(asks) > 0 {
+		snap.AskDepth = asks[len(asks)-1].Cumulative
+	}
+	bidDepth, _ := strconv.ParseFloat(snap.BidDepth, 64)
+	askDepth, _ := strconv.ParseFloat(snap.AskDepth, 64)
+	snap.Imbalance = imbalance(bidDepth, askDepth)
+	return snap, nil
+}
+
+// Top returns a copy of book reduced to the best n levels per side
+func Top(book tt.Orderbook, n int) tt.Orderbook {
+	out := book
+	out.Bids = append([]tt.OrderbookLevel(nil), book.Bids[:min(len(book.Bids), n)]...)
+	out.Asks = append([]tt.OrderbookLevel(nil), book.Asks[:min(len(book.Asks), n)]...)
+	return out
+}
+
+// Imbalance returns (bid - ask) / (bid + ask) of the quantity in the best n
+// levels per side; n <= 0 uses all levels. Positive values mean more resting
+// bids than asks.
+func Imbalance(book tt.Orderbook, n int) (float64, error) {
+	if n <= 0 {
+		n = max(len(book.Bids), len(book.Asks))
+	}
+	var sums [2]float64
+	for i, levels := range [][]tt.OrderbookLevel{book.Bids, book.Asks} {
+		for _, l := range levels[:min(len(levels), n)] {
+			q, err := strconv.ParseFloat(l.Quantity, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid quantity %q at %s", l.Quantity, l.Price)
+			}
+			sums[i] += q
+		}
+	}
+	return imbalance(sums[0], sums[1]), nil
+}
+
+// Cumulative returns the running quantity sums of levels, best first
+func Cumulative(levels []tt.OrderbookLevel) ([]string, error) {
+	out := make([]string, len(levels))
+	total := 0.0
+	for i, l := range levels {
+		q, err := strconv.ParseFloat(l.Quantity, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q at %s", l.Quantity, l.Price)
+		}
+		total += q
+		out[i] = utils.FormatSignificant(total)
+	}
+	return out, nil
+}
+
+// aggregate merges sorted levels into buckets of size. Bids round down and
+// asks round up, so a bucket never crosses the spread.
+func aggregate(levels []tt.OrderbookLevel, size float64, decimals int, ask bool) ([]Bucket, error) {
+	buckets := make([]Bucket, 0, len(levels))
+	var price, quantity, total float64
+	count := 0
+
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		total += quantity
+		buckets = append(buckets, Bucket{
+			Price:      strconv.FormatFloat(price, 'f', decimals, 64),
+			Quantity:   utils.FormatSignificant(quantity),
+			Cumulative: utils.FormatSignificant(total),
+			Levels:     count,
+		})
+	}
+
+	for _, l := range levels {
+		p, err := strconv.ParseFloat(l.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q", l.Price)
+		}
+		q, err := strconv.ParseFloat(l.Quantity, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q at %s", l.Quantity, l.Price)
+		}
+
+		// The epsilon keeps prices already on a boundary in their bucket
+		var bucket float64
+		if ask {
+			bucket = math.Ceil(p/size-1e-9) * size
+		} else {
+			bucket = math.Floor(p/size+1e-9) * size
+		}
+		bucket = roundTo(bucket, decimals)
+
+		if count > 0 && bucket != price {
+			flush()
+			quantity, count = 0, 0
+		}
+		price = bucket
+		quantity += q
+		count++
+	}
+	flush()
+	return buckets, nil
+}
+
+func imbalance(bid, ask float64) float64 {
+	if bid+ask == 0 {
+		return 0
+	}
+	return (bid - ask) / (bid + ask)
+}
+
+func roundTo(v float64, decimals int) float64 {
+	pow := math.Pow10(decimals)
+	return math.Round(v*pow) / pow
+}
@@ -0,0 +1,72 @@
+package depth
+
+import (
+	"testing"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+var book = tt.Orderbook{
+	Symbol: "BTCUSDT",
+	Bids: []tt.OrderbookLevel{
+		{Price: "100.05", Quantity: "1"},
+		{Price: "100.00", Quantity: "2"},
+		{Price: "99.95", Quantity: "3"},
+		{Price: "99.80", Quantity: "4"},
+	},
+	Asks: []tt.OrderbookLevel{
+		{Price: "100.10", Quantity: "1"},
+		{Price: "100.15", Quantity: "1.5"},
+		{Price: "100.30", Quantity: "2"},
+	},
+}
+
+func TestBuildBuckets(t *testing.T) {
+	snap, err := Build(book, "0.05", Options{Multiple: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.BucketSize != "0.10" {
+		t.Fatalf("unexpected bucket size %s", snap.BucketSize)
+	}
+
+	// Bids floor: 100.05 and 100.00 -> 100.00, 99.95 -> 99.90, 99.80 -> 99.80
+	want := []Bucket{
+		{Price: "100.00", Quantity: "3", Cumulative: "3", Levels: 2},
+		{Price: "99.90", Quantity: "3", Cumulative: "6", Levels: 1},
+		{Price: "99.80", Quantity: "4", Cumulative: "10", Levels: 1},
+	}
+	if len(snap.Bids) != len(want) {
+		t.Fatalf("unexpected bids %+v", snap.Bids)
+	}
+	for i := range want {
+		if snap.Bids[i] != want[i] {
+			t.Fatalf("bid %d: expected %+v, got %+v", i, want[i], snap.Bids[i])
+		}
+	}
+
+	// Asks ceil: 100.10 -> 100.10, 100.15 -> 100.20, 100.30 -> 100.30
+	if len(snap.Asks) != 3 || snap.Asks[1].Price != "100.20" || snap.AskDepth != "4.5" {
+		t.Fatalf("unexpected asks %+v", snap.Asks)
+	}
+}
+
+func TestImbalanceAndTop(t *testing.T) {
+	imb, err := Imbalance(book, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imb != 0 {
+		t.Fatalf("expected balanced top level, got %f", imb)
+	}
+
+	top := Top(book, 2)
+	if len(top.Bids) != 2 || len(top.Asks) != 2 || len(book.Bids) != 4 {
+		t.Fatal("unexpected top-N result")
+	}
+
+	snap, _ := Build(book, "0.05", Options{Levels: 1})
+	if snap.Imbalance != 0 || snap.BidDepth != "1" {
+		t.Fatalf("unexpected limited snapshot %+v", snap)
+	}
+}
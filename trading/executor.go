@@ -0,0 +1,24 @@
+package trading
+
+// Balance is the holding of one asset. Amounts are strings to preserve precision.
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"` // Reserved by open orders
+}
+
+// OrderExecutor places and manages orders on a venue. Live execution code and
+// the paper trading account implement it, so strategies can switch between
+// the two without changes.
+type OrderExecutor interface {
+	// PlaceOrder submits an order and returns it with ID and status set
+	PlaceOrder(order Order) (Order, error)
+	// CancelOrder cancels an open order
+	CancelOrder(symbol, id string) error
+	// GetOrder returns the current state of an order
+	GetOrder(symbol, id string) (Order, error)
+	// OpenOrders returns the open orders of symbol, or of all markets if symbol is empty
+	OpenOrders(symbol string) ([]Order, error)
+	// Balances returns the account balances
+	Balances() ([]Balance, error)
+}
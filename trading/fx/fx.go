@@ -0,0 +1,217 @@
+// Package fx converts amounts between currencies using the prices of
+// available markets. Rates form a graph of assets; conversions without a
+// direct market are resolved over the fewest intermediate assets, e.g.
+// ADA→EUR via ADA/USDT and EUR/USDT.
+//
+//	conv := fx.NewConverter(5 * time.Minute)
+//	conv.SetMarketPrice(adaUsdt, "0.45", ts)
+//	conv.SetMarketPrice(eurUsdt, "1.08", ts)
+//
+//	value, quote, err := conv.Convert("1000", "ADA", "EUR")
+//	if quote.Stale {
+//	    // one of the prices is older than five minutes
+//	}
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/trading/utils"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// ErrNoPath is returned when no chain of rates connects two currencies
+var ErrNoPath = errors.New("fx: no conversion path")
+
+// Quote is a resolved conversion rate. Price amounts of From are multiplied
+// by Rate to get amounts of To.
+type Quote struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Rate  string    `json:"rate"`
+	Path  []string  `json:"path"`           // Assets visited, starting with From and ending with To
+	AsOf  time.Time `json:"asOf,omitempty"` // Time of the oldest rate used; zero for fixed rates only
+	Stale bool      `json:"stale,omitempty"`
+}
+
+// edge is a rate from one asset to another
+type edge struct {
+	rate float64
+	at   time.Time // zero for fixed rates, which never go stale
+}
+
+// Converter holds the latest rates and resolves conversions between them.
+// It is safe for concurrent use.
+type Converter struct {
+	mu     sync.RWMutex
+	edges  map[string]map[string]edge
+	maxAge time.Duration
+	clock  clock.Clock
+}
+
+// NewConverter creates a converter that marks quotes as stale when one of
+// their rates is older than maxAge; 0 disables staleness checks
+func NewConverter(maxAge time.Duration) *Converter {
+	return &Converter{
+		edges:  make(map[string]map[string]edge),
+		maxAge: maxAge,
+		clock:  wasmutils.HostClock,
+	}
+}
+
+// SetClock replaces the clock used for staleness checks
+func (c *Converter) SetClock(cl clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = cl
+}
+
+// SetRate records that one base is worth price quote at time at. The
+// inverse rate is recorded as well. A zero at marks a fixed rate, e.g. a
+// USDT/USD peg.
+func (c *Converter) SetRate(base, quote, price string, at time.Time) error {
+	p, err := strconv.ParseFloat(price, 64)
+	if err != nil || p <= 0 {
+		return fmt.Errorf("fx: invalid price %q for %s/%s", price, base, quote)
+	}
+	if base == quote {
+		return fmt.Errorf("fx: base and quote are both %s", base)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(base, quote, edge{rate: p, at: at})
+	c.set(quote, base, edge{rate: 1 / p, at: at})
+	return nil
+}
+
+// SetMarketPrice records the price of a market, usually its last or mid price
+func (c *Converter) SetMarketPrice(m tt.Market, price string, at time.Time) error {
+	if m.Base == "" || m.Quote == "" {
+		return fmt.Errorf("fx: market %s has no base or quote", m.Symbol)
+	}
+	return c.SetRate(m.Base, m.Quote, price, at)
+}
+
+func (c *Converter) set(from, to string, e edge) {
+	if c.edges[from] == nil {
+		c.edges[from] = make(map[string]edge)
+	}
+	c.edges[from][to] = e
+}
+
+// Rate resolves the conversion rate from one currency to another over the
+// fewest intermediate assets. Among equally short paths the one with the
+// freshest oldest rate wins.
+func (c *Converter) Rate(from, to string) (Quote, error) {
+	if from == to {
+		return Quote{From: from, To: to, Rate: "1", Path: []string{from}}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	path := c.shortestPath(from, to)
+	if path == nil {
+		return Quote{}, fmt.Errorf("%w from %s to %s", ErrNoPath, from, to)
+	}
+
+	rate := 1.0
+	var asOf time.Time
+	for i := 1; i < len(path); i++ {
+		e := c.edges[path[i-1]][path[i]]
+		rate *= e.rate
+		if !e.at.IsZero() && (asOf.IsZero() || e.at.Before(asOf)) {
+			asOf = e.at
+		}
+	}
+
+	q := Quote{
+		From: from,
+		To:   to,
+		Rate: utils.FormatSignificant(rate),
+		Path: path,
+		AsOf: asOf,
+	}
+	if c.maxAge > 0 && !asOf.IsZero() {
+		q.Stale = c.clock.Now().Sub(asOf) > c.maxAge
+	}
+	return q, nil
+}
+
+// Convert converts amount of from into to
+func (c *Converter) Convert(amount, from, to string) (string, Quote, error) {
+	a, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return "", Quote{}, fmt.Errorf("fx: invalid amount %q", amount)
+	}
+	q, err := c.Rate(from, to)
+	if err != nil {
+		return "", q, err
+	}
+	rate, _ := strconv.ParseFloat(q.Rate, 64)
+	return strconv.FormatFloat(a*rate, 'f', -1, 64), q, nil
+}
+
+// shortestPath runs a breadth-first search from from to to. Each level keeps
+// for every asset the path whose oldest rate is the newest.
+func (c *Converter) shortestPath(from, to string) []string {
+	type candidate struct {
+		path   []string
+		oldest time.Time // zero means only fixed rates so far
+	}
+	fresher := func(a, b candidate) bool {
+		switch {
+		case a.oldest.IsZero():
+			return !b.oldest.IsZero()
+		case b.oldest.IsZero():
+			return false
+		}
+		return a.oldest.After(b.oldest)
+	}
+
+	visited := map[string]bool{from: true}
+	level := []candidate{{path: []string{from}}}
+	for len(level) > 0 {
+		next := make(map[string]candidate)
+		for _, cand := range level {
+			last := cand.path[len(cand.path)-1]
+			for asset, e := range c.edges[last] {
+				if visited[asset] {
+					continue
+				}
+				oldest := cand.oldest
+				if !e.at.IsZero() && (oldest.IsZero() || e.at.Before(oldest)) {
+					oldest = e.at
+				}
+				path := append(append([]string{}, cand.path...), asset)
+				nc := candidate{path: path, oldest: oldest}
+				if prev, ok := next[asset]; !ok || fresher(nc, prev) {
+					next[asset] = nc
+				}
+			}
+		}
+		if found, ok := next[to]; ok {
+			return found.path
+		}
+
+		assets := make([]string, 0, len(next))
+		for asset := range next {
+			visited[asset] = true
+			assets = append(assets, asset)
+		}
+		sort.Strings(assets)
+		level = level[:0]
+		for _, asset := range assets {
+			level = append(level, next[asset])
+		}
+	}
+	return nil
+}
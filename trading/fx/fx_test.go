@@ -0,0 +1,57 @@
+package fx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestCrossRate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	conv := NewConverter(5 * time.Minute)
+	conv.SetClock(clock.Func(func() time.Time { return now }))
+
+	mustSet := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSet(conv.SetMarketPrice(tt.Market{Symbol: "ADAUSDT", Base: "ADA", Quote: "USDT"}, "0.5", now.Add(-time.Minute)))
+	mustSet(conv.SetMarketPrice(tt.Market{Symbol: "EURUSDT", Base: "EUR", Quote: "USDT"}, "1.25", now.Add(-10*time.Minute)))
+
+	value, q, err := conv.Convert("1000", "ADA", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "400" {
+		t.Fatalf("expected 400 EUR, got %s", value)
+	}
+	if len(q.Path) != 3 || q.Path[1] != "USDT" {
+		t.Fatalf("unexpected path %v", q.Path)
+	}
+	if !q.Stale || !q.AsOf.Equal(now.Add(-10*time.Minute)) {
+		t.Fatalf("expected stale quote as of the EUR rate, got %+v", q)
+	}
+
+	if _, err := conv.Rate("ADA", "JPY"); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("expected ErrNoPath, got %v", err)
+	}
+}
+
+func TestFixedRatesNeverStale(t *testing.T) {
+	conv := NewConverter(time.Minute)
+	if err := conv.SetRate("USDT", "USD", "1", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	q, err := conv.Rate("USD", "USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Stale || q.Rate != "1" {
+		t.Fatalf("unexpected quote %+v", q)
+	}
+}
@@ -0,0 +1,47 @@
+package trading
+
+// OrderType is how an order is executed
+type OrderType string
+
+const (
+	OrderTypeMarket OrderType = "market"
+	OrderTypeLimit  OrderType = "limit"
+)
+
+// OrderStatus is the lifecycle state of an order
+type OrderStatus string
+
+const (
+	OrderStatusOpen            OrderStatus = "open"
+	OrderStatusPartiallyFilled OrderStatus = "partiallyFilled"
+	OrderStatusFilled          OrderStatus = "filled"
+	OrderStatusCanceled        OrderStatus = "canceled"
+	OrderStatusRejected        OrderStatus = "rejected"
+)
+
+// Order is an order to buy or sell a market. Price and quantity are strings
+// to preserve precision, as in Market.
+type Order struct {
+	ID            string      `json:"id,omitempty"` // Assigned by the venue
+	ClientOrderID string      `json:"clientOrderId,omitempty"`
+	Symbol        string      `json:"symbol"`
+	Side          string      `json:"side"` // SideBuy or SideSell
+	Type          OrderType   `json:"type"`
+	Quantity      string      `json:"quantity"`
+	Price         string      `json:"price,omitempty"` // Limit price, empty for market orders
+	Status        OrderStatus `json:"status,omitempty"`
+	FilledQty     string      `json:"filledQty,omitempty"`
+	CreatedAt     int64       `json:"createdAt,omitempty"` // Unix timestamp in milliseconds
+}
+
+// Fill is a (partial) execution of an order
+type Fill struct {
+	OrderID   string `json:"orderId"`
+	Symbol    string `json:"symbol"`
+	Side      string `json:"side"`
+	Price     string `json:"price"`
+	Quantity  string `json:"quantity"`
+	Fee       string `json:"fee"`       // In quote currency
+	Maker     bool   `json:"maker"`     // Whether the fill added liquidity
+	Timestamp int64  `json:"timestamp"` // Unix timestamp in milliseconds
+}
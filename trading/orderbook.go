@@ -0,0 +1,17 @@
+package trading
+
+// OrderbookLevel is the resting quantity at one price
+type OrderbookLevel struct {
+	Price    string `json:"price"`
+	Quantity string `json:"quantity"`
+}
+
+// Orderbook is a snapshot of an order book. Bids are sorted best (highest)
+// first and asks best (lowest) first.
+type Orderbook struct {
+	Symbol    string           `json:"symbol"`
+	Timestamp int64            `json:"timestamp"` // Unix timestamp in milliseconds
+	Sequence  int64            `json:"sequence,omitempty"`
+	Bids      []OrderbookLevel `json:"bids"`
+	Asks      []OrderbookLevel `json:"asks"`
+}
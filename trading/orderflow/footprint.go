@@ -0,0 +1,218 @@
+// Package orderflow aggregates the trade tape into footprint bars: per candle
+// buy and sell volume, delta and the volume traded at each price level.
+//
+//	agg, err := orderflow.NewAggregator(tf, market.PriceTick)
+//
+//	// for every trade of the stream
+//	bar, closed, err := agg.Add(trade)
+//	if closed {
+//	    emit(bar)
+//	}
+package orderflow
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/trading/utils"
+)
+
+// FootprintRow is the volume traded at one price level of a bar. Bid volume
+// is sold into the bid (aggressive sells), ask volume is bought from the ask
+// (aggressive buys).
+type FootprintRow struct {
+	Price     string `json:"price"`
+	BidVolume string `json:"bidVolume"`
+	AskVolume string `json:"askVolume"`
+	Delta     string `json:"delta"` // AskVolume - BidVolume
+	Trades    int    `json:"trades"`
+}
+
+// FootprintBar is a candle with its order flow. Trades without aggressor
+// side count towards Volume only.
+type FootprintBar struct {
+	OpenTime   int64          `json:"openTime"` // Unix timestamp in milliseconds
+	Open       string         `json:"open"`
+	High       string         `json:"high"`
+	Low        string         `json:"low"`
+	Close      string         `json:"close"`
+	Volume     string         `json:"volume"`
+	BuyVolume  string         `json:"buyVolume"`
+	SellVolume string         `json:"sellVolume"`
+	Delta      string         `json:"delta"` // BuyVolume - SellVolume
+	Trades     int            `json:"trades"`
+	POC        string         `json:"poc"`  // Price level with the most volume
+	Rows       []FootprintRow `json:"rows"` // Highest price first
+}
+
+// Aggregator builds footprint bars from trades in time order
+type Aggregator struct {
+	tf       tt.Timeframe
+	tick     float64
+	decimals int
+
+	bar *bar
+}
+
+type level struct {
+	bid, ask, other float64
+	trades          int
+}
+
+type bar struct {
+	openTime               time.Time
+	open, high, low, close float64
+	volume, buy, sell      float64
+	trades                 int
+	levels                 map[float64]*level
+}
+
+// NewAggregator creates an aggregator for bars of tf with price levels of
+// tick, usually the market's PriceTick or a multiple of it
+func NewAggregator(tf tt.Timeframe, tick string) (*Aggregator, error) {
+	t, err := strconv.ParseFloat(tick, 64)
+	if err != nil || t <= 0 {
+		return nil, fmt.Errorf("invalid tick %q", tick)
+	}
+	if tf.IsZero() {
+		return nil, fmt.Errorf("timeframe is required")
+	}
+	return &Aggregator{tf: tf, tick: t, decimals: utils.TickDecimals(tick)}, nil
+}
+
+// Add adds a trade. When the trade opens a new bar, the previous bar is
+// returned with closed set to true.
+func (a *Aggregator) Add(trade tt.TradeRecord) (closedBar FootprintBar, closed bool, err error) {
+	price, err := strconv.ParseFloat(trade.Price, 64)
+	if err != nil {
+		return FootprintBar{}, false, fmt.Errorf("trade %s: invalid price %q", trade.ID, trade.Price)
+	}
+	qty, err := strconv.ParseFloat(trade.Quantity, 64)
+	if err != nil {
+		return FootprintBar{}, false, fmt.Errorf("trade %s: invalid quantity %q", trade.ID, trade.Quantity)
+	}
+
+	openTime := a.tf.LastOpen(time.UnixMilli(trade.Timestamp))
+	if a.bar != nil && openTime.Before(a.bar.openTime) {
+		return FootprintBar{}, false, fmt.Errorf("trade %s at %d is older than the current bar", trade.ID, trade.Timestamp)
+	}
+	if a.bar != nil && openTime.After(a.bar.openTime) {
+		closedBar, closed = a.build(a.bar), true
+		a.bar = nil
+	}
+	if a.bar == nil {
+		a.bar = &bar{openTime: openTime, open: price, high: price, low: price, levels: make(map[float64]*level)}
+	}
+
+	b := a.bar
+	b.high = math.Max(b.high, price)
+	b.low = math.Min(b.low, price)
+	b.close = price
+	b.volume += qty
+	b.trades++
+
+	key := math.Round(math.Floor(price/a.tick+1e-9) * a.tick * math.Pow10(a.decimals))
+	l := b.levels[key]
+	if l == nil {
+		l = &level{}
+		b.levels[key] = l
+	}
+	l.trades++
+	switch trade.Side {
+	case tt.SideBuy:
+		b.buy += qty
+		l.ask += qty
+	case tt.SideSell:
+		b.sell += qty
+		l.bid += qty
+	default:
+		l.other += qty
+	}
+	return closedBar, closed, nil
+}
+
+// Current returns the bar in progress
+func (a *Aggregator) Current() (FootprintBar, bool) {
+	if a.bar == nil {
+		return FootprintBar{}, false
+	}
+	return a.build(a.bar), true
+}
+
+// Flush returns the bar in progress and resets the aggregator, e.g. at the
+// end of a historical range
+func (a *Aggregator) Flush() (FootprintBar, bool) {
+	bar, ok := a.Current()
+	a.bar = nil
+	return bar, ok
+}
+
+// Aggregate builds the footprint bars of trades sorted by time
+func Aggregate(trades []tt.TradeRecord, tf tt.Timeframe, tick string) ([]FootprintBar, error) {
+	agg, err := NewAggregator(tf, tick)
+	if err != nil {
+		return nil, err
+	}
+	var bars []FootprintBar
+	for _, trade := range trades {
+		bar, closed, err := agg.Add(trade)
+		if err != nil {
+			return nil, err
+		}
+		if closed {
+			bars = append(bars, bar)
+		}
+	}
+	if bar, ok := agg.Flush(); ok {
+		bars = append(bars, bar)
+	}
+	return bars, nil
+}
+
+func (a *Aggregator) build(b *bar) FootprintBar {
+	out := FootprintBar{
+		OpenTime:   b.openTime.UnixMilli(),
+		Open:       a.price(b.open),
+		High:       a.price(b.high),
+		Low:        a.price(b.low),
+		Close:      a.price(b.close),
+		Volume:     utils.FormatSignificant(b.volume),
+		BuyVolume:  utils.FormatSignificant(b.buy),
+		SellVolume: utils.FormatSignificant(b.sell),
+		Delta:      utils.FormatSignificant(b.buy - b.sell),
+		Trades:     b.trades,
+		Rows:       make([]FootprintRow, 0, len(b.levels)),
+	}
+
+	keys := make([]float64, 0, len(b.levels))
+	for k := range b.levels {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(keys)))
+
+	pocVolume := -1.0
+	for _, k := range keys {
+		l := b.levels[k]
+		price := strconv.FormatFloat(k/math.Pow10(a.decimals), 'f', a.decimals, 64)
+		out.Rows = append(out.Rows, FootprintRow{
+			Price:     price,
+			BidVolume: utils.FormatSignificant(l.bid),
+			AskVolume: utils.FormatSignificant(l.ask),
+			Delta:     utils.FormatSignificant(l.ask - l.bid),
+			Trades:    l.trades,
+		})
+		if total := l.bid + l.ask + l.other; total > pocVolume {
+			pocVolume = total
+			out.POC = price
+		}
+	}
+	return out
+}
+
+func (a *Aggregator) price(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
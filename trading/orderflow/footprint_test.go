@@ -0,0 +1,44 @@
+package orderflow
+
+import (
+	"testing"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+func TestAggregateFootprint(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	trades := []tt.TradeRecord{
+		{Timestamp: base + 1000, Price: "100.04", Quantity: "1", Side: tt.SideBuy},
+		{Timestamp: base + 2000, Price: "100.00", Quantity: "3", Side: tt.SideSell},
+		{Timestamp: base + 3000, Price: "100.12", Quantity: "0.5", Side: tt.SideBuy},
+		{Timestamp: base + 4000, Price: "100.10", Quantity: "2"},
+		{Timestamp: base + 61000, Price: "100.20", Quantity: "1", Side: tt.SideSell},
+	}
+
+	bars, err := Aggregate(trades, tt.NewTimeframe(1, tt.Minutes), "0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bars) != 2 {
+		t.Fatalf("expected 2 bars, got %d", len(bars))
+	}
+
+	bar := bars[0]
+	if bar.OpenTime != base || bar.High != "100.12" || bar.Close != "100.1" {
+		t.Fatalf("unexpected ohlc %+v", bar)
+	}
+	if bar.Volume != "6.5" || bar.BuyVolume != "1.5" || bar.SellVolume != "3" || bar.Delta != "-1.5" || bar.Trades != 4 {
+		t.Fatalf("unexpected volumes %+v", bar)
+	}
+	if len(bar.Rows) != 2 || bar.Rows[0].Price != "100.1" || bar.Rows[1].Price != "100.0" {
+		t.Fatalf("unexpected rows %+v", bar.Rows)
+	}
+	if bar.Rows[1].BidVolume != "3" || bar.Rows[1].AskVolume != "1" || bar.Rows[1].Delta != "-2" {
+		t.Fatalf("unexpected row %+v", bar.Rows[1])
+	}
+	if bar.POC != "100.0" {
+		t.Fatalf("unexpected POC %s", bar.POC)
+	}
+}
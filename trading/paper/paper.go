@@ -0,0 +1,367 @@
+// Package paper simulates an exchange account for paper trading. Orders are
+// matched against the live candles and trades fed into the account, using the
+// tick sizes, limits and maker/taker fees of each trading.Market.
+//
+// Account implements trading.OrderExecutor, so a strategy written against
+// that interface runs unchanged on paper and live:
+//
+//	account, err := paper.NewAccount(map[string]string{"USDT": "10000"}, market)
+//	if err != nil {
+//	    return err
+//	}
+//	var exec trading.OrderExecutor = account
+//
+//	// in the stream handler
+//	fills, err := account.OnTrade(market.Symbol, trade)
+package paper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/plusev-terminal/go-plugin-common/backtest"
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	"github.com/plusev-terminal/go-plugin-common/wasmutils"
+)
+
+// DefaultHistoryLimit is the number of filled, canceled or rejected orders
+// and of fills an Account keeps by default
+const DefaultHistoryLimit = 1000
+
+// Account is a simulated spot account. Orders fill completely or not at all;
+// market orders fill on the next candle or trade of their market.
+type Account struct {
+	mu sync.Mutex
+
+	markets      map[string]*backtest.FillSimulator
+	free         map[string]float64
+	locked       map[string]float64
+	orders       map[string]*order
+	closed       []string // IDs of terminal orders, oldest first
+	lastPrice    map[string]float64
+	fills        []tt.Fill
+	historyLimit int
+	nextID       int
+	clock        clock.Clock
+}
+
+// order is an account order with the funds it reserved
+type order struct {
+	tt.Order
+	lockAsset  string
+	lockAmount float64
+}
+
+// NewAccount creates an account holding balances (asset to amount) that
+// trades the given markets
+func NewAccount(balances map[string]string, markets ...tt.Market) (*Account, error) {
+	a := &Account{
+		markets:      make(map[string]*backtest.FillSimulator, len(markets)),
+		free:         make(map[string]float64, len(balances)),
+		locked:       make(map[string]float64),
+		orders:       make(map[string]*order),
+		lastPrice:    make(map[string]float64),
+		historyLimit: DefaultHistoryLimit,
+		clock:        wasmutils.HostClock,
+	}
+
+	for asset, amount := range balances {
+		v, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance %q for %s", amount, asset)
+		}
+		a.free[asset] = v
+	}
+	for _, market := range markets {
+		sim, err := backtest.NewFillSimulator(market)
+		if err != nil {
+			return nil, err
+		}
+		a.markets[market.Symbol] = sim
+	}
+	return a, nil
+}
+
+// SetClock replaces the clock used for order timestamps
+func (a *Account) SetClock(c clock.Clock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock = c
+}
+
+// SetHistoryLimit sets how many terminal orders and fills the account keeps
+// for GetOrder and Fills; older ones are dropped. n <= 0 restores
+// DefaultHistoryLimit.
+func (a *Account) SetHistoryLimit(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n <= 0 {
+		n = DefaultHistoryLimit
+	}
+	a.historyLimit = n
+	a.prune()
+}
+
+// SetSlippage sets the slippage of market orders, see backtest.FillSimulator
+func (a *Account) SetSlippage(slippage float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, sim := range a.markets {
+		sim.Slippage = slippage
+	}
+}
+
+// PlaceOrder implements trading.OrderExecutor. It rejects orders the free
+// balance cannot cover and reserves the funds until the order fills or is
+// canceled.
+func (a *Account) PlaceOrder(req tt.Order) (tt.Order, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sim, ok := a.markets[req.Symbol]
+	if !ok {
+		return req, fmt.Errorf("unknown market %q", req.Symbol)
+	}
+	ref := a.lastPrice[req.Symbol]
+	if req.Type == tt.OrderTypeMarket && ref == 0 {
+		return req, fmt.Errorf("no price for %s yet", req.Symbol)
+	}
+
+	prepared, err := sim.Prepare(req, ref)
+	if err != nil {
+		return req, err
+	}
+	qty, _ := strconv.ParseFloat(prepared.Quantity, 64)
+	price := ref
+	if prepared.Type == tt.OrderTypeLimit {
+		price, _ = strconv.ParseFloat(prepared.Price, 64)
+	}
+
+	o := &order{Order: prepared}
+	market := sim.Market()
+	if prepared.Side == tt.SideBuy {
+		maker, taker := sim.FeeRates()
+		o.lockAsset = market.Quote
+		o.lockAmount = price * qty * (1 + sim.Slippage) * (1 + max(maker, taker))
+	} else {
+		o.lockAsset = market.Base
+		o.lockAmount = qty
+	}
+	if a.free[o.lockAsset] < o.lockAmount {
+		return req, fmt.Errorf("insufficient %s balance: need %g, have %g", o.lockAsset, o.lockAmount, a.free[o.lockAsset])
+	}
+	a.free[o.lockAsset] -= o.lockAmount
+	a.locked[o.lockAsset] += o.lockAmount
+
+	a.nextID++
+	o.ID = "paper-" + strconv.Itoa(a.nextID)
+	o.Status = tt.OrderStatusOpen
+	o.CreatedAt = a.clock.Now().UnixMilli()
+	a.orders[o.ID] = o
+	return o.Order, nil
+}
+
+// CancelOrder implements trading.OrderExecutor
+func (a *Account) CancelOrder(symbol, id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	o, ok := a.orders[id]
+	if !ok || o.Symbol != symbol {
+		return fmt.Errorf("unknown order %s", id)
+	}
+	if o.Status != tt.OrderStatusOpen {
+		return fmt.Errorf("order %s is %s", id, o.Status)
+	}
+	a.release(o)
+	a.close(o, tt.OrderStatusCanceled)
+	return nil
+}
+
+// GetOrder implements trading.OrderExecutor
+func (a *Account) GetOrder(symbol, id string) (tt.Order, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	o, ok := a.orders[id]
+	if !ok || o.Symbol != symbol {
+		return tt.Order{}, fmt.Errorf("unknown order %s", id)
+	}
+	return o.Order, nil
+}
+
+// OpenOrders implements trading.OrderExecutor
+func (a *Account) OpenOrders(symbol string) ([]tt.Order, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.openOrders(symbol), nil
+}
+
+// Balances implements trading.OrderExecutor
+func (a *Account) Balances() ([]tt.Balance, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	assets := make(map[string]bool)
+	for asset := range a.free {
+		assets[asset] = true
+	}
+	for asset := range a.locked {
+		assets[asset] = true
+	}
+
+	balances := make([]tt.Balance, 0, len(assets))
+	for asset := range assets {
+		balances = append(balances, tt.Balance{
+			Asset:  asset,
+			Free:   strconv.FormatFloat(a.free[asset], 'f', -1, 64),
+			Locked: strconv.FormatFloat(a.locked[asset], 'f', -1, 64),
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return balances, nil
+}
+
+// Fills returns the fills so far, up to the history limit
+func (a *Account) Fills() []tt.Fill {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]tt.Fill(nil), a.fills...)
+}
+
+// OnCandle matches the open orders of symbol against candle and returns the
+// resulting fills
+func (a *Account) OnCandle(symbol string, candle tt.OHLCVRecord) ([]tt.Fill, error) {
+	return a.onData(symbol, candle.Close, func(sim *backtest.FillSimulator, o tt.Order) (tt.Fill, bool, error) {
+		return sim.MatchCandle(o, candle)
+	})
+}
+
+// OnTrade matches the open orders of symbol against trade and returns the
+// resulting fills
+func (a *Account) OnTrade(symbol string, trade tt.TradeRecord) ([]tt.Fill, error) {
+	return a.onData(symbol, trade.Price, func(sim *backtest.FillSimulator, o tt.Order) (tt.Fill, bool, error) {
+		return sim.MatchTrade(o, trade)
+	})
+}
+
+func (a *Account) onData(symbol, last string, match func(*backtest.FillSimulator, tt.Order) (tt.Fill, bool, error)) ([]tt.Fill, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sim, ok := a.markets[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown market %q", symbol)
+	}
+
+	var fills []tt.Fill
+	for _, open := range a.openOrders(symbol) {
+		fill, ok, err := match(sim, open)
+		if err != nil {
+			return fills, err
+		}
+		if !ok {
+			continue
+		}
+		booked, err := a.book(sim.Market(), a.orders[open.ID], fill)
+		if err != nil {
+			return fills, err
+		}
+		if booked {
+			fills = append(fills, fill)
+		}
+	}
+
+	price, err := strconv.ParseFloat(last, 64)
+	if err != nil {
+		return fills, fmt.Errorf("invalid price %q", last)
+	}
+	a.lastPrice[symbol] = price
+	return fills, nil
+}
+
+// book releases the reserved funds of o and settles fill. A buy whose fill
+// costs more than the account holds, e.g. a market order filling after a gap
+// up, is rejected instead and false is returned.
+func (a *Account) book(market tt.Market, o *order, fill tt.Fill) (bool, error) {
+	price, err := strconv.ParseFloat(fill.Price, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid fill price %q", fill.Price)
+	}
+	qty, err := strconv.ParseFloat(fill.Quantity, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid fill quantity %q", fill.Quantity)
+	}
+	fee, err := strconv.ParseFloat(fill.Fee, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid fill fee %q", fill.Fee)
+	}
+
+	a.release(o)
+	if fill.Side == tt.SideBuy {
+		cost := price*qty + fee
+		if a.free[market.Quote] < cost {
+			a.close(o, tt.OrderStatusRejected)
+			return false, nil
+		}
+		a.free[market.Quote] -= cost
+		a.free[market.Base] += qty
+	} else {
+		a.free[market.Base] -= qty
+		a.free[market.Quote] += price*qty - fee
+	}
+
+	o.FilledQty = fill.Quantity
+	a.close(o, tt.OrderStatusFilled)
+	a.fills = append(a.fills, fill)
+	a.prune()
+	return true, nil
+}
+
+// close moves o into a terminal status
+func (a *Account) close(o *order, status tt.OrderStatus) {
+	o.Status = status
+	a.closed = append(a.closed, o.ID)
+	a.prune()
+}
+
+// prune drops the oldest terminal orders and fills beyond the history limit
+func (a *Account) prune() {
+	if n := len(a.closed) - a.historyLimit; n > 0 {
+		for _, id := range a.closed[:n] {
+			delete(a.orders, id)
+		}
+		a.closed = append(a.closed[:0], a.closed[n:]...)
+	}
+	if n := len(a.fills) - a.historyLimit; n > 0 {
+		a.fills = append(a.fills[:0], a.fills[n:]...)
+	}
+}
+
+func (a *Account) release(o *order) {
+	a.locked[o.lockAsset] -= o.lockAmount
+	a.free[o.lockAsset] += o.lockAmount
+	o.lockAmount = 0
+}
+
+// openOrders returns the open orders of symbol (all if empty) in placement order
+func (a *Account) openOrders(symbol string) []tt.Order {
+	var orders []tt.Order
+	for _, o := range a.orders {
+		if o.Status == tt.OrderStatusOpen && (symbol == "" || o.Symbol == symbol) {
+			orders = append(orders, o.Order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orderSeq(orders[i].ID) < orderSeq(orders[j].ID)
+	})
+	return orders
+}
+
+func orderSeq(id string) int {
+	n, _ := strconv.Atoi(id[len("paper-"):])
+	return n
+}
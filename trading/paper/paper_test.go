@@ -0,0 +1,106 @@
+package paper
+
+import (
+	"testing"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+var _ tt.OrderExecutor = (*Account)(nil)
+
+func TestLimitOrderLifecycle(t *testing.T) {
+	market := tt.Market{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT", PriceTick: "0.01", QuantityTick: "0.001", MakerFee: "0.001", TakerFee: "0.002"}
+	account, err := NewAccount(map[string]string{"USDT": "1000"}, market)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := account.PlaceOrder(tt.Order{Symbol: "ETHUSDT", Side: tt.SideBuy, Type: tt.OrderTypeLimit, Price: "100", Quantity: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := account.PlaceOrder(tt.Order{Symbol: "ETHUSDT", Side: tt.SideBuy, Type: tt.OrderTypeLimit, Price: "100", Quantity: "9"}); err == nil {
+		t.Fatal("expected insufficient balance")
+	}
+
+	fills, err := account.OnTrade("ETHUSDT", tt.TradeRecord{Timestamp: 1, Price: "101", Quantity: "1"})
+	if err != nil || len(fills) != 0 {
+		t.Fatalf("limit order filled above its price: %v, %v", fills, err)
+	}
+	fills, err = account.OnTrade("ETHUSDT", tt.TradeRecord{Timestamp: 2, Price: "99.5", Quantity: "1"})
+	if err != nil || len(fills) != 1 {
+		t.Fatalf("expected one fill, got %v, %v", fills, err)
+	}
+	// A buy limit crossed on arrival takes liquidity at the trade price
+	if fills[0].Price != "99.50" || fills[0].Maker {
+		t.Fatalf("unexpected fill %+v", fills[0])
+	}
+
+	got, err := account.GetOrder("ETHUSDT", order.ID)
+	if err != nil || got.Status != tt.OrderStatusFilled {
+		t.Fatalf("expected filled order, got %+v, %v", got, err)
+	}
+
+	balances, _ := account.Balances()
+	want := []tt.Balance{{Asset: "ETH", Free: "2", Locked: "0"}, {Asset: "USDT", Free: "800.602", Locked: "0"}}
+	if len(balances) != 2 || balances[0] != want[0] || balances[1] != want[1] {
+		t.Fatalf("unexpected balances %+v", balances)
+	}
+}
+
+func TestMarketBuyRejectedOnGapUp(t *testing.T) {
+	market := tt.Market{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT", PriceTick: "0.01", QuantityTick: "0.001", TakerFee: "0.002"}
+	account, err := NewAccount(map[string]string{"USDT": "1000"}, market)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := account.OnTrade("ETHUSDT", tt.TradeRecord{Timestamp: 1, Price: "100", Quantity: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := account.PlaceOrder(tt.Order{Symbol: "ETHUSDT", Side: tt.SideBuy, Type: tt.OrderTypeMarket, Quantity: "9.9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fills, err := account.OnCandle("ETHUSDT", tt.OHLCVRecord{OpenTime: 60, Open: "110", High: "111", Low: "109", Close: "110", Volume: "5"})
+	if err != nil || len(fills) != 0 {
+		t.Fatalf("expected no fill, got %v, %v", fills, err)
+	}
+
+	got, _ := account.GetOrder("ETHUSDT", order.ID)
+	if got.Status != tt.OrderStatusRejected {
+		t.Fatalf("expected rejected order, got %s", got.Status)
+	}
+	balances, _ := account.Balances()
+	if len(balances) != 1 || balances[0] != (tt.Balance{Asset: "USDT", Free: "1000", Locked: "0"}) {
+		t.Fatalf("unexpected balances %+v", balances)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	market := tt.Market{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT", PriceTick: "0.01", QuantityTick: "0.001"}
+	account, err := NewAccount(map[string]string{"USDT": "1000"}, market)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account.SetHistoryLimit(2)
+
+	var ids []string
+	for range 3 {
+		order, err := account.PlaceOrder(tt.Order{Symbol: "ETHUSDT", Side: tt.SideBuy, Type: tt.OrderTypeLimit, Price: "100", Quantity: "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := account.CancelOrder("ETHUSDT", order.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, order.ID)
+	}
+
+	if _, err := account.GetOrder("ETHUSDT", ids[0]); err == nil {
+		t.Fatal("expected the oldest canceled order to be pruned")
+	}
+	if got, err := account.GetOrder("ETHUSDT", ids[2]); err != nil || got.Status != tt.OrderStatusCanceled {
+		t.Fatalf("expected latest order kept, got %+v, %v", got, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/plusev-terminal/go-plugin-common/clock"
 	"github.com/plusev-terminal/go-plugin-common/utils"
 )
 
@@ -37,6 +38,24 @@ type Timeframe struct {
 	Location *time.Location `json:"location" gorm:"-"` // Timezone for the timeframe
 }
 
+// unitMinutes returns the length of unit in minutes; months and years are
+// approximated with 30 and 365 days
+func unitMinutes(unit Unit) int {
+	switch unit {
+	case Hours:
+		return 60
+	case Days:
+		return 60 * 24
+	case Weeks:
+		return 60 * 24 * 7
+	case Months:
+		return 60 * 24 * 30
+	case Years:
+		return 60 * 24 * 365
+	}
+	return 1
+}
+
 func NewTimeframe(val uint64, unit Unit, location ...*time.Location) Timeframe {
 	loc := time.UTC
 	if len(location) > 0 && location[0] != nil {
@@ -61,42 +80,29 @@ func (tf *Timeframe) StringWithLocation() string {
 	return fmt.Sprintf("%d%s:%s", tf.Value, tf.Unit, locStr)
 }
 
+// ToMinutes returns the length of the timeframe in minutes. Months and
+// years are approximated with 30 and 365 days unless a reference time is
+// given.
 func (tf Timeframe) ToMinutes(ref ...time.Time) int {
-	mul := 1
-
-	if tf.Unit == Hours {
-		mul = 60
-	}
-
-	if tf.Unit == Days {
-		mul = 60 * 24
-	}
-
-	if tf.Unit == Weeks {
-		mul = 60 * 24 * 7
-	}
-
-	if tf.Unit == Months {
-		if len(ref) > 0 {
+	if len(ref) > 0 {
+		switch tf.Unit {
+		case Months:
 			// Calculate exact minutes using the reference time
 			startOfMonth := utils.StartOfMonth(ref[0])
 			endTime := startOfMonth.AddDate(0, int(tf.Value), 0)
 			return int(endTime.Sub(startOfMonth).Minutes())
-		}
-		mul = 60 * 24 * 30 // Approximate 30 days per month
-	}
-
-	if tf.Unit == Years {
-		if len(ref) > 0 {
-			// Calculate exact minutes using the reference time
+		case Years:
 			startOfYear := utils.StartOfYear(ref[0])
 			endTime := startOfYear.AddDate(int(tf.Value), 0, 0)
 			return int(endTime.Sub(startOfYear).Minutes())
 		}
-		mul = 60 * 24 * 365 // Approximate 365 days per year
 	}
+	return unitMinutes(tf.Unit) * int(tf.Value)
+}
 
-	return mul * int(tf.Value)
+// Duration returns ToMinutes as a time.Duration
+func (tf Timeframe) Duration() time.Duration {
+	return time.Duration(tf.ToMinutes()) * time.Minute
 }
 
 func (tf Timeframe) LowerThan(other Timeframe) bool {
@@ -171,6 +177,10 @@ func (tf Timeframe) IsValidCandleOpenTime(openTime time.Time) bool {
 }
 
 func (tf Timeframe) LastOpen(openTime time.Time) time.Time {
+	if (tf.Unit == Minutes || tf.Unit == Hours) && (tf.Location == nil || tf.Location == time.UTC) && openTime.Unix() >= 0 {
+		return tf.lastOpenUTC(openTime.Unix())
+	}
+
 	localTime := tf.InLocation(openTime)
 
 	if tf.Unit == Minutes {
@@ -205,6 +215,20 @@ func (tf Timeframe) LastOpen(openTime time.Time) time.Time {
 	return localTime
 }
 
+// lastOpenUTC is LastOpen for minute and hour candles in UTC, computed on
+// the Unix timestamp instead of the calendar fields
+func (tf Timeframe) lastOpenUTC(sec int64) time.Time {
+	if tf.Unit == Minutes {
+		sec -= sec % 60
+		delta := int64(tf.ToMinutes() % 60)
+		sec -= sec / 60 % 60 % delta * 60
+	} else {
+		sec -= sec % 3600
+		sec -= sec / 3600 % 24 % int64(tf.Value) * 3600
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
 func (tf Timeframe) NextOpen(openTime time.Time) time.Time {
 	lastOpen := tf.LastOpen(openTime)
 
@@ -221,7 +245,7 @@ func (tf Timeframe) NextOpen(openTime time.Time) time.Time {
 		return lastOpen.AddDate(int(tf.Value), 0, 0)
 	}
 
-	return lastOpen.Add(time.Duration(tf.ToMinutes()) * time.Minute)
+	return lastOpen.Add(tf.Duration())
 }
 
 func (tf Timeframe) CloseTime(openTime time.Time) time.Time {
@@ -236,7 +260,29 @@ func (tf Timeframe) CloseTime(openTime time.Time) time.Time {
 		return openTime.AddDate(int(tf.Value), 0, 0)
 	}
 
-	return openTime.Add(time.Duration(tf.ToMinutes()) * time.Minute)
+	return openTime.Add(tf.Duration())
+}
+
+// Advance moves the candle open t forward by n candles. Months and years
+// use calendar arithmetic.
+func (tf Timeframe) Advance(t time.Time, n int) time.Time {
+	switch tf.Unit {
+	case Months:
+		return t.AddDate(0, int(tf.Value)*n, 0)
+	case Years:
+		return t.AddDate(int(tf.Value)*n, 0, 0)
+	}
+	return t.Add(time.Duration(n) * tf.Duration())
+}
+
+// CurrentOpen returns the open time of the candle running at the clock's current time
+func (tf Timeframe) CurrentOpen(c clock.Clock) time.Time {
+	return tf.LastOpen(c.Now())
+}
+
+// IsClosed reports whether the candle opened at openTime has closed at the clock's current time
+func (tf Timeframe) IsClosed(openTime time.Time, c clock.Clock) bool {
+	return !c.Now().Before(tf.CloseTime(openTime))
 }
 
 func TimeframeFromString(str string) (Timeframe, error) {
@@ -277,9 +323,5 @@ func TimeframeFromString(str string) (Timeframe, error) {
 		location = time.UTC // Default to UTC if no location provided
 	}
 
-	return Timeframe{
-		Value:    valStr,
-		Unit:     unit,
-		Location: location,
-	}, nil
+	return NewTimeframe(valStr, unit, location), nil
 }
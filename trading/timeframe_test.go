@@ -0,0 +1,106 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/clock"
+)
+
+func TestTimeframeCandleBoundary(t *testing.T) {
+	tf := NewTimeframe(15, Minutes)
+	open := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	c := clock.NewFake(open.Add(15*time.Minute - time.Nanosecond))
+
+	if got := tf.CurrentOpen(c); !got.Equal(open) {
+		t.Errorf("expected current open %s, got %s", open, got)
+	}
+	if tf.IsClosed(open, c) {
+		t.Error("expected candle to be open one nanosecond before close")
+	}
+
+	c.Advance(time.Nanosecond)
+	if !tf.IsClosed(open, c) {
+		t.Error("expected candle to be closed at close time")
+	}
+	if got := tf.CurrentOpen(c); !got.Equal(open.Add(15 * time.Minute)) {
+		t.Errorf("expected next candle to be current, got %s", got)
+	}
+}
+
+func TestTimeframeMinutes(t *testing.T) {
+	tf := NewTimeframe(4, Hours)
+	if got := tf.ToMinutes(); got != 240 {
+		t.Fatalf("expected 240 minutes, got %d", got)
+	}
+
+	tf.Value = 1
+	tf.Unit = Days
+	if got := tf.ToMinutes(); got != 1440 {
+		t.Errorf("expected 1440 minutes after changing the unit, got %d", got)
+	}
+	if got := tf.Duration(); got != 24*time.Hour {
+		t.Errorf("expected 24h, got %s", got)
+	}
+
+	literal := Timeframe{Value: 3, Unit: Months}
+	ref := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	if got, want := literal.ToMinutes(ref), (31+29+31)*24*60; got != want {
+		t.Errorf("expected %d minutes from the reference time, got %d", want, got)
+	}
+	if got := literal.ToMinutes(); got != 3*30*24*60 {
+		t.Errorf("expected the approximated month length, got %d", got)
+	}
+}
+
+func TestTimeframeComparable(t *testing.T) {
+	parsed, err := TimeframeFromString("5m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != NewTimeframe(5, Minutes) {
+		t.Error("expected equal timeframes to compare equal")
+	}
+	seen := map[Timeframe]bool{NewTimeframe(5, Minutes): true}
+	if !seen[parsed] {
+		t.Error("expected timeframes to work as map keys")
+	}
+}
+
+func TestTimeframeLastOpenUTCFastPath(t *testing.T) {
+	zero := time.FixedZone("UTC0", 0)
+	start := time.Date(2024, 3, 30, 21, 7, 13, 500, time.UTC)
+	for _, str := range []string{"1m", "5m", "15m", "90m", "1h", "4h", "6h"} {
+		tf, err := TimeframeFromString(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slow := NewTimeframe(tf.Value, tf.Unit, zero)
+		for i := range 500 {
+			tm := start.Add(time.Duration(i) * 7 * time.Minute)
+			if got, want := tf.LastOpen(tm), slow.LastOpen(tm); !got.Equal(want) || got.Location() != time.UTC {
+				t.Fatalf("%s: expected last open %s for %s, got %s", str, want, tm, got)
+			}
+		}
+	}
+}
+
+// benchmarkCandleWalk steps through 100k candle boundaries like gap filling
+// and batch sanitization do
+func benchmarkCandleWalk(b *testing.B, tf Timeframe) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	higher := NewTimeframe(1, Hours)
+	for b.Loop() {
+		open := start
+		for range 100_000 {
+			if !tf.LowerThan(higher) {
+				b.Fatal("unexpected comparison result")
+			}
+			open = tf.CloseTime(open)
+		}
+	}
+}
+
+func BenchmarkTimeframeCandleWalk(b *testing.B) {
+	benchmarkCandleWalk(b, NewTimeframe(5, Minutes))
+}
@@ -0,0 +1,17 @@
+package trading
+
+// Aggressor sides of a trade
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// TradeRecord represents a single executed trade (a print on the tape).
+// Price and quantity are strings to preserve precision, as in OHLCVRecord.
+type TradeRecord struct {
+	ID        string `json:"id,omitempty"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp in milliseconds
+	Price     string `json:"price"`
+	Quantity  string `json:"quantity"`
+	Side      string `json:"side,omitempty"` // Aggressor side: SideBuy or SideSell, empty if unknown
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TickDecimals returns the number of fractional digits of a tick such as
+// "0.001"; trailing zeros don't count
+func TickDecimals(tick string) int {
+	if i := strings.IndexByte(tick, '.'); i >= 0 {
+		return len(strings.TrimRight(tick[i+1:], "0"))
+	}
+	return 0
+}
+
+// FormatSignificant renders v with 12 significant digits and without
+// exponent, which hides the rounding noise of float sums and chained
+// multiplications. Zero, including negative zero, renders as "0".
+func FormatSignificant(v float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	if rounded == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
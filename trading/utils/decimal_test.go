@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTickDecimals(t *testing.T) {
+	for tick, want := range map[string]int{"1": 0, "0.01": 2, "0.0010": 3, "10.5": 1, "5.": 0} {
+		if got := TickDecimals(tick); got != want {
+			t.Errorf("TickDecimals(%q) = %d, want %d", tick, got, want)
+		}
+	}
+}
+
+func TestFormatSignificant(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{0.1 + 0.2, "0.3"},
+		{1.1 * 1.1 * 1.1, "1.331"},
+		{123456789.123, "123456789.123"},
+		{0.00000012, "0.00000012"},
+		{math.Copysign(0, -1), "0"},
+		{-2.5, "-2.5"},
+	}
+	for _, tt := range tests {
+		if got := FormatSignificant(tt.v); got != tt.want {
+			t.Errorf("FormatSignificant(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"cmp"
 	"fmt"
-	"sort"
+	"slices"
+	"sync"
 
 	tt "github.com/plusev-terminal/go-plugin-common/trading"
 )
@@ -10,32 +12,39 @@ import (
 // OHLCVSanitizer processes OHLCV data batches to eliminate duplicates and fill gaps
 type OHLCVSanitizer struct {
 	timeframe   tt.Timeframe
-	lastCandle  *tt.OHLCVRecord // Track the last processed candle to detect gaps
-	firstCandle *tt.OHLCVRecord // Track the first processed candle for backward pagination
-	initialized bool            // Whether we've processed at least one batch
+	candleSecs  int64          // Candle length, computed once per timeframe
+	lastCandle  tt.OHLCVRecord // Track the last processed candle to detect gaps
+	firstCandle tt.OHLCVRecord // Track the first processed candle for backward pagination
+	initialized bool           // Whether we've processed at least one batch
 }
 
+// batchPool holds result slices handed back through Release
+var batchPool sync.Pool
+
 // NewOHLCVSanitizer creates a new OHLCV sanitizer for the specified timeframe
 func NewOHLCVSanitizer(timeframe tt.Timeframe) *OHLCVSanitizer {
 	return &OHLCVSanitizer{
 		timeframe:   timeframe,
+		candleSecs:  int64(timeframe.ToMinutes() * 60),
 		initialized: false,
 	}
 }
 
-// SanitizeBatch processes a batch of OHLCV records, removing duplicates and filling gaps
+// SanitizeBatch processes a batch of OHLCV records, removing duplicates and filling gaps.
+// The batch is sorted in place. The result may be handed back with Release
+// once it is no longer used, which lets backfills of many batches reuse it.
 func (s *OHLCVSanitizer) SanitizeBatch(batch []tt.OHLCVRecord) ([]tt.OHLCVRecord, error) {
 	if len(batch) == 0 {
 		return batch, nil
 	}
 
-	// Sort batch by opentime to ensure proper ordering
-	sort.Slice(batch, func(i, j int) bool {
-		return batch[i].OpenTime < batch[j].OpenTime
-	})
+	// Sort batch by opentime to ensure proper ordering, pages usually are already
+	if !slices.IsSortedFunc(batch, compareOpenTime) {
+		slices.SortStableFunc(batch, compareOpenTime)
+	}
 
-	candleDurationSeconds := int64(s.timeframe.ToMinutes() * 60)
-	result := make([]tt.OHLCVRecord, 0, len(batch))
+	candleDurationSeconds := s.candleSecs
+	result := getBatch(len(batch) + s.gapSize(batch, candleDurationSeconds))
 
 	for i, candle := range batch {
 		// 1. Internal Duplicate Check
@@ -44,7 +53,7 @@ func (s *OHLCVSanitizer) SanitizeBatch(batch []tt.OHLCVRecord) ([]tt.OHLCVRecord
 		}
 
 		// 2. External Duplicate Check
-		if s.initialized && s.firstCandle != nil && s.lastCandle != nil {
+		if s.initialized {
 			if candle.OpenTime >= s.firstCandle.OpenTime && candle.OpenTime <= s.lastCandle.OpenTime {
 				continue
 			}
@@ -53,7 +62,7 @@ func (s *OHLCVSanitizer) SanitizeBatch(batch []tt.OHLCVRecord) ([]tt.OHLCVRecord
 		// 3. Gap Filling (Before the first valid candle of this batch)
 		// Only fill gaps if we haven't added any candles to result yet (meaning this is the first new candle)
 		// and we have a previous history to connect to.
-		if len(result) == 0 && s.initialized && s.lastCandle != nil {
+		if len(result) == 0 && s.initialized {
 			if candle.OpenTime > s.lastCandle.OpenTime {
 				nextTs := s.lastCandle.OpenTime + candleDurationSeconds
 				for nextTs < candle.OpenTime {
@@ -75,43 +84,85 @@ func (s *OHLCVSanitizer) SanitizeBatch(batch []tt.OHLCVRecord) ([]tt.OHLCVRecord
 	}
 
 	if len(result) == 0 {
+		s.Release(result)
 		return []tt.OHLCVRecord{}, nil
 	}
 
 	// Update boundaries
 	if !s.initialized || result[0].OpenTime < s.firstCandle.OpenTime {
-		first := result[0]
-		s.firstCandle = &first
+		s.firstCandle = result[0]
 	}
 	if !s.initialized || result[len(result)-1].OpenTime > s.lastCandle.OpenTime {
-		last := result[len(result)-1]
-		s.lastCandle = &last
+		s.lastCandle = result[len(result)-1]
 	}
 	s.initialized = true
 
 	return result, nil
 }
 
+// gapSize returns the number of candles filled in front of the sorted batch
+func (s *OHLCVSanitizer) gapSize(batch []tt.OHLCVRecord, candleDurationSeconds int64) int {
+	if !s.initialized || candleDurationSeconds <= 0 {
+		return 0
+	}
+	for _, candle := range batch {
+		if candle.OpenTime >= s.firstCandle.OpenTime && candle.OpenTime <= s.lastCandle.OpenTime {
+			continue
+		}
+		if candle.OpenTime <= s.lastCandle.OpenTime {
+			return 0
+		}
+		return int((candle.OpenTime - s.lastCandle.OpenTime - 1) / candleDurationSeconds)
+	}
+	return 0
+}
+
+// Release hands a result of SanitizeBatch back for reuse by later batches.
+// The records must not be used afterwards.
+func (s *OHLCVSanitizer) Release(records []tt.OHLCVRecord) {
+	if cap(records) == 0 {
+		return
+	}
+	records = records[:0]
+	batchPool.Put(&records)
+}
+
+// getBatch returns an empty slice with room for n records
+func getBatch(n int) []tt.OHLCVRecord {
+	if p, ok := batchPool.Get().(*[]tt.OHLCVRecord); ok {
+		if cap(*p) >= n {
+			return *p
+		}
+		// Too small for this batch, let it be collected
+	}
+	return make([]tt.OHLCVRecord, 0, n)
+}
+
+func compareOpenTime(a, b tt.OHLCVRecord) int {
+	return cmp.Compare(a.OpenTime, b.OpenTime)
+}
+
 // Reset clears the sanitizer state (useful for switching symbols/timeframes)
 func (s *OHLCVSanitizer) Reset() {
-	s.firstCandle = nil
-	s.lastCandle = nil
+	s.firstCandle = tt.OHLCVRecord{}
+	s.lastCandle = tt.OHLCVRecord{}
 	s.initialized = false
 }
 
 // GetLastCandle returns the last processed candle (useful for debugging)
 func (s *OHLCVSanitizer) GetLastCandle() *tt.OHLCVRecord {
-	if s.lastCandle == nil {
+	if !s.initialized {
 		return nil
 	}
 	// Return a copy to prevent external modification
-	lastCopy := *s.lastCandle
+	lastCopy := s.lastCandle
 	return &lastCopy
 }
 
 // SetTimeframe updates the timeframe (triggers reset)
 func (s *OHLCVSanitizer) SetTimeframe(timeframe tt.Timeframe) {
 	s.timeframe = timeframe
+	s.candleSecs = int64(timeframe.ToMinutes() * 60)
 	s.Reset() // Reset state when timeframe changes
 }
 
@@ -12,13 +12,13 @@ func TestOHLCVSanitizer_RemoveDuplicates(t *testing.T) {
 
 	// Test batch with duplicate first candle
 	batch1 := []tt.OHLCVRecord{
-		{Timestamp: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
-		{Timestamp: 1300, Open: "100.5", High: "102.0", Low: "100.0", Close: "101.0", Volume: "2000"},
+		{OpenTime: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
+		{OpenTime: 1300, Open: "100.5", High: "102.0", Low: "100.0", Close: "101.0", Volume: "2000"},
 	}
 
 	batch2 := []tt.OHLCVRecord{
-		{Timestamp: 1300, Open: "100.5", High: "102.0", Low: "100.0", Close: "101.0", Volume: "2000"}, // Duplicate
-		{Timestamp: 1600, Open: "101.0", High: "103.0", Low: "101.0", Close: "102.0", Volume: "1500"},
+		{OpenTime: 1300, Open: "100.5", High: "102.0", Low: "100.0", Close: "101.0", Volume: "2000"}, // Duplicate
+		{OpenTime: 1600, Open: "101.0", High: "103.0", Low: "101.0", Close: "102.0", Volume: "1500"},
 	}
 
 	// Process first batch
@@ -41,8 +41,8 @@ func TestOHLCVSanitizer_RemoveDuplicates(t *testing.T) {
 		t.Fatalf("Expected 1 record in second batch after duplicate removal, got %d", len(result2))
 	}
 
-	if result2[0].Timestamp != 1600 {
-		t.Fatalf("Expected timestamp 1600, got %d", result2[0].Timestamp)
+	if result2[0].OpenTime != 1600 {
+		t.Fatalf("Expected timestamp 1600, got %d", result2[0].OpenTime)
 	}
 }
 
@@ -52,12 +52,12 @@ func TestOHLCVSanitizer_FillGaps(t *testing.T) {
 
 	// First batch
 	batch1 := []tt.OHLCVRecord{
-		{Timestamp: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
+		{OpenTime: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
 	}
 
 	// Second batch with gap (should be at 1300, but starts at 1900 - missing 2 candles)
 	batch2 := []tt.OHLCVRecord{
-		{Timestamp: 1900, Open: "102.0", High: "103.0", Low: "101.5", Close: "102.5", Volume: "1500"},
+		{OpenTime: 1900, Open: "102.0", High: "103.0", Low: "101.5", Close: "102.5", Volume: "1500"},
 	}
 
 	// Process first batch
@@ -78,10 +78,10 @@ func TestOHLCVSanitizer_FillGaps(t *testing.T) {
 	}
 
 	// Check gap fill candles
-	expectedTimestamps := []int64{1300, 1600, 1900}
-	for i, expected := range expectedTimestamps {
-		if result2[i].Timestamp != expected {
-			t.Fatalf("Expected timestamp %d at index %d, got %d", expected, i, result2[i].Timestamp)
+	expectedOpenTimes := []int64{1300, 1600, 1900}
+	for i, expected := range expectedOpenTimes {
+		if result2[i].OpenTime != expected {
+			t.Fatalf("Expected timestamp %d at index %d, got %d", expected, i, result2[i].OpenTime)
 		}
 	}
 
@@ -101,14 +101,14 @@ func TestOHLCVSanitizer_OverlapAndGaps(t *testing.T) {
 
 	// Batch 1: 1000, 1300
 	batch1 := []tt.OHLCVRecord{
-		{Timestamp: 1000, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
-		{Timestamp: 1300, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 1000, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 1300, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
 	}
 
 	// Batch 2: 1300 (overlap), 1900 (gap of 1600)
 	batch2 := []tt.OHLCVRecord{
-		{Timestamp: 1300, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
-		{Timestamp: 1900, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 1300, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 1900, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
 	}
 
 	_, _ = sanitizer.SanitizeBatch(batch1)
@@ -124,11 +124,11 @@ func TestOHLCVSanitizer_OverlapAndGaps(t *testing.T) {
 		t.Fatalf("Expected 2 records (1 gap fill + 1 new), got %d", len(result))
 	}
 
-	if result[0].Timestamp != 1600 {
-		t.Errorf("Expected gap fill at 1600, got %d", result[0].Timestamp)
+	if result[0].OpenTime != 1600 {
+		t.Errorf("Expected gap fill at 1600, got %d", result[0].OpenTime)
 	}
-	if result[1].Timestamp != 1900 {
-		t.Errorf("Expected new candle at 1900, got %d", result[1].Timestamp)
+	if result[1].OpenTime != 1900 {
+		t.Errorf("Expected new candle at 1900, got %d", result[1].OpenTime)
 	}
 }
 
@@ -138,10 +138,10 @@ func TestOHLCVSanitizer_BackwardFetch(t *testing.T) {
 
 	// Fetching backwards: newer data comes first
 	batch1 := []tt.OHLCVRecord{
-		{Timestamp: 2000, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 2000, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
 	}
 	batch2 := []tt.OHLCVRecord{
-		{Timestamp: 1700, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
+		{OpenTime: 1700, Open: "100", High: "100", Low: "100", Close: "100", Volume: "100"},
 	}
 
 	_, _ = sanitizer.SanitizeBatch(batch1)
@@ -151,8 +151,8 @@ func TestOHLCVSanitizer_BackwardFetch(t *testing.T) {
 	if len(result) != 1 {
 		t.Fatalf("Expected 1 record, got %d", len(result))
 	}
-	if result[0].Timestamp != 1700 {
-		t.Errorf("Expected timestamp 1700, got %d", result[0].Timestamp)
+	if result[0].OpenTime != 1700 {
+		t.Errorf("Expected timestamp 1700, got %d", result[0].OpenTime)
 	}
 }
 
@@ -162,7 +162,7 @@ func TestOHLCVSanitizer_Validation(t *testing.T) {
 
 	// Test invalid OHLC relationships
 	invalidBatch := []tt.OHLCVRecord{
-		{Timestamp: 1000, Open: "100.0", High: "99.0", Low: "101.0", Close: "100.5", Volume: "1000"}, // High < Low
+		{OpenTime: 1000, Open: "100.0", High: "99.0", Low: "101.0", Close: "100.5", Volume: "1000"}, // High < Low
 	}
 
 	err := sanitizer.ValidateBatch(invalidBatch)
@@ -172,7 +172,7 @@ func TestOHLCVSanitizer_Validation(t *testing.T) {
 
 	// Test invalid timestamp
 	invalidBatch2 := []tt.OHLCVRecord{
-		{Timestamp: 0, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
+		{OpenTime: 0, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
 	}
 
 	err = sanitizer.ValidateBatch(invalidBatch2)
@@ -187,7 +187,7 @@ func TestOHLCVSanitizer_Reset(t *testing.T) {
 
 	// Process a batch
 	batch := []tt.OHLCVRecord{
-		{Timestamp: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
+		{OpenTime: 1000, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "1000"},
 	}
 
 	_, err := sanitizer.SanitizeBatch(batch)
@@ -212,3 +212,76 @@ func TestOHLCVSanitizer_Reset(t *testing.T) {
 		t.Fatalf("Expected initialized to be false after reset")
 	}
 }
+
+func TestOHLCVSanitizer_Release(t *testing.T) {
+	timeframe, _ := tt.TimeframeFromString("1m")
+	sanitizer := NewOHLCVSanitizer(timeframe)
+
+	first, _ := sanitizer.SanitizeBatch([]tt.OHLCVRecord{
+		{OpenTime: 600, Close: "1"},
+		{OpenTime: 60, Close: "1"},
+	})
+	if len(first) != 2 || first[0].OpenTime != 60 {
+		t.Fatalf("Expected 2 sorted records, got %+v", first)
+	}
+	sanitizer.Release(first)
+
+	result, _ := sanitizer.SanitizeBatch([]tt.OHLCVRecord{{OpenTime: 900, Close: "2"}})
+	if len(result) != 5 || cap(result) < 5 {
+		t.Fatalf("Expected 4 gap fills and the new candle, got %+v", result)
+	}
+	for i, c := range result[:4] {
+		if c.OpenTime != int64(660+i*60) || c.Close != "1" {
+			t.Errorf("Unexpected gap fill %+v at %d", c, i)
+		}
+	}
+	if result[4].OpenTime != 900 || result[4].Close != "2" {
+		t.Errorf("Unexpected new candle %+v", result[4])
+	}
+}
+
+// backfillBatches returns 100 pages of 1000 1m candles with a gap of ten
+// candles between consecutive pages
+func backfillBatches() [][]tt.OHLCVRecord {
+	batches := make([][]tt.OHLCVRecord, 100)
+	for i := range batches {
+		batch := make([]tt.OHLCVRecord, 1000)
+		for j := range batch {
+			openTime := int64(i*1010+j) * 60
+			batch[j] = tt.OHLCVRecord{OpenTime: openTime, Open: "100.0", High: "101.0", Low: "99.0", Close: "100.5", Volume: "10"}
+		}
+		batches[i] = batch
+	}
+	return batches
+}
+
+func BenchmarkOHLCVSanitizer_SanitizeBatch(b *testing.B) {
+	timeframe, _ := tt.TimeframeFromString("1m")
+	batches := backfillBatches()
+
+	b.Run("keep", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sanitizer := NewOHLCVSanitizer(timeframe)
+			for _, batch := range batches {
+				if _, err := sanitizer.SanitizeBatch(batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("release", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sanitizer := NewOHLCVSanitizer(timeframe)
+			for _, batch := range batches {
+				result, err := sanitizer.SanitizeBatch(batch)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sanitizer.Release(result)
+			}
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// Package ohlcvtest generates synthetic OHLCV series for tests.
+package ohlcvtest
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+)
+
+// Options tunes GenerateOHLCV. Zero values use the defaults noted per field.
+type Options struct {
+	Seed       uint64  // Same seed, same series
+	StartPrice float64 // Default 100
+	Volatility float64 // Standard deviation of the per-candle return, default 0.01
+	BaseVolume float64 // Average volume, default 1000
+	Precision  int     // Decimal places of prices and volumes, default 2
+
+	GapRate       float64 // Probability that a candle is left out
+	DuplicateRate float64 // Probability that a candle is emitted twice
+	AnomalyRate   float64 // Probability that a candle has high and low swapped
+}
+
+// GenerateOHLCV returns a random-walk series of n candle slots of tf,
+// starting at the first candle open at or after start. OpenTime is in unix
+// seconds. With gaps enabled fewer than n candles are returned, with
+// duplicates more.
+//
+// Example:
+//
+//	tf, _ := tt.TimeframeFromString("1h")
+//	candles := ohlcvtest.GenerateOHLCV(tf, start, 500, ohlcvtest.Options{Seed: 1, GapRate: 0.05})
+func GenerateOHLCV(tf tt.Timeframe, start time.Time, n int, opts Options) []tt.OHLCVRecord {
+	opts = opts.withDefaults()
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+
+	candles := make([]tt.OHLCVRecord, 0, n)
+	price := opts.StartPrice
+	openTime := tf.NextOpen(start)
+
+	for i := 0; i < n; i++ {
+		open := price
+		close := open * math.Exp(rng.NormFloat64()*opts.Volatility)
+		high := math.Max(open, close) * (1 + math.Abs(rng.NormFloat64())*opts.Volatility/2)
+		low := math.Min(open, close) * (1 - math.Abs(rng.NormFloat64())*opts.Volatility/2)
+		volume := opts.BaseVolume * rng.ExpFloat64()
+		price = close
+
+		ts := openTime
+		openTime = tf.CloseTime(openTime)
+
+		if rng.Float64() < opts.GapRate {
+			continue
+		}
+		if rng.Float64() < opts.AnomalyRate {
+			high, low = low, high
+		}
+
+		candle := tt.OHLCVRecord{
+			OpenTime: ts.Unix(),
+			Open:     opts.format(open),
+			High:     opts.format(high),
+			Low:      opts.format(low),
+			Close:    opts.format(close),
+			Volume:   opts.format(volume),
+		}
+		candles = append(candles, candle)
+		if rng.Float64() < opts.DuplicateRate {
+			candles = append(candles, candle)
+		}
+	}
+
+	return candles
+}
+
+func (o Options) withDefaults() Options {
+	if o.StartPrice <= 0 {
+		o.StartPrice = 100
+	}
+	if o.Volatility <= 0 {
+		o.Volatility = 0.01
+	}
+	if o.BaseVolume <= 0 {
+		o.BaseVolume = 1000
+	}
+	if o.Precision <= 0 {
+		o.Precision = 2
+	}
+	return o
+}
+
+func (o Options) format(v float64) string {
+	return strconv.FormatFloat(v, 'f', o.Precision, 64)
+}
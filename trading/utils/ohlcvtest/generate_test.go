@@ -0,0 +1,40 @@
+package ohlcvtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tt "github.com/plusev-terminal/go-plugin-common/trading"
+	tu "github.com/plusev-terminal/go-plugin-common/trading/utils"
+)
+
+func TestGenerateOHLCV(t *testing.T) {
+	tf, _ := tt.TimeframeFromString("1h")
+	start := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	candles := GenerateOHLCV(tf, start, 100, Options{Seed: 42})
+	if len(candles) != 100 {
+		t.Fatalf("expected 100 candles, got %d", len(candles))
+	}
+	if first := time.Unix(candles[0].OpenTime, 0).UTC(); !first.Equal(start.Add(30 * time.Minute)) {
+		t.Errorf("expected first candle aligned to the next hour, got %s", first)
+	}
+	for i := 1; i < len(candles); i++ {
+		if candles[i].OpenTime-candles[i-1].OpenTime != 3600 {
+			t.Fatalf("expected contiguous candles at %d", i)
+		}
+	}
+	if err := tu.NewOHLCVSanitizer(tf).ValidateBatch(candles); err != nil {
+		t.Errorf("expected valid candles: %v", err)
+	}
+
+	if again := GenerateOHLCV(tf, start, 100, Options{Seed: 42}); !reflect.DeepEqual(candles, again) {
+		t.Error("expected same series for same seed")
+	}
+
+	broken := GenerateOHLCV(tf, start, 100, Options{Seed: 42, AnomalyRate: 1})
+	if err := tu.NewOHLCVSanitizer(tf).ValidateBatch(broken); err == nil {
+		t.Error("expected anomalies to fail validation")
+	}
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalDivPlaces is the number of fractional digits DecimalDiv rounds to
+const DecimalDivPlaces = 18
+
+// maxDecimalExponent bounds exponents like "1e-8" so a malformed input
+// cannot allocate huge numbers
+const maxDecimalExponent = 1000
+
+// DecimalAdd returns a + b for decimal strings such as OHLCV prices, without
+// the rounding of float64
+//
+// Example:
+//
+//	total, err := utils.DecimalAdd(candle.Volume, "0.1") // "1234.5" + "0.1" = "1234.6"
+func DecimalAdd(a, b string) (string, error) {
+	return decimalOp(a, b, (*big.Rat).Add)
+}
+
+// DecimalSub returns a - b for decimal strings
+func DecimalSub(a, b string) (string, error) {
+	return decimalOp(a, b, (*big.Rat).Sub)
+}
+
+// DecimalMul returns a * b for decimal strings
+func DecimalMul(a, b string) (string, error) {
+	return decimalOp(a, b, (*big.Rat).Mul)
+}
+
+// DecimalDiv returns a / b for decimal strings, rounded half away from zero
+// to DecimalDivPlaces fractional digits
+func DecimalDiv(a, b string) (string, error) {
+	x, y, err := parseDecimals(a, b)
+	if err != nil {
+		return "", err
+	}
+	if y.Sign() == 0 {
+		return "", fmt.Errorf("decimal division by zero")
+	}
+	return trimDecimal(new(big.Rat).Quo(x, y).FloatString(DecimalDivPlaces)), nil
+}
+
+// DecimalCmp compares decimal strings and returns -1 if a < b, 0 if they are
+// equal (e.g. "1.50" and "1.5") and +1 if a > b
+func DecimalCmp(a, b string) (int, error) {
+	x, y, err := parseDecimals(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return x.Cmp(y), nil
+}
+
+func decimalOp(a, b string, op func(z, x, y *big.Rat) *big.Rat) (string, error) {
+	x, y, err := parseDecimals(a, b)
+	if err != nil {
+		return "", err
+	}
+	return formatDecimal(op(new(big.Rat), x, y)), nil
+}
+
+func parseDecimals(a, b string) (*big.Rat, *big.Rat, error) {
+	x, err := parseDecimal(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := parseDecimal(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, y, nil
+}
+
+// parseDecimal accepts an optionally signed decimal number with an optional
+// exponent, e.g. "-12.5" or "1e-8"
+func parseDecimal(s string) (*big.Rat, error) {
+	mantissa, exp, hasExp := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "e")
+	digits := strings.TrimLeft(mantissa, "+-")
+	valid := digits != "" && digits != "." && len(mantissa)-len(digits) <= 1
+	for i := 0; valid && i < len(digits); i++ {
+		valid = digits[i] >= '0' && digits[i] <= '9' || digits[i] == '.' && strings.Count(digits, ".") == 1
+	}
+	if valid && hasExp {
+		n, err := strconv.Atoi(exp)
+		valid = err == nil && n >= -maxDecimalExponent && n <= maxDecimalExponent
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return r, nil
+}
+
+// formatDecimal prints a terminating decimal exactly, without trailing zeros
+func formatDecimal(r *big.Rat) string {
+	// The fractional digits needed are the larger power of 2 or 5 in the denominator
+	denom := new(big.Int).Set(r.Denom())
+	twos := int(denom.TrailingZeroBits())
+	denom.Rsh(denom, uint(twos))
+
+	fives := 0
+	five, mod := big.NewInt(5), new(big.Int)
+	for denom.Cmp(big.NewInt(1)) > 0 {
+		q, m := new(big.Int).QuoRem(denom, five, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		denom = q
+		fives++
+	}
+	return trimDecimal(r.FloatString(max(twos, fives)))
+}
+
+// trimDecimal removes trailing fractional zeros and the sign of zero
+func trimDecimal(s string) string {
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
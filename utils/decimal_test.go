@@ -0,0 +1,43 @@
+package utils
+
+import "testing"
+
+func TestDecimalArithmetic(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fn   func(a, b string) (string, error)
+		a, b string
+		want string
+	}{
+		{"add", DecimalAdd, "0.1", "0.2", "0.3"},
+		{"add", DecimalAdd, "1234.50", "-1234.5", "0"},
+		{"sub", DecimalSub, "42000.12345678", "0.00000001", "42000.12345677"},
+		{"sub", DecimalSub, "1", "1e-8", "0.99999999"},
+		{"mul", DecimalMul, "0.00012", "25000", "3"},
+		{"mul", DecimalMul, "-1.5", "1.5", "-2.25"},
+		{"div", DecimalDiv, "1", "3", "0.333333333333333333"},
+		{"div", DecimalDiv, "2", "3", "0.666666666666666667"},
+		{"div", DecimalDiv, "100", "0.25", "400"},
+	} {
+		got, err := tc.fn(tc.a, tc.b)
+		if err != nil || got != tc.want {
+			t.Errorf("%s(%s, %s): expected %s, got %s (%v)", tc.name, tc.a, tc.b, tc.want, got, err)
+		}
+	}
+
+	if c, err := DecimalCmp("1.50", "1.5"); err != nil || c != 0 {
+		t.Errorf("expected equal, got %d (%v)", c, err)
+	}
+	if c, _ := DecimalCmp("-2", "1"); c != -1 {
+		t.Errorf("expected -1, got %d", c)
+	}
+
+	if _, err := DecimalDiv("1", "0"); err == nil {
+		t.Error("expected an error for division by zero")
+	}
+	for _, bad := range []string{"", "abc", "1/3", "0x10", "1.2.3", "--1", "1e100000", "."} {
+		if _, err := DecimalAdd(bad, "1"); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
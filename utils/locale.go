@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Locale holds the names FormatHumanDate renders for a language
+type Locale struct {
+	Months      [12]string // January to December
+	MonthsShort [12]string
+	Days        [7]string // Sunday to Saturday, like time.Weekday
+	DaysShort   [7]string
+	AM, PM      string
+	Ordinal     func(n int) string // Day of month with ordinal marker, e.g. "1st"; nil prints the number
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]Locale{
+		"en": {
+			Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			MonthsShort: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			DaysShort:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal:     englishOrdinal,
+		},
+		"de": {
+			Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			MonthsShort: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			DaysShort:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal:     suffixOrdinal("."),
+		},
+		"fr": {
+			Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			MonthsShort: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			DaysShort:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal: func(n int) string {
+				if n == 1 {
+					return "1er"
+				}
+				return strconv.Itoa(n)
+			},
+		},
+		"es": {
+			Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			MonthsShort: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			DaysShort:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			AM:          "a. m.",
+			PM:          "p. m.",
+			Ordinal:     suffixOrdinal("º"),
+		},
+		"it": {
+			Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			MonthsShort: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+			Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+			DaysShort:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal:     suffixOrdinal("º"),
+		},
+		"pt": {
+			Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+			MonthsShort: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+			Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+			DaysShort:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal:     suffixOrdinal("º"),
+		},
+		"nl": {
+			Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+			MonthsShort: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+			Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+			DaysShort:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+			AM:          "AM",
+			PM:          "PM",
+			Ordinal:     suffixOrdinal("e"),
+		},
+	}
+)
+
+// RegisterLocale adds or replaces the locale for a language tag, e.g. "pl"
+func RegisterLocale(tag string, locale Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[strings.ToLower(tag)] = locale
+}
+
+// LocaleFor returns the locale for a language tag. Region subtags fall back
+// to the language ("de-AT" uses "de") and unknown languages to English.
+func LocaleFor(tag string) Locale {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if l, ok := locales[tag]; ok {
+		return l
+	}
+	if lang, _, ok := strings.Cut(tag, "-"); ok {
+		if l, ok := locales[lang]; ok {
+			return l
+		}
+	}
+	return locales["en"]
+}
+
+func englishOrdinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+func suffixOrdinal(suffix string) func(int) string {
+	return func(n int) string {
+		return strconv.Itoa(n) + suffix
+	}
+}
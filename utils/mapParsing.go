@@ -1,21 +1,25 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	mapstructure "github.com/go-viper/mapstructure/v2"
 )
 
 // MapToStruct populates a struct from a map and validates it.
 // T is the type of the struct to populate (must be a pointer to a struct).
-// Returns an error if parsing or validation fails.
+// Returns an error if parsing or validation fails; validation failures are a
+// *ValidationError with field-keyed messages.
 func MapToStruct[T any](data map[string]any, target *T) error {
 	// Initialize mapstructure decoder
 	config := &mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			numberToTimeHook,
 			mapstructure.StringToTimeHookFunc(time.RFC3339Nano), // Handle time parsing
 		),
 		Metadata:         nil,
@@ -34,13 +38,36 @@ func MapToStruct[T any](data map[string]any, target *T) error {
 		return fmt.Errorf("failed to parse map into struct: %w", err)
 	}
 
-	// Initialize validator
-	validate := validator.New()
-	if err := validate.Struct(target); err != nil {
-		// Customize error message for validation
-		return fmt.Errorf("validation failed: %w", err)
+	return ValidateStruct(target)
+}
+
+// numberToTimeHook decodes json.Number unix millis into time.Time, like
+// ExtractTime; without it the number would be parsed as RFC3339 string
+func numberToTimeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	n, ok := data.(json.Number)
+	if !ok || to != reflect.TypeOf(time.Time{}) {
+		return data, nil
+	}
+	ms, err := n.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid unix millis %s", n)
 	}
+	return time.UnixMilli(ms), nil
+}
 
+// DecodeJSON decodes JSON like json.Unmarshal, but numbers in generic values
+// (any, map[string]any) become json.Number instead of float64. Use it where
+// maps carry int64 IDs or precise decimals; GetValue, ExtractInt64,
+// ExtractDecimal and MapToStruct all accept json.Number.
+func DecodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("invalid character after top-level value")
+	}
 	return nil
 }
 
@@ -1,6 +1,11 @@
 package utils
 
-import "time"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type mapValue interface {
 	float64 | string | bool
@@ -13,8 +18,31 @@ func IfThen[T any](condition bool, trueValue T, falseValue T) T {
 	return falseValue
 }
 
+// GetValue returns the value for key if it is of type T, or the default.
+// json.Number values are accepted for float64 and, keeping their exact
+// digits, for string.
 func GetValue[T mapValue](key string, data map[string]any, defaultValue ...T) T {
-	value, ok := data[key].(T)
+	return valueOr(data[key], defaultValue)
+}
+
+// GetPath is GetValue for a dot separated path into nested maps, e.g.
+// "market.symbol". Numeric segments index into arrays, as in "bids.0.price".
+//
+// Example:
+//
+//	symbol := utils.GetPath[string]("market.symbol", req.StreamContext)
+//	price := utils.GetPath("data.0.p", msg, 0.0)
+func GetPath[T mapValue](path string, data map[string]any, defaultValue ...T) T {
+	value, _ := lookupPath(path, data)
+	return valueOr(value, defaultValue)
+}
+
+// valueOr converts value to T like GetValue
+func valueOr[T mapValue](v any, defaultValue []T) T {
+	value, ok := v.(T)
+	if n, isNumber := v.(json.Number); isNumber {
+		value, ok = numberAs[T](n)
+	}
 	if !ok {
 		if len(defaultValue) > 0 {
 			return defaultValue[0]
@@ -42,6 +70,37 @@ func ExtractMap(key string, data map[string]any) map[string]any {
 	return nil
 }
 
+// ExtractMapPath returns the nested map at a dot separated path, or nil
+func ExtractMapPath(path string, data map[string]any) map[string]any {
+	value, _ := lookupPath(path, data)
+	subMap, _ := value.(map[string]any)
+	return subMap
+}
+
+// lookupPath walks a dot separated path through maps and arrays
+func lookupPath(path string, data map[string]any) (any, bool) {
+	var current any = data
+	for segment := range strings.SplitSeq(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
 func AnyMatches[T comparable](predicate func(T) bool, values ...T) bool {
 	for _, v := range values {
 		if predicate(v) {
@@ -51,23 +110,96 @@ func AnyMatches[T comparable](predicate func(T) bool, values ...T) bool {
 	return false
 }
 
+// numberAs converts a json.Number to the GetValue type T
+func numberAs[T mapValue](n json.Number) (T, bool) {
+	var out T
+	switch p := any(&out).(type) {
+	case *float64:
+		f, err := n.Float64()
+		if err != nil {
+			return out, false
+		}
+		*p = f
+	case *string:
+		*p = n.String()
+	default:
+		return out, false
+	}
+	return out, true
+}
+
 // ExtractInt safely extracts an int value from the map
 func ExtractInt(key string, data map[string]any) int {
+	return int(ExtractInt64(key, data))
+}
+
+// ExtractInt64 safely extracts an int64 value from the map. json.Number and
+// decimal strings are parsed exactly, so IDs beyond 2^53 survive.
+func ExtractInt64(key string, data map[string]any) int64 {
 	if val, ok := data[key]; ok {
 		switch v := val.(type) {
 		case int:
-			return v
+			return int64(v)
 		case int64:
-			return int(v)
+			return v
 		case float64:
-			return int(v)
+			return int64(v)
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return n
+			}
+			if f, err := v.Float64(); err == nil {
+				return int64(f)
+			}
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
 		}
 	}
 	return 0
 }
 
+// ExtractDecimal extracts a number as decimal string, e.g. a quantity to pass
+// on to an exchange. Strings and json.Number keep their exact digits; float64
+// values are formatted with the fewest digits that represent them.
+func ExtractDecimal(key string, data map[string]any) string {
+	if val, ok := data[key]; ok {
+		switch v := val.(type) {
+		case string:
+			return v
+		case json.Number:
+			return v.String()
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			return strconv.Itoa(v)
+		case int64:
+			return strconv.FormatInt(v, 10)
+		}
+	}
+	return ""
+}
+
+// ToFloat64 converts a numeric map value (float64, int, int64 or
+// json.Number) to float64
+func ToFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
 // ExtractTime safely extracts a time.Time value from the map
-// Supports: string (RFC3339), time.Time, int64/float64 (unix millis)
+// Supports: string (RFC3339), time.Time, int64/float64/json.Number (unix millis)
 func ExtractTime(key string, data map[string]any) *time.Time {
 	if val, ok := data[key]; ok && val != nil {
 		switch v := val.(type) {
@@ -86,6 +218,11 @@ func ExtractTime(key string, data map[string]any) *time.Time {
 		case float64:
 			t := time.UnixMilli(int64(v))
 			return &t
+		case json.Number:
+			if ms, err := v.Int64(); err == nil {
+				t := time.UnixMilli(ms)
+				return &t
+			}
 		}
 	}
 	return nil
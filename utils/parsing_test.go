@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestGetPath(t *testing.T) {
+	var data map[string]any
+	if err := DecodeJSON([]byte(`{
+		"market": {"symbol": "BTCUSDT", "tick": 0.01, "spot": true},
+		"bids": [{"price": "42000.5", "id": 9007199254740993}],
+		"flat": "x"
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := GetPath[string]("market.symbol", data); got != "BTCUSDT" {
+		t.Errorf("expected BTCUSDT, got %q", got)
+	}
+	if got := GetPath[float64]("market.tick", data); got != 0.01 {
+		t.Errorf("expected 0.01, got %v", got)
+	}
+	if !GetPath[bool]("market.spot", data) {
+		t.Error("expected spot to be true")
+	}
+	if got := GetPath[string]("bids.0.price", data); got != "42000.5" {
+		t.Errorf("expected the first bid price, got %q", got)
+	}
+	if got := GetPath[string]("bids.0.id", data); got != "9007199254740993" {
+		t.Errorf("expected the exact id, got %q", got)
+	}
+	if got := GetPath("flat", data, "y"); got != "x" {
+		t.Errorf("expected a single segment to work like GetValue, got %q", got)
+	}
+
+	for _, path := range []string{"market.base", "bids.1.price", "bids.x", "flat.value", "market.symbol.x", ""} {
+		if got := GetPath(path, data, "default"); got != "default" {
+			t.Errorf("%q: expected the default, got %q", path, got)
+		}
+	}
+
+	if m := ExtractMapPath("bids.0", data); m == nil || m["price"] != "42000.5" {
+		t.Errorf("expected the first bid map, got %v", m)
+	}
+	if m := ExtractMapPath("market.symbol", data); m != nil {
+		t.Errorf("expected nil for a non-map value, got %v", m)
+	}
+}
+
+func TestNumberHelpers(t *testing.T) {
+	data := map[string]any{
+		"id":    json.Number("9007199254740993"),
+		"qty":   json.Number("0.12345678901234567891"),
+		"float": 1.5,
+		"str":   "123",
+	}
+
+	if got := ExtractInt64("id", data); got != 9007199254740993 {
+		t.Errorf("expected the exact id, got %d", got)
+	}
+	if got := ExtractInt64("str", data); got != 123 {
+		t.Errorf("expected 123, got %d", got)
+	}
+	if got := ExtractDecimal("qty", data); got != "0.12345678901234567891" {
+		t.Errorf("expected the exact decimal, got %s", got)
+	}
+	if got := ExtractDecimal("float", data); got != "1.5" {
+		t.Errorf("expected 1.5, got %s", got)
+	}
+	if got := GetValue[float64]("id", data); got != 9007199254740992 {
+		t.Errorf("expected the float64 value, got %v", got)
+	}
+}
+
+func TestMapToStructFieldErrors(t *testing.T) {
+	type market struct {
+		Symbol string `mapstructure:"symbol" validate:"required"`
+	}
+	type params struct {
+		Timeframe string   `mapstructure:"timeframe" validate:"required"`
+		Limit     int      `mapstructure:"limit" validate:"min=1,max=1000"`
+		Side      string   `json:"side" validate:"oneof=buy sell"`
+		Tags      []string `mapstructure:"tags" validate:"max=2"`
+		Market    market   `mapstructure:"market"`
+	}
+
+	var p params
+	err := MapToStruct(map[string]any{"limit": 0, "side": "hold", "tags": []string{"a", "b", "c"}}, &p)
+	if err == nil {
+		t.Fatal("expected a validation error")
+	}
+
+	want := FieldErrors{
+		"timeframe":     "is required",
+		"limit":         "must be at least 1",
+		"side":          "must be one of: buy, sell",
+		"tags":          "must contain at most 2 items",
+		"market.symbol": "is required",
+	}
+	got := FieldErrorsOf(fmt.Errorf("wrapped: %w", err))
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for field, msg := range want {
+		if got[field] != msg {
+			t.Errorf("%s: expected %q, got %q", field, msg, got[field])
+		}
+	}
+
+	const msg = "validation failed: limit must be at least 1; market.symbol is required; side must be one of: buy, sell; tags must contain at most 2 items; timeframe is required"
+	if err.Error() != msg {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Error("expected the validator errors to stay reachable")
+	}
+	if FieldErrorsOf(errors.New("other")) != nil {
+		t.Error("expected no field errors for other errors")
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrRetryCanceled is returned, joined with the last error, when the cancel
+// channel of Retry closes before fn succeeded
+var ErrRetryCanceled = errors.New("retry canceled")
+
+// Backoff controls the delays between the attempts of Retry
+type Backoff struct {
+	Initial    time.Duration // Delay before the first retry
+	Max        time.Duration // Upper bound of the delay, 0 for none
+	Multiplier float64       // Growth per retry, 0 means 2
+	Jitter     float64       // Fraction of the delay randomized in both directions, e.g. 0.2 for ±20%
+
+	Retryable func(error) bool    // Reports whether an error is worth retrying, nil retries all errors
+	Sleep     func(time.Duration) // Waits between attempts, nil uses a timer that also observes cancel
+}
+
+// DefaultBackoff starts at 500ms and doubles up to 30s with ±20% jitter
+var DefaultBackoff = Backoff{Initial: 500 * time.Millisecond, Max: 30 * time.Second, Jitter: 0.2}
+
+// Delay returns the delay before retry n, starting at 1
+func (b Backoff) Delay(n int) time.Duration {
+	mul := b.Multiplier
+	if mul <= 0 {
+		mul = 2
+	}
+
+	delay := float64(b.Initial) * math.Pow(mul, float64(n-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		delay *= 1 + b.Jitter*(2*rand.Float64()-1)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// Retry calls fn until it succeeds, returns an error Backoff.Retryable
+// rejects, or attempts calls were made, and returns the last error. cancel
+// works like ctx.Done(): once closed no further attempt is made and the
+// result wraps ErrRetryCanceled. A nil cancel never fires.
+//
+// Example:
+//
+//	err := utils.Retry(nil, 5, utils.DefaultBackoff, func(attempt int) error {
+//	    _, err := req.Send(request, &page)
+//	    return err
+//	})
+func Retry(cancel <-chan struct{}, attempts int, backoff Backoff, fn func(attempt int) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+		if attempt >= attempts || (backoff.Retryable != nil && !backoff.Retryable(err)) {
+			return err
+		}
+		if !wait(cancel, backoff.Delay(attempt), backoff.Sleep) {
+			return errors.Join(ErrRetryCanceled, err)
+		}
+	}
+}
+
+// wait sleeps for d and reports whether cancel is still open afterwards
+func wait(cancel <-chan struct{}, d time.Duration, sleep func(time.Duration)) bool {
+	if sleep != nil {
+		sleep(d)
+	} else if cancel != nil {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-cancel:
+			return false
+		}
+	} else {
+		time.Sleep(d)
+	}
+
+	select {
+	case <-cancel:
+		return false
+	default:
+		return true
+	}
+}
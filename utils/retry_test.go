@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var slept []time.Duration
+	backoff := Backoff{Initial: time.Second, Max: 3 * time.Second, Sleep: func(d time.Duration) { slept = append(slept, d) }}
+	errTemporary := errors.New("temporary")
+
+	calls := 0
+	err := Retry(nil, 5, backoff, func(attempt int) error {
+		calls++
+		if attempt != calls {
+			t.Errorf("expected attempt %d, got %d", calls, attempt)
+		}
+		if attempt < 4 {
+			return errTemporary
+		}
+		return nil
+	})
+	if err != nil || calls != 4 {
+		t.Fatalf("expected success on the 4th attempt, got %v after %d calls", err, calls)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(slept) != len(want) {
+		t.Fatalf("expected delays %v, got %v", want, slept)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Errorf("delay %d: expected %s, got %s", i, want[i], slept[i])
+		}
+	}
+
+	calls = 0
+	if err := Retry(nil, 3, backoff, func(int) error { calls++; return errTemporary }); !errors.Is(err, errTemporary) || calls != 3 {
+		t.Errorf("expected the last error after 3 calls, got %v after %d", err, calls)
+	}
+
+	errFatal := errors.New("fatal")
+	backoff.Retryable = func(err error) bool { return !errors.Is(err, errFatal) }
+	calls = 0
+	if err := Retry(nil, 3, backoff, func(int) error { calls++; return errFatal }); !errors.Is(err, errFatal) || calls != 1 {
+		t.Errorf("expected no retry of a fatal error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	cancel := make(chan struct{})
+	backoff := Backoff{Initial: time.Hour}
+	errTemporary := errors.New("temporary")
+
+	calls := 0
+	err := Retry(cancel, 3, backoff, func(int) error {
+		calls++
+		close(cancel)
+		return errTemporary
+	})
+	if !errors.Is(err, ErrRetryCanceled) || !errors.Is(err, errTemporary) || calls != 1 {
+		t.Errorf("expected cancellation after one call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Jitter: 0.5}
+	for range 100 {
+		if d := backoff.Delay(2); d < time.Second || d > 3*time.Second {
+			t.Fatalf("delay %s outside of 2s ±50%%", d)
+		}
+	}
+}
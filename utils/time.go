@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"maps"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -76,57 +80,220 @@ var humanDateFormatTokenMap = map[string]string{
 	"z":    "Z07:00",
 }
 
+// HumanDateFormatToGoFormat converts a human date format such as
+// "DD.MM.YYYY HH:mm" into a Go layout. Tokens match case-insensitively, an
+// exact case match wins (so "MM" is the month and "mm" the minute). Tokens
+// without Go layout equivalent, like quarters, are copied as-is; use
+// FormatHumanDate for them.
 func HumanDateFormatToGoFormat(format string) string {
-	// Check for predefined format names first
+	if layout, ok := predefinedDateFormat(format); ok {
+		return layout
+	}
+
+	// Create a new result string by scanning through the input format
+	var result strings.Builder
+	tokens := goDateTokens()
+	remaining := format
+
+	for len(remaining) > 0 {
+		// Try to match each token at the current position
+		if token, ok := matchDateToken(remaining, tokens); ok {
+			result.WriteString(humanDateFormatTokenMap[token])
+			remaining = remaining[len(token):]
+			continue
+		}
+
+		// If no token matches at current position, copy the character as-is
+		result.WriteByte(remaining[0])
+		remaining = remaining[1:]
+	}
+
+	return result.String()
+}
+
+// FormatHumanDate formats t with a human date format in the given locale
+// (a language tag like "de" or "fr-CA", see LocaleFor). Besides the tokens
+// of HumanDateFormatToGoFormat it supports:
+//
+//	Q     quarter (1-4)
+//	Do    day of month with ordinal marker (1st, 1., 1er)
+//	DOY   day of year, zero padded (001-366)
+//	W WW  ISO week (1-53, 01-53)
+//	GGGG  ISO week-numbering year
+//
+// Example:
+//
+//	utils.FormatHumanDate(t, "dddd, Do MMMM YYYY", "de") // Montag, 1. Januar 2024
+//	utils.FormatHumanDate(t, "YYYY-[Q]Q", "en")          // 2024-Q1
+//
+// Text in square brackets is copied without token replacement.
+func FormatHumanDate(t time.Time, format string, locale string) string {
+	if layout, ok := predefinedDateFormat(format); ok {
+		return t.Format(layout)
+	}
+
+	loc := LocaleFor(locale)
+	tokens := humanDateTokens()
+	var result strings.Builder
+	remaining := format
+
+	for len(remaining) > 0 {
+		if remaining[0] == '[' {
+			if end := strings.IndexByte(remaining, ']'); end > 0 {
+				result.WriteString(remaining[1:end])
+				remaining = remaining[end+1:]
+				continue
+			}
+		}
+		if token, ok := matchDateToken(remaining, tokens); ok {
+			result.WriteString(renderDateToken(t, token, &loc))
+			remaining = remaining[len(token):]
+			continue
+		}
+		result.WriteByte(remaining[0])
+		remaining = remaining[1:]
+	}
+
+	return result.String()
+}
+
+// predefinedDateFormat resolves the named formats like "iso" or "datetime"
+func predefinedDateFormat(format string) (string, bool) {
 	switch strings.ToLower(format) {
 	case "iso", "iso8601":
-		return "2006-01-02T15:04:05Z07:00"
+		return "2006-01-02T15:04:05Z07:00", true
 	case "rfc3339":
-		return time.RFC3339
+		return time.RFC3339, true
 	case "short":
-		return "2006-01-02"
+		return "2006-01-02", true
 	case "time":
-		return "15:04:05"
+		return "15:04:05", true
 	case "datetime":
-		return "2006-01-02 15:04:05"
+		return "2006-01-02 15:04:05", true
 	}
+	return "", false
+}
 
-	// Get tokens sorted by length (longest first) to handle overlapping patterns
-	var tokens []string
-	for token := range humanDateFormatTokenMap {
-		tokens = append(tokens, token)
-	}
-	sort.Slice(tokens, func(i, j int) bool {
-		return len(tokens[i]) > len(tokens[j])
-	})
+// humanDateExtraTokens are the tokens only FormatHumanDate supports
+var humanDateExtraTokens = []string{"Q", "Do", "DOY", "W", "WW", "GGGG"}
 
-	// Create a new result string by scanning through the input format
-	var result strings.Builder
-	remaining := format
+var (
+	goDateTokens = sync.OnceValue(func() []string {
+		return sortDateTokens(slices.Collect(maps.Keys(humanDateFormatTokenMap)))
+	})
+	humanDateTokens = sync.OnceValue(func() []string {
+		return sortDateTokens(append(slices.Collect(maps.Keys(humanDateFormatTokenMap)), humanDateExtraTokens...))
+	})
+)
 
-	for len(remaining) > 0 {
-		foundMatch := false
+// sortDateTokens orders tokens longest first to handle overlapping patterns
+func sortDateTokens(tokens []string) []string {
+	sort.Slice(tokens, func(i, j int) bool {
+		if len(tokens[i]) != len(tokens[j]) {
+			return len(tokens[i]) > len(tokens[j])
+		}
+		return tokens[i] < tokens[j]
+	})
+	return tokens
+}
 
-		// Try to match each token at the current position
-		for _, token := range tokens {
-			// Check for case-insensitive match at the start of remaining string
-			if len(remaining) >= len(token) &&
-				strings.EqualFold(remaining[:len(token)], token) {
-				// Found a match, add the Go format string
-				result.WriteString(humanDateFormatTokenMap[token])
-				// Remove the matched portion from the remaining string
-				remaining = remaining[len(token):]
-				foundMatch = true
-				break
+// matchDateToken returns the longest token at the start of s. Among tokens
+// of the same length an exact case match wins over a case-insensitive one.
+func matchDateToken(s string, tokens []string) (string, bool) {
+	folded := ""
+	for i, token := range tokens {
+		if len(s) >= len(token) {
+			if s[:len(token)] == token {
+				return token, true
 			}
+			if folded == "" && strings.EqualFold(s[:len(token)], token) {
+				folded = token
+			}
+		}
+		if folded != "" && (i+1 == len(tokens) || len(tokens[i+1]) < len(token)) {
+			return folded, true
 		}
+	}
+	return "", false
+}
 
-		// If no token matches at current position, copy the character as-is
-		if !foundMatch {
-			result.WriteByte(remaining[0])
-			remaining = remaining[1:]
+// renderDateToken formats a single token of FormatHumanDate
+func renderDateToken(t time.Time, token string, loc *Locale) string {
+	pad := func(n, width int) string {
+		s := strconv.Itoa(n)
+		for len(s) < width {
+			s = "0" + s
 		}
+		return s
+	}
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	meridiem := loc.AM
+	if t.Hour() >= 12 {
+		meridiem = loc.PM
 	}
 
-	return result.String()
+	switch token {
+	case "dddd":
+		return loc.Days[t.Weekday()]
+	case "ddd":
+		return loc.DaysShort[t.Weekday()]
+	case "dd":
+		return pad(t.Day(), 2)
+	case "d":
+		return strconv.Itoa(t.Day())
+	case "Do":
+		if loc.Ordinal == nil {
+			return strconv.Itoa(t.Day())
+		}
+		return loc.Ordinal(t.Day())
+	case "DOY":
+		return pad(t.YearDay(), 3)
+	case "MMMM":
+		return loc.Months[t.Month()-1]
+	case "MMM":
+		return loc.MonthsShort[t.Month()-1]
+	case "MM":
+		return pad(int(t.Month()), 2)
+	case "M":
+		return strconv.Itoa(int(t.Month()))
+	case "Q":
+		return strconv.Itoa((int(t.Month())-1)/3 + 1)
+	case "YYYY":
+		return pad(t.Year(), 4)
+	case "YY":
+		return pad(t.Year()%100, 2)
+	case "GGGG":
+		year, _ := t.ISOWeek()
+		return pad(year, 4)
+	case "WW":
+		_, week := t.ISOWeek()
+		return pad(week, 2)
+	case "W":
+		_, week := t.ISOWeek()
+		return strconv.Itoa(week)
+	case "HH", "H":
+		return pad(t.Hour(), 2)
+	case "hh":
+		return pad(hour12, 2)
+	case "h":
+		return strconv.Itoa(hour12)
+	case "mm":
+		return pad(t.Minute(), 2)
+	case "m":
+		return strconv.Itoa(t.Minute())
+	case "ss":
+		return pad(t.Second(), 2)
+	case "s":
+		return strconv.Itoa(t.Second())
+	case "AMPM", "AP":
+		return meridiem
+	case "ampm", "ap":
+		return strings.ToLower(meridiem)
+	case "Z", "z":
+		return t.Format("Z07:00")
+	}
+	return token
 }
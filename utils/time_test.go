@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanDateFormatToGoFormat(t *testing.T) {
+	for format, want := range map[string]string{
+		"DD.MM.YYYY HH:mm": "02.01.2006 15:04",
+		"yyyy-mm-dd":       "2006-04-02",
+		"hh:mm ampm":       "03:04 pm",
+		"datetime":         "2006-01-02 15:04:05",
+		"YYYY-Q":           "2006-Q",
+	} {
+		for range 10 {
+			if got := HumanDateFormatToGoFormat(format); got != want {
+				t.Fatalf("%s: expected %s, got %s", format, want, got)
+			}
+		}
+	}
+}
+
+func TestFormatHumanDate(t *testing.T) {
+	tm := time.Date(2024, 12, 30, 14, 5, 9, 0, time.UTC) // Monday of ISO week 1 of 2025
+
+	for _, tc := range []struct {
+		format, locale, want string
+	}{
+		{"dddd, Do MMMM YYYY", "en", "Monday, 30th December 2024"},
+		{"dddd, Do MMMM YYYY", "de-AT", "Montag, 30. Dezember 2024"},
+		{"ddd d MMM", "fr", "lun. 30 déc."},
+		{"YYYY-[Q]Q", "en", "2024-Q4"},
+		{"GGGG-[W]WW", "en", "2025-W01"},
+		{"YYYY-DOY", "en", "2024-365"},
+		{"h:mm AMPM", "es", "2:05 p. m."},
+		{"DD.MM.YYYY HH:mm:ss", "xx", "30.12.2024 14:05:09"},
+		{"iso", "de", "2024-12-30T14:05:09Z"},
+	} {
+		if got := FormatHumanDate(tm, tc.format, tc.locale); got != tc.want {
+			t.Errorf("%s (%s): expected %q, got %q", tc.format, tc.locale, tc.want, got)
+		}
+	}
+
+	for day, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd"} {
+		if got := FormatHumanDate(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), "Do", "en"); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+	if got := FormatHumanDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "Do MMMM", "fr"); got != "1er mars" {
+		t.Errorf("expected 1er mars, got %s", got)
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import "time"
+
+// Range is a half-open [Start, End) time range in which Count intervals
+// open
+type Range struct {
+	Start time.Time
+	End   time.Time
+	Count int
+}
+
+// Interval is the unit SplitRange counts in. trading.Timeframe implements
+// it with calendar aware months and years; Every adapts a fixed duration.
+type Interval interface {
+	// NextOpen returns t if an interval opens at t, or the next open after t
+	NextOpen(t time.Time) time.Time
+	// Advance moves the open t forward by n intervals
+	Advance(t time.Time, n int) time.Time
+}
+
+// Every is an Interval of fixed length, aligned to the Unix epoch
+type Every time.Duration
+
+// NextOpen implements Interval
+func (e Every) NextOpen(t time.Time) time.Time {
+	d := time.Duration(e)
+	if d <= 0 {
+		return t
+	}
+	open := time.Unix(0, 0).Add(t.Sub(time.Unix(0, 0)) / d * d).In(t.Location())
+	if open.Before(t) {
+		open = open.Add(d)
+	}
+	return open
+}
+
+// Advance implements Interval
+func (e Every) Advance(t time.Time, n int) time.Time {
+	return t.Add(time.Duration(n) * time.Duration(e))
+}
+
+// SplitRange splits [start, end) into consecutive ranges in which at most
+// maxItemsPerChunk intervals open, e.g. candles per OHLCV request. The first
+// range begins at start, all later boundaries fall on interval opens, so no
+// interval is counted in two ranges. maxItemsPerChunk <= 0 returns the whole
+// range as one chunk.
+//
+// Example:
+//
+//	// OHLCV pages of at most 1000 candles
+//	for _, r := range utils.SplitRange(from, to, tf, 1000) {
+//	    fetch(r.Start, r.End, r.Count)
+//	}
+//
+//	// Planner imports a week of days at a time
+//	for _, r := range utils.SplitRange(params.From, params.To, utils.Every(24*time.Hour), 7) {
+//	    importEvents(r.Start, r.End)
+//	}
+func SplitRange(start, end time.Time, interval Interval, maxItemsPerChunk int) []Range {
+	var ranges []Range
+	open := interval.NextOpen(start)
+	if !start.Before(end) || !interval.Advance(open, 1).After(open) {
+		return ranges
+	}
+
+	for cur := start; cur.Before(end); {
+		next := end
+		if maxItemsPerChunk > 0 {
+			next = interval.Advance(open, maxItemsPerChunk)
+		}
+		if !next.Before(end) {
+			next = end
+		}
+
+		count := 0
+		for ; open.Before(next); open = interval.Advance(open, 1) {
+			count++
+		}
+		ranges = append(ranges, Range{Start: cur, End: next, Count: count})
+		cur = next
+	}
+	return ranges
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitRange(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	fiveMin := Every(5 * time.Minute)
+
+	// Unaligned start: the candle at 00:00 opened before it and is not counted
+	ranges := SplitRange(at(0, 2), at(1, 0), fiveMin, 4)
+	want := []Range{
+		{Start: at(0, 2), End: at(0, 25), Count: 4},
+		{Start: at(0, 25), End: at(0, 45), Count: 4},
+		{Start: at(0, 45), End: at(1, 0), Count: 3},
+	}
+	if len(ranges) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ranges)
+	}
+	total := 0
+	for i, r := range ranges {
+		if !r.Start.Equal(want[i].Start) || !r.End.Equal(want[i].End) || r.Count != want[i].Count {
+			t.Errorf("range %d: expected %v, got %v", i, want[i], r)
+		}
+		total += r.Count
+	}
+	if total != 11 {
+		t.Errorf("expected every candle from 00:05 to 00:55 once, got %d", total)
+	}
+
+	if r := SplitRange(at(0, 0), at(1, 0), fiveMin, 0); len(r) != 1 || r[0].Count != 12 {
+		t.Errorf("expected a single chunk of 12, got %v", r)
+	}
+	if r := SplitRange(at(1, 0), at(0, 0), fiveMin, 4); len(r) != 0 {
+		t.Errorf("expected no ranges for an empty range, got %v", r)
+	}
+	if r := SplitRange(at(0, 0), at(1, 0), Every(0), 4); len(r) != 0 {
+		t.Errorf("expected no ranges for a zero interval, got %v", r)
+	}
+}
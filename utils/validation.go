@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldErrors maps field paths to user readable messages, e.g.
+// {"timeframe": "is required", "market.symbol": "is required"}. Paths use the
+// mapstructure (or json) names of the fields, like GetPath.
+type FieldErrors map[string]string
+
+// String joins the messages sorted by field, e.g.
+// "limit must be at least 1; timeframe is required"
+func (e FieldErrors) String() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field + " " + e[field]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidationError is returned by MapToStruct when the decoded struct fails
+// validation. Its message only contains the field paths and messages; the
+// validator errors stay available through errors.As.
+type ValidationError struct {
+	Fields FieldErrors
+	Err    validator.ValidationErrors // nil for checks outside validate tags
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Fields.String()
+}
+
+func (e *ValidationError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// ValidateStruct runs the validate tags of a struct like MapToStruct does and
+// returns a *ValidationError keyed by the mapstructure or json field names
+func ValidateStruct(v any) error {
+	if err := validate().Struct(v); err != nil {
+		var errs validator.ValidationErrors
+		if errors.As(err, &errs) {
+			return &ValidationError{Fields: FormatValidationErrors(errs), Err: errs}
+		}
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return nil
+}
+
+// FieldErrorsOf returns the field errors carried by err, or nil if err is
+// no (wrapped) ValidationError or validator.ValidationErrors
+func FieldErrorsOf(err error) FieldErrors {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.Fields
+	}
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		return FormatValidationErrors(errs)
+	}
+	return nil
+}
+
+// FormatValidationErrors converts validator errors into field errors
+func FormatValidationErrors(errs validator.ValidationErrors) FieldErrors {
+	out := make(FieldErrors, len(errs))
+	for _, fe := range errs {
+		field := fe.Namespace()
+		// Drop the name of the validated struct type
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest
+		}
+		if _, exists := out[field]; !exists {
+			out[field] = validationMessage(fe)
+		}
+	}
+	return out
+}
+
+// validationMessage describes a failed validation tag
+func validationMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	size := func(verb string) string {
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", verb, param)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must contain %s %s items", verb, param)
+		}
+		return fmt.Sprintf("must be %s %s", verb, param)
+	}
+
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_with_all", "required_without", "required_without_all":
+		return "is required"
+	case "min", "gte":
+		return size("at least")
+	case "max", "lte":
+		return size("at most")
+	case "len", "eq":
+		return size("exactly")
+	case "gt":
+		return size("more than")
+	case "lt":
+		return size("less than")
+	case "ne":
+		return "must not be " + param
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url", "uri", "http_url":
+		return "must be a valid URL"
+	case "numeric", "number":
+		return "must be a number"
+	case "semver":
+		return "must be a semantic version"
+	case "uuid", "uuid4":
+		return "must be a UUID"
+	}
+	return fmt.Sprintf("failed the %q check", fe.Tag())
+}
+
+// validate is the validator used by MapToStruct. Field names in its errors
+// are the mapstructure or json names instead of the Go field names.
+var validate = sync.OnceValue(func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"mapstructure", "json"} {
+			// "-" is not returned as it would skip validating the field
+			if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+				return name
+			}
+		}
+		return ""
+	})
+	return v
+})
@@ -4,28 +4,32 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/extism/go-pdk"
+	"github.com/plusev-terminal/go-plugin-common/clock"
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
 )
 
-// Import the time_now host function
-//
-//go:wasmimport extism:host/user time_now
-func hostTimeNow(_ uint64) uint64
+// HostClock is a clock.Clock backed by the host's time_now function.
+// It reports the zero time when the host call fails.
+var HostClock clock.Clock = clock.Func(func() time.Time {
+	now, _ := Now()
+	return now
+})
 
 // Now returns the current time from the host
-// This is necessary because WASM plugins don't have reliable access to system time
+// This is necessary because WASM plugins don't have reliable access to system time.
+// Native builds without an installed host (plain unit tests) use the system time.
 func Now() (time.Time, error) {
-	// Call the host function to get current time (pass 0 as dummy parameter)
-	offset := hostTimeNow(0)
-	if offset == 0 {
+	if !host.Available() {
+		return time.Now(), nil
+	}
+
+	// Call the host function to get current time (no input)
+	timeBytes := host.CallTimeNow(nil)
+	if timeBytes == nil {
 		// Host function failed, return zero time
 		return time.Time{}, nil
 	}
 
-	// Read the time data from memory
-	timeMem := pdk.FindMemory(offset)
-	timeBytes := timeMem.ReadBytes()
-
 	// Unmarshal the JSON time
 	var t time.Time
 	if err := json.Unmarshal(timeBytes, &t); err != nil {
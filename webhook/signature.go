@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/plusev-terminal/go-plugin-common/crypto"
+)
+
+// ErrInvalidSignature is returned when a webhook signature does not match
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifyHMACSHA256Hex checks a hex encoded HMAC-SHA256 of body, as sent by
+// GitHub-style webhooks. A "sha256=" prefix is accepted.
+func VerifyHMACSHA256Hex(secret, body []byte, signature string) error {
+	signature = strings.TrimPrefix(signature, "sha256=")
+	expected := crypto.HexEncode(crypto.HMACSHA256(secret, body))
+	if signature == "" || !crypto.EqualString(strings.ToLower(signature), expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyHMACSHA256Base64 checks a base64 encoded HMAC-SHA256 of body
+func VerifyHMACSHA256Base64(secret, body []byte, signature string) error {
+	expected := crypto.Base64Encode(crypto.HMACSHA256(secret, body))
+	if signature == "" || !crypto.EqualString(signature, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyToken compares a shared secret token sent with the request (e.g. in
+// a TradingView alert message or a query parameter) in constant time
+func VerifyToken(provided, expected string) error {
+	if expected == "" || !crypto.EqualString(provided, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyTimestamp rejects requests whose timestamp is further than tolerance
+// from now, protecting signed webhooks against replays
+func VerifyTimestamp(ts, now time.Time, tolerance time.Duration) error {
+	if d := now.Sub(ts); d > tolerance || d < -tolerance {
+		return errors.New("webhook timestamp outside tolerance")
+	}
+	return nil
+}
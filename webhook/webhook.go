@@ -0,0 +1,122 @@
+// Package webhook lets plugins receive HTTP callbacks, e.g. TradingView alerts
+// or exchange order callbacks. Endpoints are declared in meta.Meta.Webhooks;
+// the host forwards matching requests to the handle_webhook export.
+//
+//	func init() {
+//	    plugin.RegisterPlugin(&MyPlugin{})
+//	    webhook.Register(webhook.HandlerFunc(func(req webhook.Request) (webhook.Response, error) {
+//	        if err := webhook.VerifyHMACSHA256Hex(secret, req.Body, req.Header("X-Signature")); err != nil {
+//	            return webhook.Status(401), nil
+//	        }
+//	        // ...
+//	        return webhook.OK(), nil
+//	    }))
+//	}
+package webhook
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/plusev-terminal/go-plugin-common/internal/host"
+	"github.com/plusev-terminal/go-plugin-common/trace"
+)
+
+// Request is the input of the handle_webhook export
+type Request struct {
+	Endpoint   string            `json:"endpoint"` // Name of the declared meta.Webhook
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Headers    map[string]string `json:"headers"`
+	Query      map[string]string `json:"query,omitempty"`
+	Body       []byte            `json:"body"`
+	RemoteAddr string            `json:"remoteAddr,omitempty"`
+	ReceivedAt int64             `json:"receivedAt"` // Unix timestamp in milliseconds
+	TraceID    string            `json:"traceId,omitempty"`
+}
+
+// Header returns a request header, matching the name case-insensitively
+func (r Request) Header(name string) string {
+	if v, ok := r.Headers[name]; ok {
+		return v
+	}
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// Response is the output of the handle_webhook export; the host answers the
+// HTTP request with it. Status defaults to 200.
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"`
+	Error   string            `json:"error,omitempty"` // Logged by the host, not sent to the caller
+}
+
+// OK returns an empty 200 response
+func OK() Response {
+	return Response{Status: http.StatusOK}
+}
+
+// Status returns an empty response with status
+func Status(status int) Response {
+	return Response{Status: status}
+}
+
+// Handler handles webhook requests
+type Handler interface {
+	HandleWebhook(req Request) (Response, error)
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(req Request) (Response, error)
+
+// HandleWebhook implements Handler
+func (f HandlerFunc) HandleWebhook(req Request) (Response, error) {
+	return f(req)
+}
+
+var registeredHandler Handler
+
+// Register sets the handler of the handle_webhook export. Call it in init().
+func Register(h Handler) {
+	registeredHandler = h
+}
+
+// ============================================================================
+// WASM Exports - Auto-generated
+// ============================================================================
+
+func init() {
+	host.RegisterExport("handle_webhook", handle_webhook)
+}
+
+//go:wasmexport handle_webhook
+func handle_webhook() int32 {
+	if registeredHandler == nil {
+		host.OutputJSON(Response{Status: http.StatusNotFound, Error: "webhook handler not registered"})
+		return 1
+	}
+
+	var req Request
+	if err := host.InputJSON(&req); err != nil {
+		host.OutputJSON(Response{Status: http.StatusBadRequest, Error: "failed to parse webhook request"})
+		return 1
+	}
+
+	defer trace.Set(req.TraceID)()
+	resp, err := registeredHandler.HandleWebhook(req)
+	if err != nil {
+		host.OutputJSON(Response{Status: http.StatusInternalServerError, Error: err.Error()})
+		return 1
+	}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	host.OutputJSON(resp)
+	return 0
+}
@@ -0,0 +1,41 @@
+//go:build !wasm
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/plusev-terminal/go-plugin-common/crypto"
+	"github.com/plusev-terminal/go-plugin-common/plugintest"
+)
+
+func TestHandleWebhookExport(t *testing.T) {
+	secret := []byte("s3cret")
+	Register(HandlerFunc(func(req Request) (Response, error) {
+		if err := VerifyHMACSHA256Hex(secret, req.Body, req.Header("x-signature")); err != nil {
+			return Status(401), nil
+		}
+		return Response{Body: []byte("ok")}, nil
+	}))
+	h := plugintest.New(t)
+
+	body := []byte(`{"ticker":"BTCUSDT"}`)
+	for sig, want := range map[string]int{
+		"sha256=" + crypto.HMACSHA256Hex(string(secret), string(body)): 200,
+		"sha256=deadbeef": 401,
+	} {
+		input, _ := json.Marshal(Request{Endpoint: "alerts", Method: "POST", Headers: map[string]string{"X-Signature": sig}, Body: body})
+		_, output, err := h.Invoke("handle_webhook", input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp Response
+		if err := json.Unmarshal(output, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != want {
+			t.Fatalf("signature %s: expected status %d, got %d", sig, want, resp.Status)
+		}
+	}
+}